@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"sort"
+
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +17,8 @@ var (
 	connectLocalPort    int
 	connectRemotePort   int
 	cliOnly             bool
+	connectPackage      string
+	openConnection      bool
 
 	connectCmd = &cobra.Command{
 		Use:     "connect {REGISTRY|LOGGING|GIT|connect-name}",
@@ -23,7 +30,8 @@ var (
 			"Packages can provide service manifests that define their own shortcut connection options. These options will be " +
 			"printed to the terminal when the package finishes deploying.\n If you don't remember what connection shortcuts your deployed " +
 			"package offers, you can search your cluster for services that have the 'zarf.dev/connect-name' label. The value of that label is " +
-			"the name you will pass into the 'zarf connect' command. \n\n" +
+			"the name you will pass into the 'zarf connect' command. The shortcuts a package offered when it was deployed are also " +
+			"recorded with it, use '--package' to list and connect to them. \n\n" +
 			"Even if the packages you deploy don't define their own shortcut connection options, you can use the command flags " +
 			"to connect into specific resources. You can read the command flag descriptions below to get a better idea how to connect " +
 			"to whatever resource you are trying to connect to.",
@@ -38,6 +46,14 @@ var (
 			if !cliOnly {
 				tunnel.EnableAutoOpen()
 			}
+
+			// Only connect to the shortcuts the package recorded when it was deployed
+			if connectPackage != "" {
+				connections := getConnectStrings(connectPackage)
+				target = chooseConnection(connections, target)
+				tunnel.SetURLSuffix(connections[target].Url)
+			}
+
 			tunnel.Connect(target, true)
 		},
 	}
@@ -47,11 +63,74 @@ var (
 		Aliases: []string{"l"},
 		Short:   "List all available connection shortcuts.",
 		Run: func(cmd *cobra.Command, args []string) {
-			k8s.PrintConnectTable()
+			connections := getConnectStrings(connectPackage)
+			message.PrintConnectStringTable(connections)
+
+			if openConnection {
+				target := chooseConnection(connections, "")
+				tunnel := k8s.NewZarfTunnel()
+				tunnel.EnableAutoOpen()
+				tunnel.SetURLSuffix(connections[target].Url)
+				tunnel.Connect(target, true)
+			}
 		},
 	}
 )
 
+// getConnectStrings returns the connection shortcuts recorded for a deployed package, or all of them found in the cluster
+func getConnectStrings(packageName string) types.ConnectStrings {
+	if packageName == "" {
+		connections, err := k8s.GetConnectStrings()
+		if err != nil {
+			message.Fatalf(err, "Unable to find the connection shortcuts in the cluster")
+		}
+		return connections
+	}
+
+	deployedPackage, err := k8s.GetDeployedPackage(packageName)
+	if err != nil {
+		message.Fatalf(err, "Unable to find the deployed package %s", packageName)
+	}
+	return deployedPackage.ConnectStrings
+}
+
+// chooseConnection makes sure a requested connection is one of the shortcuts, prompting for one when none was requested
+func chooseConnection(connections types.ConnectStrings, target string) string {
+	if len(connections) == 0 {
+		message.Fatal(nil, "No connection shortcuts were found")
+	}
+
+	if target != "" {
+		if _, ok := connections[target]; !ok {
+			message.Fatalf(nil, "The connection shortcut %s was not found", target)
+		}
+		return target
+	}
+
+	var names []string
+	for name := range connections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 1 {
+		return names[0]
+	}
+
+	prompt := &survey.Select{
+		Message: "Choose a connection to open:",
+		Options: names,
+		Description: func(value string, index int) string {
+			return connections[value].Description
+		},
+	}
+	if err := survey.AskOne(prompt, &target); err != nil {
+		message.Fatalf(nil, "Connection selection canceled: %s", err.Error())
+	}
+
+	return target
+}
+
 func init() {
 	rootCmd.AddCommand(connectCmd)
 	connectCmd.AddCommand(connectListCmd)
@@ -62,4 +141,8 @@ func init() {
 	connectCmd.Flags().IntVar(&connectLocalPort, "local-port", 0, "(Optional, autogenerated if not provided) Specify the local port to bind to.  E.g. local-port=42000")
 	connectCmd.Flags().IntVar(&connectRemotePort, "remote-port", 0, "Specify the remote port of the resource to bind to.  E.g. remote-port=8080")
 	connectCmd.Flags().BoolVar(&cliOnly, "cli-only", false, "Disable browser auto-open")
+	connectCmd.Flags().StringVar(&connectPackage, "package", "", "Connect to one of the shortcuts recorded when this package was deployed, prompting for one if none is given")
+
+	connectListCmd.Flags().StringVar(&connectPackage, "package", "", "Only list the shortcuts recorded when this package was deployed")
+	connectListCmd.Flags().BoolVar(&openConnection, "open", false, "Choose one of the listed shortcuts to connect to and open in the browser")
 }
@@ -1,18 +1,44 @@
 package cmd
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/credentials"
+	"github.com/defenseunicorns/zarf/src/internal/git"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/packager"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var (
+	gitCAFile                 string
+	registryCAFile            string
+	registryS3CAFile          string
+	registryCredentialsSecret string
+	caFile                    string
+	registryTLSCertFile       string
+	registryTLSKeyFile        string
+	gitTLSCertFile            string
+	gitTLSKeyFile             string
 )
 
 // initCmd represents the init command
@@ -36,9 +62,17 @@ var initCmd = &cobra.Command{
 		"# Initializing without any optional components:\nzarf init\n\n" +
 		"# Initializing w/ Zarfs internal git server:\nzarf init --components=git-server\n\n" +
 		"# Initializing w/ Zarfs internal git server and PLG stack:\nzarf init --components=git-server,logging\n\n" +
-		"# Initializing w/ an internal registry but with a different nodeport:\nzarf init --nodeport=30333\n\n" +
+		"# Initializing w/ an internal registry but with a different nodeport:\nzarf init --registry-node-port=30333\n\n" +
+		"# Initializing w/ an internal registry the nodes pull from through an ingress:\nzarf init --registry-service-type=ClusterIP --registry-ingress-host={HOST}\n\n" +
 		"# Initializing w/ an external registry:\nzarf init --registry-push-password={PASSWORD} --registry-push-username={USERNAME} --registry-url={URL}\n\n" +
-		"# Initializing w/ an external git server:\nzarf init --git-push-password={PASSWORD} --git-push-username={USERNAME} --git-url={URL}\n\n",
+		"# Initializing w/ an external ECR registry using the AWS credentials of this machine:\nzarf init --registry-url={URL} --registry-credential-helper=ecr-login\n\n" +
+		"# Initializing w/ an external Harbor registry that keeps images in a project:\nzarf init --registry-push-password={PASSWORD} --registry-push-username={USERNAME} --registry-url={URL} --registry-project={PROJECT} --registry-push-path='{{.Project}}/{{.Namespace}}/{{.Name}}'\n\n" +
+		"# Initializing w/ the internal registry as a pull-through cache of an upstream mirror:\nzarf init --registry-proxy-url={URL} --registry-proxy-username={USERNAME} --registry-proxy-password={PASSWORD}\n\n" +
+		"# Initializing w/ the internal registry keeping its images in a MinIO bucket:\nzarf init --registry-s3-endpoint={URL} --registry-s3-bucket={BUCKET} --registry-s3-access-key={ACCESS_KEY} --registry-s3-secret-key={SECRET_KEY} --registry-s3-ca-file=./ca.pem\n\n" +
+		"# Initializing w/ the internal registry and git server ingress served with certificates from an enterprise CA:\nzarf init --registry-tls-cert=./registry.pem --registry-tls-key=./registry-key.pem --git-ingress-host={HOST} --git-tls-cert=./git.pem --git-tls-key=./git-key.pem --ca-file=./ca.pem\n\n" +
+		"# Initializing w/ a registry already running in the cluster:\nzarf init --registry-service={NAMESPACE}/{NAME} --registry-credentials-secret={NAMESPACE}/{NAME}\n\n" +
+		"# Initializing w/ an external git server:\nzarf init --git-push-password={PASSWORD} --git-push-username={USERNAME} --git-url={URL}\n\n" +
+		"# Initializing w/ an external registry and git server signed by an enterprise CA:\nzarf init --registry-push-password={PASSWORD} --registry-push-username={USERNAME} --registry-url={URL} --registry-ca-file=./ca.pem --git-push-password={PASSWORD} --git-push-username={USERNAME} --git-url={URL} --git-ca-file=./ca.pem\n\n",
 
 	Run: func(cmd *cobra.Command, args []string) {
 		zarfLogo := message.GetLogo()
@@ -49,30 +83,28 @@ var initCmd = &cobra.Command{
 			message.Fatal(err, "Invalid command flags were provided.")
 		}
 
+		// Pick the init package for the architecture of the cluster nodes when one wasn't given
+		if config.CliArch == "" {
+			if clusterArch, err := k8s.GetArchitecture(); err == nil {
+				message.Debugf("Using the init package for the %s cluster architecture", clusterArch)
+				config.CliArch = clusterArch
+			}
+		}
+
 		// Continue running package deploy for all components like any other package
 		initPackageName := config.GetInitPackageName()
-		config.DeployOptions.PackagePath = initPackageName
-
-		// Try to use an init-package in the executable directory if none exist in current working directory
-		if utils.InvalidPath(config.DeployOptions.PackagePath) {
-			// Get the path to the executable
-			if executablePath, err := utils.GetFinalExecutablePath(); err != nil {
-				message.Errorf(err, "Unable to get the path to the executable")
-			} else {
-				executableDir := path.Dir(executablePath)
-				config.DeployOptions.PackagePath = filepath.Join(executableDir, initPackageName)
-			}
+		config.DeployOptions.PackagePath = findInitPackage(initPackageName)
 
-			// If the init-package doesn't exist in the executable directory, try the cache directory
-			if err != nil || utils.InvalidPath(config.DeployOptions.PackagePath) {
-				config.DeployOptions.PackagePath = filepath.Join(config.GetAbsCachePath(), initPackageName)
+		// A multi-arch init package can initialize a cluster of either architecture
+		if config.DeployOptions.PackagePath == "" {
+			config.DeployOptions.PackagePath = findInitPackage(config.GetMultiArchInitPackageName())
+		}
 
-				// If the init-package doesn't exist in the cache directory, suggest downloading it
-				if utils.InvalidPath(config.DeployOptions.PackagePath) {
-					if err := downloadInitPackage(initPackageName); err != nil {
-						message.Fatal(err, "Failed to download the init package")
-					}
-				}
+		// If the init-package doesn't exist anywhere, suggest downloading it to the cache directory
+		if config.DeployOptions.PackagePath == "" {
+			config.DeployOptions.PackagePath = filepath.Join(config.GetAbsCachePath(), initPackageName)
+			if err := downloadInitPackage(initPackageName); err != nil {
+				message.Fatal(err, "Failed to download the init package")
 			}
 		}
 
@@ -81,6 +113,26 @@ var initCmd = &cobra.Command{
 	},
 }
 
+// findInitPackage looks for the init package in the current working directory, then the executable directory and then the cache directory
+func findInitPackage(initPackageName string) string {
+	if !utils.InvalidPath(initPackageName) {
+		return initPackageName
+	}
+
+	// Try to use an init-package in the executable directory if none exist in current working directory
+	if executablePath, err := utils.GetFinalExecutablePath(); err != nil {
+		message.Errorf(err, "Unable to get the path to the executable")
+	} else if packagePath := filepath.Join(path.Dir(executablePath), initPackageName); !utils.InvalidPath(packagePath) {
+		return packagePath
+	}
+
+	if packagePath := filepath.Join(config.GetAbsCachePath(), initPackageName); !utils.InvalidPath(packagePath) {
+		return packagePath
+	}
+
+	return ""
+}
+
 func downloadInitPackage(initPackageName string) error {
 	if config.CommonOptions.Confirm {
 		return fmt.Errorf("this command requires a zarf-init package, but one was not found on the local system")
@@ -115,6 +167,51 @@ func downloadInitPackage(initPackageName string) error {
 	return nil
 }
 
+// readCAFile reads the CA chain given to the flag and makes sure it has certificates in it
+func readCAFile(flag, path string) ([]byte, error) {
+	caBundle, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the '%s': %w", flag, err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(caBundle) {
+		return nil, fmt.Errorf("the '%s' does not contain any PEM encoded certificates", flag)
+	}
+	return caBundle, nil
+}
+
+// validateStateEncryption checks the key to encrypt the state with, a missing key file is generated and its path is made absolute for later runs
+func validateStateEncryption(encryption *types.StateEncryption, providerFlag, keyFlag string) error {
+	if encryption.Provider == "" {
+		return nil
+	}
+	if !k8s.IsValidStateEncryption(encryption.Provider) {
+		return fmt.Errorf("the '%s' flag must be one of key-file or vault-transit", providerFlag)
+	}
+	if encryption.Key == "" {
+		return fmt.Errorf("the '%s' flag must be provided if the '%s' flag is set", keyFlag, providerFlag)
+	}
+
+	if encryption.Provider == k8s.StateEncryptionVaultTransit {
+		if os.Getenv("VAULT_TOKEN") == "" {
+			return fmt.Errorf("the VAULT_TOKEN environment variable must be set if the '%s' flag is vault-transit", providerFlag)
+		}
+		return nil
+	}
+
+	keyPath, err := filepath.Abs(encryption.Key)
+	if err != nil {
+		return err
+	}
+	encryption.Key = keyPath
+	if utils.InvalidPath(keyPath) {
+		if err := k8s.GenerateStateKeyFile(keyPath); err != nil {
+			return fmt.Errorf("unable to create the key file %s: %w", keyPath, err)
+		}
+		message.Notef("Created the key file %s, keep a copy of it somewhere safe since the Zarf state can't be read without it", keyPath)
+	}
+	return nil
+}
+
 func validateInitFlags() error {
 	// If 'git-url' is provided, make sure they provided values for the username and password of the push user
 	if config.InitOptions.GitServer.Address != "" {
@@ -123,15 +220,460 @@ func validateInitFlags() error {
 		}
 	}
 
+	if config.InitOptions.GitServer.Provider != "" {
+		if !git.IsValidProvider(config.InitOptions.GitServer.Provider) {
+			return fmt.Errorf("the 'git-provider' flag must be one of gitea, gitlab or http")
+		}
+		// The Zarf git server is always Gitea
+		if config.InitOptions.GitServer.Address == "" {
+			return fmt.Errorf("the 'git-provider' flag can only be used with the 'git-url' flag")
+		}
+	}
+
 	//If 'registry-url' is provided, make sure they provided values for the username and password of the push user
 	if config.InitOptions.RegistryInfo.Address != "" {
-		if config.InitOptions.RegistryInfo.PushUsername == "" || config.InitOptions.RegistryInfo.PushPassword == "" {
+		if config.InitOptions.RegistryInfo.PushIdentity != "" {
+			if !credentials.IsValidIdentity(config.InitOptions.RegistryInfo.PushIdentity) {
+				return fmt.Errorf("the 'registry-push-identity' flag must be one of aws, azure or gcp")
+			}
+			// The tokens replace the push user entirely
+			config.InitOptions.RegistryInfo.PushUsername = ""
+			config.InitOptions.RegistryInfo.PushPassword = ""
+		} else if config.InitOptions.RegistryInfo.CredentialHelper != "" {
+			if _, err := credentials.NewHelper(config.InitOptions.RegistryInfo.CredentialHelper); err != nil {
+				return fmt.Errorf("the 'registry-credential-helper' flag is not a usable credential helper: %w", err)
+			}
+			// The Zarf agent refreshes the pull secrets with the workload identity the helper matches
+			if _, ok := credentials.GetHelperIdentity(config.InitOptions.RegistryInfo.CredentialHelper); !ok {
+				return fmt.Errorf("the 'registry-credential-helper' flag must be a workload identity (aws, azure, gcp) or a docker credential helper of one (ecr-login, acr-env, gcr, gcloud) so the Zarf agent can refresh the pull secrets")
+			}
+			// The tokens replace both the push and pull users
+			config.InitOptions.RegistryInfo.PushUsername = ""
+			config.InitOptions.RegistryInfo.PushPassword = ""
+			config.InitOptions.RegistryInfo.PullUsername = ""
+			config.InitOptions.RegistryInfo.PullPassword = ""
+		} else if config.InitOptions.RegistryInfo.PushUsername == "" || config.InitOptions.RegistryInfo.PushPassword == "" {
 			return fmt.Errorf("the 'registry-push-username' and 'registry-push-password' flags must be provided if the 'registry-url' flag is provided ")
 		}
+
+		// Image references can't carry a scheme, so only keep the host and port
+		registryURL := strings.TrimSuffix(config.InitOptions.RegistryInfo.Address, "/")
+		registryURL = strings.TrimPrefix(strings.TrimPrefix(registryURL, "https://"), "http://")
+		if strings.Contains(registryURL, ":") {
+			_, port, err := net.SplitHostPort(registryURL)
+			if err != nil {
+				return fmt.Errorf("the 'registry-url' flag is not a valid host:port address: %w", err)
+			}
+			if portNumber, err := strconv.Atoi(port); err != nil || portNumber < 1 || portNumber > 65535 {
+				return fmt.Errorf("the 'registry-url' flag has an invalid port %s", port)
+			}
+		}
+		config.InitOptions.RegistryInfo.Address = registryURL
+	}
+
+	// If 'registry-service' is provided, the registry is already running in the cluster and brings its own users
+	if config.InitOptions.RegistryInfo.InClusterService != "" {
+		if config.InitOptions.RegistryInfo.Address != "" {
+			return fmt.Errorf("the 'registry-service' and 'registry-url' flags can not be used together")
+		}
+		namespace, name, found := strings.Cut(config.InitOptions.RegistryInfo.InClusterService, "/")
+		if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("the 'registry-service' flag must be in the format NAMESPACE/NAME")
+		}
+
+		if registryCredentialsSecret != "" {
+			namespace, name, found := strings.Cut(registryCredentialsSecret, "/")
+			if !found || namespace == "" || name == "" {
+				return fmt.Errorf("the 'registry-credentials-secret' flag must be in the format NAMESPACE/NAME")
+			}
+			username, password, err := k8s.GetRegistryCredentials(namespace, name)
+			if err != nil {
+				return fmt.Errorf("unable to read the 'registry-credentials-secret': %w", err)
+			}
+			config.InitOptions.RegistryInfo.PushUsername = username
+			config.InitOptions.RegistryInfo.PushPassword = password
+		} else if config.InitOptions.RegistryInfo.PushPassword == "" {
+			return fmt.Errorf("the 'registry-credentials-secret' flag or the 'registry-push-username' and 'registry-push-password' flags must be provided if the 'registry-service' flag is provided")
+		}
+	} else if registryCredentialsSecret != "" {
+		return fmt.Errorf("the 'registry-credentials-secret' flag can only be used with the 'registry-service' flag")
+	}
+
+	if config.InitOptions.RegistryInfo.PushIdentity != "" && config.InitOptions.RegistryInfo.Address == "" {
+		return fmt.Errorf("the 'registry-push-identity' flag can only be used with the 'registry-url' flag")
+	}
+
+	if config.InitOptions.RegistryInfo.CredentialHelper != "" {
+		if config.InitOptions.RegistryInfo.Address == "" {
+			return fmt.Errorf("the 'registry-credential-helper' flag can only be used with the 'registry-url' flag")
+		}
+		if config.InitOptions.RegistryInfo.PushIdentity != "" {
+			return fmt.Errorf("the 'registry-credential-helper' and 'registry-push-identity' flags can not be used together")
+		}
+	}
+
+	// The Zarf registry is always flat, only registries Zarf doesn't deploy can need a push path
+	if config.InitOptions.RegistryInfo.PushPath != "" {
+		if config.InitOptions.RegistryInfo.Address == "" && config.InitOptions.RegistryInfo.InClusterService == "" {
+			return fmt.Errorf("the 'registry-push-path' flag can only be used with the 'registry-url' or 'registry-service' flags")
+		}
+		sample := utils.ImagePushPath{Project: config.InitOptions.RegistryInfo.Project, Host: "docker.io", Namespace: "library", Name: "nginx", Path: "library/nginx"}
+		if _, err := utils.RenderImagePushPath(config.InitOptions.RegistryInfo.PushPath, sample); err != nil {
+			return fmt.Errorf("the 'registry-push-path' flag is not a valid template: %w", err)
+		}
+	} else if config.InitOptions.RegistryInfo.Project != "" {
+		return fmt.Errorf("the 'registry-project' flag can only be used with the 'registry-push-path' flag")
+	}
+
+	// Only the registry Zarf deploys can be made a pull-through cache
+	if config.InitOptions.RegistryInfo.ProxyURL != "" {
+		if config.InitOptions.RegistryInfo.Address != "" || config.InitOptions.RegistryInfo.InClusterService != "" {
+			return fmt.Errorf("the 'registry-proxy-url' flag can not be used with the 'registry-url' or 'registry-service' flags")
+		}
+		if !strings.HasPrefix(config.InitOptions.RegistryInfo.ProxyURL, "https://") && !strings.HasPrefix(config.InitOptions.RegistryInfo.ProxyURL, "http://") {
+			return fmt.Errorf("the 'registry-proxy-url' flag must start with https:// or http://")
+		}
+		config.InitOptions.RegistryInfo.ProxyURL = strings.TrimSuffix(config.InitOptions.RegistryInfo.ProxyURL, "/")
+	} else if config.InitOptions.RegistryInfo.ProxyUsername != "" || config.InitOptions.RegistryInfo.ProxyPassword != "" {
+		return fmt.Errorf("the 'registry-proxy-username' and 'registry-proxy-password' flags can only be used with the 'registry-proxy-url' flag")
+	}
+
+	manifestFormat := config.InitOptions.RegistryInfo.ManifestFormat
+	if manifestFormat != "" && manifestFormat != images.ManifestFormatDocker && manifestFormat != images.ManifestFormatOCI {
+		return fmt.Errorf("the 'registry-manifest-format' flag must be either docker or oci")
+	}
+
+	if err := validateRegistryS3Flags(); err != nil {
+		return err
+	}
+	if err := validateRegistryDeploymentFlags(); err != nil {
+		return err
+	}
+
+	if registryCAFile != "" || config.InitOptions.RegistryInfo.InsecureSkipVerify {
+		if config.InitOptions.RegistryInfo.Address == "" {
+			return fmt.Errorf("the 'registry-ca-file' and 'registry-insecure-skip-verify' flags can only be used with the 'registry-url' flag")
+		}
+	}
+	if registryCAFile != "" {
+		caBundle, err := readCAFile("registry-ca-file", registryCAFile)
+		if err != nil {
+			return err
+		}
+		config.InitOptions.RegistryInfo.CABundle = caBundle
+	}
+
+	if err := validateServiceFlags(); err != nil {
+		return err
+	}
+	if err := validateCustomTLSFlags(); err != nil {
+		return err
+	}
+
+	if gitCAFile != "" || config.InitOptions.GitServer.InsecureSkipVerify {
+		if config.InitOptions.GitServer.Address == "" {
+			return fmt.Errorf("the 'git-ca-file' and 'git-insecure-skip-verify' flags can only be used with the 'git-url' flag")
+		}
+	}
+	if gitCAFile != "" {
+		caBundle, err := readCAFile("git-ca-file", gitCAFile)
+		if err != nil {
+			return err
+		}
+		config.InitOptions.GitServer.CABundle = caBundle
+	}
+
+	stateStore := config.InitOptions.StateStore
+	if stateStore.Type != "" && !k8s.IsValidStateStore(stateStore.Type) {
+		return fmt.Errorf("the 'state-store' flag must be one of kubernetes, vault or external-secret")
+	}
+	if stateStore.Type == k8s.StateStoreVault {
+		if stateStore.URL == "" {
+			return fmt.Errorf("the 'state-store-url' flag must be provided if the 'state-store' flag is vault")
+		}
+		if os.Getenv("VAULT_TOKEN") == "" {
+			return fmt.Errorf("the VAULT_TOKEN environment variable must be set if the 'state-store' flag is vault")
+		}
+	}
+	if stateStore.Type == k8s.StateStoreExternalSecret {
+		namespace, name, found := strings.Cut(stateStore.Secret, "/")
+		if !found || namespace == "" || name == "" {
+			return fmt.Errorf("the 'state-store-secret' flag must be in the format NAMESPACE/NAME if the 'state-store' flag is external-secret")
+		}
+	}
+
+	if err := validateStateEncryption(&config.InitOptions.StateEncryption, "state-encryption", "state-encryption-key"); err != nil {
+		return err
+	}
+
+	agentWebhook := config.InitOptions.AgentWebhook
+	if agentWebhook.FailurePolicy != "Fail" && agentWebhook.FailurePolicy != "Ignore" {
+		return fmt.Errorf("the 'agent-failure-policy' flag must be either 'Fail' or 'Ignore'")
+	}
+
+	if agentWebhook.TimeoutSeconds < 1 || agentWebhook.TimeoutSeconds > 30 {
+		return fmt.Errorf("the 'agent-timeout' flag must be between 1 and 30 seconds")
+	}
+
+	if agentWebhook.NamespaceSelector != "" {
+		if _, err := labels.Parse(agentWebhook.NamespaceSelector); err != nil {
+			return fmt.Errorf("the 'agent-namespace-selector' flag is not a valid label selector: %w", err)
+		}
+	}
+
+	if agentWebhook.Replicas < 1 {
+		return fmt.Errorf("the 'agent-replicas' flag must be at least 1")
+	}
+	if agentWebhook.Replicas == 1 {
+		message.Warn("With a single Zarf agent replica, node drains are blocked by its PodDisruptionBudget until the agent is deleted or scaled up")
+	}
+
+	if agentWebhook.Mode != k8s.AgentModeAll && agentWebhook.Mode != k8s.AgentModeOptIn {
+		return fmt.Errorf("the 'agent-mode' flag must be either '%s' or '%s'", k8s.AgentModeAll, k8s.AgentModeOptIn)
+	}
+
+	for _, namespace := range agentWebhook.IgnoredNamespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return fmt.Errorf("the 'agent-ignored-namespaces' flag has an invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// validateRegistryDeploymentFlags checks the flags that size the Zarf registry, which only apply when Zarf deploys the registry
+func validateRegistryDeploymentFlags() error {
+	deployment := config.InitOptions.RegistryInfo.Deployment
+	if deployment == (types.RegistryDeployment{}) {
+		return nil
+	}
+	if config.InitOptions.RegistryInfo.Address != "" || config.InitOptions.RegistryInfo.InClusterService != "" {
+		return fmt.Errorf("the 'registry-pvc-*', 'registry-storage-class', 'registry-*-replicas' and 'registry-*-request/limit' flags can not be used with the 'registry-url' or 'registry-service' flags")
+	}
+
+	for flag, value := range map[string]string{
+		"registry-pvc-size":       deployment.PVCSize,
+		"registry-cpu-request":    deployment.CPURequest,
+		"registry-memory-request": deployment.MemoryRequest,
+		"registry-cpu-limit":      deployment.CPULimit,
+		"registry-memory-limit":   deployment.MemoryLimit,
+	} {
+		if value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("the '%s' flag is not a valid quantity: %w", flag, err)
+		}
+	}
+
+	if deployment.PVCAccessMode != "" && deployment.PVCAccessMode != string(corev1.ReadWriteOnce) && deployment.PVCAccessMode != string(corev1.ReadWriteMany) {
+		return fmt.Errorf("the 'registry-pvc-access-mode' flag must be either ReadWriteOnce or ReadWriteMany")
+	}
+	if deployment.Replicas < 0 {
+		return fmt.Errorf("the 'registry-replicas' flag must be at least 1")
+	}
+	if deployment.MaxReplicas != 0 && deployment.MaxReplicas < deployment.Replicas {
+		return fmt.Errorf("the 'registry-max-replicas' flag must be at least the 'registry-replicas' flag")
+	}
+
+	// Replicas keeping their images in an object store share the bucket instead of a volume
+	if config.InitOptions.RegistryInfo.S3.Bucket != "" {
+		if deployment.PVCSize != "" || deployment.PVCAccessMode != "" || deployment.StorageClass != "" {
+			return fmt.Errorf("the 'registry-pvc-*' and 'registry-storage-class' flags can not be used with the 'registry-s3-bucket' flag")
+		}
+		return nil
+	}
+
+	// Every replica mounts the same volume to serve the same images
+	if (deployment.Replicas > 1 || deployment.MaxReplicas > 1) && deployment.PVCAccessMode != string(corev1.ReadWriteMany) {
+		return fmt.Errorf("more than one registry replica needs the 'registry-pvc-access-mode' flag to be ReadWriteMany")
+	}
+	return nil
+}
+
+// validateServiceFlags checks the flags that expose the Zarf registry and git server, which only apply when Zarf deploys them
+func validateServiceFlags() error {
+	registryInfo := config.InitOptions.RegistryInfo
+	if registryInfo.ServiceType != "" || registryInfo.IngressHost != "" {
+		if registryInfo.Address != "" || registryInfo.InClusterService != "" {
+			return fmt.Errorf("the 'registry-service-type' and 'registry-ingress-host' flags can not be used with the 'registry-url' or 'registry-service' flags")
+		}
+	}
+	switch corev1.ServiceType(registryInfo.ServiceType) {
+	case "", corev1.ServiceTypeNodePort:
+		if registryInfo.IngressHost != "" {
+			return fmt.Errorf("the 'registry-ingress-host' flag can only be used when the 'registry-service-type' flag is ClusterIP")
+		}
+	case corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeClusterIP:
+		// The nodes can only reach a ClusterIP registry through an ingress
+		if registryInfo.ServiceType == string(corev1.ServiceTypeClusterIP) && registryInfo.IngressHost == "" {
+			return fmt.Errorf("the 'registry-ingress-host' flag must be provided if the 'registry-service-type' flag is ClusterIP")
+		}
+		if registryInfo.ServiceType == string(corev1.ServiceTypeLoadBalancer) && registryInfo.IngressHost != "" {
+			return fmt.Errorf("the 'registry-ingress-host' flag can only be used when the 'registry-service-type' flag is ClusterIP")
+		}
+		if registryInfo.NodePort != 0 {
+			return fmt.Errorf("the 'registry-node-port' flag can only be used when the 'registry-service-type' flag is NodePort")
+		}
+	default:
+		return fmt.Errorf("the 'registry-service-type' flag must be one of NodePort, LoadBalancer or ClusterIP")
+	}
+	if registryInfo.NodePort != 0 && (registryInfo.NodePort < 30000 || registryInfo.NodePort > 32767) {
+		return fmt.Errorf("the 'registry-node-port' flag must be between 30000 and 32767")
+	}
+
+	gitServer := config.InitOptions.GitServer
+	if gitServer.ServiceType != "" || gitServer.NodePort != 0 || gitServer.IngressHost != "" {
+		if gitServer.Address != "" {
+			return fmt.Errorf("the 'git-service-type', 'git-node-port' and 'git-ingress-host' flags can not be used with the 'git-url' flag")
+		}
+	}
+	switch corev1.ServiceType(gitServer.ServiceType) {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeLoadBalancer:
+		if gitServer.NodePort != 0 {
+			return fmt.Errorf("the 'git-node-port' flag can only be used when the 'git-service-type' flag is NodePort")
+		}
+	case corev1.ServiceTypeNodePort:
+		if gitServer.NodePort != 0 && (gitServer.NodePort < 30000 || gitServer.NodePort > 32767) {
+			return fmt.Errorf("the 'git-node-port' flag must be between 30000 and 32767")
+		}
+		registryNodePort := registryInfo.NodePort
+		if registryNodePort == 0 {
+			registryNodePort = config.ZarfInClusterContainerRegistryNodePort
+		}
+		if gitServer.NodePort == registryNodePort {
+			return fmt.Errorf("the 'git-node-port' flag can not be the nodeport of the registry (%d)", registryNodePort)
+		}
+	default:
+		return fmt.Errorf("the 'git-service-type' flag must be one of ClusterIP, NodePort or LoadBalancer")
+	}
+	return nil
+}
+
+// validateRegistryS3Flags checks the flags that keep the images of the Zarf registry in an object store
+func validateRegistryS3Flags() error {
+	s3 := config.InitOptions.RegistryInfo.S3
+	if s3.Bucket == "" {
+		if s3.Endpoint != "" || s3.Region != "" || s3.AccessKey != "" || s3.SecretKey != "" || registryS3CAFile != "" {
+			return fmt.Errorf("the 'registry-s3-*' flags can only be used with the 'registry-s3-bucket' flag")
+		}
+		return nil
+	}
+	if config.InitOptions.RegistryInfo.Address != "" || config.InitOptions.RegistryInfo.InClusterService != "" {
+		return fmt.Errorf("the 'registry-s3-bucket' flag can not be used with the 'registry-url' or 'registry-service' flags")
+	}
+
+	if s3.Endpoint != "" && !strings.HasPrefix(s3.Endpoint, "https://") && !strings.HasPrefix(s3.Endpoint, "http://") {
+		return fmt.Errorf("the 'registry-s3-endpoint' flag must start with https:// or http://")
+	}
+	if (s3.AccessKey == "") != (s3.SecretKey == "") {
+		return fmt.Errorf("the 'registry-s3-access-key' and 'registry-s3-secret-key' flags must be used together")
+	}
+
+	if registryS3CAFile != "" {
+		if !strings.HasPrefix(s3.Endpoint, "https://") {
+			return fmt.Errorf("the 'registry-s3-ca-file' flag can only be used with an https:// 'registry-s3-endpoint'")
+		}
+		caBundle, err := readCAFile("registry-s3-ca-file", registryS3CAFile)
+		if err != nil {
+			return err
+		}
+		config.InitOptions.RegistryInfo.S3.CABundle = caBundle
+	}
+	return nil
+}
+
+// validateCustomTLSFlags loads the certificates the Zarf registry and git server ingress are served with and checks they verify against the 'ca-file'
+func validateCustomTLSFlags() error {
+	if (registryTLSCertFile == "") != (registryTLSKeyFile == "") {
+		return fmt.Errorf("the 'registry-tls-cert' and 'registry-tls-key' flags must be used together")
+	}
+	if (gitTLSCertFile == "") != (gitTLSKeyFile == "") {
+		return fmt.Errorf("the 'git-tls-cert' and 'git-tls-key' flags must be used together")
+	}
+	if registryTLSCertFile == "" && gitTLSCertFile == "" {
+		if caFile != "" {
+			return fmt.Errorf("the 'ca-file' flag can only be used with the 'registry-tls-cert' or 'git-tls-cert' flags")
+		}
+		return nil
+	}
+	if caFile == "" {
+		return fmt.Errorf("the 'ca-file' flag must be provided if the 'registry-tls-cert' or 'git-tls-cert' flags are provided")
+	}
+	caBundle, err := readCAFile("ca-file", caFile)
+	if err != nil {
+		return err
+	}
+
+	registryInfo := config.InitOptions.RegistryInfo
+	if registryTLSCertFile != "" {
+		if registryInfo.Address != "" || registryInfo.InClusterService != "" {
+			return fmt.Errorf("the 'registry-tls-cert' flag can not be used with the 'registry-url' or 'registry-service' flags")
+		}
+		// The ingress serves the nodes, otherwise the nodes and Zarf both reach the registry on localhost
+		host := config.IPV4Localhost
+		if registryInfo.ServiceType == string(corev1.ServiceTypeClusterIP) {
+			host = registryInfo.IngressHost
+		}
+		pki, err := readTLSFiles("registry-tls-cert", registryTLSCertFile, registryTLSKeyFile, caBundle, host)
+		if err != nil {
+			return err
+		}
+		config.InitOptions.RegistryTLS = pki
+	}
+
+	if gitTLSCertFile != "" {
+		if config.InitOptions.GitServer.Address != "" {
+			return fmt.Errorf("the 'git-tls-cert' flag can not be used with the 'git-url' flag")
+		}
+		// Zarf reaches the git server on its in-cluster address, so only the ingress is served with the certificate
+		if config.InitOptions.GitServer.IngressHost == "" {
+			return fmt.Errorf("the 'git-ingress-host' flag must be provided if the 'git-tls-cert' flag is provided")
+		}
+		pki, err := readTLSFiles("git-tls-cert", gitTLSCertFile, gitTLSKeyFile, caBundle, config.InitOptions.GitServer.IngressHost)
+		if err != nil {
+			return err
+		}
+		config.InitOptions.GitTLS = pki
 	}
 	return nil
 }
 
+// readTLSFiles reads a certificate and its key and makes sure the certificate is valid for the host under the CA
+func readTLSFiles(flag, certPath, keyPath string, caBundle []byte, host string) (types.GeneratedPKI, error) {
+	cert, err := os.ReadFile(certPath)
+	if err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("unable to read the '%s': %w", flag, err)
+	}
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("unable to read the key of the '%s': %w", flag, err)
+	}
+
+	keyPair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("the '%s' and its key are not a valid key pair: %w", flag, err)
+	}
+	leaf, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("unable to parse the '%s': %w", flag, err)
+	}
+
+	// Any intermediates come after the certificate in the same file
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caBundle)
+	intermediates := x509.NewCertPool()
+	for _, der := range keyPair.Certificate[1:] {
+		if intermediate, err := x509.ParseCertificate(der); err == nil {
+			intermediates.AddCert(intermediate)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots, Intermediates: intermediates}); err != nil {
+		return types.GeneratedPKI{}, fmt.Errorf("the '%s' is not valid for %s under the 'ca-file': %w", flag, host, err)
+	}
+
+	return types.GeneratedPKI{CA: caBundle, Cert: cert, Key: key}, nil
+}
+
 func init() {
 	initViper()
 
@@ -139,25 +681,86 @@ func init() {
 
 	v.SetDefault(V_INIT_COMPONENTS, "")
 	v.SetDefault(V_INIT_STORAGE_CLASS, "")
+	v.SetDefault(V_INIT_STORAGE_CLASS_CHECK, false)
+	v.SetDefault(V_INIT_POD_SECURITY_EXEMPTION, false)
+	v.SetDefault(V_INIT_RESULT_FILE, "")
+	v.SetDefault(V_INIT_CA_FILE, "")
 
 	v.SetDefault(V_INIT_GIT_URL, "")
 	v.SetDefault(V_INIT_GIT_PUSH_USER, config.ZarfGitPushUser)
 	v.SetDefault(V_INIT_GIT_PUSH_PASS, "")
 	v.SetDefault(V_INIT_GIT_PULL_USER, "")
 	v.SetDefault(V_INIT_GIT_PULL_PASS, "")
+	v.SetDefault(V_INIT_GIT_PROVIDER, "")
+	v.SetDefault(V_INIT_GIT_CA_FILE, "")
+	v.SetDefault(V_INIT_GIT_INSECURE, false)
+	v.SetDefault(V_INIT_GIT_SVC_TYPE, "")
+	v.SetDefault(V_INIT_GIT_NODEPORT, 0)
+	v.SetDefault(V_INIT_GIT_INGRESS, "")
+	v.SetDefault(V_INIT_GIT_TLS_CERT, "")
+	v.SetDefault(V_INIT_GIT_TLS_KEY, "")
 
 	v.SetDefault(V_INIT_REGISTRY_URL, "")
 	v.SetDefault(V_INIT_REGISTRY_NODEPORT, 0)
+	v.SetDefault(V_INIT_REGISTRY_SVC_TYPE, "")
+	v.SetDefault(V_INIT_REGISTRY_INGRESS, "")
 	v.SetDefault(V_INIT_REGISTRY_SECRET, "")
 	v.SetDefault(V_INIT_REGISTRY_PUSH_USER, config.ZarfRegistryPushUser)
 	v.SetDefault(V_INIT_REGISTRY_PUSH_PASS, "")
+	v.SetDefault(V_INIT_REGISTRY_PUSH_ID, "")
+	v.SetDefault(V_INIT_REGISTRY_CRED_HELPER, "")
 	v.SetDefault(V_INIT_REGISTRY_PULL_USER, "")
 	v.SetDefault(V_INIT_REGISTRY_PULL_PASS, "")
+	v.SetDefault(V_INIT_REGISTRY_CA_FILE, "")
+	v.SetDefault(V_INIT_REGISTRY_INSECURE, false)
+	v.SetDefault(V_INIT_REGISTRY_SERVICE, "")
+	v.SetDefault(V_INIT_REGISTRY_CREDENTIALS, "")
+	v.SetDefault(V_INIT_REGISTRY_PUSH_PATH, "")
+	v.SetDefault(V_INIT_REGISTRY_PROJECT, "")
+	v.SetDefault(V_INIT_REGISTRY_MANIFEST, "")
+	v.SetDefault(V_INIT_REGISTRY_FOREIGN, false)
+	v.SetDefault(V_INIT_REGISTRY_PROXY_URL, "")
+	v.SetDefault(V_INIT_REGISTRY_PROXY_USER, "")
+	v.SetDefault(V_INIT_REGISTRY_PROXY_PASS, "")
+	v.SetDefault(V_INIT_REGISTRY_PVC_SIZE, "")
+	v.SetDefault(V_INIT_REGISTRY_PVC_ACCESS, "")
+	v.SetDefault(V_INIT_REGISTRY_STORAGE, "")
+	v.SetDefault(V_INIT_REGISTRY_REPLICAS, 0)
+	v.SetDefault(V_INIT_REGISTRY_MAX_REPLICA, 0)
+	v.SetDefault(V_INIT_REGISTRY_CPU_REQ, "")
+	v.SetDefault(V_INIT_REGISTRY_MEM_REQ, "")
+	v.SetDefault(V_INIT_REGISTRY_CPU_LIMIT, "")
+	v.SetDefault(V_INIT_REGISTRY_MEM_LIMIT, "")
+	v.SetDefault(V_INIT_REGISTRY_S3_ENDPOINT, "")
+	v.SetDefault(V_INIT_REGISTRY_S3_REGION, "")
+	v.SetDefault(V_INIT_REGISTRY_S3_BUCKET, "")
+	v.SetDefault(V_INIT_REGISTRY_S3_ACCESS, "")
+	v.SetDefault(V_INIT_REGISTRY_S3_SECRET, "")
+	v.SetDefault(V_INIT_REGISTRY_S3_CA_FILE, "")
+	v.SetDefault(V_INIT_REGISTRY_TLS_CERT, "")
+	v.SetDefault(V_INIT_REGISTRY_TLS_KEY, "")
+
+	v.SetDefault(V_INIT_AGENT_FAILURE_POLICY, "Fail")
+	v.SetDefault(V_INIT_AGENT_TIMEOUT, 10)
+	v.SetDefault(V_INIT_AGENT_NAMESPACE_SELECTOR, "")
+	v.SetDefault(V_INIT_AGENT_IGNORED_NAMESPACES, []string{})
+	v.SetDefault(V_INIT_AGENT_MODE, k8s.AgentModeAll)
+	v.SetDefault(V_INIT_AGENT_REPLICAS, config.ZarfAgentReplicas)
+
+	v.SetDefault(V_INIT_STATE_STORE, "")
+	v.SetDefault(V_INIT_STATE_STORE_URL, "")
+	v.SetDefault(V_INIT_STATE_STORE_SECRET, "")
+
+	v.SetDefault(V_INIT_STATE_ENCRYPTION, "")
+	v.SetDefault(V_INIT_STATE_ENCRYPTION_KEY, "")
 
 	// Continue to require --confirm flag for init command to avoid accidental deployments
 	initCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, "Confirm the install without prompting")
-	initCmd.Flags().StringVar(&config.InitOptions.Components, "components", v.GetString(V_INIT_COMPONENTS), "Comma-separated list of components to install.")
+	initCmd.Flags().StringVar(&config.InitOptions.Components, "components", v.GetString(V_INIT_COMPONENTS), "Comma-separated list of components to install, or '*' for all of them.")
 	initCmd.Flags().StringVar(&config.InitOptions.StorageClass, "storage-class", v.GetString(V_INIT_STORAGE_CLASS), "Describe the StorageClass to be used")
+	initCmd.Flags().BoolVar(&config.InitOptions.PodSecurityExemption, "pod-security-exemption", v.GetBool(V_INIT_POD_SECURITY_EXEMPTION), "Enforce the Pod Security Standard the selected init components need on the zarf namespace when the cluster enforces a stricter one")
+	initCmd.Flags().BoolVar(&config.InitOptions.StorageClassCheck, "storage-class-check", v.GetBool(V_INIT_STORAGE_CLASS_CHECK), "Create a test claim on the StorageClass and wait for it to bind before deploying the stateful init components")
+	initCmd.Flags().StringVar(&config.CommonOptions.ResultFile, "result-file", v.GetString(V_INIT_RESULT_FILE), "Write a JSON summary of the init (status, durations, errors, connect strings and where the generated credentials are kept) to this file when it finishes or fails")
 
 	// Flags for using an external Git server
 	initCmd.Flags().StringVar(&config.InitOptions.GitServer.Address, "git-url", v.GetString(V_INIT_GIT_URL), "External git server url to use for this Zarf cluster")
@@ -165,15 +768,81 @@ func init() {
 	initCmd.Flags().StringVar(&config.InitOptions.GitServer.PushPassword, "git-push-password", v.GetString(V_INIT_GIT_PUSH_PASS), "Password for the push-user to access the git server")
 	initCmd.Flags().StringVar(&config.InitOptions.GitServer.PullUsername, "git-pull-username", v.GetString(V_INIT_GIT_PULL_USER), "Username for pull-only access to the git server")
 	initCmd.Flags().StringVar(&config.InitOptions.GitServer.PullPassword, "git-pull-password", v.GetString(V_INIT_GIT_PULL_PASS), "Password for the pull-only user to access the git server")
+	initCmd.Flags().StringVar(&config.InitOptions.GitServer.Provider, "git-provider", v.GetString(V_INIT_GIT_PROVIDER), "API of the external git server used to create repos and give the pull-only user access to them. Valid options are: gitea, gitlab, http (push only)")
+	initCmd.Flags().StringVar(&gitCAFile, "git-ca-file", v.GetString(V_INIT_GIT_CA_FILE), "Path to a PEM encoded CA chain to trust for the external git server. The cluster nodes must also trust this CA")
+	initCmd.Flags().BoolVar(&config.InitOptions.GitServer.InsecureSkipVerify, "git-insecure-skip-verify", v.GetBool(V_INIT_GIT_INSECURE), "Skip verifying the TLS certificate of the external git server. Prefer 'git-ca-file' where possible")
+	initCmd.Flags().StringVar(&config.InitOptions.GitServer.ServiceType, "git-service-type", v.GetString(V_INIT_GIT_SVC_TYPE), "Type of the service of the Zarf git server. Valid options are: ClusterIP, NodePort, LoadBalancer (default ClusterIP)")
+	initCmd.Flags().IntVar(&config.InitOptions.GitServer.NodePort, "git-node-port", v.GetInt(V_INIT_GIT_NODEPORT), "Nodeport of the Zarf git server when the 'git-service-type' is NodePort. Between [30000-32767] (default assigned by the cluster)")
+	initCmd.Flags().StringVar(&config.InitOptions.GitServer.IngressHost, "git-ingress-host", v.GetString(V_INIT_GIT_INGRESS), "Host of an ingress to the Zarf git server")
 
 	// Flags for using an external registry
 	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Address, "registry-url", v.GetString(V_INIT_REGISTRY_URL), "External registry url address to use for this Zarf cluster")
+	initCmd.Flags().IntVar(&config.InitOptions.RegistryInfo.NodePort, "registry-node-port", v.GetInt(V_INIT_REGISTRY_NODEPORT), fmt.Sprintf("Nodeport to access a registry internal to the k8s cluster. Between [30000-32767] (default %d)", config.ZarfInClusterContainerRegistryNodePort))
 	initCmd.Flags().IntVar(&config.InitOptions.RegistryInfo.NodePort, "nodeport", v.GetInt(V_INIT_REGISTRY_NODEPORT), "Nodeport to access a registry internal to the k8s cluster. Between [30000-32767]")
+	_ = initCmd.Flags().MarkDeprecated("nodeport", "use --registry-node-port instead")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.ServiceType, "registry-service-type", v.GetString(V_INIT_REGISTRY_SVC_TYPE), "Type of the service of the Zarf registry, which sets the address the nodes pull from. Valid options are: NodePort (localhost on each node), LoadBalancer (the load balancer address), ClusterIP (the 'registry-ingress-host') (default NodePort)")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.IngressHost, "registry-ingress-host", v.GetString(V_INIT_REGISTRY_INGRESS), "Host of an ingress to the Zarf registry the nodes pull from when the 'registry-service-type' is ClusterIP. The ingress controller must serve it with a certificate the nodes trust")
 	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.PushUsername, "registry-push-username", v.GetString(V_INIT_REGISTRY_PUSH_USER), "Username to access to the registry Zarf is configured to use")
 	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.PushPassword, "registry-push-password", v.GetString(V_INIT_REGISTRY_PUSH_PASS), "Password for the push-user to connect to the registry")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.PushIdentity, "registry-push-identity", v.GetString(V_INIT_REGISTRY_PUSH_ID), "Get short-lived push tokens from the workload identity of the pod Zarf runs in instead of using a push user. Valid options are: aws, azure, gcp")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.CredentialHelper, "registry-credential-helper", v.GetString(V_INIT_REGISTRY_CRED_HELPER), "Get short-lived push and pull tokens from a workload identity (aws, azure, gcp) or a docker credential helper on the PATH (e.g. ecr-login) instead of using push and pull users. The Zarf agent keeps the pull secrets refreshed with the matching workload identity")
 	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.PullUsername, "registry-pull-username", v.GetString(V_INIT_REGISTRY_PULL_USER), "Username for pull-only access to the registry")
 	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.PullPassword, "registry-pull-password", v.GetString(V_INIT_REGISTRY_PULL_PASS), "Password for the pull-only user to access the registry")
 	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Secret, "registry-secret", v.GetString(V_INIT_REGISTRY_SECRET), "Registry secret value")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.InClusterService, "registry-service", v.GetString(V_INIT_REGISTRY_SERVICE), "Use a registry already running in the cluster, given as the NAMESPACE/NAME of its NodePort service, instead of deploying the Zarf registry")
+	initCmd.Flags().StringVar(&registryCredentialsSecret, "registry-credentials-secret", v.GetString(V_INIT_REGISTRY_CREDENTIALS), "NAMESPACE/NAME of a basic-auth or dockerconfigjson secret holding the push credentials for the 'registry-service' registry")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.PushPath, "registry-push-path", v.GetString(V_INIT_REGISTRY_PUSH_PATH), "Go template of the path images are pushed to in the external registry instead of a flattened name with a checksum (e.g. '{{.Project}}/{{.Namespace}}/{{.Name}}'). Can use .Project, .Host, .Namespace, .Name and .Path")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Project, "registry-project", v.GetString(V_INIT_REGISTRY_PROJECT), "Project the external registry requires images to be pushed under (e.g. a Harbor project), used by the 'registry-push-path' as {{.Project}}")
+	initCmd.Flags().StringVar(&registryCAFile, "registry-ca-file", v.GetString(V_INIT_REGISTRY_CA_FILE), "Path to a PEM encoded CA chain to trust for the external registry. The cluster nodes must also trust this CA")
+	initCmd.Flags().BoolVar(&config.InitOptions.RegistryInfo.InsecureSkipVerify, "registry-insecure-skip-verify", v.GetBool(V_INIT_REGISTRY_INSECURE), "Skip verifying the TLS certificate of the external registry. Prefer 'registry-ca-file' where possible")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.ManifestFormat, "registry-manifest-format", v.GetString(V_INIT_REGISTRY_MANIFEST), "Convert images to this manifest format before pushing them, for registries that only accept one. This changes their digests, so pinned images can't be converted and their signatures aren't pushed. Valid options are: docker, oci (default the format they were pulled in, retrying in the other one if the registry rejects it)")
+	initCmd.Flags().BoolVar(&config.InitOptions.RegistryInfo.PushForeignLayers, "registry-push-foreign-layers", v.GetBool(V_INIT_REGISTRY_FOREIGN), "Push the foreign (non-distributable) layers of images, such as Windows base layers, as regular layers for registries that reject them or clusters that can't reach where they are hosted")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.ProxyURL, "registry-proxy-url", v.GetString(V_INIT_REGISTRY_PROXY_URL), "Run the Zarf registry as a pull-through cache of this upstream registry (e.g. a mirror fed over a one-way link) instead of pushing images to it")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.ProxyUsername, "registry-proxy-username", v.GetString(V_INIT_REGISTRY_PROXY_USER), "Username the Zarf registry pulls from the 'registry-proxy-url' registry with")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.ProxyPassword, "registry-proxy-password", v.GetString(V_INIT_REGISTRY_PROXY_PASS), "Password the Zarf registry pulls from the 'registry-proxy-url' registry with")
+
+	// Flags for sizing the Zarf registry
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.PVCSize, "registry-pvc-size", v.GetString(V_INIT_REGISTRY_PVC_SIZE), fmt.Sprintf("Size of the volume claim the Zarf registry stores images in (default %s)", config.ZarfRegistryPVCSize))
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.PVCAccessMode, "registry-pvc-access-mode", v.GetString(V_INIT_REGISTRY_PVC_ACCESS), "Access mode of the volume claim of the Zarf registry, which must be ReadWriteMany to run more than one replica. Valid options are: ReadWriteOnce, ReadWriteMany (default ReadWriteOnce)")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.StorageClass, "registry-storage-class", v.GetString(V_INIT_REGISTRY_STORAGE), "StorageClass of the volume claim of the Zarf registry when it differs from 'storage-class'")
+	initCmd.Flags().IntVar(&config.InitOptions.RegistryInfo.Deployment.Replicas, "registry-replicas", v.GetInt(V_INIT_REGISTRY_REPLICAS), "Number of Zarf registry replicas, or the fewest when it autoscales (default 1)")
+	initCmd.Flags().IntVar(&config.InitOptions.RegistryInfo.Deployment.MaxReplicas, "registry-max-replicas", v.GetInt(V_INIT_REGISTRY_MAX_REPLICA), "Autoscale the Zarf registry on CPU between 'registry-replicas' and this many replicas with a HorizontalPodAutoscaler")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.CPURequest, "registry-cpu-request", v.GetString(V_INIT_REGISTRY_CPU_REQ), fmt.Sprintf("CPU request of each Zarf registry replica (default %s)", config.ZarfRegistryCPURequest))
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.MemoryRequest, "registry-memory-request", v.GetString(V_INIT_REGISTRY_MEM_REQ), fmt.Sprintf("Memory request of each Zarf registry replica (default %s)", config.ZarfRegistryMemoryRequest))
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.CPULimit, "registry-cpu-limit", v.GetString(V_INIT_REGISTRY_CPU_LIMIT), fmt.Sprintf("CPU limit of each Zarf registry replica (default %s)", config.ZarfRegistryCPULimit))
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.Deployment.MemoryLimit, "registry-memory-limit", v.GetString(V_INIT_REGISTRY_MEM_LIMIT), fmt.Sprintf("Memory limit of each Zarf registry replica (default %s)", config.ZarfRegistryMemoryLimit))
+
+	// Flags for keeping the images of the Zarf registry in an object store
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.S3.Bucket, "registry-s3-bucket", v.GetString(V_INIT_REGISTRY_S3_BUCKET), "Keep the images of the Zarf registry in this bucket of an S3 compatible object store instead of a volume claim")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.S3.Endpoint, "registry-s3-endpoint", v.GetString(V_INIT_REGISTRY_S3_ENDPOINT), "URL of the object store the 'registry-s3-bucket' is in (e.g. https://minio.example.com:9000), left empty for AWS S3")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.S3.Region, "registry-s3-region", v.GetString(V_INIT_REGISTRY_S3_REGION), fmt.Sprintf("Region of the 'registry-s3-bucket' (default %s)", config.ZarfRegistryS3Region))
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.S3.AccessKey, "registry-s3-access-key", v.GetString(V_INIT_REGISTRY_S3_ACCESS), "Access key the Zarf registry uses for the 'registry-s3-bucket', left empty to use the identity of the registry pods")
+	initCmd.Flags().StringVar(&config.InitOptions.RegistryInfo.S3.SecretKey, "registry-s3-secret-key", v.GetString(V_INIT_REGISTRY_S3_SECRET), "Secret key the Zarf registry uses for the 'registry-s3-bucket'")
+	initCmd.Flags().StringVar(&registryS3CAFile, "registry-s3-ca-file", v.GetString(V_INIT_REGISTRY_S3_CA_FILE), "Path to a PEM encoded CA chain the Zarf registry trusts for the 'registry-s3-endpoint'")
+
+	// Flags for serving the Zarf registry and git server with certificates from the CA of the organization
+	initCmd.Flags().StringVar(&caFile, "ca-file", v.GetString(V_INIT_CA_FILE), "Path to the PEM encoded CA chain that issued the 'registry-tls-cert' and 'git-tls-cert'. Zarf trusts it and copies it to the containerd config of the nodes for a NodePort registry")
+	initCmd.Flags().StringVar(&registryTLSCertFile, "registry-tls-cert", v.GetString(V_INIT_REGISTRY_TLS_CERT), "Path to a PEM encoded certificate (and any intermediates) the Zarf registry is served with instead of plain HTTP. Must be valid for 127.0.0.1 and the address of the load balancer when the 'registry-service-type' is LoadBalancer, or the 'registry-ingress-host' when the 'registry-service-type' is ClusterIP")
+	initCmd.Flags().StringVar(&registryTLSKeyFile, "registry-tls-key", v.GetString(V_INIT_REGISTRY_TLS_KEY), "Path to the PEM encoded private key of the 'registry-tls-cert'")
+	initCmd.Flags().StringVar(&gitTLSCertFile, "git-tls-cert", v.GetString(V_INIT_GIT_TLS_CERT), "Path to a PEM encoded certificate (and any intermediates) the ingress of the Zarf git server is served with. Must be valid for the 'git-ingress-host'")
+	initCmd.Flags().StringVar(&gitTLSKeyFile, "git-tls-key", v.GetString(V_INIT_GIT_TLS_KEY), "Path to the PEM encoded private key of the 'git-tls-cert'")
+
+	// Flags for tuning the agent webhook
+	initCmd.Flags().StringVar(&config.InitOptions.AgentWebhook.FailurePolicy, "agent-failure-policy", v.GetString(V_INIT_AGENT_FAILURE_POLICY), "How the API server handles pod requests when the Zarf agent is unavailable. Valid options are: Fail, Ignore")
+	initCmd.Flags().IntVar(&config.InitOptions.AgentWebhook.TimeoutSeconds, "agent-timeout", v.GetInt(V_INIT_AGENT_TIMEOUT), "Seconds the API server waits for the Zarf agent before applying the failure policy. Between [1-30]")
+	initCmd.Flags().StringVar(&config.InitOptions.AgentWebhook.NamespaceSelector, "agent-namespace-selector", v.GetString(V_INIT_AGENT_NAMESPACE_SELECTOR), "Additional label selector a namespace must match for the Zarf agent to mutate its resources (e.g. 'env in (dev,test)')")
+	initCmd.Flags().StringSliceVar(&config.InitOptions.AgentWebhook.IgnoredNamespaces, "agent-ignored-namespaces", v.GetStringSlice(V_INIT_AGENT_IGNORED_NAMESPACES), "Comma-separated list of namespaces the Zarf agent never mutates resources in, like kube-system, such as ones with images that were already pointed at the registry")
+	initCmd.Flags().StringVar(&config.InitOptions.AgentWebhook.Mode, "agent-mode", v.GetString(V_INIT_AGENT_MODE), "Which namespaces the Zarf agent mutates resources in. 'all' for every namespace that didn't opt out, 'opt-in' for only the namespaces Zarf manages and ones labeled zarf.dev/agent=enabled. Valid options are: all, opt-in")
+	initCmd.Flags().IntVar(&config.InitOptions.AgentWebhook.Replicas, "agent-replicas", v.GetInt(V_INIT_AGENT_REPLICAS), "Number of Zarf agent pods to run, spread across nodes. More than one keeps pods admitted while a node with an agent is drained")
+
+	// Flags for keeping the state credentials in a secrets manager
+	initCmd.Flags().StringVar(&config.InitOptions.StateStore.Type, "state-store", v.GetString(V_INIT_STATE_STORE), "Where to keep the credentials of the Zarf state instead of the zarf-state secret. Valid options are: kubernetes, vault, external-secret")
+	initCmd.Flags().StringVar(&config.InitOptions.StateStore.URL, "state-store-url", v.GetString(V_INIT_STATE_STORE_URL), "API URL of the Vault KV version 2 secret to keep the credentials in (e.g. https://vault.example.com/v1/secret/data/zarf). Authenticates with the VAULT_TOKEN environment variable")
+	initCmd.Flags().StringVar(&config.InitOptions.StateStore.Secret, "state-store-secret", v.GetString(V_INIT_STATE_STORE_SECRET), "NAMESPACE/NAME of the secret an external secrets operator syncs the credentials into")
+
+	// Flags for encrypting the state credentials
+	initCmd.Flags().StringVar(&config.InitOptions.StateEncryption.Provider, "state-encryption", v.GetString(V_INIT_STATE_ENCRYPTION), "Encrypt the credentials of the Zarf state and sensitive package exports with a key. Valid options are: key-file, vault-transit")
+	initCmd.Flags().StringVar(&config.InitOptions.StateEncryption.Key, "state-encryption-key", v.GetString(V_INIT_STATE_ENCRYPTION_KEY), "Path of the key file, created if missing, or URL of the Vault transit key (e.g. https://vault.example.com/v1/transit/keys/zarf) to encrypt with")
 
 	initCmd.Flags().SortFlags = true
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/defenseunicorns/zarf/src/internal/agent"
 	"github.com/defenseunicorns/zarf/src/internal/api"
 	"github.com/defenseunicorns/zarf/src/internal/git"
+	"github.com/defenseunicorns/zarf/src/internal/inventory"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/types"
@@ -35,6 +36,17 @@ var agentCmd = &cobra.Command{
 	},
 }
 
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Runs the zarf inventory service",
+	Long: "NOTE: This command is a hidden command and generally shouldn't be run by a human.\n" +
+		"This command starts up a http server that aggregates the images and SBOM references of every " +
+		"package deployed to the cluster.",
+	Run: func(cmd *cobra.Command, args []string) {
+		inventory.StartServer()
+	},
+}
+
 var generateCLIDocs = &cobra.Command{
 	Use:   "generate-cli-docs",
 	Short: "Creates auto-generated markdown of all the commands for the CLI",
@@ -116,6 +128,7 @@ func init() {
 	rootCmd.AddCommand(internalCmd)
 
 	internalCmd.AddCommand(agentCmd)
+	internalCmd.AddCommand(inventoryCmd)
 	internalCmd.AddCommand(generateCLIDocs)
 	internalCmd.AddCommand(configSchemaCmd)
 	internalCmd.AddCommand(apiSchemaCmd)
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
@@ -19,8 +24,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var insecureDeploy bool
 var shasum string
+var insecurePublish bool
+var listOutputFormat string
+var inspectClusterPackage string
+var removeDryRun bool
 
 var packageCmd = &cobra.Command{
 	Use:     "package",
@@ -34,8 +42,8 @@ var packageCreateCmd = &cobra.Command{
 	Args:    cobra.MaximumNArgs(1),
 	Short:   "Use to create a Zarf package from a given directory or the current directory",
 	Long: "Builds an archive of resources and dependencies defined by the 'zarf.yaml' in the active directory.\n" +
-		"Private registries and repositories are accessed via credentials in your local '~/.docker/config.json' " +
-		"and '~/.git-credentials'.\n",
+		"Private registries and repositories are accessed via credentials from 'zarf tools registry login', " +
+		"your local '~/.docker/config.json' and '~/.git-credentials'.\n",
 	Run: func(cmd *cobra.Command, args []string) {
 
 		var baseDir string
@@ -51,6 +59,27 @@ var packageCreateCmd = &cobra.Command{
 			config.CommonOptions.CachePath = config.ZarfDefaultCachePath
 		}
 
+		if config.CreateOptions.MaxPackageSize != "" {
+			if _, err := utils.ParseByteSize(config.CreateOptions.MaxPackageSize); err != nil {
+				message.Fatalf(err, "Invalid --max-package-size")
+			}
+		}
+
+		validateCompressionFlags()
+
+		// Rebuilds happen unattended so there is no one to answer prompts
+		if config.CreateOptions.Watch && !config.CommonOptions.Confirm {
+			message.Fatal(nil, "The --watch flag requires --confirm and any package variables to be provided with --set")
+		}
+
+		// A multi-arch package is built for every architecture, so it can't also be built for just one
+		if config.CreateOptions.MultiArch {
+			if config.CliArch != "" && config.CliArch != config.MultiArch {
+				message.Fatal(nil, "The --multi-arch and --architecture flags can't be used together")
+			}
+			config.CliArch = config.MultiArch
+		}
+
 		packager.Create(baseDir)
 	},
 }
@@ -58,27 +87,66 @@ var packageCreateCmd = &cobra.Command{
 var packageDeployCmd = &cobra.Command{
 	Use:     "deploy [PACKAGE]",
 	Aliases: []string{"d"},
-	Short:   "Use to deploy a Zarf package from a local file or URL (runs offline)",
-	Long:    "Uses current kubecontext to deploy the packaged tarball onto a k8s cluster.",
-	Args:    cobra.MaximumNArgs(1),
+	Short:   "Use to deploy a Zarf package from a local file, URL or OCI registry (runs offline)",
+	Long: "Uses current kubecontext to deploy the packaged tarball onto a k8s cluster.\n" +
+		"Packages published to an OCI registry can be deployed with an oci:// reference (e.g. oci://registry.example.com/packages/app:1.0.0).\n" +
+		"Use '-' as the package to stream the archive from stdin (e.g. cat package.tar.zst | zarf package deploy - --confirm).",
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var done func()
 		packageName := choosePackage(args)
-		config.DeployOptions.PackagePath, done = packager.HandleIfURL(packageName, shasum, insecureDeploy)
+
+		// Prompts can't be answered when the package itself is being read from stdin
+		if packageName == "-" && !config.CommonOptions.Confirm {
+			message.Fatal(nil, "The --confirm flag is required when deploying a package from stdin")
+		}
+
+		validateDeployFlags()
+
+		// Values not given with --set were read from the zarf-config file
+		if !cmd.Flags().Changed("set") {
+			config.SetVariablesSource = config.VariableSourceConfigFile
+		}
+
+		config.DeployOptions.PackagePath, done = packager.HandleIfURL(packageName, shasum, config.DeployOptions.Insecure)
 		defer done()
 		packager.Deploy()
 	},
 }
 
+var packagePublishCmd = &cobra.Command{
+	Use:     "publish {PACKAGE} {REFERENCE}",
+	Aliases: []string{"p"},
+	Short:   "Publish a Zarf package to an OCI registry",
+	Long: "Pushes a compiled package to an OCI registry as an artifact so it can be deployed with " +
+		"'zarf package deploy oci://REFERENCE'. Registry credentials are read from 'zarf tools registry login' and then your local '~/.docker/config.json'.",
+	Example: "  zarf package publish zarf-package-app-amd64.tar.zst oci://registry.example.com/packages/app:1.0.0",
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		packager.Publish(args[0], args[1], insecurePublish)
+	},
+}
+
 var packageInspectCmd = &cobra.Command{
 	Use:     "inspect [PACKAGE]",
 	Aliases: []string{"i"},
 	Short:   "Lists the payload of a Zarf package (runs offline)",
 	Long: "Lists the payload of a compiled package file (runs offline)\n" +
 		"Unpacks the package tarball into a temp directory and displays the " +
-		"contents of the archive.",
+		"contents of the archive.\n" +
+		"Use '--cluster NAME' to inspect a package deployed to the cluster instead, without the package tarball.",
+	Example: "  zarf package inspect zarf-package-app-amd64.tar.zst\n" +
+		"  zarf package inspect --cluster app",
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if inspectClusterPackage != "" {
+			if len(args) > 0 || packager.ViewSBOM {
+				message.Fatal(nil, "The --cluster flag can't be used with a package file or the --sbom flag, deployed packages don't keep their SBOMs")
+			}
+			packager.InspectDeployed(inspectClusterPackage)
+			return
+		}
+
 		packageName := choosePackage(args)
 		packager.Inspect(packageName)
 	},
@@ -88,28 +156,62 @@ var packageListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"l"},
 	Short:   "List out all of the packages that have been deployed to the cluster",
+	Long: "Lists the packages deployed to the cluster with their version, the version of the Zarf CLI that deployed them, " +
+		"their deployed components and when they were last deployed.\n" +
+		"Use '-o json' to print the list as JSON for automation.",
 	Run: func(cmd *cobra.Command, args []string) {
+		if listOutputFormat != "table" && listOutputFormat != "json" {
+			message.Fatalf(nil, "The --output flag must be table or json, not %s", listOutputFormat)
+		}
+
 		// Get all the deployed packages
 		deployedZarfPackages, err := k8s.GetDeployedZarfPackages()
 		if err != nil {
 			message.Fatalf(err, "Unable to get the packages deployed to the cluster")
 		}
+		sort.Slice(deployedZarfPackages, func(i, j int) bool {
+			return deployedZarfPackages[i].Name < deployedZarfPackages[j].Name
+		})
 
-		// Populate a pterm table of all the deployed packages
-		packageTable := pterm.TableData{
-			{"     Package ", "Components"},
-		}
-
+		var listedPackages []listedPackage
 		for _, pkg := range deployedZarfPackages {
-			var components []string
-
+			listed := listedPackage{
+				Name:       pkg.Name,
+				Version:    pkg.Data.Metadata.Version,
+				CLIVersion: pkg.CLIVersion,
+				Components: []string{},
+				DeployedAt: pkg.DeployedAt,
+			}
 			for _, component := range pkg.DeployedComponents {
-				components = append(components, component.Name)
+				listed.Components = append(listed.Components, component.Name)
+			}
+			listedPackages = append(listedPackages, listed)
+		}
+
+		if listOutputFormat == "json" {
+			if listedPackages == nil {
+				listedPackages = []listedPackage{}
+			}
+			output, err := json.MarshalIndent(listedPackages, "", "  ")
+			if err != nil {
+				message.Fatalf(err, "Unable to format the deployed packages as JSON")
 			}
+			fmt.Println(string(output))
+			return
+		}
+
+		// Populate a pterm table of all the deployed packages
+		packageTable := pterm.TableData{
+			{"     Package ", "Version", "CLI Version", "Components", "Deployed"},
+		}
 
+		for _, pkg := range listedPackages {
 			packageTable = append(packageTable, pterm.TableData{{
 				fmt.Sprintf("     %s", pkg.Name),
-				fmt.Sprintf("%v", components),
+				pkg.Version,
+				pkg.CLIVersion,
+				fmt.Sprintf("%v", pkg.Components),
+				pkg.DeployedAt,
 			}}...)
 		}
 
@@ -118,14 +220,27 @@ var packageListCmd = &cobra.Command{
 	},
 }
 
+// listedPackage is what zarf package list shows for each deployed package
+type listedPackage struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	CLIVersion string   `json:"cliVersion"`
+	Components []string `json:"components"`
+	DeployedAt string   `json:"deployedAt"`
+}
+
 var packageRemoveCmd = &cobra.Command{
 	Use:     "remove {PACKAGE_NAME|PACKAGE_FILE}",
 	Aliases: []string{"u"},
 	Args:    cobra.ExactArgs(1),
 	Short:   "Use to remove a Zarf package that has been deployed already",
+	Long: "Shows the helm releases, host files and emptied namespaces the removal takes out and asks to confirm before removing them. " +
+		"Components are removed before the components they depend on, and removing a component that a component staying behind depends on is refused.",
+	Example: "  zarf package remove my-package --dry-run\n" +
+		"  zarf package remove my-package --components app --confirm",
 	Run: func(cmd *cobra.Command, args []string) {
 		pkgName := args[0]
-		isTarball := regexp.MustCompile(`.*zarf-package-.*\.tar\.zst$`).MatchString
+		isTarball := regexp.MustCompile(`.*zarf-package-.*\.tar(\.zst)?$`).MatchString
 		if isTarball(pkgName) {
 			if utils.InvalidPath(pkgName) {
 				message.Fatalf(nil, "Invalid tarball path provided")
@@ -150,12 +265,94 @@ var packageRemoveCmd = &cobra.Command{
 
 			pkgName = pkgConfig.Metadata.Name
 		}
-		if err := packager.Remove(pkgName); err != nil {
+		if err := packager.Remove(pkgName, removeDryRun); err != nil {
 			message.Fatalf(err, "Unable to remove the package with an error of: %#v", err)
 		}
 	},
 }
 
+var packageVerifyCmd = &cobra.Command{
+	Use:     "verify PACKAGE_NAME",
+	Aliases: []string{"v"},
+	Args:    cobra.ExactArgs(1),
+	Short:   "Use to re-validate a Zarf package that has been deployed already",
+	Long: "Checks that the images in the registry still match the digests recorded at deploy time, the git refs still exist, " +
+		"the helm releases are still deployed and any data injection markers are intact, then prints a pass/fail matrix.\n",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := packager.Verify(args[0]); err != nil {
+			message.Fatalf(err, "Package verification failed: %s", err.Error())
+		}
+	},
+}
+
+func validateCompressionFlags() {
+	level := config.CreateOptions.CompressionLevel
+
+	switch config.CreateOptions.Compression {
+	case config.ZarfCompressionZstd, "":
+		if level < 0 || level > 22 {
+			message.Fatalf(nil, "Invalid --compression-level %d, zstd levels are 1-22", level)
+		}
+	case config.ZarfCompressionGzip:
+		if level < 0 || level > 9 {
+			message.Fatalf(nil, "Invalid --compression-level %d, gzip levels are 1-9", level)
+		}
+	case config.ZarfCompressionNone:
+		if level != 0 {
+			message.Fatalf(nil, "--compression-level cannot be used with --compression none")
+		}
+	default:
+		message.Fatalf(nil, "Invalid --compression %s, must be one of zstd, gzip or none", config.CreateOptions.Compression)
+	}
+}
+
+func validateDeployFlags() {
+	if config.DeployOptions.Profile != "" && config.DeployOptions.Components != "" {
+		message.Fatalf(nil, "Invalid --profile %s, a profile already picks the components so it can't be used with --components", config.DeployOptions.Profile)
+	}
+
+	isPhase := func(phase string) bool {
+		if phase == packager.AllDeployPhases {
+			return true
+		}
+		for _, known := range packager.DeployPhases {
+			if phase == known {
+				return true
+			}
+		}
+		return false
+	}
+	phases := strings.Join(append(append([]string{}, packager.DeployPhases...), packager.AllDeployPhases), ", ")
+
+	for phase, value := range config.DeployOptions.Retries {
+		if !isPhase(phase) {
+			message.Fatalf(nil, "Invalid --retries phase %s, must be one of %s", phase, phases)
+		}
+		if retries, err := strconv.Atoi(value); err != nil || retries < 0 {
+			message.Fatalf(err, "Invalid --retries %s=%s, must be a count of zero or more", phase, value)
+		}
+	}
+
+	for phase, value := range config.DeployOptions.Timeouts {
+		if !isPhase(phase) {
+			message.Fatalf(nil, "Invalid --timeout phase %s, must be one of %s", phase, phases)
+		}
+		if timeout, err := time.ParseDuration(value); err != nil || timeout <= 0 {
+			message.Fatalf(err, "Invalid --timeout %s=%s, must be a positive duration such as 30m", phase, value)
+		}
+	}
+
+	if config.DeployOptions.AtomicCharts && config.DeployOptions.KeepFailedCharts {
+		message.Fatal(nil, "--atomic-charts and --keep-failed-charts cannot be used together")
+	}
+
+	if config.DeployOptions.Deadline != "" {
+		if deadline, err := time.ParseDuration(config.DeployOptions.Deadline); err != nil || deadline <= 0 {
+			message.Fatalf(err, "Invalid --deadline %s, must be a positive duration such as 2h", config.DeployOptions.Deadline)
+		}
+	}
+}
+
 func choosePackage(args []string) string {
 	if len(args) > 0 {
 		return args[0]
@@ -182,14 +379,18 @@ func init() {
 	rootCmd.AddCommand(packageCmd)
 	packageCmd.AddCommand(packageCreateCmd)
 	packageCmd.AddCommand(packageDeployCmd)
+	packageCmd.AddCommand(packagePublishCmd)
 	packageCmd.AddCommand(packageInspectCmd)
 	packageCmd.AddCommand(packageRemoveCmd)
 	packageCmd.AddCommand(packageListCmd)
+	packageCmd.AddCommand(packageVerifyCmd)
 
 	bindCreateFlags()
 	bindDeployFlags()
 	bindInspectFlags()
 	bindRemoveFlags()
+	bindPublishFlags()
+	bindListFlags()
 }
 
 func bindCreateFlags() {
@@ -202,11 +403,40 @@ func bindCreateFlags() {
 	v.SetDefault(V_PKG_CREATE_OUTPUT_DIR, "")
 	v.SetDefault(V_PKG_CREATE_SKIP_SBOM, false)
 	v.SetDefault(V_PKG_CREATE_INSECURE, false)
+	v.SetDefault(V_PKG_CREATE_IMAGE_SIZE_WARNING, 1024)
+	v.SetDefault(V_PKG_CREATE_DIFFERENTIAL, "")
+	v.SetDefault(V_PKG_CREATE_MAX_PACKAGE_SIZE, "")
+	v.SetDefault(V_PKG_CREATE_COMPRESSION, "")
+	v.SetDefault(V_PKG_CREATE_COMPRESSION_LEVEL, 0)
+	v.SetDefault(V_PKG_CREATE_IMAGE_POLICY, "")
+	v.SetDefault(V_PKG_CREATE_PIN_DIGESTS, false)
+	v.SetDefault(V_PKG_CREATE_INCLUDE_SIGNATURES, false)
+	v.SetDefault(V_PKG_CREATE_SIGNATURE_KEYS, []string{})
+	v.SetDefault(V_PKG_CREATE_MULTI_ARCH, false)
+	v.SetDefault(V_PKG_CREATE_INTOTO_LINKS, "")
+
+	// Private helm repo settings are only read from the config file (no flag), this also covers prepare find-images
+	if err := v.UnmarshalKey(V_PKG_CREATE_HELM_REPOSITORIES, &config.CreateOptions.HelmRepositories); err != nil {
+		message.Fatalf(err, "Unable to read %s from the config file", V_PKG_CREATE_HELM_REPOSITORIES)
+	}
 
 	createFlags.StringToStringVar(&config.CreateOptions.SetVariables, "set", v.GetStringMapString(V_PKG_CREATE_SET), "Specify package variables to set on the command line (KEY=value)")
 	createFlags.StringVarP(&config.CreateOptions.OutputDirectory, "output-directory", "o", v.GetString(V_PKG_CREATE_OUTPUT_DIR), "Specify the output directory for the created Zarf package")
 	createFlags.BoolVar(&config.CreateOptions.SkipSBOM, "skip-sbom", v.GetBool(V_PKG_CREATE_SKIP_SBOM), "Skip generating SBOM for this package")
 	createFlags.BoolVar(&config.CreateOptions.Insecure, "insecure", v.GetBool(V_PKG_CREATE_INSECURE), "Allow insecure registry connections when pulling OCI images")
+	createFlags.StringVar(&config.CreateOptions.DifferentialPath, "differential", v.GetString(V_PKG_CREATE_DIFFERENTIAL), "Path to a previously built package, images and pinned git repos that have not changed since it are left out of the new package")
+	createFlags.StringVar(&config.CreateOptions.Compression, "compression", v.GetString(V_PKG_CREATE_COMPRESSION), "Compression for the package archive: zstd, gzip or none (defaults to zstd unless the package sets metadata.uncompressed)")
+	createFlags.IntVar(&config.CreateOptions.CompressionLevel, "compression-level", v.GetInt(V_PKG_CREATE_COMPRESSION_LEVEL), "Compression level, 1-22 for zstd or 1-9 for gzip, 0 uses the default level")
+	createFlags.StringVar(&config.CreateOptions.MaxPackageSize, "max-package-size", v.GetString(V_PKG_CREATE_MAX_PACKAGE_SIZE), "Split the package into numbered parts no larger than this size (e.g. 4GB), parts are reassembled automatically on deploy")
+	createFlags.StringVar(&config.CreateOptions.ImagePolicyPath, "image-policy", v.GetString(V_PKG_CREATE_IMAGE_POLICY), "Path to an image policy file (allowed repositories, denied repositories and denied tags) that every image in the package must meet")
+	createFlags.BoolVar(&config.CreateOptions.ForceImagePolicy, "force-image-policy", false, "Build the package even when images violate the --image-policy, the violations are recorded in the package build data")
+	createFlags.BoolVar(&config.CreateOptions.Watch, "watch", false, "Keep running after the package is created and rebuild only the components whose zarf.yaml definition or local files change")
+	createFlags.BoolVar(&config.CreateOptions.PinDigests, "pin-digests", v.GetBool(V_PKG_CREATE_PIN_DIGESTS), "Resolve each image tag to its digest, record the images as name:tag@sha256:... in the package and push them by digest on deploy")
+	createFlags.BoolVar(&config.CreateOptions.IncludeSignatures, "include-signatures", v.GetBool(V_PKG_CREATE_INCLUDE_SIGNATURES), "Bundle the cosign signatures and attestations of the images and push them next to the images on deploy so in-cluster policy engines can verify them")
+	createFlags.StringArrayVar(&config.CreateOptions.SignatureKeys, "signature-key", v.GetStringSlice(V_PKG_CREATE_SIGNATURE_KEYS), "Public key (path, URL or KMS URI) the bundled signatures must verify against, can be given more than once and implies --include-signatures")
+	createFlags.BoolVar(&config.CreateOptions.MultiArch, "multi-arch", v.GetBool(V_PKG_CREATE_MULTI_ARCH), "Build the package for both amd64 and arm64, deploy and init pick the images and components for the architecture of the cluster")
+	createFlags.StringVar(&config.CreateOptions.InTotoLinksPath, "intoto-links", v.GetString(V_PKG_CREATE_INTOTO_LINKS), "Directory of the in-toto link metadata (*.link files) the build pipeline recorded, they are added to the package so deploy can verify them against a layout with --intoto-layout")
+	createFlags.IntVar(&config.CreateOptions.ImageSizeWarningMB, "image-size-warning", v.GetInt(V_PKG_CREATE_IMAGE_SIZE_WARNING), "Warn about images larger than this many megabytes, 0 disables the check")
 }
 
 func bindDeployFlags() {
@@ -217,25 +447,76 @@ func bindDeployFlags() {
 
 	v.SetDefault(V_PKG_DEPLOY_SET, map[string]string{})
 	v.SetDefault(V_PKG_DEPLOY_COMPONENTS, "")
+	v.SetDefault(V_PKG_DEPLOY_PROFILE, "")
 	v.SetDefault(V_PKG_DEPLOY_INSECURE, false)
+	v.SetDefault(V_PKG_DEPLOY_OCI_INSECURE, false)
 	v.SetDefault(V_PKG_DEPLOY_SHASUM, "")
 	v.SetDefault(V_PKG_DEPLOY_SGET, "")
+	v.SetDefault(V_PKG_DEPLOY_IMAGE_SIZE_WARNING, 1024)
+	v.SetDefault(V_PKG_DEPLOY_RESUME, false)
+	v.SetDefault(V_PKG_DEPLOY_BIN_DIR, config.ZarfDefaultBinDir)
+	v.SetDefault(V_PKG_DEPLOY_RETRIES, map[string]string{})
+	v.SetDefault(V_PKG_DEPLOY_TIMEOUT, map[string]string{})
+	v.SetDefault(V_PKG_DEPLOY_DEADLINE, "")
+	v.SetDefault(V_PKG_DEPLOY_ADOPT, false)
+	v.SetDefault(V_PKG_DEPLOY_ATOMIC_CHARTS, false)
+	v.SetDefault(V_PKG_DEPLOY_KEEP_FAILED_CHARTS, false)
+	v.SetDefault(V_PKG_DEPLOY_GIT_FORCE, false)
+	v.SetDefault(V_PKG_DEPLOY_GIT_CHUNK_SIZE, config.DefaultGitChunkSizeMB)
+	v.SetDefault(V_PKG_DEPLOY_IMAGE_POLICY, "")
+	v.SetDefault(V_PKG_DEPLOY_OCI_CONCURRENCY, config.DefaultOCIConcurrency)
+	v.SetDefault(V_PKG_DEPLOY_PRE_PULL_SIZE, 0)
+	v.SetDefault(V_PKG_DEPLOY_RESULT_FILE, "")
+	v.SetDefault(V_PKG_DEPLOY_INTOTO_LAYOUT, "")
+	v.SetDefault(V_PKG_DEPLOY_INTOTO_LAYOUT_KEYS, []string{})
 
 	deployFlags.StringToStringVar(&config.DeployOptions.SetVariables, "set", v.GetStringMapString(V_PKG_DEPLOY_SET), "Specify deployment variables to set on the command line (KEY=value)")
-	deployFlags.StringVar(&config.DeployOptions.Components, "components", v.GetString(V_PKG_DEPLOY_COMPONENTS), "Comma-separated list of components to install.  Adding this flag will skip the init prompts for which components to install")
-	deployFlags.BoolVar(&insecureDeploy, "insecure", v.GetBool(V_PKG_DEPLOY_INSECURE), "Skip shasum validation of remote package. Required if deploying a remote package and `--shasum` is not provided")
+	deployFlags.StringVar(&config.DeployOptions.Components, "components", v.GetString(V_PKG_DEPLOY_COMPONENTS), "Comma-separated list of components to install, or '*' for all of them.  Adding this flag will skip the init prompts for which components to install")
+	deployFlags.StringVar(&config.DeployOptions.Profile, "profile", v.GetString(V_PKG_DEPLOY_PROFILE), "Name of a profile defined by the package to deploy its components and variable values instead of choosing them")
+	deployFlags.BoolVar(&config.DeployOptions.Insecure, "insecure", v.GetBool(V_PKG_DEPLOY_INSECURE), "Skip shasum validation of remote package. Required if deploying a remote package and `--shasum` is not provided")
+	deployFlags.BoolVar(&config.DeployOptions.OCIInsecure, "oci-insecure", v.GetBool(V_PKG_DEPLOY_OCI_INSECURE), "Allow plain HTTP and unverified TLS connections to the OCI registry of an oci:// package")
 	deployFlags.StringVar(&shasum, "shasum", v.GetString(V_PKG_DEPLOY_SHASUM), "Shasum of the package to deploy. Required if deploying a remote package and `--insecure` is not provided")
 	deployFlags.StringVar(&config.DeployOptions.SGetKeyPath, "sget", v.GetString(V_PKG_DEPLOY_SGET), "Path to public sget key file for remote packages signed via cosign")
+	deployFlags.BoolVar(&config.DeployOptions.Resume, "resume", v.GetBool(V_PKG_DEPLOY_RESUME), "Skip the components an earlier failed deployment of this package already finished")
+	deployFlags.StringVar(&config.DeployOptions.BinDirectory, "bin-dir", v.GetString(V_PKG_DEPLOY_BIN_DIR), "Directory on the host to install component binaries into")
+	deployFlags.StringToStringVar(&config.DeployOptions.Retries, "retries", v.GetStringMapString(V_PKG_DEPLOY_RETRIES), "Times to retry each deploy phase after it fails (PHASE=count, phases are extract, images, repos, charts, data or all)")
+	deployFlags.StringToStringVar(&config.DeployOptions.Timeouts, "timeout", v.GetStringMapString(V_PKG_DEPLOY_TIMEOUT), "Time limit for each deploy phase including its retries (PHASE=duration, e.g. images=30m)")
+	deployFlags.StringVar(&config.DeployOptions.Deadline, "deadline", v.GetString(V_PKG_DEPLOY_DEADLINE), "Time limit for the whole deployment (e.g. 2h), phases still running when it passes fail the deployment")
+	deployFlags.BoolVar(&config.DeployOptions.AdoptExistingResources, "adopt-existing-resources", v.GetBool(V_PKG_DEPLOY_ADOPT), "Take over resources that already exist in the cluster (e.g. namespaces, deployments and CRDs) by adding helm ownership metadata to them before installing charts")
+	deployFlags.BoolVar(&config.DeployOptions.AtomicCharts, "atomic-charts", v.GetBool(V_PKG_DEPLOY_ATOMIC_CHARTS), "Roll back or uninstall a release as soon as any attempt fails, for charts that don't set keepFailed")
+	deployFlags.BoolVar(&config.DeployOptions.KeepFailedCharts, "keep-failed-charts", v.GetBool(V_PKG_DEPLOY_KEEP_FAILED_CHARTS), "Leave releases that fail their last attempt in place for debugging, for charts that don't set atomic")
+	deployFlags.BoolVar(&config.DeployOptions.GitForce, "git-force", v.GetBool(V_PKG_DEPLOY_GIT_FORCE), "Force-push every repo and remove the branches and tags the package no longer has from the git server, for repos whose upstream history was rewritten")
+	deployFlags.IntVar(&config.DeployOptions.GitChunkSizeMB, "git-chunk-size", v.GetInt(V_PKG_DEPLOY_GIT_CHUNK_SIZE), "Push the history of repos larger than this many megabytes in chunks of about this size, so a retry resumes from the last chunk the git server has, 0 disables chunking")
+	deployFlags.StringVar(&config.DeployOptions.ImagePolicyPath, "image-policy", v.GetString(V_PKG_DEPLOY_IMAGE_POLICY), "Path to an image policy file (allowed repositories, denied repositories and denied tags) the package images must meet before they are pushed")
+	deployFlags.BoolVar(&config.DeployOptions.ForceImagePolicy, "force-image-policy", false, "Deploy the package even when images violate the --image-policy, the violations are recorded with the deployed package in the cluster")
+	deployFlags.StringVar(&config.DeployOptions.InTotoLayoutPath, "intoto-layout", v.GetString(V_PKG_DEPLOY_INTOTO_LAYOUT), "Path to a signed in-toto layout the in-toto links in the package must meet, packages built outside the pipeline it describes are rejected")
+	deployFlags.StringArrayVar(&config.DeployOptions.InTotoLayoutKeys, "intoto-layout-key", v.GetStringSlice(V_PKG_DEPLOY_INTOTO_LAYOUT_KEYS), "Path to a public key of the owner of the --intoto-layout, can be given more than once")
+	deployFlags.IntVar(&config.DeployOptions.OCIConcurrency, "oci-concurrency", v.GetInt(V_PKG_DEPLOY_OCI_CONCURRENCY), "Number of images, and layers of each image, to push to the registry at once")
+	deployFlags.IntVar(&config.DeployOptions.PrePullSizeMB, "pre-pull-size", v.GetInt(V_PKG_DEPLOY_PRE_PULL_SIZE), "Have every node pull the pushed images of at least this many megabytes before the charts that use them deploy, 0 disables the pre-pull")
+	deployFlags.IntVar(&config.DeployOptions.ImageSizeWarningMB, "image-size-warning", v.GetInt(V_PKG_DEPLOY_IMAGE_SIZE_WARNING), "Warn about images larger than this many megabytes, 0 disables the check")
+	deployFlags.StringVar(&config.CommonOptions.ResultFile, "result-file", v.GetString(V_PKG_DEPLOY_RESULT_FILE), "Write a JSON summary of the deployment (status, durations, errors, connect strings) to this file when it finishes or fails")
 }
 
 func bindInspectFlags() {
 	inspectFlags := packageInspectCmd.Flags()
 	inspectFlags.BoolVarP(&packager.ViewSBOM, "sbom", "s", false, "View SBOM contents while inspecting the package")
+	inspectFlags.StringVar(&inspectClusterPackage, "cluster", "", "Name of a package deployed to the cluster to inspect instead of a package file")
+}
+
+func bindPublishFlags() {
+	publishFlags := packagePublishCmd.Flags()
+	publishFlags.BoolVar(&insecurePublish, "insecure", false, "Allow insecure connections to the OCI registry")
+}
+
+func bindListFlags() {
+	listFlags := packageListCmd.Flags()
+	listFlags.StringVarP(&listOutputFormat, "output", "o", "table", "Output format for the deployed packages: table or json")
 }
 
 func bindRemoveFlags() {
 	removeFlags := packageRemoveCmd.Flags()
-	removeFlags.BoolVar(&config.CommonOptions.Confirm, "confirm", false, "REQUIRED. Confirm the removal action to prevent accidental deletions")
+	removeFlags.BoolVar(&config.CommonOptions.Confirm, "confirm", false, "Confirm the removal without prompting")
 	removeFlags.StringVar(&config.DeployOptions.Components, "components", v.GetString(V_PKG_DEPLOY_COMPONENTS), "Comma-separated list of components to uninstall")
-	_ = packageRemoveCmd.MarkFlagRequired("confirm")
+	removeFlags.BoolVar(&removeDryRun, "dry-run", false, "Only show what would be removed, in the order it would be removed")
+	removeFlags.StringVar(&config.CommonOptions.ResultFile, "result-file", "", "Write a JSON summary of the removal (status, durations and errors) to this file when it finishes or fails")
 }
@@ -14,7 +14,9 @@ import (
 
 var skipLogFile bool
 var logLevel string
+var logFormat string
 var arch string
+var site string
 
 // Viper instance used by the cmd package
 var v *viper.Viper
@@ -53,18 +55,24 @@ func init() {
 	initViper()
 
 	v.SetDefault(V_LOG_LEVEL, "info")
+	v.SetDefault(V_LOG_FORMAT, "console")
 	v.SetDefault(V_ARCHITECTURE, "")
 	v.SetDefault(V_NO_LOG_FILE, false)
 	v.SetDefault(V_NO_PROGRESS, false)
 	v.SetDefault(V_ZARF_CACHE, config.ZarfDefaultCachePath)
 	v.SetDefault(V_TMP_DIR, "")
+	v.SetDefault(V_SITE, "")
+	v.SetDefault(V_NO_PROXY_CLUSTER, false)
 
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", v.GetString(V_LOG_LEVEL), "Log level when running Zarf. Valid options are: warn, info, debug, trace")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", v.GetString(V_LOG_FORMAT), "How to show the output of Zarf. Valid options are: console, plain, json")
 	rootCmd.PersistentFlags().StringVarP(&arch, "architecture", "a", v.GetString(V_ARCHITECTURE), "Architecture for OCI images")
 	rootCmd.PersistentFlags().BoolVar(&skipLogFile, "no-log-file", v.GetBool(V_NO_LOG_FILE), "Disable log file creation")
 	rootCmd.PersistentFlags().BoolVar(&message.NoProgress, "no-progress", v.GetBool(V_NO_PROGRESS), "Disable fancy UI progress bars, spinners, logos, etc")
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", v.GetString(V_ZARF_CACHE), "Specify the location of the Zarf cache directory")
 	rootCmd.PersistentFlags().StringVar(&config.CommonOptions.TempDirectory, "tmpdir", v.GetString(V_TMP_DIR), "Specify the temporary directory to use for intermediate files")
+	rootCmd.PersistentFlags().BoolVar(&config.CommonOptions.NoProxyCluster, "no-proxy-cluster", v.GetBool(V_NO_PROXY_CLUSTER), "Connect to the cluster API, port-forward tunnels and the Zarf registry and git server directly instead of through HTTP_PROXY/HTTPS_PROXY")
+	rootCmd.PersistentFlags().StringVar(&site, "site", v.GetString(V_SITE), "Layer the settings for this site from the config file's sites section over its shared defaults")
 }
 
 func cliSetup() {
@@ -87,6 +95,21 @@ func cliSetup() {
 		}
 	}
 
+	// Headless runs stream their messages and steps as log lines or JSON, which leave no room for spinners, progress bars or other output from pterm
+	switch logFormat {
+	case "", "console":
+	case "plain":
+		message.NoProgress = true
+		message.SetRenderer(message.NewPlainRenderer())
+		pterm.DisableStyling()
+	case "json":
+		message.NoProgress = true
+		message.SetRenderer(message.NewJSONRenderer())
+		pterm.DisableOutput()
+	default:
+		message.Warnf("invalid log format %s, valid options are: console, plain, json", logFormat)
+	}
+
 	// Disable progress bars for CI envs
 	if os.Getenv("CI") == "true" {
 		message.Debug("CI environment detected, disabling progress bars")
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// selfUpdateRegistryPrefix marks a --from source as an artifact in the Zarf registry instead of a local file
+const selfUpdateRegistryPrefix = "registry://"
+
+var selfUpdateSource string
+var selfUpdateSignature string
+var selfUpdateKey string
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Replaces this Zarf binary with a signed binary from a file or the Zarf registry",
+	Long: "Replaces this Zarf binary with a new one once its cosign signature verifies against the given public key.\n\n" +
+		"A local binary is checked against a signature made with 'cosign sign-blob' (--signature, defaulting to the binary path with .sig added). " +
+		"A binary in the Zarf registry ('registry://' followed by its path and tag) is a single layer artifact, such as one uploaded with 'cosign upload blob', " +
+		"signed with 'cosign sign'.\n\n" +
+		"The binary is swapped in with a single rename so an interrupted update never leaves a partial executable, and the Zarf cache and config files are left as they are.",
+	Example: "  zarf self-update --from ./zarf_v0.23.0_Linux_amd64 --key cosign.pub\n" +
+		"  zarf self-update --from registry://cli/zarf:v0.23.0-amd64 --key cosign.pub --confirm",
+	Run: func(cmd *cobra.Command, args []string) {
+		// Only signed binaries are installed, the key can come from the config file to update a fleet
+		if selfUpdateKey == "" {
+			message.Fatalf(nil, "The --key flag is required to verify the new binary")
+		}
+
+		executable, err := utils.GetFinalExecutablePath()
+		if err != nil {
+			message.Fatal(err, "Unable to find the path of the Zarf binary")
+		}
+
+		tempDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+		if err != nil {
+			message.Fatal(err, "Unable to create a temporary directory")
+		}
+		defer os.RemoveAll(tempDir)
+
+		// The binary is staged in the temp directory so the file that is verified is the one that gets installed
+		binaryPath := filepath.Join(tempDir, "zarf")
+		if strings.HasPrefix(selfUpdateSource, selfUpdateRegistryPrefix) {
+			pullSelfUpdateBinary(strings.TrimPrefix(selfUpdateSource, selfUpdateRegistryPrefix), binaryPath)
+		} else {
+			if err := utils.CreatePathAndCopy(selfUpdateSource, binaryPath); err != nil {
+				message.Fatalf(err, "Unable to read the binary %s", selfUpdateSource)
+			}
+			signature := selfUpdateSignature
+			if signature == "" {
+				signature = selfUpdateSource + ".sig"
+			}
+
+			spinner := message.NewProgressSpinner("Verifying the signature of %s", selfUpdateSource)
+			if err := utils.VerifyBlobSignature(binaryPath, signature, selfUpdateKey); err != nil {
+				spinner.Fatalf(err, "Unable to verify the binary %s: %s", selfUpdateSource, err.Error())
+			}
+			spinner.Successf("Verified the signature of %s", selfUpdateSource)
+		}
+
+		// Running the new binary checks it was built for this machine before it replaces a working one
+		if err := os.Chmod(binaryPath, 0700); err != nil {
+			message.Fatal(err, "Unable to make the new binary executable")
+		}
+		version, _, err := utils.ExecCommandWithContext(context.TODO(), false, binaryPath, "version")
+		if err != nil {
+			message.Fatalf(err, "The new binary doesn't run on this machine: %s", err.Error())
+		}
+		version = strings.TrimSpace(version)
+
+		message.Notef("Replacing Zarf %s at %s with Zarf %s", config.CLIVersion, executable, version)
+		if !config.CommonOptions.Confirm {
+			var confirm bool
+			prompt := &survey.Confirm{
+				Message: "Replace this Zarf binary?",
+			}
+			if err := survey.AskOne(prompt, &confirm); err != nil || !confirm {
+				message.Fatalf(nil, "Self-update cancelled")
+			}
+		}
+
+		if err := utils.ReplaceExecutable(executable, binaryPath); err != nil {
+			message.Fatalf(err, "Unable to replace the Zarf binary at %s: %s", executable, err.Error())
+		}
+		message.SuccessF("Zarf %s is now installed at %s", version, executable)
+	},
+}
+
+// pullSelfUpdateBinary writes a signed binary from the Zarf registry to the path
+func pullSelfUpdateBinary(reference, path string) {
+	state, err := k8s.LoadZarfState()
+	if err != nil || state.Distro == "" {
+		message.Fatal(err, "Unable to load the zarf/zarf-state secret, did you remember to run zarf init first?")
+	}
+	config.InitState(state)
+
+	file, err := os.Create(path)
+	if err != nil {
+		message.Fatal(err, "Unable to create the file for the new binary")
+	}
+	defer file.Close()
+
+	if err := images.PullSignedFile(reference, selfUpdateKey, file); err != nil {
+		message.Fatalf(err, "Unable to pull the binary %s from the Zarf registry: %s", reference, err.Error())
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	v.SetDefault(V_SELF_UPDATE_KEY, "")
+
+	selfUpdateCmd.Flags().StringVar(&selfUpdateSource, "from", "", "REQUIRED. Path to the new binary, or registry://PATH:TAG for a binary in the Zarf registry")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateSignature, "signature", "", "Path to the cosign sign-blob signature of a local binary (defaults to the binary path with .sig added)")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateKey, "key", v.GetString(V_SELF_UPDATE_KEY), "Public key (path, URL or KMS URI) the signature of the new binary must verify against")
+	selfUpdateCmd.Flags().BoolVar(&config.CommonOptions.Confirm, "confirm", false, "Replace the binary without prompting")
+
+	_ = selfUpdateCmd.MarkFlagRequired("from")
+}
@@ -1,21 +1,64 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/anchore/syft/cmd/syft/cli"
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/packager"
 	"github.com/defenseunicorns/zarf/src/internal/pki"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
 	k9s "github.com/derailed/k9s/cmd"
+	dockerTypes "github.com/docker/cli/cli/config/types"
 	craneCmd "github.com/google/go-containerregistry/cmd/crane/cmd"
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/mholt/archiver/v3"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
 var subAltNames []string
+var pkiOptions = pki.DefaultOptions()
+var pkiCACertPath string
+var pkiCAKeyPath string
+var insecureCopy bool
+var catalogBinDir string
+var catalogPackage string
+var gcRunID string
+var gcStaleAfter time.Duration
+var registryLoginUsername string
+var registryLoginPassword string
+var registryLoginPasswordStdin bool
+var registryPruneConfirm bool
+var registryPruneDryRun bool
+var getCredsOutputFormat string
+var stateExportOutput string
+var stateImportConfirm bool
+var stateEncryption types.StateEncryption
+
+// statePassphraseEnv is the environment variable the passphrase of a state backup is read from instead of a prompt
+const statePassphraseEnv = "ZARF_STATE_PASSPHRASE"
+
+// The applications zarf tools get-creds can print the credentials of
+var credentialApplications = []string{"registry", "registry-readonly", "git", "git-readonly", "logging"}
+
+// listedCredential is a username and password printed by zarf tools get-creds
+type listedCredential struct {
+	Application string `json:"application"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	Connect     string `json:"connect,omitempty"`
+}
 
 var toolsCmd = &cobra.Command{
 	Use:     "tools",
@@ -55,6 +98,20 @@ var archiverDecompressCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		sourceArchive, destinationPath := args[0], args[1]
+
+		// Reassemble multi-part packages before decompressing them
+		if indexPath := utils.GetSplitIndexPath(sourceArchive); indexPath != "" {
+			tempPath, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+			if err != nil {
+				message.Fatal(err, "Unable to create a directory to reassemble the archive")
+			}
+			defer os.RemoveAll(tempPath)
+
+			if sourceArchive, err = utils.ReassembleSplitFile(indexPath, tempPath); err != nil {
+				message.Fatal(err, "Unable to reassemble the multi-part archive")
+			}
+		}
+
 		err := archiver.Unarchive(sourceArchive, destinationPath)
 		if err != nil {
 			message.Fatal(err, "Unable to perform decompression")
@@ -62,16 +119,144 @@ var archiverDecompressCmd = &cobra.Command{
 	},
 }
 
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "List the binaries Zarf packages have installed on this host",
+	Long: "Lists the binaries deployed packages installed into the bin directory along with their version, owning package " +
+		"and whether the binary on disk still matches the checksum recorded when it was installed.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		packager.ListBinaryCatalog(catalogBinDir, catalogPackage)
+	},
+}
+
 var registryCmd = &cobra.Command{
 	Use:     "registry",
 	Aliases: []string{"r", "crane"},
 	Short:   "Collection of registry commands provided by Crane",
 }
 
+var registryCopyFromPackageCmd = &cobra.Command{
+	Use:   "copy-from-package {PACKAGE} {IMAGE} {DESTINATION}",
+	Short: "Copy a single image from a Zarf package archive to any registry",
+	Long: "Extracts the image store from a Zarf package archive and pushes one of its images to the given destination " +
+		"without deploying the package. Credentials for the destination are read from 'zarf tools registry login' and then your local '~/.docker/config.json'.",
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := packager.CopyImageFromPackage(args[0], args[1], args[2], insecureCopy); err != nil {
+			message.Fatalf(err, "Unable to copy the image from the package: %s", err.Error())
+		}
+	},
+}
+
+var registryLoginCmd = &cobra.Command{
+	Use:   "login {SERVER}",
+	Short: "Log in to a registry",
+	Long: "Stores credentials for a registry in the Zarf auth file (~/.zarf-auth.json) instead of the docker config of the host. " +
+		"Zarf uses them ahead of the docker config when it pulls images during package create and in the registry tools.",
+	Example: "  zarf tools registry login reg.example.com -u AzureDiamond --password-stdin < password.txt",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		registry, err := name.NewRegistry(args[0])
+		if err != nil {
+			message.Fatalf(err, "Invalid registry %s", args[0])
+		}
+
+		if registryLoginPasswordStdin {
+			contents, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				message.Fatal(err, "Unable to read the password from stdin")
+			}
+			registryLoginPassword = strings.TrimRight(string(contents), "\r\n")
+		}
+		if registryLoginUsername == "" || registryLoginPassword == "" {
+			message.Fatal(nil, "A username and password are required to log in")
+		}
+		message.AddSensitiveValue(registryLoginPassword)
+
+		authFile, err := config.LoadAuthFile()
+		if err != nil {
+			message.Fatal(err, err.Error())
+		}
+
+		authFile.AuthConfigs[config.GetAuthKey(registry.Name())] = dockerTypes.AuthConfig{
+			Username: registryLoginUsername,
+			Password: registryLoginPassword,
+		}
+		if err := authFile.Save(); err != nil {
+			message.Fatalf(err, "Unable to save the Zarf auth file %s", authFile.Filename)
+		}
+
+		message.SuccessF("Logged in to %s as %s, credentials are stored in %s", registry.Name(), registryLoginUsername, authFile.Filename)
+	},
+}
+
+var registryLogoutCmd = &cobra.Command{
+	Use:   "logout {SERVER}",
+	Short: "Remove the stored credentials for a registry",
+	Long:  "Removes the credentials for a registry from the Zarf auth file, the docker config of the host is not changed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		registry, err := name.NewRegistry(args[0])
+		if err != nil {
+			message.Fatalf(err, "Invalid registry %s", args[0])
+		}
+
+		authFile, err := config.LoadAuthFile()
+		if err != nil {
+			message.Fatal(err, err.Error())
+		}
+
+		key := config.GetAuthKey(registry.Name())
+		if _, ok := authFile.AuthConfigs[key]; !ok {
+			message.Warnf("Not logged in to %s", registry.Name())
+			return
+		}
+
+		delete(authFile.AuthConfigs, key)
+		if err := authFile.Save(); err != nil {
+			message.Fatalf(err, "Unable to save the Zarf auth file %s", authFile.Filename)
+		}
+
+		message.SuccessF("Logged out of %s", registry.Name())
+	},
+}
+
+var registryListLoginsCmd = &cobra.Command{
+	Use:     "list-logins",
+	Aliases: []string{"logins"},
+	Short:   "List the registries Zarf has stored credentials for",
+	Long:    "Lists the registries and usernames in the Zarf auth file, passwords are never shown",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		authFile, err := config.LoadAuthFile()
+		if err != nil {
+			message.Fatal(err, err.Error())
+		}
+
+		if len(authFile.AuthConfigs) == 0 {
+			message.Notef("No registry logins are stored in %s", authFile.Filename)
+			return
+		}
+
+		var registries []string
+		for registry := range authFile.AuthConfigs {
+			registries = append(registries, registry)
+		}
+		sort.Strings(registries)
+
+		loginTable := pterm.TableData{{"     Registry", "Username"}}
+		for _, registry := range registries {
+			loginTable = append(loginTable, []string{"     " + registry, authFile.AuthConfigs[registry].Username})
+		}
+		_ = pterm.DefaultTable.WithHasHeader().WithData(loginTable).Render()
+	},
+}
+
 var readCredsCmd = &cobra.Command{
 	Use:   "get-git-password",
 	Short: "Returns the push user's password for the Git server",
-	Long:  "Reads the password for a user with push access to the configured Git server from the zarf-state secret in the zarf namespace",
+	Long:  "Reads the password for a user with push access to the configured Git server from the zarf-state secret in the zarf namespace.\nUse 'zarf tools get-creds' for the other credentials Zarf manages.",
 	Run: func(cmd *cobra.Command, args []string) {
 		state, err := k8s.LoadZarfState()
 		if err != nil {
@@ -91,6 +276,115 @@ var readCredsCmd = &cobra.Command{
 	},
 }
 
+var getCredsCmd = &cobra.Command{
+	Use:   "get-creds [registry|registry-readonly|git|git-readonly|logging]",
+	Short: "Prints the credentials Zarf manages for the registry, git server and logging stack",
+	Long: "Reads the usernames and passwords of the services Zarf manages from the zarf-state secret in the zarf namespace.\n" +
+		"Give an application to only print its credentials, and use '-o json' to print them as JSON for scripts and CI.",
+	Example: "  zarf tools get-creds\n" +
+		"  zarf tools get-creds git -o json",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if getCredsOutputFormat != "table" && getCredsOutputFormat != "json" {
+			message.Fatalf(nil, "The --output flag must be table or json, not %s", getCredsOutputFormat)
+		}
+
+		application := ""
+		if len(args) > 0 {
+			application = args[0]
+			known := false
+			for _, name := range credentialApplications {
+				known = known || name == application
+			}
+			if !known {
+				message.Fatalf(nil, "Invalid application %s, must be one of %s", application, strings.Join(credentialApplications, ", "))
+			}
+		}
+
+		state, err := k8s.LoadZarfState()
+		if err != nil {
+			message.Fatal(err, "Unable to load Zarf state")
+		}
+
+		if state.Distro == "" {
+			// If no distro the zarf secret did not load properly
+			message.Fatalf(nil, "Unable to load the zarf/zarf-state secret, did you remember to run zarf init first?")
+		}
+
+		// The git server and logging stack are optional init components, their passwords are generated whether or not they were deployed
+		deployed := make(map[string]bool)
+		if initPackage, err := k8s.GetDeployedPackage("init"); err == nil {
+			for _, component := range initPackage.DeployedComponents {
+				deployed[component.Name] = true
+			}
+		} else {
+			message.Debugf("Unable to find the deployed init package: %s", err.Error())
+		}
+
+		registry := state.RegistryInfo
+		git := state.GitServer
+		var credentials []listedCredential
+		credentials = append(credentials,
+			listedCredential{"registry", registry.PushUsername, registry.PushPassword, "zarf connect registry"},
+			listedCredential{"registry-readonly", registry.PullUsername, registry.PullPassword, "zarf connect registry"},
+		)
+		if !git.InternalServer || deployed["git-server"] {
+			credentials = append(credentials,
+				listedCredential{"git", git.PushUsername, git.PushPassword, "zarf connect git"},
+				listedCredential{"git-readonly", git.PullUsername, git.PullPassword, "zarf connect git"},
+			)
+		}
+		if deployed["logging"] {
+			credentials = append(credentials, listedCredential{"logging", "zarf-admin", state.LoggingSecret, "zarf connect logging"})
+		}
+
+		var listed []listedCredential
+		for _, credential := range credentials {
+			if application == "" || credential.Application == application {
+				listed = append(listed, credential)
+			}
+		}
+		if len(listed) == 0 {
+			message.Fatalf(nil, "Zarf doesn't manage credentials for %s in this cluster", application)
+		}
+
+		if getCredsOutputFormat == "json" {
+			output, err := json.MarshalIndent(listed, "", "  ")
+			if err != nil {
+				message.Fatalf(err, "Unable to format the credentials as JSON")
+			}
+			fmt.Println(string(output))
+			return
+		}
+
+		credentialTable := pterm.TableData{{"     Application", "Username", "Password", "Connect"}}
+		for _, credential := range listed {
+			credentialTable = append(credentialTable, []string{"     " + credential.Application, credential.Username, credential.Password, credential.Connect})
+		}
+		_ = pterm.DefaultTable.WithHasHeader().WithData(credentialTable).Render()
+	},
+}
+
+var registryPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Removes the images no deployed package uses from the Zarf registry",
+	Long: "Deletes the tags in the Zarf registry that none of the images recorded with the deployed packages point to, " +
+		"then runs the registry garbage collector to free the storage of their layers. Images pushed outside of a package deploy are deleted too, " +
+		"so review the list with --dry-run first and don't prune while packages are being deployed. " +
+		"The registry restarts in read-only mode while the garbage collector runs, so it keeps serving pulls but rejects pushes until it restarts again.",
+	Example: "  zarf tools registry prune --dry-run\n" +
+		"  zarf tools registry prune --confirm",
+	Run: func(cmd *cobra.Command, args []string) {
+		if !registryPruneConfirm && !registryPruneDryRun {
+			message.Fatal(nil, "Pass --confirm to prune the Zarf registry, or --dry-run to list what would be pruned")
+		}
+
+		if err := packager.PruneRegistry(registryPruneDryRun); err != nil {
+			message.Fatalf(err, "Unable to prune the Zarf registry: %s", err.Error())
+		}
+	},
+}
+
 var k9sCmd = &cobra.Command{
 	Use:     "monitor",
 	Aliases: []string{"m", "k9s"},
@@ -119,9 +413,30 @@ var generatePKICmd = &cobra.Command{
 	Use:     "gen-pki {HOST}",
 	Aliases: []string{"pki"},
 	Short:   "Generates a Certificate Authority and PKI chain of trust for the given host",
-	Args:    cobra.ExactArgs(1),
+	Long: "Generates a Certificate Authority and PKI chain of trust for the given host.\n" +
+		"With --ca-cert and --ca-key the certificate is signed by a new intermediate CA under that CA instead of a new root CA, " +
+		"tls.crt then holds the certificate followed by the intermediate and tls.ca holds the given CA.",
+	Example: "  zarf tools gen-pki registry.example.com --ecdsa-curve P384 --validity 2160h --org \"Example Org\"\n" +
+		"  zarf tools gen-pki registry.example.com --ca-cert ca.crt --ca-key ca.key --rsa-bits 4096",
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		pki := pki.GeneratePKI(args[0], subAltNames...)
+		if (pkiCACertPath == "") != (pkiCAKeyPath == "") {
+			message.Fatalf(nil, "The 'ca-cert' and 'ca-key' flags must be given together")
+		}
+		if pkiCACertPath != "" {
+			var err error
+			if pkiOptions.CACert, err = os.ReadFile(pkiCACertPath); err != nil {
+				message.Fatalf(err, "Unable to read the CA certificate %s: %s", pkiCACertPath, err.Error())
+			}
+			if pkiOptions.CAKey, err = os.ReadFile(pkiCAKeyPath); err != nil {
+				message.Fatalf(err, "Unable to read the CA key %s: %s", pkiCAKeyPath, err.Error())
+			}
+		}
+
+		pki, err := pki.GeneratePKIWithOptions(args[0], pkiOptions, subAltNames...)
+		if err != nil {
+			message.Fatalf(err, "Unable to generate the PKI for %s: %s", args[0], err.Error())
+		}
 		if err := os.WriteFile("tls.ca", pki.CA, 0644); err != nil {
 			message.Fatalf(err, "Failed to write the CA file: %s", err.Error())
 		}
@@ -135,32 +450,309 @@ var generatePKICmd = &cobra.Command{
 	},
 }
 
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Removes the temporary resources interrupted Zarf runs left in the cluster",
+	Long: "Removes the injector pods, seed configmaps, services, test claims, helm test pods and script service accounts that Zarf labels with the ID of the run that created them.\n" +
+		"Deployments clean up after themselves, this is for runs that were interrupted. Without --run only the resources of runs that haven't created anything for --stale-after are removed, so runs still in progress keep theirs.",
+	Run: func(cmd *cobra.Command, args []string) {
+		spinner := message.NewProgressSpinner("Removing temporary Zarf resources")
+		defer spinner.Stop()
+
+		runs, err := k8s.DeleteRunResources(gcRunID, gcStaleAfter)
+		if err != nil {
+			spinner.Fatalf(err, "Unable to remove the temporary Zarf resources")
+		}
+
+		if len(runs) == 0 {
+			spinner.Successf("No temporary Zarf resources found")
+			return
+		}
+		spinner.Successf("Removed the temporary resources of the runs %s", strings.Join(runs, ", "))
+	},
+}
+
+var rotateAgentCertsCmd = &cobra.Command{
+	Use:   "rotate-agent-certs",
+	Short: "Generates and rolls out a new TLS certificate for the Zarf agent",
+	Long: "Generates a new CA and certificate for the Zarf agent, updates the agent-hook-tls secret and the webhook CA bundle.\n" +
+		"The agent rotates its certificate automatically before it expires, this command is a manual fallback.",
+	Run: func(cmd *cobra.Command, args []string) {
+		spinner := message.NewProgressSpinner("Rotating the Zarf agent TLS certificate")
+		defer spinner.Stop()
+
+		if err := k8s.RotateAgentTLS(); err != nil {
+			spinner.Fatalf(err, "Unable to rotate the Zarf agent TLS certificate")
+		}
+
+		spinner.Success()
+	},
+}
+
+var agentModeCmd = &cobra.Command{
+	Use:   "agent-mode {all|opt-in}",
+	Short: "Switches the Zarf agent between mutating every namespace and only the opted in ones",
+	Long: "Saves the mode to the Zarf state and updates the namespace selector of the agent webhook.\n\n" +
+		"In opt-in mode the agent only mutates resources in the namespaces labeled zarf.dev/agent=enabled. " +
+		"Zarf adds the label to the namespaces it manages, including the ones it already deployed to, and to the namespaces of later deployments. " +
+		"Other namespaces opt in with 'kubectl label namespace NAME zarf.dev/agent=enabled'.",
+	Example: "  zarf tools agent-mode opt-in\n" +
+		"  zarf tools agent-mode all",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{k8s.AgentModeAll, k8s.AgentModeOptIn},
+	Run: func(cmd *cobra.Command, args []string) {
+		mode := args[0]
+		if mode != k8s.AgentModeAll && mode != k8s.AgentModeOptIn {
+			message.Fatalf(nil, "The mode must be either '%s' or '%s'", k8s.AgentModeAll, k8s.AgentModeOptIn)
+		}
+
+		spinner := message.NewProgressSpinner("Switching the Zarf agent to the %s mode", mode)
+		defer spinner.Stop()
+
+		state, err := k8s.LoadZarfState()
+		if err != nil || state.Distro == "" {
+			spinner.Fatalf(err, "Unable to load the zarf/zarf-state secret, did you remember to run zarf init first?")
+		}
+
+		// Label the managed namespaces before the webhook requires it so none of them is skipped in between
+		if mode == k8s.AgentModeOptIn {
+			spinner.Updatef("Enabling the Zarf agent in the namespaces Zarf manages")
+			if err := k8s.EnableAgentInManagedNamespaces(); err != nil {
+				spinner.Fatalf(err, "Unable to enable the Zarf agent in the namespaces Zarf manages: %s", err.Error())
+			}
+		}
+
+		if err := k8s.SetMutatingWebhookMode(config.ZarfAgentWebhookName, mode); err != nil {
+			spinner.Fatalf(err, "Unable to update the Zarf agent webhook: %s", err.Error())
+		}
+
+		state.AgentWebhook.Mode = mode
+		if err := k8s.SaveZarfState(state); err != nil {
+			spinner.Fatalf(err, "Unable to save the Zarf state: %s", err.Error())
+		}
+
+		spinner.Successf("The Zarf agent is in the %s mode", mode)
+	},
+}
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Backs up, restores and encrypts the Zarf state of a cluster",
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Writes the Zarf state and deployed package secrets to an encrypted file",
+	Long: "Writes the zarf/zarf-state secret, including credentials kept in a state store, and the secrets of the deployed packages to a file " +
+		"encrypted with a passphrase. The passphrase is read from " + statePassphraseEnv + " or prompted for.\n\n" +
+		"When the state is encrypted with 'zarf tools state encrypt' or 'zarf init --state-encryption', the backup records which key it was and " +
+		"the sensitive exports of the packages stay encrypted with it. The key is not part of the backup, keep it with the backup to import it.\n\n" +
+		"The images and repositories in the Zarf registry and git server are not part of the backup.",
+	Example: "  zarf tools state export --output zarf-state-backup.enc",
+	Run: func(cmd *cobra.Command, args []string) {
+		spinner := message.NewProgressSpinner("Exporting the Zarf state")
+		defer spinner.Stop()
+
+		backup, err := k8s.ExportZarfState()
+		if err != nil {
+			spinner.Fatalf(err, "Unable to export the Zarf state: %s", err.Error())
+		}
+		spinner.Success()
+
+		data, err := json.Marshal(backup)
+		if err != nil {
+			message.Fatal(err, "Unable to encode the Zarf state")
+		}
+		encrypted, err := utils.EncryptWithPassphrase(data, getStatePassphrase(true))
+		if err != nil {
+			message.Fatal(err, "Unable to encrypt the Zarf state")
+		}
+
+		// The backup holds every credential Zarf manages so only the current user can read it
+		if err := os.WriteFile(stateExportOutput, encrypted, 0600); err != nil {
+			message.Fatalf(err, "Unable to write the backup to %s", stateExportOutput)
+		}
+
+		message.SuccessF("Exported the Zarf state and %d package secrets to %s", len(backup.Secrets), stateExportOutput)
+		if info := backup.State.Encryption; info.Provider != "" {
+			message.Notef("The backup needs the %s key %s to be imported, it is not part of the backup", info.Provider, info.Key)
+		}
+	},
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import {FILE}",
+	Short: "Restores the Zarf state and deployed package secrets from a file written by zarf tools state export",
+	Long: "Replaces the zarf/zarf-state secret and the deployed package secrets of the cluster with the ones in a backup. " +
+		"Credentials are saved to the state store of the backup if it has one. A backup of an encrypted state needs the key it was encrypted with, " +
+		"set " + k8s.StateKeyFileEnv + " when the key file is at a different path than when it was exported.\n\n" +
+		"The images and repositories in the Zarf registry and git server are not restored, run 'zarf init' after the import to bring the " +
+		"Zarf components back up with the credentials of the backup and then redeploy your packages.",
+	Example: "  zarf tools state import zarf-state-backup.enc --confirm",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		encrypted, err := os.ReadFile(args[0])
+		if err != nil {
+			message.Fatalf(err, "Unable to read the backup %s", args[0])
+		}
+		data, err := utils.DecryptWithPassphrase(encrypted, getStatePassphrase(false))
+		if err != nil {
+			message.Fatalf(err, "Unable to decrypt the backup %s: %s", args[0], err.Error())
+		}
+
+		var backup types.ZarfStateBackup
+		if err := json.Unmarshal(data, &backup); err != nil {
+			message.Fatalf(err, "Unable to read the backup %s", args[0])
+		}
+
+		message.Notef("Restoring the %s state exported by Zarf %s at %s with %d package secrets",
+			backup.State.Distro, backup.CLIVersion, backup.ExportedAt, len(backup.Secrets))
+		if !stateImportConfirm {
+			var confirm bool
+			prompt := &survey.Confirm{
+				Message: "Replace the Zarf state of this cluster?",
+			}
+			if err := survey.AskOne(prompt, &confirm); err != nil || !confirm {
+				message.Fatalf(nil, "State import cancelled")
+			}
+		}
+
+		spinner := message.NewProgressSpinner("Importing the Zarf state")
+		defer spinner.Stop()
+
+		if err := k8s.ImportZarfState(backup); err != nil {
+			spinner.Fatalf(err, "Unable to import the Zarf state: %s", err.Error())
+		}
+		spinner.Success()
+
+		message.Note("Run 'zarf init' to bring the Zarf components back up with the restored credentials, then redeploy your packages")
+	},
+}
+
+var stateEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypts the credentials of the Zarf state and the sensitive exports of deployed packages",
+	Long: "Encrypts the credentials in the zarf/zarf-state secret and the values of sensitive exports in the deployed package secrets of an existing cluster. " +
+		"The values are encrypted with a data key, which is itself encrypted with the key given here and kept in the state.\n\n" +
+		"Running it on a state that is already encrypted switches the data key over to the new key, so the key can be rotated without re-encrypting the values. " +
+		"Zarf needs the key every time it reads the state, set " + k8s.StateKeyFileEnv + " on hosts where the key file is at a different path.",
+	Example: "  zarf tools state encrypt --provider key-file --key ~/.zarf-state.key\n" +
+		"  VAULT_TOKEN=... zarf tools state encrypt --provider vault-transit --key https://vault.example.com/v1/transit/keys/zarf",
+	Run: func(cmd *cobra.Command, args []string) {
+		if stateEncryption.Provider == "" {
+			message.Fatalf(nil, "The --provider flag is required")
+		}
+		if err := validateStateEncryption(&stateEncryption, "provider", "key"); err != nil {
+			message.Fatalf(err, "Invalid state encryption: %s", err.Error())
+		}
+
+		spinner := message.NewProgressSpinner("Encrypting the Zarf state with the %s key", stateEncryption.Provider)
+		defer spinner.Stop()
+
+		state, err := k8s.LoadZarfState()
+		if err != nil || state.Distro == "" {
+			spinner.Fatalf(err, "Unable to load the zarf/zarf-state secret, did you remember to run zarf init first?")
+		}
+		if err := k8s.SetStateEncryption(&state, stateEncryption); err != nil {
+			spinner.Fatalf(err, "Unable to encrypt the Zarf state: %s", err.Error())
+		}
+		if err := k8s.SaveZarfState(state); err != nil {
+			spinner.Fatalf(err, "Unable to save the Zarf state: %s", err.Error())
+		}
+
+		spinner.Updatef("Encrypting the sensitive exports of the deployed packages")
+		if err := k8s.EncryptDeployedPackageSecrets(); err != nil {
+			spinner.Fatalf(err, "Unable to encrypt the deployed package secrets: %s", err.Error())
+		}
+
+		spinner.Successf("Encrypted the Zarf state with the %s key %s", stateEncryption.Provider, stateEncryption.Key)
+	},
+}
+
+// getStatePassphrase reads the passphrase of a state backup from the environment or a prompt, which asks twice when it is for a new backup
+func getStatePassphrase(confirm bool) string {
+	if passphrase := os.Getenv(statePassphraseEnv); passphrase != "" {
+		return passphrase
+	}
+
+	var passphrase string
+	if err := survey.AskOne(&survey.Password{Message: "Passphrase for the backup:"}, &passphrase, survey.WithValidator(survey.Required)); err != nil {
+		message.Fatalf(nil, "A passphrase is required, set %s or enter one at the prompt", statePassphraseEnv)
+	}
+	if confirm {
+		var again string
+		if err := survey.AskOne(&survey.Password{Message: "Confirm the passphrase:"}, &again); err != nil || again != passphrase {
+			message.Fatalf(nil, "The passphrases don't match")
+		}
+	}
+	return passphrase
+}
+
 func init() {
+	initViper()
+
 	rootCmd.AddCommand(toolsCmd)
 	toolsCmd.AddCommand(archiverCmd)
 	toolsCmd.AddCommand(readCredsCmd)
+	toolsCmd.AddCommand(getCredsCmd)
+	getCredsCmd.Flags().StringVarP(&getCredsOutputFormat, "output", "o", "table", "Output format for the credentials: table or json")
 	toolsCmd.AddCommand(k9sCmd)
 	toolsCmd.AddCommand(registryCmd)
 
 	toolsCmd.AddCommand(clearCacheCmd)
 	clearCacheCmd.Flags().StringVar(&config.CommonOptions.CachePath, "zarf-cache", config.ZarfDefaultCachePath, "Specify the location of the Zarf  artifact cache (images and git repositories)")
 
+	toolsCmd.AddCommand(rotateAgentCertsCmd)
+	toolsCmd.AddCommand(agentModeCmd)
+
+	toolsCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateExportCmd.Flags().StringVarP(&stateExportOutput, "output", "o", "zarf-state-backup.enc", "Path of the encrypted file to write")
+	stateCmd.AddCommand(stateImportCmd)
+	stateImportCmd.Flags().BoolVar(&stateImportConfirm, "confirm", false, "Replace the Zarf state without prompting")
+	stateCmd.AddCommand(stateEncryptCmd)
+	stateEncryptCmd.Flags().StringVar(&stateEncryption.Provider, "provider", "", "REQUIRED. Kind of key to encrypt with. Valid options are: key-file, vault-transit")
+	stateEncryptCmd.Flags().StringVar(&stateEncryption.Key, "key", "", "Path of the key file, created if missing, or URL of the Vault transit key to encrypt with")
+
+	toolsCmd.AddCommand(gcCmd)
+	gcCmd.Flags().StringVar(&gcRunID, "run", "", "Only remove the resources of the run with this ID, whether or not it is stale")
+	gcCmd.Flags().DurationVar(&gcStaleAfter, "stale-after", time.Hour, "How long a run must not have created anything before its resources are removed without --run")
+
+	toolsCmd.AddCommand(catalogCmd)
+	v.SetDefault(V_PKG_DEPLOY_BIN_DIR, config.ZarfDefaultBinDir)
+	catalogCmd.Flags().StringVar(&catalogBinDir, "bin-dir", v.GetString(V_PKG_DEPLOY_BIN_DIR), "Directory on the host that binaries were installed into")
+	catalogCmd.Flags().StringVar(&catalogPackage, "package", "", "Only list binaries installed by this package")
+
 	toolsCmd.AddCommand(generatePKICmd)
 	generatePKICmd.Flags().StringArrayVar(&subAltNames, "sub-alt-name", []string{}, "Specify Subject Alternative Names for the certificate")
+	generatePKICmd.Flags().IntVar(&pkiOptions.RSABits, "rsa-bits", pkiOptions.RSABits, "Size of the RSA keys, at least 2048")
+	generatePKICmd.Flags().StringVar(&pkiOptions.ECDSACurve, "ecdsa-curve", "", "Create ECDSA keys on this curve instead of RSA keys. Valid options are: P256, P384, P521")
+	generatePKICmd.Flags().DurationVar(&pkiOptions.ValidFor, "validity", pkiOptions.ValidFor, "How long the certificates are valid, such as 2160h. A certificate is never valid for longer than the CA that signs it")
+	generatePKICmd.Flags().StringVar(&pkiOptions.Organization, "org", "", "Organization of the CA and certificate (default \"Zarf Community\" for the CA and \"Zarf Cluster\" for the certificate)")
+	generatePKICmd.Flags().StringVar(&pkiOptions.CommonName, "common-name", "", "Common name of the certificate (default the host)")
+	generatePKICmd.Flags().StringVar(&pkiCACertPath, "ca-cert", "", "PEM encoded CA certificate that signs an intermediate CA instead of creating a new root CA, requires --ca-key")
+	generatePKICmd.Flags().StringVar(&pkiCAKeyPath, "ca-key", "", "PEM encoded private key of the --ca-cert")
 
 	archiverCmd.AddCommand(archiverCompressCmd)
 	archiverCmd.AddCommand(archiverDecompressCmd)
 
 	cranePlatformOptions := config.GetCraneOptions()
 
-	craneLogin := craneCmd.NewCmdAuthLogin()
-	craneLogin.Example = ""
-
-	registryCmd.AddCommand(craneLogin)
+	registryCmd.AddCommand(registryLoginCmd)
+	registryLoginCmd.Flags().StringVarP(&registryLoginUsername, "username", "u", "", "Username")
+	registryLoginCmd.Flags().StringVarP(&registryLoginPassword, "password", "p", "", "Password")
+	registryLoginCmd.Flags().BoolVar(&registryLoginPasswordStdin, "password-stdin", false, "Take the password from stdin")
+	registryCmd.AddCommand(registryLogoutCmd)
+	registryCmd.AddCommand(registryListLoginsCmd)
+	registryCmd.AddCommand(registryPruneCmd)
+	registryPruneCmd.Flags().BoolVar(&registryPruneConfirm, "confirm", false, "REQUIRED unless --dry-run is given. Confirm pruning the images to prevent accidental deletions")
+	registryPruneCmd.Flags().BoolVar(&registryPruneDryRun, "dry-run", false, "Only list the images that would be pruned")
 	registryCmd.AddCommand(craneCmd.NewCmdPull(&cranePlatformOptions))
 	registryCmd.AddCommand(craneCmd.NewCmdPush(&cranePlatformOptions))
 	registryCmd.AddCommand(craneCmd.NewCmdCopy(&cranePlatformOptions))
 	registryCmd.AddCommand(craneCmd.NewCmdCatalog(&cranePlatformOptions))
+	registryCmd.AddCommand(registryCopyFromPackageCmd)
+	registryCopyFromPackageCmd.Flags().BoolVar(&insecureCopy, "insecure", false, "Allow insecure connections to the destination registry")
 
 	syftCmd, err := cli.New()
 	if err != nil {
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/spf13/viper"
@@ -10,15 +13,30 @@ import (
 const (
 	// Root config keys
 	V_LOG_LEVEL    = "log_level"
+	V_LOG_FORMAT   = "log_format"
 	V_ARCHITECTURE = "architecture"
 	V_NO_LOG_FILE  = "no_log_file"
 	V_NO_PROGRESS  = "no_progress"
 	V_ZARF_CACHE   = "zarf_cache"
 	V_TMP_DIR      = "tmp_dir"
+	V_SITE         = "site"
+
+	V_NO_PROXY_CLUSTER = "no_proxy_cluster"
+
+	// Self-update config keys
+	V_SELF_UPDATE_KEY = "self_update.key"
+
+	// Fleet site config keys
+	V_SITES         = "sites"
+	V_SITES_EXTENDS = "extends"
 
 	// Init config keys
-	V_INIT_COMPONENTS    = "init.components"
-	V_INIT_STORAGE_CLASS = "init.storage_class"
+	V_INIT_COMPONENTS             = "init.components"
+	V_INIT_STORAGE_CLASS          = "init.storage_class"
+	V_INIT_STORAGE_CLASS_CHECK    = "init.storage_class_check"
+	V_INIT_POD_SECURITY_EXEMPTION = "init.pod_security_exemption"
+	V_INIT_RESULT_FILE            = "init.result_file"
+	V_INIT_CA_FILE                = "init.ca_file"
 
 	// Init Git config keys
 	V_INIT_GIT_URL       = "init.git.url"
@@ -26,28 +44,116 @@ const (
 	V_INIT_GIT_PUSH_PASS = "init.git.push_password"
 	V_INIT_GIT_PULL_USER = "init.git.pull_username"
 	V_INIT_GIT_PULL_PASS = "init.git.pull_password"
+	V_INIT_GIT_PROVIDER  = "init.git.provider"
+	V_INIT_GIT_CA_FILE   = "init.git.ca_file"
+	V_INIT_GIT_INSECURE  = "init.git.insecure_skip_verify"
+	V_INIT_GIT_SVC_TYPE  = "init.git.service_type"
+	V_INIT_GIT_NODEPORT  = "init.git.nodeport"
+	V_INIT_GIT_INGRESS   = "init.git.ingress_host"
+	V_INIT_GIT_TLS_CERT  = "init.git.tls_cert"
+	V_INIT_GIT_TLS_KEY   = "init.git.tls_key"
 
 	// Init Registry config keys
-	V_INIT_REGISTRY_URL       = "init.registry.url"
-	V_INIT_REGISTRY_NODEPORT  = "init.registry.nodeport"
-	V_INIT_REGISTRY_SECRET    = "init.registry.secret"
-	V_INIT_REGISTRY_PUSH_USER = "init.registry.push_username"
-	V_INIT_REGISTRY_PUSH_PASS = "init.registry.push_password"
-	V_INIT_REGISTRY_PULL_USER = "init.registry.pull_username"
-	V_INIT_REGISTRY_PULL_PASS = "init.registry.pull_password"
+	V_INIT_REGISTRY_URL         = "init.registry.url"
+	V_INIT_REGISTRY_NODEPORT    = "init.registry.nodeport"
+	V_INIT_REGISTRY_SVC_TYPE    = "init.registry.service_type"
+	V_INIT_REGISTRY_INGRESS     = "init.registry.ingress_host"
+	V_INIT_REGISTRY_SECRET      = "init.registry.secret"
+	V_INIT_REGISTRY_PUSH_USER   = "init.registry.push_username"
+	V_INIT_REGISTRY_PUSH_PASS   = "init.registry.push_password"
+	V_INIT_REGISTRY_PUSH_ID     = "init.registry.push_identity"
+	V_INIT_REGISTRY_CRED_HELPER = "init.registry.credential_helper"
+	V_INIT_REGISTRY_PULL_USER   = "init.registry.pull_username"
+	V_INIT_REGISTRY_PULL_PASS   = "init.registry.pull_password"
+	V_INIT_REGISTRY_CA_FILE     = "init.registry.ca_file"
+	V_INIT_REGISTRY_INSECURE    = "init.registry.insecure_skip_verify"
+	V_INIT_REGISTRY_SERVICE     = "init.registry.service"
+	V_INIT_REGISTRY_CREDENTIALS = "init.registry.credentials_secret"
+	V_INIT_REGISTRY_PUSH_PATH   = "init.registry.push_path"
+	V_INIT_REGISTRY_PROJECT     = "init.registry.project"
+	V_INIT_REGISTRY_MANIFEST    = "init.registry.manifest_format"
+	V_INIT_REGISTRY_FOREIGN     = "init.registry.push_foreign_layers"
+	V_INIT_REGISTRY_PROXY_URL   = "init.registry.proxy_url"
+	V_INIT_REGISTRY_PROXY_USER  = "init.registry.proxy_username"
+	V_INIT_REGISTRY_PROXY_PASS  = "init.registry.proxy_password"
+	V_INIT_REGISTRY_PVC_SIZE    = "init.registry.pvc_size"
+	V_INIT_REGISTRY_PVC_ACCESS  = "init.registry.pvc_access_mode"
+	V_INIT_REGISTRY_STORAGE     = "init.registry.storage_class"
+	V_INIT_REGISTRY_REPLICAS    = "init.registry.replicas"
+	V_INIT_REGISTRY_MAX_REPLICA = "init.registry.max_replicas"
+	V_INIT_REGISTRY_CPU_REQ     = "init.registry.cpu_request"
+	V_INIT_REGISTRY_MEM_REQ     = "init.registry.memory_request"
+	V_INIT_REGISTRY_CPU_LIMIT   = "init.registry.cpu_limit"
+	V_INIT_REGISTRY_MEM_LIMIT   = "init.registry.memory_limit"
+	V_INIT_REGISTRY_S3_ENDPOINT = "init.registry.s3.endpoint"
+	V_INIT_REGISTRY_S3_REGION   = "init.registry.s3.region"
+	V_INIT_REGISTRY_S3_BUCKET   = "init.registry.s3.bucket"
+	V_INIT_REGISTRY_S3_ACCESS   = "init.registry.s3.access_key"
+	V_INIT_REGISTRY_S3_SECRET   = "init.registry.s3.secret_key"
+	V_INIT_REGISTRY_S3_CA_FILE  = "init.registry.s3.ca_file"
+	V_INIT_REGISTRY_TLS_CERT    = "init.registry.tls_cert"
+	V_INIT_REGISTRY_TLS_KEY     = "init.registry.tls_key"
+
+	// Init Agent config keys
+	V_INIT_AGENT_FAILURE_POLICY     = "init.agent.failure_policy"
+	V_INIT_AGENT_TIMEOUT            = "init.agent.timeout"
+	V_INIT_AGENT_NAMESPACE_SELECTOR = "init.agent.namespace_selector"
+	V_INIT_AGENT_IGNORED_NAMESPACES = "init.agent.ignored_namespaces"
+	V_INIT_AGENT_MODE               = "init.agent.mode"
+	V_INIT_AGENT_REPLICAS           = "init.agent.replicas"
+
+	// Init state store config keys
+	V_INIT_STATE_STORE        = "init.state_store.type"
+	V_INIT_STATE_STORE_URL    = "init.state_store.url"
+	V_INIT_STATE_STORE_SECRET = "init.state_store.secret"
+
+	// Init state encryption config keys
+	V_INIT_STATE_ENCRYPTION     = "init.state_encryption.provider"
+	V_INIT_STATE_ENCRYPTION_KEY = "init.state_encryption.key"
 
 	// Package create config keys
-	V_PKG_CREATE_SET        = "package.create.set"
-	V_PKG_CREATE_OUTPUT_DIR = "package.create.output_directory"
-	V_PKG_CREATE_SKIP_SBOM  = "package.create.skip_sbom"
-	V_PKG_CREATE_INSECURE   = "package.create.insecure"
+	V_PKG_CREATE_SET                = "package.create.set"
+	V_PKG_CREATE_OUTPUT_DIR         = "package.create.output_directory"
+	V_PKG_CREATE_SKIP_SBOM          = "package.create.skip_sbom"
+	V_PKG_CREATE_INSECURE           = "package.create.insecure"
+	V_PKG_CREATE_IMAGE_SIZE_WARNING = "package.create.image_size_warning"
+	V_PKG_CREATE_DIFFERENTIAL       = "package.create.differential"
+	V_PKG_CREATE_MAX_PACKAGE_SIZE   = "package.create.max_package_size"
+	V_PKG_CREATE_COMPRESSION        = "package.create.compression"
+	V_PKG_CREATE_COMPRESSION_LEVEL  = "package.create.compression_level"
+	V_PKG_CREATE_HELM_REPOSITORIES  = "package.create.helm_repositories"
+	V_PKG_CREATE_IMAGE_POLICY       = "package.create.image_policy"
+	V_PKG_CREATE_PIN_DIGESTS        = "package.create.pin_digests"
+	V_PKG_CREATE_INCLUDE_SIGNATURES = "package.create.include_signatures"
+	V_PKG_CREATE_SIGNATURE_KEYS     = "package.create.signature_keys"
+	V_PKG_CREATE_MULTI_ARCH         = "package.create.multi_arch"
+	V_PKG_CREATE_INTOTO_LINKS       = "package.create.intoto_links"
 
 	// Package deploy config keys
-	V_PKG_DEPLOY_SET        = "package.deploy.set"
-	V_PKG_DEPLOY_COMPONENTS = "package.deploy.components"
-	V_PKG_DEPLOY_INSECURE   = "package.deploy.insecure"
-	V_PKG_DEPLOY_SHASUM     = "package.deploy.shasum"
-	V_PKG_DEPLOY_SGET       = "package.deploy.sget"
+	V_PKG_DEPLOY_SET                = "package.deploy.set"
+	V_PKG_DEPLOY_COMPONENTS         = "package.deploy.components"
+	V_PKG_DEPLOY_PROFILE            = "package.deploy.profile"
+	V_PKG_DEPLOY_INSECURE           = "package.deploy.insecure"
+	V_PKG_DEPLOY_OCI_INSECURE       = "package.deploy.oci_insecure"
+	V_PKG_DEPLOY_SHASUM             = "package.deploy.shasum"
+	V_PKG_DEPLOY_SGET               = "package.deploy.sget"
+	V_PKG_DEPLOY_IMAGE_SIZE_WARNING = "package.deploy.image_size_warning"
+	V_PKG_DEPLOY_RESUME             = "package.deploy.resume"
+	V_PKG_DEPLOY_BIN_DIR            = "package.deploy.bin_dir"
+	V_PKG_DEPLOY_RETRIES            = "package.deploy.retries"
+	V_PKG_DEPLOY_TIMEOUT            = "package.deploy.timeout"
+	V_PKG_DEPLOY_DEADLINE           = "package.deploy.deadline"
+	V_PKG_DEPLOY_ADOPT              = "package.deploy.adopt_existing_resources"
+	V_PKG_DEPLOY_ATOMIC_CHARTS      = "package.deploy.atomic_charts"
+	V_PKG_DEPLOY_KEEP_FAILED_CHARTS = "package.deploy.keep_failed_charts"
+	V_PKG_DEPLOY_GIT_FORCE          = "package.deploy.git_force"
+	V_PKG_DEPLOY_GIT_CHUNK_SIZE     = "package.deploy.git_chunk_size"
+	V_PKG_DEPLOY_IMAGE_POLICY       = "package.deploy.image_policy"
+	V_PKG_DEPLOY_OCI_CONCURRENCY    = "package.deploy.oci_concurrency"
+	V_PKG_DEPLOY_PRE_PULL_SIZE      = "package.deploy.pre_pull_size"
+	V_PKG_DEPLOY_RESULT_FILE        = "package.deploy.result_file"
+	V_PKG_DEPLOY_INTOTO_LAYOUT      = "package.deploy.intoto_layout"
+	V_PKG_DEPLOY_INTOTO_LAYOUT_KEYS = "package.deploy.intoto_layout_keys"
 )
 
 func initViper() {
@@ -71,12 +177,18 @@ func initViper() {
 		v.SetConfigName("zarf-config")
 	}
 
+	// E.g. ZARF_LOG_LEVEL=debug or ZARF_INIT_STORAGE_CLASS=local-path
 	v.SetEnvPrefix("zarf")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
-	// E.g. ZARF_LOG_LEVEL=debug
-	v.SetEnvPrefix("zarf")
-	v.AutomaticEnv()
+	// Keep supporting the older dotted form of nested keys, e.g. ZARF_INIT.STORAGE_CLASS=local-path
+	for _, env := range os.Environ() {
+		name := strings.SplitN(env, "=", 2)[0]
+		if strings.HasPrefix(name, "ZARF_") && strings.Contains(name, ".") {
+			_ = v.BindEnv(strings.ToLower(strings.TrimPrefix(name, "ZARF_")), name)
+		}
+	}
 
 	// Optional, so ignore errors
 	err := v.ReadInConfig()
@@ -89,4 +201,59 @@ func initViper() {
 	} else {
 		message.Notef("Using config file %s", v.ConfigFileUsed())
 	}
+
+	// Layer the selected site over the shared defaults before any flag defaults are read
+	if site := getSelectedSite(); site != "" {
+		applySiteOverlay(site)
+	}
+}
+
+// getSelectedSite finds the --site flag (or ZARF_SITE) early, since flag defaults are read from viper before cobra parses flags
+func getSelectedSite() string {
+	args := os.Args[1:]
+	for idx, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "--site=") {
+			return strings.TrimPrefix(arg, "--site=")
+		}
+		if arg == "--site" && idx+1 < len(args) {
+			return args[idx+1]
+		}
+	}
+
+	return os.Getenv("ZARF_SITE")
+}
+
+// applySiteOverlay merges the config for a site, and any sites it extends, over the shared defaults in the config file
+func applySiteOverlay(site string) {
+	isValidSite := regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`).MatchString
+
+	// Walk up the extends chain so the most general site is applied first
+	var chain []string
+	seen := make(map[string]bool)
+	for name := site; name != ""; name = v.GetString(fmt.Sprintf("%s.%s.%s", V_SITES, name, V_SITES_EXTENDS)) {
+		if !isValidSite(name) {
+			message.Fatalf(nil, "Invalid site name %s, site names may only contain letters, numbers, _ and -", name)
+		}
+		if seen[name] {
+			message.Fatalf(nil, "The site %s extends itself through the site %s", site, name)
+		}
+		if !v.IsSet(fmt.Sprintf("%s.%s", V_SITES, name)) {
+			message.Fatalf(nil, "The site %s was not found under %s in the config file %s", name, V_SITES, v.ConfigFileUsed())
+		}
+		seen[name] = true
+		chain = append([]string{name}, chain...)
+	}
+
+	for _, name := range chain {
+		overlay := v.GetStringMap(fmt.Sprintf("%s.%s", V_SITES, name))
+		delete(overlay, V_SITES_EXTENDS)
+		if err := v.MergeConfigMap(overlay); err != nil {
+			message.Fatalf(err, "Unable to apply the config for the site %s", name)
+		}
+	}
+
+	message.Notef("Using the config for site %s (%s)", site, strings.Join(chain, " > "))
 }
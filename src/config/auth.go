@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// zarfKeychain resolves registry credentials from the Zarf auth file
+type zarfKeychain struct{}
+
+// GetAbsAuthPath gets the absolute path of the file zarf tools registry login stores credentials in
+func GetAbsAuthPath() string {
+	homePath, _ := os.UserHomeDir()
+	return strings.Replace(ZarfDefaultAuthPath, "~", homePath, 1)
+}
+
+// GetKeychain looks up registry credentials in the Zarf auth file first and the docker config of the host second
+func GetKeychain() authn.Keychain {
+	return authn.NewMultiKeychain(zarfKeychain{}, authn.DefaultKeychain)
+}
+
+// GetCraneKeychainOption returns the crane option that authenticates with the keychain of GetKeychain
+func GetCraneKeychainOption() crane.Option {
+	return crane.WithAuthFromKeychain(GetKeychain())
+}
+
+// LoadAuthFile reads the Zarf auth file, returning an empty one if no logins have been stored yet
+func LoadAuthFile() (*configfile.ConfigFile, error) {
+	authPath := GetAbsAuthPath()
+	authFile := configfile.New(authPath)
+
+	file, err := os.Open(authPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return authFile, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := authFile.LoadFromReader(file); err != nil {
+		return nil, fmt.Errorf("unable to read the Zarf auth file %s: %w", authPath, err)
+	}
+	return authFile, nil
+}
+
+// GetAuthKey returns the key credentials for a registry are stored under, which is the legacy index URL for Docker Hub
+func GetAuthKey(registry string) string {
+	if registry == name.DefaultRegistry {
+		return authn.DefaultAuthKey
+	}
+	return registry
+}
+
+// Resolve returns the stored credentials for the registry or anonymous access when there are none
+func (zarfKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	authFile, err := LoadAuthFile()
+	if err != nil {
+		return nil, err
+	}
+
+	auth, ok := authFile.AuthConfigs[GetAuthKey(target.RegistryStr())]
+	if !ok || (auth.Username == "" && auth.Password == "" && auth.IdentityToken == "" && auth.RegistryToken == "") {
+		return authn.Anonymous, nil
+	}
+
+	message.Debugf("Using the Zarf auth file credentials for %s", target.RegistryStr())
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		IdentityToken: auth.IdentityToken,
+		RegistryToken: auth.RegistryToken,
+	}), nil
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"crypto/rand"
 	"embed"
+	"encoding/hex"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -35,17 +39,32 @@ const (
 	ZarfGeneratedPasswordLen = 24
 	ZarfGeneratedSecretLen   = 48
 
-	ZarfAgentHost = "agent-hook.zarf.svc"
+	ZarfAgentHost          = "agent-hook.zarf.svc"
+	ZarfAgentTLSSecretName = "agent-hook-tls"
+	ZarfAgentWebhookName   = "zarf"
+	ZarfAgentReplicas      = 2
 
 	ZarfConnectLabelName             = "zarf.dev/connect-name"
 	ZarfConnectAnnotationDescription = "zarf.dev/connect-description"
 	ZarfConnectAnnotationUrl         = "zarf.dev/connect-url"
 
+	ZarfInventoryLabel = "zarf.dev/inventory"
+
+	ZarfSkipInjectionAnnotation = "zarf.dev/skipZarfInjection"
+
+	// Namespaces and resources with this label or annotation set to skip or ignore are left alone by the agent
+	ZarfAgentLabel = "zarf.dev/agent"
+
+	// Marks temporary resources with the run of Zarf that created them so they can be cleaned up
+	ZarfRunLabel = "zarf.dev/run"
+
 	ZarfManagedByLabel     = "app.kubernetes.io/managed-by"
 	ZarfCleanupScriptsPath = "/opt/zarf"
+	ZarfDefaultBinDir      = "/usr/local/bin"
 
 	ZarfImageCacheDir = "images"
 	ZarfGitCacheDir   = "repos"
+	ZarfChartCacheDir = "charts"
 
 	ZarfYAML    = "zarf.yaml"
 	ZarfSBOMDir = "zarf-sbom"
@@ -53,10 +72,44 @@ const (
 	ZarfInClusterContainerRegistryURL      = "http://zarf-registry-http.zarf.svc.cluster.local:5000"
 	ZarfInClusterContainerRegistryNodePort = 31999
 
+	// The address of the Zarf registry service, which pods reach without going through the nodes
+	ZarfInClusterRegistryHost = "zarf-docker-registry.zarf.svc.cluster.local:5000"
+
+	// The size and resources of the Zarf registry unless zarf init is told otherwise
+	ZarfRegistryPVCSize       = "20Gi"
+	ZarfRegistryPVCAccessMode = "ReadWriteOnce"
+	ZarfRegistryCPURequest    = "100m"
+	ZarfRegistryMemoryRequest = "256Mi"
+	ZarfRegistryCPULimit      = "3"
+	ZarfRegistryMemoryLimit   = "2Gi"
+
+	// The registry needs a region even when the object store it keeps its images in has none
+	ZarfRegistryS3Region = "us-east-1"
+	// The secret the registry reads the CA of its object store from, when zarf init is given one
+	ZarfRegistryS3CASecretName = "zarf-registry-s3-ca"
+	// The registry reads the credentials of the registry it is a pull-through cache of from this secret, so they stay out of its helm values
+	ZarfRegistryProxySecretName = "zarf-registry-proxy"
+	// The secrets the registry and git server ingress are served from, when zarf init is given certificates for them
+	ZarfRegistryTLSSecretName = "zarf-registry-tls"
+	ZarfGitTLSSecretName      = "zarf-git-tls"
+
 	ZarfInClusterGitServiceURL = "http://zarf-gitea-http.zarf.svc.cluster.local:3000"
 
 	ZarfSeedImage = "registry"
 	ZarfSeedTag   = "2.8.1"
+
+	ZarfCompressionZstd = "zstd"
+	ZarfCompressionGzip = "gzip"
+	ZarfCompressionNone = "none"
+
+	// DefaultOCIConcurrency is how many images, and layers of each image, are pushed at once
+	DefaultOCIConcurrency = 3
+
+	// DefaultGitChunkSizeMB is the size of the repos whose history is pushed in chunks, and roughly how much each chunk sends
+	DefaultGitChunkSizeMB = 512
+
+	// MultiArch is the architecture of packages that carry the images and binaries of every architecture in MultiArchitectures
+	MultiArch = "multi"
 )
 
 var (
@@ -78,6 +131,9 @@ var (
 	// CliArch is the computer architecture of the device executing the CLI commands
 	CliArch string
 
+	// MultiArchitectures are the architectures a multi-arch package is built for, deploy picks the one the cluster runs
+	MultiArchitectures = []string{"amd64", "arm64"}
+
 	// ZarfSeedPort is the NodePort Zarf uses for the 'seed registry'
 	ZarfSeedPort string
 
@@ -95,9 +151,13 @@ var (
 
 	// Timestamp of when the CLI was started
 	operationStartTime  = time.Now().Unix()
+	runID               = newRunID()
 	dataInjectionMarker = ".zarf-injection-%d"
 
 	ZarfDefaultCachePath = filepath.Join("~", ".zarf-cache")
+
+	// Registry credentials from zarf tools registry login, kept apart from the docker config of the host
+	ZarfDefaultAuthPath = filepath.Join("~", ".zarf-auth.json")
 )
 
 // Timestamp of when the CLI was started
@@ -105,6 +165,32 @@ func GetStartTime() int64 {
 	return operationStartTime
 }
 
+// GetRunID returns the ID of this run of the CLI that temporary cluster resources are labeled with
+func GetRunID() string {
+	return runID
+}
+
+// newRunID generates a random run ID, so runs started in the same second don't share their temporary resources
+func newRunID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%d", operationStartTime)
+	}
+	return hex.EncodeToString(id)
+}
+
+// GetInClusterRegistryHost returns the service host pods reach a registry running in the cluster on, or an empty string for a registry outside of it
+func GetInClusterRegistryHost(registryInfo types.RegistryInfo) string {
+	if registryInfo.InClusterExternal {
+		namespace, name, _ := strings.Cut(registryInfo.InClusterService, "/")
+		return fmt.Sprintf("%s.%s.svc.cluster.local:%d", name, namespace, registryInfo.InClusterServicePort)
+	}
+	if registryInfo.InternalRegistry {
+		return ZarfInClusterRegistryHost
+	}
+	return ""
+}
+
 func GetDataInjectionMarker() string {
 	return fmt.Sprintf(dataInjectionMarker, operationStartTime)
 }
@@ -131,6 +217,11 @@ func GetArch() string {
 	return runtime.GOARCH
 }
 
+// IsMultiArch returns whether the active package is, or is being built as, a multi-arch package
+func IsMultiArch() bool {
+	return CliArch == MultiArch || active.Metadata.Architecture == MultiArch
+}
+
 func GetCraneOptions() []crane.Option {
 	var options []crane.Option
 
@@ -139,13 +230,16 @@ func GetCraneOptions() []crane.Option {
 		options = append(options, crane.Insecure)
 	}
 
-	// Add the image platform info
-	options = append(options,
-		crane.WithPlatform(&v1.Platform{
-			OS:           "linux",
-			Architecture: GetArch(),
-		}),
-	)
+	// Add the image platform info, a multi-arch package resolves images to their index and picks the platform as each architecture is pulled
+	if GetArch() != MultiArch {
+		options = append(options,
+			crane.WithPlatform(&v1.Platform{
+				OS:           "linux",
+				Architecture: GetArch(),
+			}),
+		)
+	}
+	options = append(options, GetCraneKeychainOption())
 
 	return options
 }
@@ -158,6 +252,15 @@ func GetCraneAuthOption(username string, secret string) crane.Option {
 		}))
 }
 
+// GetClusterProxy returns how connections to the cluster and its Zarf registry and git server find their proxy
+// They use the HTTP(S)_PROXY and NO_PROXY settings of the environment unless --no-proxy-cluster sends them directly
+func GetClusterProxy() func(*http.Request) (*url.URL, error) {
+	if CommonOptions.NoProxyCluster {
+		return func(*http.Request) (*url.URL, error) { return nil, nil }
+	}
+	return http.ProxyFromEnvironment
+}
+
 func GetSeedRegistry() string {
 	return fmt.Sprintf("%s:%s", IPV4Localhost, ZarfSeedPort)
 }
@@ -171,16 +274,41 @@ func GetPackageName() string {
 		return GetInitPackageName()
 	}
 
-	if metadata.Uncompressed {
+	switch GetPackageCompression() {
+	case ZarfCompressionGzip:
+		suffix = "tar.gz"
+	case ZarfCompressionNone:
 		suffix = "tar"
 	}
 	return fmt.Sprintf("%s-%s-%s.%s", prefix, metadata.Name, GetArch(), suffix)
 }
 
+// GetPackageCompression returns the compression for the package being created, init packages are always zstd compressed
+func GetPackageCompression() string {
+	if IsZarfInitConfig() {
+		return ZarfCompressionZstd
+	}
+
+	if CreateOptions.Compression != "" {
+		return CreateOptions.Compression
+	}
+
+	if GetMetaData().Uncompressed {
+		return ZarfCompressionNone
+	}
+
+	return ZarfCompressionZstd
+}
+
 func GetInitPackageName() string {
 	return fmt.Sprintf("zarf-init-%s-%s.tar.zst", GetArch(), CLIVersion)
 }
 
+// GetMultiArchInitPackageName returns the name of the init package that can initialize a cluster of any of the MultiArchitectures
+func GetMultiArchInitPackageName() string {
+	return fmt.Sprintf("zarf-init-%s-%s.tar.zst", MultiArch, CLIVersion)
+}
+
 func GetMetaData() types.ZarfMetadata {
 	return active.Metadata
 }
@@ -209,8 +337,12 @@ func GetBuildData() types.ZarfBuildData {
 	return active.Build
 }
 
-func GetValidPackageExtensions() [3]string {
-	return [...]string{".tar.zst", ".tar", ".zip"}
+func SetBuildData(build types.ZarfBuildData) {
+	active.Build = build
+}
+
+func GetValidPackageExtensions() [4]string {
+	return [...]string{".tar.zst", ".tar.gz", ".tar", ".zip"}
 }
 
 func InitState(tmpState types.ZarfState) {
@@ -224,7 +356,8 @@ func GetState() types.ZarfState {
 
 func GetRegistry() string {
 	// If a node port is populated, then we are using a registry internal to the cluster. Ignore the provided address and use localhost
-	if state.RegistryInfo.NodePort >= 30000 {
+	// A registry Zarf didn't deploy may use a cluster with a different nodeport range
+	if state.RegistryInfo.NodePort >= 30000 || (state.RegistryInfo.InClusterExternal && state.RegistryInfo.NodePort > 0) {
 		return fmt.Sprintf("%s:%d", IPV4Localhost, state.RegistryInfo.NodePort)
 	}
 
@@ -318,8 +451,8 @@ func isCompatibleComponent(component types.ZarfComponent, filterByOS bool) bool
 
 	targetArch := GetArch()
 
-	// Test for valid architecture
-	if component.Only.Cluster.Architecture == "" || component.Only.Cluster.Architecture == targetArch {
+	// Test for valid architecture, a multi-arch package keeps the components of every architecture
+	if component.Only.Cluster.Architecture == "" || component.Only.Cluster.Architecture == targetArch || targetArch == MultiArch {
 		validArch = true
 	} else {
 		message.Debugf("Skipping component %s, %s is not compatible with %s", component.Name, component.Only.Cluster.Architecture, targetArch)
@@ -10,6 +10,24 @@ import (
 	"github.com/defenseunicorns/zarf/src/types"
 )
 
+// Where the value of a deploy variable came from
+const (
+	VariableSourceDefault    = "default"
+	VariableSourcePrompt     = "prompt"
+	VariableSourceFlag       = "flag"
+	VariableSourceConfigFile = "config-file"
+	VariableSourceProfile    = "profile"
+)
+
+// SetVariablesSource is where DeployOptions.SetVariables were read from
+var SetVariablesSource = VariableSourceFlag
+
+// SetVariableSources tracks where each value in SetVariableMap came from
+var SetVariableSources = map[string]string{}
+
+// The values exported by the components deployed so far
+var exportedValueMap = map[string]string{}
+
 // FillActiveTemplate handles setting the active variables and reloading the base template.
 func FillActiveTemplate() error {
 	packageVariables, err := utils.FindYamlTemplates(&active, "###ZARF_PKG_VAR_", "###")
@@ -39,7 +57,10 @@ func FillActiveTemplate() error {
 		}
 	}
 
-	templateMap := map[string]string{}
+	// The architecture being built for lets a package pick architecture-specific files, such as the injector binary
+	templateMap := map[string]string{
+		"###ZARF_PKG_ARCH###": GetArch(),
+	}
 	for key, value := range packageVariables {
 		// Variable keys are always uppercase in the format ###ZARF_PKG_VAR_KEY###
 		templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_PKG_VAR_%s###", key))] = *value
@@ -54,18 +75,36 @@ func SetActiveVariables() error {
 		value := DeployOptions.SetVariables[key]
 		// Ensure uppercase for VIPER
 		SetVariableMap[strings.ToUpper(key)] = value
+		SetVariableSources[strings.ToUpper(key)] = SetVariablesSource
 	}
 
+	// A profile presets the variables it lists, --set still overrides them
+	if profile, ok := GetProfile(DeployOptions.Profile); ok {
+		for key, value := range profile.Variables {
+			key = strings.ToUpper(key)
+			if _, present := SetVariableMap[key]; !present {
+				SetVariableMap[key] = value
+				SetVariableSources[key] = VariableSourceProfile
+			}
+		}
+	}
+
+	declared := make(map[string]bool)
 	for _, variable := range active.Variables {
-		_, present := SetVariableMap[variable.Name]
+		declared[variable.Name] = true
+		value, present := SetVariableMap[variable.Name]
 
 		// Variable is present, no need to continue checking
 		if present {
+			if variable.Sensitive {
+				message.AddSensitiveValue(value)
+			}
 			continue
 		}
 
 		// First set default (may be overridden by prompt)
 		SetVariableMap[variable.Name] = variable.Default
+		SetVariableSources[variable.Name] = VariableSourceDefault
 
 		// Variable is set to prompt the user
 		if variable.Prompt && !CommonOptions.Confirm {
@@ -77,12 +116,45 @@ func SetActiveVariables() error {
 			}
 
 			SetVariableMap[variable.Name] = val
+			SetVariableSources[variable.Name] = VariableSourcePrompt
+		}
+
+		if variable.Sensitive {
+			message.AddSensitiveValue(SetVariableMap[variable.Name])
+		}
+	}
+
+	// Values for variables the package doesn't declare are still templated, but are likely a typo
+	for key := range DeployOptions.SetVariables {
+		if !declared[strings.ToUpper(key)] {
+			message.Warnf("The variable %s was set but is not declared by this package", strings.ToUpper(key))
 		}
 	}
 
 	return nil
 }
 
+// GetProfile returns the profile of the active package with the given name
+func GetProfile(name string) (types.ZarfPackageProfile, bool) {
+	for _, profile := range active.Profiles {
+		if name != "" && profile.Name == name {
+			return profile, true
+		}
+	}
+	return types.ZarfPackageProfile{}, false
+}
+
+// SetExportedValue records a value a component exported for the components deployed after it
+func SetExportedValue(name string, value string) {
+	exportedValueMap[name] = value
+}
+
+// GetExportedValue returns a value exported by a component deployed earlier
+func GetExportedValue(name string) (string, bool) {
+	value, ok := exportedValueMap[name]
+	return value, ok
+}
+
 // InjectImportedVariable determines if an imported package variable exists in the active config and adds it if not.
 func InjectImportedVariable(importedVariable types.ZarfPackageVariable) {
 	presentInActive := false
@@ -117,8 +189,22 @@ func promptVariable(variable types.ZarfPackageVariable) (value string, err error
 		message.Question(variable.Description)
 	}
 
+	question := fmt.Sprintf("Please provide a value for \"%s\"", variable.Name)
+
+	// Don't echo sensitive values, an empty answer keeps the default
+	if variable.Sensitive {
+		prompt := &survey.Password{Message: question}
+		if err = survey.AskOne(prompt, &value); err != nil {
+			return "", err
+		}
+		if value == "" {
+			value = variable.Default
+		}
+		return value, nil
+	}
+
 	prompt := &survey.Input{
-		Message: fmt.Sprintf("Please provide a value for \"%s\"", variable.Name),
+		Message: question,
 		Default: variable.Default,
 	}
 
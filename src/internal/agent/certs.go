@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/pki"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// How often the agent checks its certificate for changes or upcoming expiry
+	certCheckInterval = time.Hour
+	// Rotate the agent certificate once it is within 30 days of expiring
+	certRotationWindow = time.Hour * 24 * 30
+)
+
+// certReloader serves the current keypair from disk, picking up the new one after the mounted secret is rotated
+type certReloader struct {
+	sync.RWMutex
+	certPath string
+	keyPath  string
+	modTime  time.Time
+	cert     *tls.Certificate
+}
+
+func newCertReloader(certPath, keyPath string) (*certReloader, error) {
+	message.Debugf("agent.newCertReloader(%s, %s)", certPath, keyPath)
+
+	reloader := &certReloader{
+		certPath: certPath,
+		keyPath:  keyPath,
+	}
+
+	return reloader, reloader.reload()
+}
+
+// reload loads the keypair from disk if the certificate has changed since the last load
+func (c *certReloader) reload() error {
+	info, err := os.Stat(c.certPath)
+	if err != nil {
+		return err
+	}
+
+	c.RLock()
+	unchanged := c.cert != nil && info.ModTime().Equal(c.modTime)
+	c.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.cert = &cert
+	c.modTime = info.ModTime()
+
+	message.Infof("Loaded the agent TLS certificate from %s", c.certPath)
+	return nil
+}
+
+// getCertificate satisfies tls.Config.GetCertificate
+func (c *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cert, nil
+}
+
+// watch periodically reloads the certificate, so every replica serves the new one after a rotation
+func (c *certReloader) watch() {
+	for range time.Tick(certCheckInterval) {
+		if err := c.reload(); err != nil {
+			message.Errorf(err, "Unable to reload the agent TLS certificate")
+		}
+	}
+}
+
+// rotateExpiringCertificate rotates the certificate when it is close to expiring until ctx is done
+// Only the replica holding the agent lease runs it, so the replicas don't rotate the certificate over each other
+func rotateExpiringCertificate(ctx context.Context) {
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := rotateExpiringCertificateOnce(); err != nil {
+			message.Errorf(err, "Unable to rotate the agent TLS certificate")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func rotateExpiringCertificateOnce() error {
+	// Check the secret rather than the mounted file, which lags behind it after a rotation
+	secret, err := k8s.GetSecret(k8s.ZarfNamespace, config.ZarfAgentTLSSecretName)
+	if err != nil {
+		return fmt.Errorf("unable to read the agent TLS secret: %w", err)
+	}
+
+	expiring, err := pki.CertExpiresWithin(secret.Data[corev1.TLSCertKey], certRotationWindow)
+	if err != nil {
+		return fmt.Errorf("unable to parse the agent TLS certificate: %w", err)
+	}
+
+	if expiring {
+		message.Info("The agent TLS certificate is about to expire, rotating it now")
+		return k8s.RotateAgentTLS()
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/agent/hooks"
+	"github.com/defenseunicorns/zarf/src/internal/credentials"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+)
+
+// How often the registry pull secrets are refreshed, the shortest lived cloud registry tokens (GCP) last an hour
+const pullCredentialsRefreshInterval = 30 * time.Minute
+
+// refreshPullCredentials keeps the registry pull secrets up to date with short-lived tokens from the credential helper of the registry
+// The tokens come from the workload identity of the agent's service account, so it has to be bound to a cloud role that can pull
+// Only the replica holding the agent lease runs it until ctx is done, so the replicas don't write the secrets over each other
+func refreshPullCredentials(ctx context.Context) {
+	for {
+		if err := refreshPullCredentialsOnce(); err != nil {
+			message.Warnf("Unable to refresh the registry pull secrets: %s", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pullCredentialsRefreshInterval):
+		}
+	}
+}
+
+func refreshPullCredentialsOnce() error {
+	state, err := hooks.LoadState()
+	if err != nil {
+		return err
+	}
+
+	registryInfo := state.RegistryInfo
+	if registryInfo.CredentialHelper == "" {
+		// The pull secrets hold static credentials that don't expire
+		return nil
+	}
+
+	// The agent has no docker credential helpers, so it asks the workload identity a helper like ecr-login gets its tokens from
+	identity, ok := credentials.GetHelperIdentity(registryInfo.CredentialHelper)
+	if !ok {
+		return fmt.Errorf("the %s credential helper has no workload identity the agent can refresh the pull secrets with", registryInfo.CredentialHelper)
+	}
+	registryInfo.CredentialHelper = identity
+
+	username, password, err := credentials.GetPullCredentials(registryInfo)
+	if err != nil {
+		return err
+	}
+
+	if err := k8s.RefreshRegistryPullCreds(registryInfo.Address, username, password); err != nil {
+		return err
+	}
+
+	message.Debugf("Refreshed the registry pull secrets with a token from the %s workload identity", identity)
+	return nil
+}
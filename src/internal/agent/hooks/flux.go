@@ -19,6 +19,9 @@ type SecretRef struct {
 }
 
 type GenericGitRepo struct {
+	Metadata struct {
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}
 	Spec struct {
 		URL       string    `json:"url"`
 		SecretRef SecretRef `json:"secretRef,omitempty"`
@@ -53,6 +56,11 @@ func mutateGitRepo(r *v1.AdmissionRequest) (*operations.Result, error) {
 	}
 	gitURL := gitRepo.Spec.URL
 
+	// Leave repositories that opted out of Zarf untouched
+	if optedOut(gitRepo.Metadata.Annotations) {
+		return &operations.Result{Allowed: true, PatchOps: patches}, nil
+	}
+
 	// Check if this is an update operation and the hostname is different from what we have in the state
 	// NOTE: We mutate on updates IF AND ONLY IF the hostname in the request is different than the hostname in the zarfState
 	// NOTE: We are checking if the hostname is different before because we do not want to potentially mutate a URL that has already been mutated.
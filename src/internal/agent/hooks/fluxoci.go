@@ -0,0 +1,135 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/agent/operations"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	v1 "k8s.io/api/admission/v1"
+)
+
+const ociScheme = "oci://"
+
+// helmChartPlaceholder stands in for the chart names under an OCI helm repository, which are only known when a chart is pulled
+const helmChartPlaceholder = "zarf-chart"
+
+// GenericOCISource has the parts of a Flux HelmRepository or OCIRepository the agent mutates
+type GenericOCISource struct {
+	Metadata struct {
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}
+	Spec struct {
+		URL       string     `json:"url"`
+		Type      string     `json:"type,omitempty"`
+		SecretRef *SecretRef `json:"secretRef,omitempty"`
+	}
+}
+
+// NewOCISourceMutationHook creates a new instance of the Flux HelmRepository and OCIRepository mutation hook
+func NewOCISourceMutationHook() operations.Hook {
+	message.Debug("hooks.NewOCISourceMutationHook()")
+	return operations.Hook{
+		Create: mutateOCISource,
+		Update: mutateOCISource,
+	}
+}
+
+// mutateOCISource points the URL of an OCI source at the Zarf registry, where the charts and artifacts of a package are pushed as OCI artifacts
+// Helm repositories served over HTTP have no equivalent in the airgap and are left as they are
+func mutateOCISource(r *v1.AdmissionRequest) (*operations.Result, error) {
+	message.Debugf("hooks.mutateOCISource()(*v1.AdmissionRequest) - %#v , %s/%s: %#v", r.Kind, r.Namespace, r.Name, r.Operation)
+
+	source := &GenericOCISource{}
+	if err := json.Unmarshal(r.Object.Raw, &source); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	// Leave sources that opted out of Zarf untouched
+	if optedOut(source.Metadata.Annotations) {
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	isHelmRepository := r.Kind.Kind == "HelmRepository"
+	if (isHelmRepository && source.Spec.Type != "oci") || !strings.HasPrefix(source.Spec.URL, ociScheme) {
+		message.Debugf("Not mutating %s, only OCI sources are served by the Zarf registry", source.Spec.URL)
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	zarfState, err := getStateFromAgentPod(zarfStatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load zarf state from file: %w", err)
+	}
+	registryInfo := zarfState.RegistryInfo
+	registry := getPodRegistryHost(registryInfo)
+
+	// Sources that already point at the registry keep their URL, so updates don't mutate it twice
+	sourceURL := source.Spec.URL
+	if !strings.HasPrefix(sourceURL, ociScheme+registry+"/") {
+		if sourceURL, err = swapOCIHost(sourceURL, registry, registryInfo, isHelmRepository); err != nil {
+			return nil, err
+		}
+		message.Debugf("original OCI URL of (%s) got mutated to (%s)", source.Spec.URL, sourceURL)
+	}
+
+	patches := []operations.PatchOperation{operations.ReplacePatchOperation("/spec/url", sourceURL)}
+
+	// The registry pull secret Zarf adds to each namespace authenticates Flux as well
+	if source.Spec.SecretRef != nil {
+		patches = append(patches, operations.ReplacePatchOperation("/spec/secretRef/name", config.ZarfImagePullSecretName))
+	} else {
+		patches = append(patches, operations.AddPatchOperation("/spec/secretRef", SecretRef{Name: config.ZarfImagePullSecretName}))
+	}
+
+	// The registry in the cluster serves plain HTTP unless zarf init gave it a certificate
+	// A registry Zarf didn't deploy is reached on the plain HTTP address zarf init found its nodeport on
+	if (registryInfo.InternalRegistry && !registryInfo.CustomTLS) || registryInfo.InClusterExternal {
+		patches = append(patches, operations.AddPatchOperation("/spec/insecure", true))
+	}
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// getPodRegistryHost returns the address pods like the Flux controllers reach the registry on, which is the registry service when it runs in the cluster
+func getPodRegistryHost(registryInfo types.RegistryInfo) string {
+	if host := config.GetInClusterRegistryHost(registryInfo); host != "" {
+		return host
+	}
+	return registryInfo.Address
+}
+
+// swapOCIHost returns the URL an OCI source has in the registry, at the path its artifacts are pushed to without a checksum
+// A helm repository is the parent of its charts, so it is placed like a chart and the chart name is dropped again
+func swapOCIHost(sourceURL, registry string, registryInfo types.RegistryInfo, isHelmRepository bool) (string, error) {
+	src := strings.TrimSuffix(strings.TrimPrefix(sourceURL, ociScheme), "/")
+	if isHelmRepository {
+		src = fmt.Sprintf("%s/%s", src, helmChartPlaceholder)
+	}
+
+	var swapped string
+	var err error
+	if registryInfo.ProxyURL != "" {
+		swapped, err = utils.SwapHostWithoutChecksum(src, registry)
+	} else {
+		swapped, err = utils.SwapHostWithPushPath(src, registry, registryInfo.PushPath, registryInfo.Project, false)
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to get the URL of %s in the registry: %w", sourceURL, err)
+	}
+
+	if isHelmRepository {
+		if !strings.HasSuffix(swapped, "/"+helmChartPlaceholder) {
+			return "", fmt.Errorf("the registry push path %s doesn't keep the charts of %s under a shared repository", registryInfo.PushPath, sourceURL)
+		}
+		swapped = strings.TrimSuffix(swapped, "/"+helmChartPlaceholder)
+	}
+
+	return ociScheme + swapped, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/agent/operations"
@@ -43,66 +44,121 @@ func mutatePod(r *v1.AdmissionRequest) (*operations.Result, error) {
 		return &operations.Result{Msg: err.Error()}, nil
 	}
 
-	if pod.Labels != nil && pod.Labels["zarf-agent"] == "patched" {
-		// We've already played with this pod, just keep swimming 🐟
+	// Ephemeral containers are added to a running pod through their own subresource after the pod was patched
+	if r.SubResource == "ephemeralcontainers" && !optedOut(pod.Annotations) {
+		swapHost, err := newImageSwapper()
+		if err != nil {
+			message.Debugf("Unable to load the ZarfState file so that the Agent can mutate pods: %#v", err)
+			return nil, err
+		}
+		for idx, container := range pod.Spec.EphemeralContainers {
+			patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("/spec/ephemeralContainers/%d/image", idx), container.Image, swapHost)...)
+		}
 		return &operations.Result{
 			Allowed:  true,
 			PatchOps: patchOperations,
 		}, nil
 	}
 
-	// Add the zarf secret to the podspec
-	zarfSecret := []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}}
-	patchOperations = append(patchOperations, operations.ReplacePatchOperation("/spec/imagePullSecrets", zarfSecret))
+	if pod.Labels != nil && pod.Labels["zarf-agent"] == "patched" || optedOut(pod.Annotations) {
+		// We've already played with this pod (or it opted out), just keep swimming 🐟
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: patchOperations,
+		}, nil
+	}
 
-	zarfState, err := getStateFromAgentPod(zarfStatePath)
+	swapHost, err := newImageSwapper()
 	if err != nil {
 		message.Debugf("Unable to load the ZarfState file so that the Agent can mutate pods: %#v", err)
 		return nil, err
 	}
+	patchOperations = append(patchOperations, mutatePodSpec(pod.Spec, "/spec", swapHost)...)
+
+	// Add a label noting the zarf mutation
+	patchOperations = append(patchOperations, operations.ReplacePatchOperation("/metadata/labels/zarf-agent", "patched"))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patchOperations,
+	}, nil
+}
+
+// newImageSwapper loads the Zarf state and returns a function that points an image at the Zarf registry
+// Images that already point at the registry are left as they are, so workloads and the pods they create can both be mutated
+func newImageSwapper() (func(string) (string, error), error) {
+	zarfState, err := getStateFromAgentPod(zarfStatePath)
+	if err != nil {
+		return nil, err
+	}
 	config.InitState(zarfState)
 	containerRegistryURL := config.GetRegistry()
+	registryInfo := config.GetContainerRegistryInfo()
 
-	// update the image host for each init container
-	for idx, container := range pod.Spec.InitContainers {
-		path := fmt.Sprintf("/spec/initContainers/%d/image", idx)
-		replacement, err := utils.SwapHost(container.Image, containerRegistryURL)
-		if err != nil {
-			message.Warnf("Unable to swap the host for (%s)", container.Image)
-			continue // Continue, because we might as well attempt to mutate the other containers for this pod
+	return func(image string) (string, error) {
+		// Images that were already rewritten, by the agent or before they were deployed, keep their reference
+		if strings.HasPrefix(image, containerRegistryURL+"/") {
+			return image, nil
+		}
+		if host := config.GetInClusterRegistryHost(registryInfo); host != "" && strings.HasPrefix(image, host+"/") {
+			return image, nil
 		}
-		patchOperations = append(patchOperations, operations.ReplacePatchOperation(path, replacement))
+		// A pull-through cache serves images under their upstream path, so only the host is swapped for it
+		if registryInfo.ProxyURL != "" {
+			return utils.SwapHostWithoutChecksum(image, containerRegistryURL)
+		}
+		return utils.SwapHostWithPushPath(image, containerRegistryURL, registryInfo.PushPath, registryInfo.Project, true)
+	}, nil
+}
+
+// mutatePodSpec returns the patches that add the Zarf pull secret to a pod spec at the path and point its images at the Zarf registry
+func mutatePodSpec(spec corev1.PodSpec, path string, swapHost func(string) (string, error)) []operations.PatchOperation {
+	// Add the zarf secret to the podspec
+	zarfSecret := []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}}
+	patchOperations := []operations.PatchOperation{operations.ReplacePatchOperation(path+"/imagePullSecrets", zarfSecret)}
+
+	// update the image host for each init container
+	for idx, container := range spec.InitContainers {
+		patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("%s/initContainers/%d/image", path, idx), container.Image, swapHost)...)
 	}
 
 	// update the image host for each ephemeral container
-	for idx, container := range pod.Spec.EphemeralContainers {
-		path := fmt.Sprintf("/spec/ephemeralContainers/%d/image", idx)
-		replacement, err := utils.SwapHost(container.Image, containerRegistryURL)
-		if err != nil {
-			message.Warnf("Unable to swap the host for (%s)", container.Image)
-			continue // Continue, because we might as well attempt to mutate the other containers for this pod
-		}
-		patchOperations = append(patchOperations, operations.ReplacePatchOperation(path, replacement))
+	for idx, container := range spec.EphemeralContainers {
+		patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("%s/ephemeralContainers/%d/image", path, idx), container.Image, swapHost)...)
 	}
 
 	// update the image host for each normal container
-	for idx, container := range pod.Spec.Containers {
-		path := fmt.Sprintf("/spec/containers/%d/image", idx)
-		replacement, err := utils.SwapHost(container.Image, containerRegistryURL)
-		if err != nil {
-			message.Warnf("Unable to swap the host for (%s)", container.Image)
-			continue // Continue, because we might as well attempt to mutate the other containers for this pod
-		}
-		patchOperations = append(patchOperations, operations.ReplacePatchOperation(path, replacement))
+	for idx, container := range spec.Containers {
+		patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("%s/containers/%d/image", path, idx), container.Image, swapHost)...)
 	}
 
-	// Add a label noting the zarf mutation
-	patchOperations = append(patchOperations, operations.ReplacePatchOperation("/metadata/labels/zarf-agent", "patched"))
+	return patchOperations
+}
 
-	return &operations.Result{
-		Allowed:  true,
-		PatchOps: patchOperations,
-	}, nil
+// optedOut returns whether the annotations of a resource ask the agent to leave it alone
+// The webhooks already skip resources with the label, the annotation covers resources whose labels are used as selectors
+func optedOut(annotations map[string]string) bool {
+	agent := annotations[config.ZarfAgentLabel]
+	return annotations[config.ZarfSkipInjectionAnnotation] == "true" || agent == "skip" || agent == "ignore"
+}
+
+// swapImagePatch returns the patch that replaces the image at the path, or none when its host can't be swapped
+func swapImagePatch(path, image string, swapHost func(string) (string, error)) []operations.PatchOperation {
+	if image == "" {
+		return nil
+	}
+	replacement, err := swapHost(image)
+	if err != nil {
+		// Continue, because we might as well attempt to mutate the other containers
+		message.Warnf("Unable to swap the host for (%s)", image)
+		return nil
+	}
+	return []operations.PatchOperation{operations.ReplacePatchOperation(path, replacement)}
+}
+
+// LoadState reads the Zarf state that was mounted into the agent pods
+func LoadState() (types.ZarfState, error) {
+	return getStateFromAgentPod(zarfStatePath)
 }
 
 // Reads the state json file that was mounted into the agent pods
@@ -123,7 +179,5 @@ func getStateFromAgentPod(zarfStatePath string) (types.ZarfState, error) {
 		return zarfState, err
 	}
 
-	message.Debugf("ZarfState from file = %#v", zarfState)
-
 	return zarfState, err
 }
@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/agent/operations"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NewServiceAccountMutationHook creates a new instance of the service account mutation hook
+func NewServiceAccountMutationHook() operations.Hook {
+	message.Debug("hooks.NewServiceAccountMutationHook()")
+	return operations.Hook{
+		Create: mutateServiceAccount,
+		Update: mutateServiceAccount,
+	}
+}
+
+// mutateServiceAccount adds the Zarf registry pull secret to a service account, creating the secret when its namespace doesn't have it yet
+// Kubernetes creates the default service account of every new namespace, so pods in namespaces Zarf didn't deploy to can pull from the registry as well
+func mutateServiceAccount(r *v1.AdmissionRequest) (*operations.Result, error) {
+	message.Debugf("hooks.mutateServiceAccount()(*v1.AdmissionRequest) - %#v , %s/%s: %#v", r.Kind, r.Namespace, r.Name, r.Operation)
+
+	var serviceAccount corev1.ServiceAccount
+	if err := json.Unmarshal(r.Object.Raw, &serviceAccount); err != nil {
+		return &operations.Result{Msg: err.Error()}, nil
+	}
+
+	if optedOut(serviceAccount.Annotations) {
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	zarfState, err := getStateFromAgentPod(zarfStatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load zarf state from file: %w", err)
+	}
+	config.InitState(zarfState)
+
+	// Missing the secret only keeps pods from pulling, which isn't a reason to block the service account
+	// A dry run must not change the cluster, so the secret is only created for real requests
+	if r.DryRun == nil || !*r.DryRun {
+		if err := k8s.CreateMissingRegistryPullCreds(zarfState.RegistryInfo, r.Namespace); err != nil {
+			message.Warnf("Unable to create the registry pull secret in the %s namespace: %s", r.Namespace, err.Error())
+		}
+	}
+
+	for _, secret := range serviceAccount.ImagePullSecrets {
+		if secret.Name == config.ZarfImagePullSecretName {
+			return &operations.Result{Allowed: true}, nil
+		}
+	}
+
+	zarfSecret := corev1.LocalObjectReference{Name: config.ZarfImagePullSecretName}
+	var patch operations.PatchOperation
+	if len(serviceAccount.ImagePullSecrets) == 0 {
+		patch = operations.AddPatchOperation("/imagePullSecrets", []corev1.LocalObjectReference{zarfSecret})
+	} else {
+		patch = operations.AddPatchOperation("/imagePullSecrets/-", zarfSecret)
+	}
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: []operations.PatchOperation{patch},
+	}, nil
+}
@@ -0,0 +1,186 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/agent/operations"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The path of the pod template in each kind of workload the agent mutates
+var workloadPodTemplatePaths = map[string]string{
+	"StatefulSet": "/spec/template",
+	"DaemonSet":   "/spec/template",
+	"Job":         "/spec/template",
+	"CronJob":     "/spec/jobTemplate/spec/template",
+}
+
+// The path of the workflow spec in each kind of Argo Workflows resource the agent mutates
+var argoWorkflowSpecPaths = map[string]string{
+	"Workflow":                "/spec",
+	"WorkflowTemplate":        "/spec",
+	"ClusterWorkflowTemplate": "/spec",
+	"CronWorkflow":            "/spec/workflowSpec",
+}
+
+// workload has the parts of a workload that hold its pod template, wherever that is for its kind
+type workload struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Template    corev1.PodTemplateSpec `json:"template"`
+		JobTemplate struct {
+			Spec struct {
+				Template corev1.PodTemplateSpec `json:"template"`
+			} `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// argoWorkflow has the parts of an Argo Workflows resource that hold images, wherever its workflow spec is for its kind
+type argoWorkflow struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		argoWorkflowSpec
+		WorkflowSpec argoWorkflowSpec `json:"workflowSpec"`
+	} `json:"spec"`
+}
+
+type argoWorkflowSpec struct {
+	Templates []argoTemplate `json:"templates"`
+}
+
+// argoTemplate is a step of a workflow, which can run a container, a script or a set of containers along with init and sidecar containers
+type argoTemplate struct {
+	Container      *argoContainer  `json:"container,omitempty"`
+	Script         *argoContainer  `json:"script,omitempty"`
+	InitContainers []argoContainer `json:"initContainers,omitempty"`
+	Sidecars       []argoContainer `json:"sidecars,omitempty"`
+	ContainerSet   *struct {
+		Containers []argoContainer `json:"containers"`
+	} `json:"containerSet,omitempty"`
+}
+
+type argoContainer struct {
+	Image string `json:"image"`
+}
+
+// NewWorkloadMutationHook creates a new instance of the workload mutation hook
+// The pods a workload creates are mutated as well, this keeps the images of the workload itself pointed at the Zarf registry
+func NewWorkloadMutationHook() operations.Hook {
+	message.Debug("hooks.NewWorkloadMutationHook()")
+	return operations.Hook{
+		Create: mutateWorkload,
+		Update: mutateWorkload,
+	}
+}
+
+// NewArgoWorkflowMutationHook creates a new instance of the Argo Workflows mutation hook
+func NewArgoWorkflowMutationHook() operations.Hook {
+	message.Debug("hooks.NewArgoWorkflowMutationHook()")
+	return operations.Hook{
+		Create: mutateArgoWorkflow,
+		Update: mutateArgoWorkflow,
+	}
+}
+
+// mutateWorkload points the images of the pod template of a workload at the Zarf registry and adds the Zarf pull secret to it
+func mutateWorkload(r *v1.AdmissionRequest) (*operations.Result, error) {
+	message.Debugf("hooks.mutateWorkload()(*v1.AdmissionRequest) - %#v , %s/%s: %#v", r.Kind, r.Namespace, r.Name, r.Operation)
+
+	templatePath, ok := workloadPodTemplatePaths[r.Kind.Kind]
+	if !ok {
+		return &operations.Result{Msg: fmt.Sprintf("the agent doesn't mutate %s resources", r.Kind.Kind)}, nil
+	}
+
+	var object workload
+	if err := json.Unmarshal(r.Object.Raw, &object); err != nil {
+		return &operations.Result{Msg: err.Error()}, nil
+	}
+
+	template := object.Spec.Template
+	if r.Kind.Kind == "CronJob" {
+		template = object.Spec.JobTemplate.Spec.Template
+	}
+
+	// The pod template can opt out the same way a pod does, with the annotation or the label the pod webhook skips
+	agentLabel := template.Labels[config.ZarfAgentLabel]
+	if optedOut(object.Annotations) || optedOut(template.Annotations) || agentLabel == "skip" || agentLabel == "ignore" {
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	swapHost, err := newImageSwapper()
+	if err != nil {
+		message.Debugf("Unable to load the ZarfState file so that the Agent can mutate workloads: %#v", err)
+		return nil, err
+	}
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: mutatePodSpec(template.Spec, templatePath+"/spec", swapHost),
+	}, nil
+}
+
+// mutateArgoWorkflow points the images of every template of an Argo workflow at the Zarf registry and adds the Zarf pull secret to it
+func mutateArgoWorkflow(r *v1.AdmissionRequest) (*operations.Result, error) {
+	message.Debugf("hooks.mutateArgoWorkflow()(*v1.AdmissionRequest) - %#v , %s/%s: %#v", r.Kind, r.Namespace, r.Name, r.Operation)
+
+	specPath, ok := argoWorkflowSpecPaths[r.Kind.Kind]
+	if !ok {
+		return &operations.Result{Msg: fmt.Sprintf("the agent doesn't mutate %s resources", r.Kind.Kind)}, nil
+	}
+
+	var object argoWorkflow
+	if err := json.Unmarshal(r.Object.Raw, &object); err != nil {
+		return &operations.Result{Msg: err.Error()}, nil
+	}
+
+	if optedOut(object.Annotations) {
+		return &operations.Result{Allowed: true}, nil
+	}
+
+	spec := object.Spec.argoWorkflowSpec
+	if r.Kind.Kind == "CronWorkflow" {
+		spec = object.Spec.WorkflowSpec
+	}
+
+	swapHost, err := newImageSwapper()
+	if err != nil {
+		message.Debugf("Unable to load the ZarfState file so that the Agent can mutate workflows: %#v", err)
+		return nil, err
+	}
+
+	// The pods of the workflow use the pull secrets of the workflow spec
+	zarfSecret := []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}}
+	patchOperations := []operations.PatchOperation{operations.ReplacePatchOperation(specPath+"/imagePullSecrets", zarfSecret)}
+
+	for idx, template := range spec.Templates {
+		templatePath := fmt.Sprintf("%s/templates/%d", specPath, idx)
+		if template.Container != nil {
+			patchOperations = append(patchOperations, swapImagePatch(templatePath+"/container/image", template.Container.Image, swapHost)...)
+		}
+		if template.Script != nil {
+			patchOperations = append(patchOperations, swapImagePatch(templatePath+"/script/image", template.Script.Image, swapHost)...)
+		}
+		for containerIdx, container := range template.InitContainers {
+			patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("%s/initContainers/%d/image", templatePath, containerIdx), container.Image, swapHost)...)
+		}
+		for containerIdx, container := range template.Sidecars {
+			patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("%s/sidecars/%d/image", templatePath, containerIdx), container.Image, swapHost)...)
+		}
+		if template.ContainerSet != nil {
+			for containerIdx, container := range template.ContainerSet.Containers {
+				patchOperations = append(patchOperations, swapImagePatch(fmt.Sprintf("%s/containerSet/containers/%d/image", templatePath, containerIdx), container.Image, swapHost)...)
+			}
+		}
+	}
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patchOperations,
+	}, nil
+}
@@ -15,6 +15,10 @@ func NewServer(port string) *http.Server {
 	// Instances hooks
 	podsMutation := hooks.NewPodMutationHook()
 	gitRepositoryMutation := hooks.NewGitRepositoryMutationHook()
+	ociSourceMutation := hooks.NewOCISourceMutationHook()
+	workloadMutation := hooks.NewWorkloadMutationHook()
+	argoWorkflowMutation := hooks.NewArgoWorkflowMutationHook()
+	serviceAccountMutation := hooks.NewServiceAccountMutationHook()
 
 	// Routers
 	ah := newAdmissionHandler()
@@ -22,6 +26,10 @@ func NewServer(port string) *http.Server {
 	mux.Handle("/healthz", healthz())
 	mux.Handle("/mutate/pod", ah.Serve(podsMutation))
 	mux.Handle("/mutate/flux-gitrepository", ah.Serve(gitRepositoryMutation))
+	mux.Handle("/mutate/flux-ocisource", ah.Serve(ociSourceMutation))
+	mux.Handle("/mutate/workload", ah.Serve(workloadMutation))
+	mux.Handle("/mutate/argo-workflow", ah.Serve(argoWorkflowMutation))
+	mux.Handle("/mutate/serviceaccount", ah.Serve(serviceAccountMutation))
 
 	return &http.Server{
 		Addr:    fmt.Sprintf(":%s", port),
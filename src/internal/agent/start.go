@@ -2,12 +2,14 @@ package agent
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	agentHttp "github.com/defenseunicorns/zarf/src/internal/agent/http"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 )
 
@@ -19,15 +21,40 @@ const (
 	httpPort = "8443"
 	tlscert  = "/etc/certs/tls.crt"
 	tlskey   = "/etc/certs/tls.key"
+
+	// leaseName is the lease the agent replicas elect the one that writes secrets with
+	leaseName = "zarf-agent"
 )
 
 // StartWebhook launches the zarf agent mutating webhook in the cluster
 func StartWebhook() {
 	message.Debug("agent.StartWebhook()")
 
+	certs, err := newCertReloader(tlscert, tlskey)
+	if err != nil {
+		message.Fatal(err, "Unable to load the agent TLS certificate")
+	}
+	go certs.watch()
+
+	// Every replica serves admission requests, but only the leader rotates the certificate and refreshes the pull secrets
+	leaderCtx, stopLeading := context.WithCancel(context.Background())
+	defer stopLeading()
+	go func() {
+		err := k8s.RunAsLeader(leaderCtx, leaseName, func(ctx context.Context) {
+			go rotateExpiringCertificate(ctx)
+			// Keep short-lived registry tokens in the pull secrets from expiring
+			refreshPullCredentials(ctx)
+		})
+		if err != nil {
+			message.Errorf(err, "Unable to take part in the election of the agent leader, the TLS certificate and pull secrets aren't kept up to date")
+		}
+	}()
+
 	server := agentHttp.NewServer(httpPort)
+	server.TLSConfig = &tls.Config{GetCertificate: certs.getCertificate}
 	go func() {
-		if err := server.ListenAndServeTLS(tlscert, tlskey); err != nil && err != http.ErrServerClosed {
+		// The cert and key are served by the reloader so they can be rotated without a restart
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 			message.Fatal(err, "Failed to start the web server")
 		}
 	}()
@@ -40,6 +67,8 @@ func StartWebhook() {
 	<-signalChan
 
 	message.Infof("Shutdown gracefully...")
+	// Hand the lease over to another replica right away
+	stopLeading()
 	if err := server.Shutdown(context.Background()); err != nil {
 		message.Fatal(err, "unable to properly shutdown the web server")
 	}
@@ -19,8 +19,11 @@ func RemovePackage(w http.ResponseWriter, r *http.Request) {
 	// Get the name of the package we're removing from the URL params
 	name := chi.URLParam(r, "name")
 
+	// The UI asks before calling this, so don't prompt again
+	config.CommonOptions.Confirm = true
+
 	// Remove the package
-	err := packager.Remove(name)
+	err := packager.Remove(name, false)
 	if err != nil {
 		message.ErrorWebf(err, w, "Unable to remove the zarf package from the cluster")
 		return
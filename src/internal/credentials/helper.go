@@ -0,0 +1,143 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// dockerHelperPrefix is the prefix of the binaries that implement the docker credential helper protocol
+const dockerHelperPrefix = "docker-credential-"
+
+// The workload identities that get the same tokens as the docker credential helpers of the clouds
+var dockerHelperIdentities = map[string]string{
+	"ecr-login": IdentityAWS,
+	"acr-env":   IdentityAzure,
+	"gcr":       IdentityGCP,
+	"gcloud":    IdentityGCP,
+}
+
+// Helper gets short-lived registry credentials from ambient cloud credentials instead of ones stored in the Zarf state
+type Helper interface {
+	// Get returns a username and token for the registry
+	Get(registryAddress string) (string, string, error)
+}
+
+// NewHelper returns the credential helper with the name, either a workload identity (aws, azure or gcp)
+// or a docker credential helper on the PATH (e.g. ecr-login for docker-credential-ecr-login)
+func NewHelper(name string) (Helper, error) {
+	if IsValidIdentity(name) {
+		return &identityHelper{identity: name}, nil
+	}
+
+	binary, err := exec.LookPath(dockerHelperPrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a workload identity (aws, azure or gcp) and %s%s is not on the PATH", name, dockerHelperPrefix, name)
+	}
+	return &dockerHelper{binary: binary}, nil
+}
+
+// GetHelperIdentity returns the workload identity that gets the same tokens as the credential helper, which is how the Zarf agent refreshes them
+// The agent image has no docker credential helpers, so a helper without a matching identity can't be refreshed in the cluster
+func GetHelperIdentity(name string) (string, bool) {
+	if IsValidIdentity(name) {
+		return name, true
+	}
+	identity, ok := dockerHelperIdentities[name]
+	return identity, ok
+}
+
+// GetPullCredentials returns the credentials to pull from the registry, asking its credential helper when it has one
+func GetPullCredentials(registryInfo types.RegistryInfo) (string, string, error) {
+	if registryInfo.CredentialHelper == "" {
+		return registryInfo.PullUsername, registryInfo.PullPassword, nil
+	}
+
+	return getHelperCredentials(registryInfo.CredentialHelper, registryInfo.Address)
+}
+
+// GetPushCredentials returns the credentials to push to the registry, asking its push identity or credential helper when it has one
+func GetPushCredentials(registryInfo types.RegistryInfo) (string, string, error) {
+	switch {
+	case registryInfo.PushIdentity != "":
+		username, password, err := getIdentityCredentials(registryInfo.PushIdentity, registryInfo.Address)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to get push credentials from the %s workload identity: %w", registryInfo.PushIdentity, err)
+		}
+		return username, password, nil
+
+	case registryInfo.CredentialHelper != "":
+		return getHelperCredentials(registryInfo.CredentialHelper, registryInfo.Address)
+	}
+
+	return registryInfo.PushUsername, registryInfo.PushPassword, nil
+}
+
+// getHelperCredentials asks the named credential helper for a username and token for the registry
+func getHelperCredentials(name, registryAddress string) (string, string, error) {
+	helper, err := NewHelper(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	username, password, err := helper.Get(registryAddress)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get registry credentials from the %s credential helper: %w", name, err)
+	}
+	message.AddSensitiveValue(password)
+
+	return username, password, nil
+}
+
+// identityHelper trades the workload identity of the pod Zarf runs in for a token
+type identityHelper struct {
+	identity string
+}
+
+func (helper *identityHelper) Get(registryAddress string) (string, string, error) {
+	return getIdentityCredentials(helper.identity, registryAddress)
+}
+
+// dockerHelper runs a docker credential helper, which uses the cloud credentials of the machine it runs on
+type dockerHelper struct {
+	binary string
+}
+
+func (helper *dockerHelper) Get(registryAddress string) (string, string, error) {
+	message.Debugf("credentials.dockerHelper.Get(%s) with %s", registryAddress, helper.binary)
+
+	// The protocol reads the registry host from stdin and writes the credentials to stdout as JSON
+	registryHost := strings.Split(registryAddress, "/")[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, helper.binary, "get")
+	cmd.Stdin = strings.NewReader(registryHost)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("%w: %s %s", err, strings.TrimSpace(stdout.String()), strings.TrimSpace(stderr.String()))
+	}
+
+	var credentials struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return "", "", fmt.Errorf("unable to read the credential helper output: %w", err)
+	}
+	if credentials.Secret == "" {
+		return "", "", fmt.Errorf("the credential helper did not return a secret for %s", registryHost)
+	}
+
+	return credentials.Username, credentials.Secret, nil
+}
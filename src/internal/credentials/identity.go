@@ -0,0 +1,210 @@
+package credentials
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+)
+
+// The cloud workload identities that can provide short-lived registry credentials
+const (
+	IdentityAWS   = "aws"
+	IdentityAzure = "azure"
+	IdentityGCP   = "gcp"
+)
+
+// The usernames registries expect alongside the short-lived tokens
+const (
+	gcpTokenUsername   = "oauth2accesstoken"
+	azureTokenUsername = "00000000-0000-0000-0000-000000000000"
+)
+
+var identityClient = &http.Client{Timeout: 30 * time.Second}
+
+// IsValidIdentity returns true when Zarf knows how to get registry credentials from the workload identity
+func IsValidIdentity(identity string) bool {
+	return identity == IdentityAWS || identity == IdentityAzure || identity == IdentityGCP
+}
+
+// getIdentityCredentials exchanges the identity of the pod Zarf is running in for a registry username and token
+// The tokens are only fetched when they are needed and are never written to the Zarf state
+func getIdentityCredentials(identity, registryAddress string) (string, string, error) {
+	message.Debugf("credentials.getIdentityCredentials(%s, %s)", identity, registryAddress)
+
+	registryHost := strings.Split(registryAddress, "/")[0]
+
+	switch identity {
+	case IdentityAWS:
+		return getAWSCredentials(registryHost)
+	case IdentityAzure:
+		return getAzureCredentials(registryHost)
+	case IdentityGCP:
+		return getGCPCredentials()
+	default:
+		return "", "", fmt.Errorf("unknown workload identity %s", identity)
+	}
+}
+
+// getGCPCredentials asks the GKE metadata server for an access token of the Kubernetes service account's Google service account
+func getGCPCredentials() (string, string, error) {
+	request, err := http.NewRequest(http.MethodGet, "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", "", err
+	}
+	request.Header.Set("Metadata-Flavor", "Google")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doIdentityRequest(request, &token); err != nil {
+		return "", "", err
+	}
+
+	return gcpTokenUsername, token.AccessToken, nil
+}
+
+// getAzureCredentials trades the projected service account token for an Azure AD token and then for an ACR refresh token
+func getAzureCredentials(registryHost string) (string, string, error) {
+	clientID := os.Getenv("AZURE_CLIENT_ID")
+	tenantID := os.Getenv("AZURE_TENANT_ID")
+	authorityHost := os.Getenv("AZURE_AUTHORITY_HOST")
+	if authorityHost == "" {
+		authorityHost = "https://login.microsoftonline.com/"
+	}
+
+	assertion, err := readIdentityToken("AZURE_FEDERATED_TOKEN_FILE")
+	if err != nil {
+		return "", "", err
+	}
+	if clientID == "" || tenantID == "" {
+		return "", "", fmt.Errorf("AZURE_CLIENT_ID and AZURE_TENANT_ID must be set, is the pod labeled for Azure workload identity?")
+	}
+
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), tenantID)
+	form := url.Values{
+		"client_id":             {clientID},
+		"scope":                 {"https://containerregistry.azure.net/.default"},
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {assertion},
+	}
+	request, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var aadToken struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doIdentityRequest(request, &aadToken); err != nil {
+		return "", "", err
+	}
+
+	form = url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {registryHost},
+		"tenant":       {tenantID},
+		"access_token": {aadToken.AccessToken},
+	}
+	request, err = http.NewRequest(http.MethodPost, fmt.Sprintf("https://%s/oauth2/exchange", registryHost), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var acrToken struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := doIdentityRequest(request, &acrToken); err != nil {
+		return "", "", err
+	}
+
+	return azureTokenUsername, acrToken.RefreshToken, nil
+}
+
+// getAWSCredentials asks ECR for an authorization token with the credentials of the pod, such as the IRSA role of its service account
+func getAWSCredentials(registryHost string) (string, string, error) {
+	// ECR hosts look like <account>.dkr.ecr.<region>.amazonaws.com
+	hostParts := strings.Split(strings.Split(registryHost, ":")[0], ".")
+	if len(hostParts) < 6 || hostParts[1] != "dkr" || hostParts[2] != "ecr" {
+		return "", "", fmt.Errorf("%s is not an ECR registry", registryHost)
+	}
+	region := hostParts[3]
+
+	ctx, cancel := context.WithTimeout(context.Background(), identityClient.Timeout)
+	defer cancel()
+
+	// The default chain assumes the role from AWS_ROLE_ARN with the token in AWS_WEB_IDENTITY_TOKEN_FILE
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region), awsconfig.WithHTTPClient(identityClient))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to load the AWS credentials of the pod: %w", err)
+	}
+
+	authorization, err := ecr.NewFromConfig(awsConfig).GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get an ECR authorization token: %w", err)
+	}
+	if len(authorization.AuthorizationData) == 0 {
+		return "", "", fmt.Errorf("ECR did not return an authorization token")
+	}
+
+	// The token is a base64 encoded username:password pair
+	decoded, err := base64.StdEncoding.DecodeString(aws.ToString(authorization.AuthorizationData[0].AuthorizationToken))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to decode the ECR authorization token: %w", err)
+	}
+	username, password, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", fmt.Errorf("the ECR authorization token is malformed")
+	}
+
+	return username, password, nil
+}
+
+// readIdentityToken reads the projected service account token the identity webhook pointed the environment variable at
+func readIdentityToken(envName string) (string, error) {
+	tokenFile := os.Getenv(envName)
+	if tokenFile == "" {
+		return "", fmt.Errorf("%s is not set, is Zarf running in a pod with a workload identity?", envName)
+	}
+
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the service account token %s: %w", tokenFile, err)
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// doIdentityRequest sends a token request and decodes the JSON response into result
+func doIdentityRequest(request *http.Request, result interface{}) error {
+	response, err := identityClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned %s: %s", request.Method, request.URL.Host, response.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,158 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/go-git/go-git/v5"
+	goConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// The history of a large repo is pushed to this temporary branch a chunk at a time, it is removed once the repo is fully pushed
+const checkpointBranch = "refs/heads/zarf-push-checkpoint"
+
+// go-git only pushes references, so each checkpoint commit is pointed to by this local ref while it is pushed
+const localCheckpointRef = "refs/zarf/push-checkpoint"
+
+// pushInChunks pushes the history of a repo larger than --git-chunk-size to the checkpoint branch a piece at a time
+// Each push only sends what the git server is missing, so a push that times out over the tunnel resumes at the last checkpoint
+// the server has instead of starting over. It returns whether the checkpoint branch needs to be removed after the full push
+func pushInChunks(ctx context.Context, repo *git.Repository, localPath string, gitServer types.GitServerInfo, gitCred http.BasicAuth, spinner *message.Spinner) (bool, error) {
+	chunkSize := int64(config.DeployOptions.GitChunkSizeMB) * 1024 * 1024
+	if chunkSize <= 0 {
+		return false, nil
+	}
+
+	size, err := utils.GetDirSize(filepath.Join(localPath, ".git"))
+	if err != nil {
+		return false, fmt.Errorf("unable to get the size of the repo: %w", err)
+	}
+
+	serverRefs, err := getServerRefs(repo, gitServer, gitCred)
+	if err != nil {
+		return false, err
+	}
+	serverCheckpoint, hasCheckpoint := serverRefs[checkpointBranch]
+
+	if size <= chunkSize {
+		return hasCheckpoint, nil
+	}
+
+	checkpoints, err := getCheckpoints(repo, int(size/chunkSize)+1)
+	if err != nil {
+		return false, err
+	}
+
+	// Skip the chunks an earlier attempt already pushed
+	start := 0
+	for idx, checkpoint := range checkpoints {
+		if hasCheckpoint && checkpoint == serverCheckpoint {
+			start = idx + 1
+			message.Debugf("The git server has %d of %d chunks of %s, resuming the push", start, len(checkpoints), localPath)
+		}
+	}
+
+	defer func() {
+		_ = repo.Storer.RemoveReference(localCheckpointRef)
+	}()
+
+	for idx := start; idx < len(checkpoints); idx++ {
+		spinner.Updatef("Pushing chunk %d of %d of git repo %s (%s)", idx+1, len(checkpoints), filepath.Base(localPath), utils.ByteFormat(float64(size), 2))
+
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(localCheckpointRef, checkpoints[idx])); err != nil {
+			return true, err
+		}
+
+		err := repo.PushContext(ctx, &git.PushOptions{
+			RemoteName:      offlineRemoteName,
+			Auth:            &gitCred,
+			RefSpecs:        []goConfig.RefSpec{goConfig.RefSpec("+" + localCheckpointRef + ":" + checkpointBranch)},
+			InsecureSkipTLS: gitServer.InsecureSkipVerify,
+			CABundle:        gitServer.CABundle,
+		})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return true, fmt.Errorf("unable to push chunk %d of %d: %w", idx+1, len(checkpoints), err)
+		}
+	}
+
+	return true, nil
+}
+
+// getCheckpoints returns evenly spaced commits along the first-parent history of HEAD, oldest first, splitting it into count chunks
+// The last chunk ends at HEAD itself, which the full push sends
+func getCheckpoints(repo *git.Repository, count int) ([]plumbing.Hash, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the HEAD of the repo: %w", err)
+	}
+
+	var history []plumbing.Hash
+	commit, err := repo.CommitObject(head.Hash())
+	for err == nil {
+		history = append([]plumbing.Hash{commit.Hash}, history...)
+		if commit.NumParents() == 0 {
+			break
+		}
+		commit, err = commit.Parent(0)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk the history of the repo: %w", err)
+	}
+
+	var checkpoints []plumbing.Hash
+	for idx := 1; idx < count; idx++ {
+		checkpoint := history[idx*len(history)/count]
+		if len(checkpoints) == 0 || checkpoints[len(checkpoints)-1] != checkpoint {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+// getServerRefs returns the refs the git server has for the repo, which are none before it is first pushed
+func getServerRefs(repo *git.Repository, gitServer types.GitServerInfo, gitCred http.BasicAuth) (map[string]plumbing.Hash, error) {
+	remote, err := repo.Remote(offlineRemoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]plumbing.Hash)
+	remoteRefs, err := remote.List(&git.ListOptions{Auth: &gitCred, InsecureSkipTLS: gitServer.InsecureSkipVerify, CABundle: gitServer.CABundle})
+	if errors.Is(err, transport.ErrRepositoryNotFound) || errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return refs, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to list the refs on the git server: %w", err)
+	}
+
+	for _, ref := range remoteRefs {
+		refs[ref.Name().String()] = ref.Hash()
+	}
+	return refs, nil
+}
+
+// removeCheckpointBranch deletes the checkpoint branch from the git server once the repo is fully pushed
+func removeCheckpointBranch(ctx context.Context, repo *git.Repository, gitServer types.GitServerInfo, gitCred http.BasicAuth) error {
+	err := repo.PushContext(ctx, &git.PushOptions{
+		RemoteName:      offlineRemoteName,
+		Auth:            &gitCred,
+		RefSpecs:        []goConfig.RefSpec{goConfig.RefSpec(":" + checkpointBranch)},
+		InsecureSkipTLS: gitServer.InsecureSkipVerify,
+		CABundle:        gitServer.CABundle,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("unable to remove the %s branch from the git server: %w", checkpointBranch, err)
+	}
+
+	// The fetch before the push may have copied the branch into the local repo
+	_ = repo.Storer.RemoveReference(plumbing.ReferenceName(checkpointBranch))
+	return nil
+}
@@ -10,7 +10,8 @@ import (
 )
 
 // clone performs a `git clone` of a given repo.
-func clone(gitDirectory string, gitURL string, onlyFetchRef bool, spinner *message.Spinner) (*git.Repository, error) {
+// Shallow clones only get the latest commit of the default branch, the ref is fetched with the same depth later.
+func clone(gitDirectory string, gitURL string, onlyFetchRef bool, shallow bool, spinner *message.Spinner) (*git.Repository, error) {
 	cloneOptions := &git.CloneOptions{
 		URL:        gitURL,
 		Progress:   spinner,
@@ -21,6 +22,12 @@ func clone(gitDirectory string, gitURL string, onlyFetchRef bool, spinner *messa
 		cloneOptions.Tags = git.NoTags
 	}
 
+	if shallow {
+		cloneOptions.Depth = 1
+		cloneOptions.SingleBranch = true
+		cloneOptions.Tags = git.NoTags
+	}
+
 	gitCred := FindAuthForHost(gitURL)
 
 	// Gracefully handle no git creds on the system (like our CI/CD)
@@ -51,6 +58,10 @@ func clone(gitDirectory string, gitURL string, onlyFetchRef bool, spinner *messa
 			cmdArgs = append(cmdArgs, "--no-tags")
 		}
 
+		if shallow {
+			cmdArgs = append(cmdArgs, "--depth", "1", "--single-branch", "--no-tags")
+		}
+
 		stdOut, stdErr, err := utils.ExecCommandWithContext(context.TODO(), false, "git", cmdArgs...)
 		spinner.Updatef(stdOut)
 		spinner.Debugf(stdErr)
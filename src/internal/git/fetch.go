@@ -38,6 +38,7 @@ func fetchHash(gitDirectory string, hash string) {
 }
 
 // fetch performs a `git fetch` of _only_ the provided git refspec(s).
+// Shallow repos stay shallow by only fetching the commits the refspecs point at.
 func fetch(gitDirectory string, refspecs ...goConfig.RefSpec) error {
 	repo, err := git.PlainOpen(gitDirectory)
 	if err != nil {
@@ -65,6 +66,11 @@ func fetch(gitDirectory string, refspecs ...goConfig.RefSpec) error {
 		fetchOptions.Auth = &gitCred.Auth
 	}
 
+	shallow := isShallow(repo)
+	if shallow {
+		fetchOptions.Depth = 1
+	}
+
 	err = repo.Fetch(fetchOptions)
 
 	if errors.Is(err, git.ErrTagExists) || errors.Is(err, git.NoErrAlreadyUpToDate) {
@@ -76,6 +82,9 @@ func fetch(gitDirectory string, refspecs ...goConfig.RefSpec) error {
 		// If we can't fetch with go-git, fallback to the host fetch
 		// Only support "all tags" due to the azure fetch url format including a username
 		cmdArgs := []string{"fetch", onlineRemoteName}
+		if shallow {
+			cmdArgs = append(cmdArgs, "--depth", "1")
+		}
 		for _, refspec := range refspecs {
 			cmdArgs = append(cmdArgs, refspec.String())
 		}
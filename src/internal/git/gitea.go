@@ -0,0 +1,78 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	netHttp "net/http"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// giteaProvider manages users and repos with the Gitea API, repos are created by pushing to them
+type giteaProvider struct {
+	serverURL string
+	gitServer types.GitServerInfo
+}
+
+// CreateReadOnlyUser uses the Gitea API to create a non-admin zarf user
+func (provider *giteaProvider) CreateReadOnlyUser() error {
+	// Create json representation of the create-user request body
+	createUserBody := map[string]interface{}{
+		"username":             provider.gitServer.PullUsername,
+		"password":             provider.gitServer.PullPassword,
+		"email":                "zarf-reader@localhost.local",
+		"must_change_password": false,
+	}
+	createUserData, err := json.Marshal(createUserBody)
+	if err != nil {
+		return err
+	}
+
+	// Send API request to create the user
+	createUserEndpoint := fmt.Sprintf("%s/api/v1/admin/users", provider.serverURL)
+	createUserRequest, _ := netHttp.NewRequest("POST", createUserEndpoint, bytes.NewBuffer(createUserData))
+	out, err := DoHttpThings(createUserRequest, provider.gitServer, provider.gitServer.PushUsername, provider.gitServer.PushPassword)
+	message.Debugf("POST %s:\n%s", createUserEndpoint, string(out))
+	if err != nil {
+		return err
+	}
+
+	// Make sure the user can't create their own repos or orgs
+	updateUserBody := map[string]interface{}{
+		"login_name":                provider.gitServer.PushUsername,
+		"max_repo_creation":         0,
+		"allow_create_organization": false,
+	}
+	updateUserData, _ := json.Marshal(updateUserBody)
+	updateUserEndpoint := fmt.Sprintf("%s/api/v1/admin/users/%s", provider.serverURL, provider.gitServer.PullUsername)
+	updateUserRequest, _ := netHttp.NewRequest("PATCH", updateUserEndpoint, bytes.NewBuffer(updateUserData))
+	out, err = DoHttpThings(updateUserRequest, provider.gitServer, provider.gitServer.PushUsername, provider.gitServer.PushPassword)
+	message.Debugf("PATCH %s:\n%s", updateUserEndpoint, string(out))
+	return err
+}
+
+// PrepareRepo does nothing since Gitea creates repos when they are pushed to
+func (provider *giteaProvider) PrepareRepo(repoName string) error {
+	return nil
+}
+
+// AddReadOnlyUserToRepo adds the read-only user to the repo as a collaborator
+func (provider *giteaProvider) AddReadOnlyUserToRepo(repoName string) error {
+	// Add the readonly user to the repo
+	addColabBody := map[string]string{
+		"permission": "read",
+	}
+	addColabData, err := json.Marshal(addColabBody)
+	if err != nil {
+		return err
+	}
+
+	// Send API request to add a user as a read-only collaborator to a repo
+	addColabEndpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/collaborators/%s", provider.serverURL, provider.gitServer.PushUsername, repoName, provider.gitServer.PullUsername)
+	addColabRequest, _ := netHttp.NewRequest("PUT", addColabEndpoint, bytes.NewBuffer(addColabData))
+	out, err := DoHttpThings(addColabRequest, provider.gitServer, provider.gitServer.PushUsername, provider.gitServer.PushPassword)
+	message.Debugf("PUT %s:\n%s", addColabEndpoint, string(out))
+	return err
+}
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	netHttp "net/http"
+	"net/url"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// The GitLab access level that can clone and pull but not push
+const gitlabReporterAccess = 20
+
+// gitlabProvider manages users and projects with the GitLab API
+// The push password is a personal access token of the push user, which needs the api scope (and admin to create the read-only user)
+type gitlabProvider struct {
+	serverURL string
+	gitServer types.GitServerInfo
+}
+
+// CreateReadOnlyUser uses the GitLab API to create a user that can't create projects or groups
+func (provider *gitlabProvider) CreateReadOnlyUser() error {
+	createUserBody := map[string]interface{}{
+		"username":          provider.gitServer.PullUsername,
+		"name":              provider.gitServer.PullUsername,
+		"password":          provider.gitServer.PullPassword,
+		"email":             "zarf-reader@localhost.local",
+		"skip_confirmation": true,
+		"projects_limit":    0,
+		"can_create_group":  false,
+	}
+
+	status, _, err := provider.request("POST", "/users", createUserBody)
+	if status == netHttp.StatusConflict {
+		message.Debugf("The GitLab user %s already exists", provider.gitServer.PullUsername)
+		return nil
+	}
+	return err
+}
+
+// PrepareRepo creates the project in the namespace of the push user if it doesn't exist yet
+func (provider *gitlabProvider) PrepareRepo(repoName string) error {
+	status, _, err := provider.request("GET", "/projects/"+provider.projectID(repoName), nil)
+	if err == nil {
+		return nil
+	}
+	if status != netHttp.StatusNotFound {
+		return err
+	}
+
+	createProjectBody := map[string]interface{}{
+		"name":       repoName,
+		"path":       repoName,
+		"visibility": "private",
+	}
+	_, _, err = provider.request("POST", "/projects", createProjectBody)
+	return err
+}
+
+// AddReadOnlyUserToRepo adds the read-only user to the project as a reporter
+func (provider *gitlabProvider) AddReadOnlyUserToRepo(repoName string) error {
+	_, out, err := provider.request("GET", "/users?username="+url.QueryEscape(provider.gitServer.PullUsername), nil)
+	if err != nil {
+		return err
+	}
+
+	var users []struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(out, &users); err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("the GitLab user %s does not exist", provider.gitServer.PullUsername)
+	}
+
+	addMemberBody := map[string]interface{}{
+		"user_id":      users[0].ID,
+		"access_level": gitlabReporterAccess,
+	}
+	status, _, err := provider.request("POST", fmt.Sprintf("/projects/%s/members", provider.projectID(repoName)), addMemberBody)
+	if status == netHttp.StatusConflict {
+		message.Debugf("The GitLab user %s is already a member of %s", provider.gitServer.PullUsername, repoName)
+		return nil
+	}
+	return err
+}
+
+// projectID returns the url encoded path GitLab accepts in place of a project id
+func (provider *gitlabProvider) projectID(repoName string) string {
+	return url.PathEscape(fmt.Sprintf("%s/%s", provider.gitServer.PushUsername, repoName))
+}
+
+// request calls the GitLab API with the token of the push user and returns the status code along with the body
+func (provider *gitlabProvider) request(method, path string, body interface{}) (int, []byte, error) {
+	var requestBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		requestBody = bytes.NewBuffer(data)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4%s", provider.serverURL, path)
+	request, err := netHttp.NewRequest(method, endpoint, requestBody)
+	if err != nil {
+		return 0, nil, err
+	}
+	request.Header.Add("PRIVATE-TOKEN", provider.gitServer.PushPassword)
+	request.Header.Add("accept", "application/json")
+	request.Header.Add("Content-Type", "application/json")
+
+	client, err := newHTTPClient(provider.gitServer)
+	if err != nil {
+		return 0, nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := io.ReadAll(response.Body)
+	message.Debugf("%s %s:\n%s", method, endpoint, string(responseBody))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, nil, fmt.Errorf("got status code of %d during http request with body of: %s", response.StatusCode, string(responseBody))
+	}
+
+	return response.StatusCode, responseBody, nil
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// The git servers Zarf can push repos to
+const (
+	ProviderGitea  = "gitea"
+	ProviderGitLab = "gitlab"
+	ProviderHTTP   = "http"
+)
+
+// GitProvider manages the repos and the read-only user on the git server Zarf pushes to
+type GitProvider interface {
+	// CreateReadOnlyUser creates the pull user without the ability to create repos of its own
+	CreateReadOnlyUser() error
+	// PrepareRepo makes sure a repo can be pushed to before it is pushed the first time
+	PrepareRepo(repoName string) error
+	// AddReadOnlyUserToRepo gives the pull user read access to a repo
+	AddReadOnlyUserToRepo(repoName string) error
+}
+
+// IsValidProvider returns true if Zarf knows how to talk to the git provider
+func IsValidProvider(provider string) bool {
+	return provider == ProviderGitea || provider == ProviderGitLab || provider == ProviderHTTP
+}
+
+// NewProvider returns the provider for the git server, reached at serverURL which may be a tunnel to it
+// Servers without a provider are the Zarf git server (Gitea) when internal and plain git http servers otherwise
+func NewProvider(gitServer types.GitServerInfo, serverURL string) (GitProvider, error) {
+	provider := gitServer.Provider
+	if provider == "" {
+		if gitServer.InternalServer {
+			provider = ProviderGitea
+		} else {
+			provider = ProviderHTTP
+		}
+	}
+
+	serverURL = strings.TrimSuffix(serverURL, "/")
+
+	switch provider {
+	case ProviderGitea:
+		return &giteaProvider{serverURL: serverURL, gitServer: gitServer}, nil
+	case ProviderGitLab:
+		return &gitlabProvider{serverURL: serverURL, gitServer: gitServer}, nil
+	case ProviderHTTP:
+		return &httpProvider{}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported git provider %s", provider)
+}
+
+// HasReadOnlyUser returns true if the git server has a pull user separate from the push user
+func HasReadOnlyUser(gitServer types.GitServerInfo) bool {
+	return gitServer.PullUsername != "" && gitServer.PullUsername != gitServer.PushUsername
+}
+
+// CreateReadOnlyUser creates the non-admin pull user on the git server Zarf is configured to use
+func CreateReadOnlyUser() error {
+	gitServerInfo := config.GetGitServerInfo()
+	gitServerURL := gitServerInfo.Address
+
+	// If this is a serviceURL, create a port-forward tunnel to that resource
+	if tunnel, err := k8s.NewTunnelFromServiceURL(gitServerURL); err != nil {
+		message.Debug(err)
+	} else {
+		tunnel.Connect("", false)
+		defer tunnel.Close()
+		gitServerURL = fmt.Sprintf("http://%s", tunnel.Endpoint())
+	}
+
+	provider, err := NewProvider(gitServerInfo, gitServerURL)
+	if err != nil {
+		return err
+	}
+
+	return provider.CreateReadOnlyUser()
+}
+
+// httpProvider pushes to plain git http servers, which create repos on push and have no users to manage
+type httpProvider struct{}
+
+func (provider *httpProvider) CreateReadOnlyUser() error {
+	message.Debug("Plain git http servers don't have a read-only user to create")
+	return nil
+}
+
+func (provider *httpProvider) PrepareRepo(repoName string) error {
+	return nil
+}
+
+func (provider *httpProvider) AddReadOnlyUserToRepo(repoName string) error {
+	return nil
+}
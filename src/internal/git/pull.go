@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/message"
@@ -44,11 +45,6 @@ func Pull(gitURL, targetFolder string, spinner *message.Spinner) (string, error)
 func pull(gitURL, targetFolder string, spinner *message.Spinner, repoName string) {
 	spinner.Updatef("Processing git repo %s", gitURL)
 
-	gitCachePath := targetFolder
-	if repoName != "" {
-		gitCachePath = filepath.Join(config.GetAbsCachePath(), filepath.Join(config.ZarfGitCacheDir, repoName))
-	}
-
 	matches := gitURLRegex.FindStringSubmatch(gitURL)
 	idx := gitURLRegex.SubexpIndex
 
@@ -58,9 +54,16 @@ func pull(gitURL, targetFolder string, spinner *message.Spinner, repoName string
 	}
 
 	onlyFetchRef := matches[idx("atRef")] != ""
+	shallow := strings.Contains(matches[idx("options")], shallowOption)
 	gitURLNoRef := fmt.Sprintf("%s%s/%s%s", matches[idx("proto")], matches[idx("hostPath")], matches[idx("repo")], matches[idx("git")])
 
-	repo, err := clone(gitCachePath, gitURLNoRef, onlyFetchRef, spinner)
+	// Shallow clones skip the cache so they never stand in for the full history of the same repo
+	gitCachePath := targetFolder
+	if repoName != "" && !shallow {
+		gitCachePath = filepath.Join(config.GetAbsCachePath(), filepath.Join(config.ZarfGitCacheDir, repoName))
+	}
+
+	repo, err := clone(gitCachePath, gitURLNoRef, onlyFetchRef, shallow, spinner)
 
 	if err == git.ErrRepositoryAlreadyExists {
 		spinner.Debugf("Repo already cloned, fetching upstream changes...")
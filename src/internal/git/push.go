@@ -1,16 +1,22 @@
 package git
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
 	"github.com/go-git/go-git/v5"
 	goConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
@@ -18,7 +24,21 @@ import (
 const offlineRemoteName = "offline-downstream"
 const onlineRemoteRefPrefix = "refs/remotes/" + onlineRemoteName + "/"
 
-func PushAllDirectories(localPath string) error {
+// PushAllDirectories pushes the repos in the local path to the git server
+// Repos whose url ends with !force, or every repo with --git-force, overwrite the history the git server has for them
+func PushAllDirectories(ctx context.Context, localPath string, repos []string) error {
+	forcedRepos := make(map[string]bool)
+	for _, repoURL := range repos {
+		if !config.DeployOptions.GitForce && !hasURLOption(repoURL, forceOption) {
+			continue
+		}
+		repoName, err := transformURLtoRepoName(repoURL)
+		if err != nil {
+			return err
+		}
+		forcedRepos[repoName] = true
+	}
+
 	gitServerInfo := config.GetGitServerInfo()
 	gitServerURL := gitServerInfo.Address
 
@@ -30,6 +50,12 @@ func PushAllDirectories(localPath string) error {
 		defer tunnel.Close()
 		gitServerURL = fmt.Sprintf("http://%s", tunnel.Endpoint())
 	}
+	installHTTPClient()
+
+	provider, err := NewProvider(gitServerInfo, gitServerURL)
+	if err != nil {
+		return err
+	}
 
 	paths, err := utils.ListDirectories(localPath)
 	if err != nil {
@@ -50,27 +76,32 @@ func PushAllDirectories(localPath string) error {
 			return err
 		}
 
-		if err := push(repo, path, spinner); err != nil {
+		// Get the upstream URL
+		remote, err := repo.Remote(onlineRemoteName)
+		if err != nil {
+			message.Warn("unable to get the information needed to prepare the repo on the git server")
+			return err
+		}
+		remoteUrl := remote.Config().URLs[0]
+		repoName, err := transformURLtoRepoName(remoteUrl)
+		if err != nil {
+			message.Warnf("Unable to get the repo name of %s", remoteUrl)
+			return err
+		}
+
+		if err := provider.PrepareRepo(repoName); err != nil {
+			message.Warnf("Unable to prepare the repo %s on the git server", repoName)
+			return err
+		}
+
+		if err := push(ctx, repo, path, forcedRepos[repoName], spinner); err != nil {
 			spinner.Warnf("Unable to push the git repo %s", basename)
 			return err
 		}
 
 		// Add the read-only user to this repo
-		if gitServerInfo.InternalServer {
-			// Get the upstream URL
-			remote, err := repo.Remote(onlineRemoteName)
-			if err != nil {
-				message.Warn("unable to get the information needed to add the read-only user to the repo")
-				return err
-			}
-			remoteUrl := remote.Config().URLs[0]
-			repoName, err := transformURLtoRepoName(remoteUrl)
-			if err != nil {
-				message.Warnf("Unable to add the read-only user to the repo: %s\n", repoName)
-				return err
-			}
-
-			err = addReadOnlyUserToRepo(gitServerURL, repoName)
+		if HasReadOnlyUser(gitServerInfo) {
+			err = provider.AddReadOnlyUserToRepo(repoName)
 			if err != nil {
 				message.Warnf("Unable to add the read-only user to the repo: %s\n", repoName)
 				return err
@@ -112,10 +143,17 @@ func prepRepoForPush(localPath, tunnelUrl, username string) (*git.Repository, er
 	return repo, nil
 }
 
-func push(repo *git.Repository, localPath string, spinner *message.Spinner) error {
+func push(ctx context.Context, repo *git.Repository, localPath string, force bool, spinner *message.Spinner) error {
+	gitServer := config.GetState().GitServer
 	gitCred := http.BasicAuth{
-		Username: config.GetState().GitServer.PushUsername,
-		Password: config.GetState().GitServer.PushPassword,
+		Username: gitServer.PushUsername,
+		Password: gitServer.PushPassword,
+	}
+
+	// The refs the package has on the git server, found before the head copies are removed below
+	pushedRefs, err := getPushedRefNames(repo)
+	if err != nil {
+		return fmt.Errorf("unable to list the git refs of the repo: %w", err)
 	}
 
 	// Since we are pushing HEAD:refs/heads/master on deployment, leaving
@@ -128,8 +166,10 @@ func push(repo *git.Repository, localPath string, spinner *message.Spinner) erro
 
 	// Fetch remote offline refs in case of old update or if multiple refs are specified in one package
 	fetchOptions := &git.FetchOptions{
-		RemoteName: offlineRemoteName,
-		Auth:       &gitCred,
+		RemoteName:      offlineRemoteName,
+		Auth:            &gitCred,
+		InsecureSkipTLS: gitServer.InsecureSkipVerify,
+		CABundle:        gitServer.CABundle,
 		RefSpecs: []goConfig.RefSpec{
 			"refs/heads/*:refs/heads/*",
 			onlineRemoteRefPrefix + "*:refs/heads/*",
@@ -138,8 +178,10 @@ func push(repo *git.Repository, localPath string, spinner *message.Spinner) erro
 	}
 
 	// Attempt the fetch, if it fails, log a warning and continue trying to push (might as well try..)
-	err = repo.Fetch(fetchOptions)
-	if errors.Is(err, transport.ErrRepositoryNotFound) {
+	// A forced push replaces what the git server has, so its (possibly rewritten) history isn't needed
+	if force {
+		message.Debugf("Forcing the push, skipping fetch...")
+	} else if err = repo.FetchContext(ctx, fetchOptions); errors.Is(err, transport.ErrRepositoryNotFound) {
 		message.Debugf("Repo not yet available offline, skipping fetch...")
 	} else if errors.Is(err, git.ErrForceNeeded) {
 		message.Debugf("Repo fetch requires force, skipping fetch...")
@@ -149,18 +191,53 @@ func push(repo *git.Repository, localPath string, spinner *message.Spinner) erro
 		message.Warnf("unable to fetch remote cleanly prior to push: %#v", err)
 	}
 
+	// If a provided refspec doesn't push anything, it is just ignored
+	pushRefSpecs := []goConfig.RefSpec{
+		"refs/heads/*:refs/heads/*",
+		onlineRemoteRefPrefix + "*:refs/heads/*",
+		"refs/tags/*:refs/tags/*",
+	}
+
+	if force {
+		for idx, refspec := range pushRefSpecs {
+			pushRefSpecs[idx] = "+" + refspec
+		}
+
+		// Remove the branches and tags upstream deleted or renamed so the git server matches the package
+		staleRefs, err := getStaleRefNames(repo, gitServer, gitCred, pushedRefs)
+		if err != nil {
+			return err
+		}
+		for _, ref := range staleRefs {
+			spinner.Debugf("Removing the stale ref %s", ref)
+			pushRefSpecs = append(pushRefSpecs, goConfig.RefSpec(":"+ref))
+		}
+	}
+
+	// Large repos send their history in chunks first so a push that times out doesn't start over
+	// Shallow repos have little history to split and are pushed by the host git in one go
+	var removeCheckpoint bool
+	if !isShallow(repo) {
+		if removeCheckpoint, err = pushInChunks(ctx, repo, localPath, gitServer, gitCred, spinner); err != nil {
+			return err
+		}
+	}
+
 	// Push all heads and tags to the offline remote
-	err = repo.Push(&git.PushOptions{
-		RemoteName: offlineRemoteName,
-		Auth:       &gitCred,
-		Progress:   spinner,
-		// If a provided refspec doesn't push anything, it is just ignored
-		RefSpecs: []goConfig.RefSpec{
-			"refs/heads/*:refs/heads/*",
-			onlineRemoteRefPrefix + "*:refs/heads/*",
-			"refs/tags/*:refs/tags/*",
-		},
-	})
+	if isShallow(repo) {
+		// go-git can't push commits whose parents it doesn't have, the host git sends them as a shallow update
+		err = pushWithHostGit(ctx, repo, localPath, gitServer, gitCred, pushRefSpecs)
+	} else {
+		err = repo.PushContext(ctx, &git.PushOptions{
+			RemoteName:      offlineRemoteName,
+			Auth:            &gitCred,
+			Progress:        spinner,
+			RefSpecs:        pushRefSpecs,
+			Force:           force,
+			InsecureSkipTLS: gitServer.InsecureSkipVerify,
+			CABundle:        gitServer.CABundle,
+		})
+	}
 
 	if errors.Is(err, git.NoErrAlreadyUpToDate) {
 		spinner.Debugf("Repo already up-to-date")
@@ -168,9 +245,119 @@ func push(repo *git.Repository, localPath string, spinner *message.Spinner) erro
 		return fmt.Errorf("unable to push repo to the gitops service: %w", err)
 	}
 
+	if removeCheckpoint {
+		if err := removeCheckpointBranch(ctx, repo, gitServer, gitCred); err != nil {
+			return err
+		}
+	}
+
 	// Add back the refs we removed just incase this push isn't the last thing
 	// being run and a later task needs to reference them.
 	addRefs(localPath, removedRefs)
 
 	return nil
 }
+
+// pushWithHostGit pushes the refspecs to the offline remote with the git on this machine
+func pushWithHostGit(ctx context.Context, repo *git.Repository, localPath string, gitServer types.GitServerInfo, gitCred http.BasicAuth, refspecs []goConfig.RefSpec) error {
+	message.Debugf("Pushing the shallow repo %s with the host git", localPath)
+
+	// The settings go through GIT_CONFIG_COUNT (git 2.31+) instead of -c, so the credentials never show up in the arguments other users can list
+	auth := base64.StdEncoding.EncodeToString([]byte(gitCred.Username + ":" + gitCred.Password))
+	settings := [][2]string{{"http.extraHeader", "Authorization: Basic " + auth}}
+
+	// The host git reads the CA bundle from a file, which replaces the system CAs for this push
+	if len(gitServer.CABundle) > 0 {
+		caFile, err := os.CreateTemp(config.CommonOptions.TempDirectory, "zarf-git-ca-*.pem")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(caFile.Name())
+		if _, err := caFile.Write(gitServer.CABundle); err != nil {
+			caFile.Close()
+			return err
+		}
+		caFile.Close()
+		settings = append(settings, [2]string{"http.sslCAInfo", caFile.Name()})
+	}
+	if gitServer.InsecureSkipVerify {
+		settings = append(settings, [2]string{"http.sslVerify", "false"})
+	}
+
+	// Unlike the Go clients, the host git sends even the loopback address of a tunnel through HTTP_PROXY, an empty proxy goes direct
+	remote, err := repo.Remote(offlineRemoteName)
+	if err != nil {
+		return err
+	}
+	if config.CommonOptions.NoProxyCluster || utils.IsLoopbackURL(remote.Config().URLs[0]) {
+		settings = append(settings, [2]string{"http.proxy", ""})
+	}
+
+	env := []string{fmt.Sprintf("GIT_CONFIG_COUNT=%d", len(settings))}
+	for idx, setting := range settings {
+		env = append(env, fmt.Sprintf("GIT_CONFIG_KEY_%d=%s", idx, setting[0]), fmt.Sprintf("GIT_CONFIG_VALUE_%d=%s", idx, setting[1]))
+	}
+
+	cmdArgs := []string{"push", offlineRemoteName}
+	for _, refspec := range refspecs {
+		cmdArgs = append(cmdArgs, refspec.String())
+	}
+
+	_, stdErr, err := utils.ExecCommandWithContextDirAndEnv(ctx, localPath, env, false, "git", cmdArgs...)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, stdErr)
+	}
+
+	if strings.Contains(stdErr, "Everything up-to-date") {
+		return git.NoErrAlreadyUpToDate
+	}
+	return nil
+}
+
+// getPushedRefNames returns the names of the branches and tags the repo has on the git server
+func getPushedRefNames(repo *git.Repository) (map[string]bool, error) {
+	references, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	err = references.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		switch {
+		case ref.Name().IsBranch(), ref.Name().IsTag():
+			names[name] = true
+		case strings.HasPrefix(name, onlineRemoteRefPrefix) && name != onlineRemoteRefPrefix+"HEAD":
+			// Refs of the online remote are pushed as branches
+			names["refs/heads/"+strings.TrimPrefix(name, onlineRemoteRefPrefix)] = true
+		}
+		return nil
+	})
+
+	return names, err
+}
+
+// getStaleRefNames returns the branches and tags on the git server that aren't being pushed
+func getStaleRefNames(repo *git.Repository, gitServer types.GitServerInfo, gitCred http.BasicAuth, pushedRefs map[string]bool) ([]string, error) {
+	remote, err := repo.Remote(offlineRemoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteRefs, err := remote.List(&git.ListOptions{Auth: &gitCred, InsecureSkipTLS: gitServer.InsecureSkipVerify, CABundle: gitServer.CABundle})
+	if errors.Is(err, transport.ErrRepositoryNotFound) || errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		// Nothing has been pushed yet
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to list the refs on the git server: %w", err)
+	}
+
+	var staleRefs []string
+	for _, ref := range remoteRefs {
+		if (ref.Name().IsBranch() || ref.Name().IsTag()) && !pushedRefs[ref.Name().String()] {
+			staleRefs = append(staleRefs, ref.Name().String())
+		}
+	}
+
+	return staleRefs, nil
+}
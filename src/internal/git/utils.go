@@ -2,8 +2,6 @@ package git
 
 import (
 	"bufio"
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -13,13 +11,16 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/defenseunicorns/zarf/src/config"
-	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
@@ -30,7 +31,15 @@ type Credential struct {
 
 var (
 	// For further explanation: https://regex101.com/r/zq64q4/1
-	gitURLRegex = regexp.MustCompile(`^(?P<proto>[a-z]+:\/\/)(?P<hostPath>.+?)\/(?P<repo>[\w\-\.]+?)(?P<git>\.git)?(?P<atRef>@(?P<ref>[\w\-\.]+))?$`)
+	// The url can end with the options below in any order
+	gitURLRegex = regexp.MustCompile(`^(?P<proto>[a-z]+:\/\/)(?P<hostPath>.+?)\/(?P<repo>[\w\-\.]+?)(?P<git>\.git)?(?P<atRef>@(?P<ref>[\w\-\.]+))?(?P<options>(?:!shallow|!force)*)$`)
+)
+
+const (
+	// shallowOption packages only the ref (or the default branch) without its history
+	shallowOption = "!shallow"
+	// forceOption force-pushes the repo and removes the branches and tags the package no longer has from the git server
+	forceOption = "!force"
 )
 
 // MutateGitURlsInText Changes the giturl hostname to use the repository Zarf is configured to use
@@ -135,6 +144,21 @@ func FindAuthForHost(baseUrl string) Credential {
 	return matchedCred
 }
 
+// hasURLOption returns true if the repo url ends with the option
+func hasURLOption(url string, option string) bool {
+	matches := gitURLRegex.FindStringSubmatch(url)
+	if len(matches) == 0 {
+		return false
+	}
+	return strings.Contains(matches[gitURLRegex.SubexpIndex("options")], option)
+}
+
+// isShallow returns true if the repo was cloned without its full history
+func isShallow(repo *git.Repository) bool {
+	shallows, err := repo.Storer.Shallow()
+	return err == nil && len(shallows) > 0
+}
+
 // removeLocalBranchRefs removes all refs that are local branches
 // It returns a slice of references deleted
 func removeLocalBranchRefs(gitDirectory string) ([]*plumbing.Reference, error) {
@@ -269,75 +293,15 @@ func deleteBranchIfExists(gitDirectory string, branchName plumbing.ReferenceName
 	return nil
 }
 
-// CreateReadOnlyUser uses the Gitea API to create a non-admin zarf user
-func CreateReadOnlyUser() error {
-	// Establish a git tunnel to send the repo
-	tunnel := k8s.NewZarfTunnel()
-	tunnel.Connect(k8s.ZarfGit, false)
-	defer tunnel.Close()
-
-	tunnelUrl := tunnel.Endpoint()
-	zarfState := config.GetState()
-
-	// Create json representation of the create-user request body
-	createUserBody := map[string]interface{}{
-		"username":             zarfState.GitServer.PullUsername,
-		"password":             zarfState.GitServer.PullPassword,
-		"email":                "zarf-reader@localhost.local",
-		"must_change_password": false,
-	}
-	createUserData, err := json.Marshal(createUserBody)
-	if err != nil {
-		return err
-	}
-
-	// Send API request to create the user
-	createUserEndpoint := fmt.Sprintf("http://%s/api/v1/admin/users", tunnelUrl)
-	createUserRequest, _ := netHttp.NewRequest("POST", createUserEndpoint, bytes.NewBuffer(createUserData))
-	out, err := DoHttpThings(createUserRequest, zarfState.GitServer.PushUsername, zarfState.GitServer.PushPassword)
-	message.Debugf("POST %s:\n%s", createUserEndpoint, string(out))
-	if err != nil {
-		return err
-	}
-
-	// Make sure the user can't create their own repos or orgs
-	updateUserBody := map[string]interface{}{
-		"login_name":                zarfState.GitServer.PushUsername,
-		"max_repo_creation":         0,
-		"allow_create_organization": false,
-	}
-	updateUserData, _ := json.Marshal(updateUserBody)
-	updateUserEndpoint := fmt.Sprintf("http://%s/api/v1/admin/users/%s", tunnelUrl, zarfState.GitServer.PullUsername)
-	updateUserRequest, _ := netHttp.NewRequest("PATCH", updateUserEndpoint, bytes.NewBuffer(updateUserData))
-	out, err = DoHttpThings(updateUserRequest, zarfState.GitServer.PushUsername, zarfState.GitServer.PushPassword)
-	message.Debugf("PATCH %s:\n%s", updateUserEndpoint, string(out))
-	return err
-}
-
-func addReadOnlyUserToRepo(tunnelUrl, repo string) error {
-	// Add the readonly user to the repo
-	addColabBody := map[string]string{
-		"permission": "read",
-	}
-	addColabData, err := json.Marshal(addColabBody)
-	if err != nil {
-		return err
-	}
-
-	// Send API request to add a user as a read-only collaborator to a repo
-	addColabEndpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/collaborators/%s", tunnelUrl, config.GetState().GitServer.PushUsername, repo, config.GetState().GitServer.PullUsername)
-	addColabRequest, _ := netHttp.NewRequest("PUT", addColabEndpoint, bytes.NewBuffer(addColabData))
-	out, err := DoHttpThings(addColabRequest, config.GetState().GitServer.PushUsername, config.GetState().GitServer.PushPassword)
-	message.Debugf("PUT %s:\n%s", addColabEndpoint, string(out))
-	return err
-}
-
 // Add http request boilerplate and perform the request, checking for a successful response
-func DoHttpThings(request *netHttp.Request, username, secret string) ([]byte, error) {
+func DoHttpThings(request *netHttp.Request, gitServer types.GitServerInfo, username, secret string) ([]byte, error) {
 	message.Debugf("Performing %s http request to %#v", request.Method, request.URL)
 
 	// Prep the request with boilerplate
-	client := &netHttp.Client{Timeout: time.Second * 20}
+	client, err := newHTTPClient(gitServer)
+	if err != nil {
+		return []byte{}, err
+	}
 	request.SetBasicAuth(username, secret)
 	request.Header.Add("accept", "application/json")
 	request.Header.Add("Content-Type", "application/json")
@@ -357,3 +321,58 @@ func DoHttpThings(request *netHttp.Request, username, secret string) ([]byte, er
 
 	return responseBody, nil
 }
+
+// pushedBytes counts the packfile bytes go-git has sent to git servers in this run
+var pushedBytes int64
+
+// PushedBytes returns how many bytes of packfiles have been pushed to git servers
+// Pushes over https with a CA bundle or skipped verification, and shallow repos pushed by the host git, aren't counted
+func PushedBytes() int64 {
+	return atomic.LoadInt64(&pushedBytes)
+}
+
+// installHTTPClient sets the client go-git reaches git servers with, which counts the bytes it pushes
+// It goes through the cluster proxy settings with --no-proxy-cluster and the proxy settings of the environment otherwise
+func installHTTPClient() {
+	transport := netHttp.DefaultTransport.(*netHttp.Transport).Clone()
+	if config.CommonOptions.NoProxyCluster {
+		transport.Proxy = config.GetClusterProxy()
+	}
+	httpClient := http.NewClient(&netHttp.Client{Transport: &countingTransport{RoundTripper: transport}})
+	client.InstallProtocol("http", httpClient)
+	client.InstallProtocol("https", httpClient)
+}
+
+// countingTransport adds the bodies of git-receive-pack requests to the pushed bytes
+type countingTransport struct {
+	netHttp.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(request *netHttp.Request) (*netHttp.Response, error) {
+	if request.Method == netHttp.MethodPost && request.Body != nil && strings.HasSuffix(request.URL.Path, "/git-receive-pack") {
+		request = request.Clone(request.Context())
+		request.Body = &countingReader{ReadCloser: request.Body}
+	}
+	return t.RoundTripper.RoundTrip(request)
+}
+
+type countingReader struct {
+	io.ReadCloser
+}
+
+func (r *countingReader) Read(data []byte) (int, error) {
+	n, err := r.ReadCloser.Read(data)
+	atomic.AddInt64(&pushedBytes, int64(n))
+	return n, err
+}
+
+// newHTTPClient returns a client for the API of the git server that uses its custom CA or TLS setting and the cluster proxy settings
+func newHTTPClient(gitServer types.GitServerInfo) (*netHttp.Client, error) {
+	transport, err := utils.NewHTTPTransport(gitServer.CABundle, gitServer.InsecureSkipVerify)
+	if err != nil {
+		return nil, fmt.Errorf("unable to use the git server CA bundle: %w", err)
+	}
+	transport.Proxy = config.GetClusterProxy()
+
+	return &netHttp.Client{Timeout: time.Second * 20, Transport: transport}, nil
+}
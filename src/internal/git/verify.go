@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/go-git/go-git/v5"
+	goConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// VerifyRepos checks that each repo is still on the configured git server along with the ref it was packaged with
+// The returned map is keyed by the repo url and holds a nil error for repos that are still intact
+func VerifyRepos(repos []string) map[string]error {
+	message.Debugf("git.VerifyRepos(%#v)", repos)
+
+	results := make(map[string]error)
+	if len(repos) == 0 {
+		return results
+	}
+
+	gitServerInfo := config.GetGitServerInfo()
+	gitServerURL := gitServerInfo.Address
+
+	// If this is a serviceURL, create a port-forward tunnel to that resource
+	if tunnel, err := k8s.NewTunnelFromServiceURL(gitServerURL); err != nil {
+		message.Debug(err)
+	} else {
+		tunnel.Connect("", false)
+		defer tunnel.Close()
+		gitServerURL = fmt.Sprintf("http://%s", tunnel.Endpoint())
+	}
+	installHTTPClient()
+
+	gitCred := http.BasicAuth{
+		Username: gitServerInfo.PullUsername,
+		Password: gitServerInfo.PullPassword,
+	}
+
+	for _, repoURL := range repos {
+		results[repoURL] = verifyRepo(gitServerURL, gitServerInfo, repoURL, &gitCred)
+	}
+
+	return results
+}
+
+func verifyRepo(gitServerURL string, gitServerInfo types.GitServerInfo, repoURL string, gitCred *http.BasicAuth) error {
+	targetURL, err := transformURL(gitServerURL, repoURL, gitServerInfo.PushUsername)
+	if err != nil {
+		return err
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &goConfig.RemoteConfig{
+		Name: offlineRemoteName,
+		URLs: []string{targetURL},
+	})
+
+	refs, err := remote.List(&git.ListOptions{Auth: gitCred, InsecureSkipTLS: gitServerInfo.InsecureSkipVerify, CABundle: gitServerInfo.CABundle})
+	if err != nil {
+		return fmt.Errorf("unable to list the refs for %s: %w", targetURL, err)
+	}
+
+	matches := gitURLRegex.FindStringSubmatch(repoURL)
+	ref := matches[gitURLRegex.SubexpIndex("ref")]
+
+	// Without a specific ref, the repo only needs to have something in it
+	if ref == "" {
+		if len(refs) == 0 {
+			return fmt.Errorf("no refs found in %s", targetURL)
+		}
+		return nil
+	}
+
+	for _, found := range refs {
+		name := found.Name().String()
+		if name == "refs/tags/"+ref || name == "refs/heads/"+ref || strings.HasPrefix(found.Hash().String(), ref) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("ref %s was not found in %s", ref, targetURL)
+}
@@ -0,0 +1,109 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// The label and annotations helm uses to decide whether an existing resource belongs to a release
+const (
+	helmManagedByLabel             = "app.kubernetes.io/managed-by"
+	helmManagedByValue             = "Helm"
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// adoptExistingResources marks resources that already exist in the cluster as owned by this release so helm will take them over
+func (r *renderer) adoptExistingResources(manifest string) error {
+	resources, err := r.actionConfig.KubeClient.Build(bytes.NewBufferString(manifest), false)
+	if err != nil {
+		return fmt.Errorf("unable to build the resources to adopt: %w", err)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]string{
+				helmManagedByLabel: helmManagedByValue,
+			},
+			"annotations": map[string]string{
+				helmReleaseNameAnnotation:      r.options.ReleaseName,
+				helmReleaseNamespaceAnnotation: r.options.Chart.Namespace,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return resources.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		existing, err := helper.Get(info.Namespace, info.Name)
+		if apierrors.IsNotFound(err) {
+			// Nothing to adopt, helm will create it
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to get the existing %s %s: %w", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+
+		accessor, err := meta.Accessor(existing)
+		if err != nil {
+			return err
+		}
+
+		// Never take a resource away from another helm release
+		releaseName := accessor.GetAnnotations()[helmReleaseNameAnnotation]
+		releaseNamespace := accessor.GetAnnotations()[helmReleaseNamespaceAnnotation]
+		if accessor.GetLabels()[helmManagedByLabel] == helmManagedByValue && releaseName != "" &&
+			(releaseName != r.options.ReleaseName || releaseNamespace != r.options.Chart.Namespace) {
+			return fmt.Errorf("the existing %s %s belongs to the helm release %s/%s and cannot be adopted",
+				info.Mapping.GroupVersionKind.Kind, info.Name, releaseNamespace, releaseName)
+		}
+
+		if releaseName == r.options.ReleaseName && releaseNamespace == r.options.Chart.Namespace {
+			// Already owned by this release
+			return nil
+		}
+
+		message.Debugf("Adopting the existing %s %s into the helm release %s", info.Mapping.GroupVersionKind.Kind, info.Name, r.options.ReleaseName)
+		if _, err := helper.Patch(info.Namespace, info.Name, k8stypes.MergePatchType, patch, nil); err != nil {
+			return fmt.Errorf("unable to adopt the existing %s %s: %w", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+
+		return nil
+	})
+}
+
+// adoptExistingNamespace labels a namespace that was already in the cluster as managed by Zarf
+func adoptExistingNamespace(existingNamespaces *corev1.NamespaceList, name string, releaseName string) error {
+	for _, namespace := range existingNamespaces.Items {
+		if namespace.Name != name || namespace.Labels[config.ZarfManagedByLabel] == "zarf" {
+			continue
+		}
+
+		message.Debugf("Adopting the existing namespace %s", name)
+		if namespace.Labels == nil {
+			namespace.Labels = make(map[string]string)
+		}
+		namespace.Labels[config.ZarfManagedByLabel] = "zarf"
+		namespace.Labels["zarf-helm-release"] = releaseName
+
+		if _, err := k8s.UpdateNamespace(&namespace); err != nil {
+			return fmt.Errorf("unable to adopt the existing namespace %s: %w", name, err)
+		}
+	}
+	return nil
+}
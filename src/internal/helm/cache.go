@@ -0,0 +1,119 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// The chart cache index maps where each chart was downloaded from to the sha256 digest of its tarball
+const chartCacheIndex = "index.json"
+
+// getCachedChart copies a chart an earlier create downloaded to the destination tarball, so it is reused without contacting the repo
+// It returns false when the chart isn't cached or its tarball no longer matches its digest, which drops it from the cache
+func getCachedChart(chart types.ZarfChart, destinationTarball string) bool {
+	key, ok := getChartCacheKey(chart)
+	if !ok {
+		return false
+	}
+
+	cachePath := getChartCachePath()
+	index := loadChartCacheIndex(cachePath)
+	digest, ok := index[key]
+	if !ok {
+		return false
+	}
+
+	cachedTarball := filepath.Join(cachePath, digest+".tgz")
+	if actual, err := utils.GetSha256Sum(cachedTarball); err != nil || actual != digest {
+		message.Debugf("The cached tarball of %s doesn't match its digest %s, downloading it again", key, digest)
+		_ = os.Remove(cachedTarball)
+		delete(index, key)
+		_ = saveChartCacheIndex(cachePath, index)
+		return false
+	}
+
+	if err := utils.CreatePathAndCopy(cachedTarball, destinationTarball); err != nil {
+		message.Debugf("Unable to copy the cached tarball of %s: %s", key, err.Error())
+		return false
+	}
+
+	message.Debugf("Using the cached tarball %s for %s", digest, key)
+	return true
+}
+
+// cacheChart adds a downloaded chart tarball to the cache under its digest, where any package that uses the same chart finds it
+func cacheChart(chart types.ZarfChart, tarball string) error {
+	key, ok := getChartCacheKey(chart)
+	if !ok {
+		return nil
+	}
+
+	digest, err := utils.GetSha256Sum(tarball)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so an interrupted create never leaves a partial tarball under the digest
+	cachePath := getChartCachePath()
+	cachedTarball := filepath.Join(cachePath, digest+".tgz")
+	if err := utils.CreatePathAndCopy(tarball, cachedTarball+".tmp"); err != nil {
+		return err
+	}
+	if err := os.Rename(cachedTarball+".tmp", cachedTarball); err != nil {
+		return err
+	}
+
+	index := loadChartCacheIndex(cachePath)
+	index[key] = digest
+	return saveChartCacheIndex(cachePath, index)
+}
+
+// getChartCacheKey returns the key a chart is cached under, only charts pinned to an exact version are cached
+// since a version range can resolve to a newer chart than the one in the cache
+func getChartCacheKey(chart types.ZarfChart) (string, bool) {
+	if _, err := semver.NewVersion(chart.Version); err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s %s %s", chart.Url, chart.Name, chart.Version), true
+}
+
+func getChartCachePath() string {
+	return filepath.Join(config.GetAbsCachePath(), config.ZarfChartCacheDir)
+}
+
+// loadChartCacheIndex reads the chart cache index, which is empty until the first chart is cached
+func loadChartCacheIndex(cachePath string) map[string]string {
+	index := make(map[string]string)
+	if contents, err := os.ReadFile(filepath.Join(cachePath, chartCacheIndex)); err == nil {
+		if err := json.Unmarshal(contents, &index); err != nil {
+			message.Debugf("Unable to read the chart cache index, starting a new one: %s", err.Error())
+			return make(map[string]string)
+		}
+	}
+	return index
+}
+
+// saveChartCacheIndex replaces the chart cache index in one step so concurrent creates always read a complete index
+func saveChartCacheIndex(cachePath string, index map[string]string) error {
+	contents, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	indexPath := filepath.Join(cachePath, chartCacheIndex)
+	if err := utils.CreateFilePath(indexPath); err != nil {
+		return err
+	}
+	if err := os.WriteFile(indexPath+".tmp", contents, 0600); err != nil {
+		return err
+	}
+	return os.Rename(indexPath+".tmp", indexPath)
+}
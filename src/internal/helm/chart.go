@@ -10,12 +10,14 @@ import (
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/types"
 
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"helm.sh/helm/v3/pkg/action"
 
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/errors"
 )
 
 type ChartOptions struct {
@@ -26,6 +28,12 @@ type ChartOptions struct {
 	ChartOverride     *chart.Chart
 	ValueOverride     map[string]any
 	Component         types.ZarfComponent
+	// Attempts is how many times to try the install or upgrade, 0 uses the chart's retries or the default of 4
+	Attempts int
+	// Deadline is when the install or upgrade must finish by, the zero time means there is no deadline
+	Deadline time.Time
+	// Timeout is how long helm waits for a chart that doesn't set its own timeout, 0 uses the default of 15 minutes
+	Timeout time.Duration
 }
 
 // InstallOrUpgradeChart performs a helm install of the given chart
@@ -63,23 +71,56 @@ func InstallOrUpgradeChart(options ChartOptions) (types.ConnectStrings, string)
 		spinner.Fatalf(err, "Unable to initialize the K8s client")
 	}
 
+	// Surface warnings from the release's pods as they happen instead of after helm times out
+	if options.Chart.Namespace != "" {
+		selector := fmt.Sprintf("app.kubernetes.io/instance=%s", options.ReleaseName)
+		stopWatching, err := k8s.WatchWarnings([]string{options.Chart.Namespace}, selector, time.Now(), func(warning string) {
+			message.Warnf("%s", warning)
+		})
+		if err != nil {
+			message.Debugf("Unable to watch the namespace %s for warnings: %s", options.Chart.Namespace, err.Error())
+		} else {
+			defer stopWatching()
+		}
+	}
+
+	attempts := options.Attempts
+	if options.Chart.Retries > 0 {
+		attempts = options.Chart.Retries + 1
+	} else if attempts < 1 {
+		attempts = 4
+	}
+
+	atomic := isAtomic(options.Chart)
+	keepFailed := !atomic && (options.Chart.KeepFailed || config.DeployOptions.KeepFailedCharts)
+
 	attempt := 0
 	for {
 		attempt++
 
-		spinner.Updatef("Attempt %d of 3 to install chart", attempt)
+		spinner.Updatef("Attempt %d of %d to install chart", attempt, attempts)
 		histClient := action.NewHistory(actionConfig)
 		histClient.Max = 1
 
-		if attempt > 4 {
-			// On total failure try to rollback or uninstall
-			if histClient.Version > 1 {
+		deadlinePassed := !options.Deadline.IsZero() && time.Now().After(options.Deadline)
+		if attempt > attempts || deadlinePassed {
+			// On total failure try to rollback or uninstall, atomic releases were already cleaned up by helm
+			if keepFailed {
+				spinner.Updatef("Leaving the failed release %s in place", options.ReleaseName)
+				message.Notef("The failed release %s was left in place, inspect it with 'helm history %s -n %s'",
+					options.ReleaseName, options.ReleaseName, options.Chart.Namespace)
+			} else if atomic {
+				spinner.Updatef("The failed release was already rolled back by helm")
+			} else if histClient.Version > 1 {
 				spinner.Updatef("Performing chart rollback")
 				_ = rollbackChart(actionConfig, options.ReleaseName)
 			} else {
 				spinner.Updatef("Performing chart uninstall")
 				_, _ = uninstallChart(actionConfig, options.ReleaseName)
 			}
+			if deadlinePassed {
+				spinner.Fatalf(nil, "Unable to complete helm chart install/upgrade before the charts timeout or the deploy deadline")
+			}
 			spinner.Fatalf(nil, "Unable to complete helm chart install/upgrade")
 			break
 		}
@@ -96,6 +137,12 @@ func InstallOrUpgradeChart(options ChartOptions) (types.ConnectStrings, string)
 
 		case nil:
 			// Otherwise, there is a prior release so upgrade it
+			if attempt == 1 {
+				spinner.Updatef("Comparing the values of the deployed release")
+				if err := showValuesDiff(actionConfig, options); err != nil {
+					message.Warnf("Unable to compare the values of %s with the deployed release: %s", options.ReleaseName, err.Error())
+				}
+			}
 			spinner.Updatef("Attempting chart upgrade")
 			output, err = upgradeChart(actionConfig, options, postRender)
 
@@ -110,6 +157,7 @@ func InstallOrUpgradeChart(options ChartOptions) (types.ConnectStrings, string)
 			time.Sleep(10 * time.Second)
 		} else {
 			spinner.Debugf(output.Info.Description)
+			labelTestPods(output)
 			spinner.Success()
 			break
 		}
@@ -120,6 +168,29 @@ func InstallOrUpgradeChart(options ChartOptions) (types.ConnectStrings, string)
 	return postRender.connectStrings, installedChartName
 }
 
+// labelTestPods labels the helm test pods of the release that are in the cluster with the run ID, so they are cleaned up with the other temporary resources
+// Helm doesn't pass hooks through the post-renderer, so they are labeled once the release is done
+func labelTestPods(rel *release.Release) {
+	for _, hook := range rel.Hooks {
+		if hook.Kind != "Pod" || !isTestHook(hook) {
+			continue
+		}
+		if err := k8s.LabelPod(rel.Namespace, hook.Name, map[string]string{config.ZarfRunLabel: config.GetRunID()}); err != nil && !errors.IsNotFound(err) {
+			message.Debugf("Unable to label the test pod %s/%s: %s", rel.Namespace, hook.Name, err.Error())
+		}
+	}
+}
+
+// isTestHook returns true if a helm hook runs for helm test
+func isTestHook(hook *release.Hook) bool {
+	for _, event := range hook.Events {
+		if event == release.HookTest {
+			return true
+		}
+	}
+	return false
+}
+
 // TemplateChart generates a helm template from a given chart
 func TemplateChart(options ChartOptions) (string, error) {
 	message.Debugf("helm.TemplateChart(%#v)", options)
@@ -167,7 +238,7 @@ func TemplateChart(options ChartOptions) (string, error) {
 }
 
 // GenerateChart generates a helm chart for a given Zarf manifest.
-func GenerateChart(basePath string, manifest types.ZarfManifest, component types.ZarfComponent) (types.ConnectStrings, string) {
+func GenerateChart(basePath string, manifest types.ZarfManifest, component types.ZarfComponent, attempts int, deadline time.Time, timeout time.Duration) (types.ConnectStrings, string) {
 	message.Debugf("helm.GenerateChart(%s, %#v, %s)", basePath, manifest, component.Name)
 	spinner := message.NewProgressSpinner("Starting helm chart generation %s", manifest.Name)
 	defer spinner.Stop()
@@ -213,6 +284,9 @@ func GenerateChart(basePath string, manifest types.ZarfManifest, component types
 		ValueOverride: map[string]any{},
 		// Images needed for eventual post-render templating
 		Component: component,
+		Attempts:  attempts,
+		Deadline:  deadline,
+		Timeout:   timeout,
 	}
 
 	spinner.Success()
@@ -225,14 +299,20 @@ func installChart(actionConfig *action.Configuration, options ChartOptions, post
 	// Bind the helm action
 	client := action.NewInstall(actionConfig)
 
-	// Let each chart run for 15 minutes
-	client.Timeout = 15 * time.Minute
+	// Let each chart run for 15 minutes unless the chart sets its own timeout (this also bounds any hooks)
+	client.Timeout = getChartTimeout(options)
 
 	// Default helm behavior for Zarf is to wait for the resources to deploy, NoWait overrides that for special cases (such as data-injection)
 	client.Wait = !options.Chart.NoWait
 
-	// We need to include CRDs or operator installations will fail spectacularly
-	client.SkipCRDs = false
+	// Optionally have helm uninstall the release as soon as an attempt fails
+	client.Atomic = client.Wait && isAtomic(options.Chart)
+
+	// Optionally wait for any jobs (such as db migrations) to complete as well
+	client.WaitForJobs = client.Wait && options.Chart.WaitForJobs
+
+	// We need to include CRDs or operator installations will fail spectacularly, unless the chart opts out
+	client.SkipCRDs = options.Chart.SkipCRDs
 
 	// Must be unique per-namespace and < 53 characters. @todo: restrict helm loadedChart name to this
 	client.ReleaseName = options.ReleaseName
@@ -256,12 +336,19 @@ func upgradeChart(actionConfig *action.Configuration, options ChartOptions, post
 	message.Debugf("helm.upgradeChart(%#v, %#v, %#v)", actionConfig, options, postRender)
 	client := action.NewUpgrade(actionConfig)
 
-	// Let each chart run for 15 minutes
-	client.Timeout = 15 * time.Minute
+	// Let each chart run for 15 minutes unless the chart sets its own timeout (this also bounds any hooks)
+	client.Timeout = getChartTimeout(options)
 
 	// Default helm behavior for Zarf is to wait for the resources to deploy, NoWait overrides that for special cases (such as data-injection)k3
 	client.Wait = !options.Chart.NoWait
 
+	// Optionally have helm roll back the release as soon as an attempt fails
+	client.Atomic = client.Wait && isAtomic(options.Chart)
+
+	// Optionally wait for any jobs (such as db migrations) to complete as well
+	client.WaitForJobs = client.Wait && options.Chart.WaitForJobs
+
+	// Helm never upgrades CRDs, Zarf applies them itself below
 	client.SkipCRDs = true
 
 	// Namespace must be specified
@@ -275,10 +362,48 @@ func upgradeChart(actionConfig *action.Configuration, options ChartOptions, post
 		return nil, fmt.Errorf("unable to load chart data: %w", err)
 	}
 
+	// Update the CRDs first so the upgraded resources can use any new fields
+	if !options.Chart.SkipCRDs {
+		if err := upgradeCRDs(actionConfig, loadedChart, options.Chart.ForceCRDs); err != nil {
+			return nil, fmt.Errorf("unable to upgrade the chart CRDs: %w", err)
+		}
+	}
+
 	// Perform the loadedChart upgrade
 	return client.Run(options.ReleaseName, loadedChart, chartValues)
 }
 
+// isAtomic returns true when helm should clean up after every failed attempt of a chart
+func isAtomic(chart types.ZarfChart) bool {
+	return chart.Atomic || (config.DeployOptions.AtomicCharts && !chart.KeepFailed)
+}
+
+// getChartTimeout returns the helm timeout for a chart, defaulting to the timeout of the deploy or 15 minutes and never running past the deadline
+func getChartTimeout(options ChartOptions) time.Duration {
+	chart := options.Chart
+	timeout := 15 * time.Minute
+
+	if options.Timeout > 0 {
+		timeout = options.Timeout
+	}
+
+	if chart.Timeout != "" {
+		if chartTimeout, err := time.ParseDuration(chart.Timeout); err == nil {
+			timeout = chartTimeout
+		} else {
+			message.Warnf("Unable to parse the timeout %s for chart %s, using the default", chart.Timeout, chart.Name)
+		}
+	}
+
+	if !options.Deadline.IsZero() {
+		if remaining := time.Until(options.Deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	return timeout
+}
+
 func rollbackChart(actionConfig *action.Configuration, name string) error {
 	message.Debugf("helm.rollbackChart(%#v, %s)", actionConfig, name)
 	client := action.NewRollback(actionConfig)
@@ -313,7 +438,7 @@ func loadChartData(options ChartOptions) (*chart.Chart, map[string]any, error) {
 			return nil, nil, fmt.Errorf("unable to load chart tarball: %w", err)
 		}
 
-		chartValues, err = parseChartValues(options)
+		chartValues, err = parseChartValues(options, loadedChart)
 		if err != nil {
 			return loadedChart, nil, fmt.Errorf("unable to parse chart values: %w", err)
 		}
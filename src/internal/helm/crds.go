@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// The field manager Zarf server-side applies chart CRDs with
+const crdFieldManager = "zarf"
+
+// upgradeCRDs server-side applies the contents of a chart's crds/ directory since helm only ever creates CRDs on install
+// Fields another manager owns are only taken over with force, otherwise the conflicts are returned
+func upgradeCRDs(actionConfig *action.Configuration, loadedChart *chart.Chart, force bool) error {
+	crds := loadedChart.CRDObjects()
+	if len(crds) == 0 {
+		return nil
+	}
+
+	var manifest bytes.Buffer
+	for _, crd := range crds {
+		message.Debugf("Applying the CRDs in %s", crd.Filename)
+		manifest.WriteString("\n---\n")
+		manifest.Write(crd.File.Data)
+	}
+
+	resources, err := actionConfig.KubeClient.Build(&manifest, false)
+	if err != nil {
+		return fmt.Errorf("unable to build the chart CRDs: %w", err)
+	}
+
+	options := &metav1.PatchOptions{FieldManager: crdFieldManager, Force: &force}
+
+	return resources.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(info.Object)
+		if err != nil {
+			return err
+		}
+
+		helper := resource.NewHelper(info.Client, info.Mapping)
+		if _, err := helper.Patch(info.Namespace, info.Name, k8stypes.ApplyPatchType, data, options); errors.IsConflict(err) {
+			return fmt.Errorf("the CRD %s has fields owned by other field managers, set forceCRDs on the chart to take them over: %w", info.Name, err)
+		} else if err != nil {
+			return fmt.Errorf("unable to apply the CRD %s: %w", info.Name, err)
+		}
+
+		return nil
+	})
+}
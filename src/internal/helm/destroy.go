@@ -5,6 +5,7 @@ import (
 
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
 )
 
 func Destroy(purgeAllZarfInstallations bool) {
@@ -63,3 +64,43 @@ func RemoveChart(namespace string, name string, spinner *message.Spinner) error
 	message.Debug(response)
 	return err
 }
+
+// GetReleaseStatus returns the status of the latest revision of a helm release
+func GetReleaseStatus(namespace string, name string, spinner *message.Spinner) (release.Status, error) {
+	actionConfig, err := createActionConfig(namespace, spinner)
+	if err != nil {
+		return release.StatusUnknown, err
+	}
+
+	client := action.NewStatus(actionConfig)
+	installedRelease, err := client.Run(name)
+	if err != nil {
+		return release.StatusUnknown, err
+	}
+
+	return installedRelease.Info.Status, nil
+}
+
+// GetReleaseNames returns the names of the helm releases in the namespace, including failed and pending ones
+func GetReleaseNames(namespace string) ([]string, error) {
+	spinner := message.NewProgressSpinner("Listing the helm releases in the %s namespace", namespace)
+	defer spinner.Stop()
+
+	actionConfig, err := createActionConfig(namespace, spinner)
+	if err != nil {
+		return nil, err
+	}
+
+	list := action.NewList(actionConfig)
+	list.All = true
+	releases, err := list.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, release := range releases {
+		names = append(names, release.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,132 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// sensitiveKeyPattern matches value names that usually hold credentials
+var sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private[-_]?key|api[-_]?key|auth)`)
+
+// showValuesDiff prints how the computed values of the deployed release differ from the ones the package is about to upgrade it with
+func showValuesDiff(actionConfig *action.Configuration, options ChartOptions) error {
+	message.Debugf("helm.showValuesDiff(%#v)", options)
+
+	deployed, err := action.NewGet(actionConfig).Run(options.ReleaseName)
+	if err != nil {
+		return fmt.Errorf("unable to get the deployed release: %w", err)
+	}
+	deployedValues, err := chartutil.CoalesceValues(deployed.Chart, deployed.Config)
+	if err != nil {
+		return fmt.Errorf("unable to compute the values of the deployed release: %w", err)
+	}
+
+	loadedChart, chartValues, err := loadChartData(options)
+	if err != nil {
+		return fmt.Errorf("unable to load chart data: %w", err)
+	}
+	incomingValues, err := chartutil.CoalesceValues(loadedChart, chartValues)
+	if err != nil {
+		return fmt.Errorf("unable to compute the values of the incoming chart: %w", err)
+	}
+
+	changes := diffValues(flattenValues("", deployedValues), flattenValues("", incomingValues))
+	if len(changes) == 0 {
+		message.Debugf("The values of %s are unchanged", options.ReleaseName)
+		return nil
+	}
+
+	message.Notef("Upgrading %s from chart %s changes %d values:\n%s",
+		options.ReleaseName, deployed.Chart.Metadata.Version, len(changes), strings.Join(changes, "\n"))
+	return nil
+}
+
+// flattenValues turns nested values into a map of dotted paths to their rendered values, list items get their index like env[0].value
+// Every value is flattened before it is masked, so a credential inside a list is matched by its own name
+func flattenValues(prefix string, values map[string]any) map[string]string {
+	flat := make(map[string]string)
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		flattenValue(path, value, flat)
+	}
+	return flat
+}
+
+// flattenValue adds the value at the path to flat, recursing into maps and lists that aren't empty
+func flattenValue(path string, value any, flat map[string]string) {
+	switch nested := value.(type) {
+	case map[string]any:
+		if len(nested) > 0 {
+			for nestedPath, nestedValue := range flattenValues(path, nested) {
+				flat[nestedPath] = nestedValue
+			}
+			return
+		}
+	case []any:
+		if len(nested) > 0 {
+			for idx, item := range nested {
+				flattenValue(fmt.Sprintf("%s[%d]", path, idx), item, flat)
+			}
+			return
+		}
+	}
+
+	rendered, err := json.Marshal(value)
+	if err != nil {
+		rendered = []byte(fmt.Sprintf("%v", value))
+	}
+	flat[path] = string(rendered)
+}
+
+// diffValues returns a line for every path added (+), removed (-) or changed (~) between the two sets of values
+func diffValues(deployed, incoming map[string]string) []string {
+	var changes []string
+	for path, incomingValue := range incoming {
+		deployedValue, ok := deployed[path]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("  + %s: %s", path, maskValue(path, incomingValue)))
+		case deployedValue != incomingValue:
+			if isSensitiveValue(path, deployedValue) || isSensitiveValue(path, incomingValue) {
+				changes = append(changes, fmt.Sprintf("  ~ %s: (sensitive value changed)", path))
+			} else {
+				changes = append(changes, fmt.Sprintf("  ~ %s: %s -> %s", path, deployedValue, incomingValue))
+			}
+		}
+	}
+	for path, deployedValue := range deployed {
+		if _, ok := incoming[path]; !ok {
+			changes = append(changes, fmt.Sprintf("  - %s: %s", path, maskValue(path, deployedValue)))
+		}
+	}
+
+	// Sort by path so related values stay together
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i][4:] < changes[j][4:]
+	})
+	return changes
+}
+
+// maskValue hides the value when its name looks like a credential or it contains a value Zarf masks
+func maskValue(path, value string) string {
+	if isSensitiveValue(path, value) {
+		return "********"
+	}
+	return value
+}
+
+// isSensitiveValue returns true when the last part of the path looks like a credential or the value holds a masked value
+func isSensitiveValue(path, value string) bool {
+	name := path[strings.LastIndex(path, ".")+1:]
+	return sensitiveKeyPattern.MatchString(name) || message.Mask(value) != value
+}
@@ -0,0 +1,68 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   map[string]string
+	}{
+		{
+			name:   "scalars",
+			values: map[string]any{"replicas": 2, "image": "nginx", "enabled": true},
+			want:   map[string]string{"replicas": "2", "image": `"nginx"`, "enabled": "true"},
+		},
+		{
+			name:   "nested maps become dotted paths",
+			values: map[string]any{"service": map[string]any{"port": 80, "tls": map[string]any{"enabled": false}}},
+			want:   map[string]string{"service.port": "80", "service.tls.enabled": "false"},
+		},
+		{
+			name:   "lists get indexed paths",
+			values: map[string]any{"args": []any{"--verbose", "--port=80"}},
+			want:   map[string]string{"args[0]": `"--verbose"`, "args[1]": `"--port=80"`},
+		},
+		{
+			name: "maps inside lists",
+			values: map[string]any{"env": []any{
+				map[string]any{"name": "USER", "value": "admin"},
+				map[string]any{"name": "PASS", "password": "hunter2"},
+			}},
+			want: map[string]string{
+				"env[0].name":     `"USER"`,
+				"env[0].value":    `"admin"`,
+				"env[1].name":     `"PASS"`,
+				"env[1].password": `"hunter2"`,
+			},
+		},
+		{
+			name:   "lists inside lists",
+			values: map[string]any{"matrix": []any{[]any{1, 2}}},
+			want:   map[string]string{"matrix[0][0]": "1", "matrix[0][1]": "2"},
+		},
+		{
+			name:   "empty maps and lists are kept as values",
+			values: map[string]any{"labels": map[string]any{}, "tolerations": []any{}},
+			want:   map[string]string{"labels": "{}", "tolerations": "[]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, flattenValues("", tt.values))
+		})
+	}
+}
+
+func TestDiffValuesMasksListItems(t *testing.T) {
+	deployed := flattenValues("", map[string]any{"users": []any{map[string]any{"name": "admin", "password": "old"}}})
+	incoming := flattenValues("", map[string]any{"users": []any{map[string]any{"name": "admin", "password": "new"}}})
+
+	changes := diffValues(deployed, incoming)
+	assert.Equal(t, []string{"  ~ users[0].password: (sensitive value changed)"}, changes)
+}
@@ -50,28 +50,45 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 	}
 	path := filepath.Join(tempDir, "chart.yaml")
 
-	// Write the context to a file for processing
-	if err := utils.WriteFile(path, renderedManifests.Bytes()); err != nil {
-		return nil, fmt.Errorf("unable to write the post-render file for the helm chart")
+	// Use helm to split the manifest bytes (same call used by helm to pass this data to postRender)
+	_, resources, err := releaseutil.SortManifests(map[string]string{path: renderedManifests.String()},
+		r.actionConfig.Capabilities.APIVersions,
+		releaseutil.InstallOrder,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("error re-rendering helm output: %w", err)
+	}
+
+	// Write each resource that has not opted out of Zarf to its own file for processing
+	skipped := make(map[int]bool)
+	for idx, resource := range resources {
+		if skipZarfInjection(resource.Content) {
+			message.Debugf("Skipping Zarf post-render modifications for %s", resource.Name)
+			skipped[idx] = true
+			continue
+		}
+
+		resourcePath := filepath.Join(tempDir, fmt.Sprintf("resource-%d.yaml", idx))
+		if err := utils.WriteFile(resourcePath, []byte(resource.Content)); err != nil {
+			return nil, fmt.Errorf("unable to write the post-render file for the helm chart")
+		}
 	}
 
 	// Run the template engine against the chart output
 	k8s.ProcessYamlFilesInPath(tempDir, r.options.Component)
 
 	// Read back the templated file contents
-	buff, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("error reading temporary post-rendered helm chart: %w", err)
-	}
-
-	// Use helm to resplit the manifest byte (same call used by helm to pass this data to postRender)
-	_, resources, err := releaseutil.SortManifests(map[string]string{path: string(buff)},
-		r.actionConfig.Capabilities.APIVersions,
-		releaseutil.InstallOrder,
-	)
+	for idx := range resources {
+		if skipped[idx] {
+			continue
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf("error re-rendering helm output: %w", err)
+		buff, err := os.ReadFile(filepath.Join(tempDir, fmt.Sprintf("resource-%d.yaml", idx)))
+		if err != nil {
+			return nil, fmt.Errorf("error reading temporary post-rendered helm chart: %w", err)
+		}
+		resources[idx].Content = string(buff)
 	}
 
 	// Dump the contents for debugging
@@ -84,7 +101,13 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 		message.Errorf(err, "Problem parsing post-render manifest data")
 	} else {
 		// Otherwise, loop over the resources,
-		for _, resource := range resources {
+		for idx, resource := range resources {
+
+			// Pass resources that opted out of Zarf straight back to helm
+			if skipped[idx] {
+				fmt.Fprintf(finalManifestsOutput, "---\n# Source: %s\n%s\n", resource.Name, resource.Content)
+				continue
+			}
 
 			// parse to unstructured to have access to more data than just the name
 			rawData := &unstructured.Unstructured{}
@@ -147,10 +170,11 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 	for name, namespace := range r.namespaces {
 
 		// Check to see if this namespace already exists
-		var existingNamespace bool
+		var existingNamespace, managedNamespace bool
 		for _, serverNamespace := range existingNamespaces.Items {
 			if serverNamespace.Name == name {
 				existingNamespace = true
+				managedNamespace = serverNamespace.Labels[config.ZarfManagedByLabel] == "zarf"
 			}
 		}
 
@@ -159,6 +183,26 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 			if _, err := k8s.CreateNamespace(name, namespace); err != nil {
 				return nil, fmt.Errorf("unable to create the missing namespace %s", name)
 			}
+		} else if config.DeployOptions.AdoptExistingResources {
+			// Take over management of the namespace that was already there
+			if err := adoptExistingNamespace(existingNamespaces, name, r.options.ReleaseName); err != nil {
+				return nil, err
+			}
+		}
+
+		// In opt-in mode the agent only mutates the namespaces Zarf manages once they are labeled for it
+		if (!existingNamespace || managedNamespace || config.DeployOptions.AdoptExistingResources) && config.GetState().AgentWebhook.Mode == k8s.AgentModeOptIn {
+			if err := k8s.EnableAgentInNamespace(name); err != nil {
+				return nil, fmt.Errorf("unable to enable the Zarf agent in the %s namespace: %w", name, err)
+			}
+		}
+
+		// Size the namespaces Zarf manages with the guardrails of the component, redeploys pick up any new sizes
+		guardrails := r.options.Component.NamespaceGuardrails
+		if (!existingNamespace || managedNamespace) && k8s.HasNamespaceGuardrails(guardrails) {
+			if err := k8s.ApplyNamespaceGuardrails(name, guardrails); err != nil {
+				return nil, fmt.Errorf("unable to add the guardrails to the %s namespace: %w", name, err)
+			}
 		}
 
 		// Create the secret
@@ -187,9 +231,27 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 
 	}
 
+	// Mark resources that already exist as belonging to this release so helm can take them over
+	if config.DeployOptions.AdoptExistingResources {
+		if err := r.adoptExistingResources(finalManifestsOutput.String()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Cleanup the temp file
 	_ = os.RemoveAll(tempDir)
 
 	// Send the bytes back to helm
 	return finalManifestsOutput, nil
 }
+
+// skipZarfInjection returns true if a rendered resource has opted out of Zarf's post-render modifications
+func skipZarfInjection(content string) bool {
+	rawData := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(content), rawData); err != nil {
+		// Let the normal processing surface any parsing errors
+		return false
+	}
+
+	return rawData.GetAnnotations()[config.ZarfSkipInjectionAnnotation] == "true"
+}
@@ -3,7 +3,9 @@ package helm
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/types"
 
 	"github.com/defenseunicorns/zarf/src/internal/git"
@@ -14,6 +16,7 @@ import (
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/repo"
 )
 
@@ -75,11 +78,18 @@ func DownloadChartFromGit(chart types.ZarfChart, destination string) string {
 	return name
 }
 
-// DownloadPublishedChart loads a specific chart version from a remote repo
+// DownloadPublishedChart loads a specific chart version from a remote repo or OCI registry
 func DownloadPublishedChart(chart types.ZarfChart, destination string) {
 	spinner := message.NewProgressSpinner("Processing helm chart %s:%s from repo %s", chart.Name, chart.Version, chart.Url)
 	defer spinner.Stop()
 
+	// Charts already downloaded by an earlier create, of this package or another, are reused without contacting the repo
+	destinationTarball := StandardName(destination, chart) + ".tgz"
+	if getCachedChart(chart, destinationTarball) {
+		spinner.Successf("Using the cached helm chart %s:%s", chart.Name, chart.Version)
+		return
+	}
+
 	// Set up the helm pull config
 	pull := action.NewPull()
 	pull.Settings = cli.New()
@@ -91,16 +101,37 @@ func DownloadPublishedChart(chart types.ZarfChart, destination string) {
 		Getters: getter.All(pull.Settings),
 	}
 
-	// @todo: process OCI-based charts
-
-	// Perform simple chart download
-	chartURL, err := repo.FindChartInRepoURL(chart.Url, chart.Name, chart.Version, pull.CertFile, pull.KeyFile, pull.CaFile, getter.All(pull.Settings))
-	if err != nil {
-		spinner.Fatalf(err, "Unable to pull the helm chart")
+	var chartURL string
+	var err error
+	if registry.IsOCI(chart.Url) {
+		// OCI charts are referenced directly (oci://ghcr.io/org/charts/name) and use the credentials from 'helm registry login'
+		regClient, err := registry.NewClient(registry.ClientOptCredentialsFile(pull.Settings.RegistryConfig))
+		if err != nil {
+			spinner.Fatalf(err, "Unable to create a helm registry client")
+		}
+		chartDownloader.RegistryClient = regClient
+		chartDownloader.Options = append(chartDownloader.Options, getter.WithRegistryClient(regClient))
+		chartURL = chart.Url
+	} else {
+		// Private repos get their credentials and TLS settings from the helm repositories file or the zarf-config
+		entry := getRepoEntry(chart.Url, pull.Settings.RepositoryConfig)
+		chartDownloader.Options = append(chartDownloader.Options,
+			getter.WithBasicAuth(entry.Username, entry.Password),
+			getter.WithPassCredentialsAll(entry.PassCredentialsAll),
+			getter.WithTLSClientConfig(entry.CertFile, entry.KeyFile, entry.CAFile),
+			getter.WithInsecureSkipVerifyTLS(entry.InsecureSkipTLSverify),
+		)
+
+		// Perform simple chart download
+		chartURL, err = repo.FindChartInAuthAndTLSAndPassRepoURL(chart.Url, entry.Username, entry.Password, chart.Name, chart.Version,
+			entry.CertFile, entry.KeyFile, entry.CAFile, entry.InsecureSkipTLSverify, entry.PassCredentialsAll, getter.All(pull.Settings))
+		if err != nil {
+			spinner.Fatalf(err, "Unable to pull the helm chart")
+		}
 	}
 
 	// Download the file (we don't control what name helm creates here)
-	saved, _, err := chartDownloader.DownloadTo(chartURL, pull.Version, destination)
+	saved, _, err := chartDownloader.DownloadTo(chartURL, chart.Version, destination)
 	if err != nil {
 		spinner.Fatalf(err, "Unable to download the helm chart")
 	}
@@ -112,11 +143,52 @@ func DownloadPublishedChart(chart types.ZarfChart, destination string) {
 	}
 
 	// Ensure the name is consistent for deployments
-	destinationTarball := StandardName(destination, chart) + ".tgz"
 	err = os.Rename(saved, destinationTarball)
 	if err != nil {
 		spinner.Fatalf(err, "Unable to save the chart tarball")
 	}
 
+	if err := cacheChart(chart, destinationTarball); err != nil {
+		spinner.Warnf("Unable to cache the helm chart %s:%s: %s", chart.Name, chart.Version, err.Error())
+	}
+
 	spinner.Success()
 }
+
+// getRepoEntry returns the credentials and TLS settings for a chart repo, preferring the zarf-config over the helm repositories file
+func getRepoEntry(repoURL, repositoryConfig string) repo.Entry {
+	normalize := func(url string) string {
+		return strings.TrimSuffix(url, "/") + "/"
+	}
+
+	entry := repo.Entry{URL: repoURL}
+
+	if repoFile, err := repo.LoadFile(repositoryConfig); err == nil {
+		for _, helmEntry := range repoFile.Repositories {
+			if strings.HasPrefix(normalize(repoURL), normalize(helmEntry.URL)) {
+				message.Debugf("Using the credentials of the helm repo %s for %s", helmEntry.Name, repoURL)
+				entry = *helmEntry
+				break
+			}
+		}
+	}
+
+	for _, zarfEntry := range config.CreateOptions.HelmRepositories {
+		if strings.HasPrefix(normalize(repoURL), normalize(zarfEntry.URL)) {
+			message.Debugf("Using the credentials from the zarf-config for %s", repoURL)
+			entry = repo.Entry{
+				URL:                   zarfEntry.URL,
+				Username:              zarfEntry.Username,
+				Password:              zarfEntry.Password,
+				CertFile:              zarfEntry.CertFile,
+				KeyFile:               zarfEntry.KeyFile,
+				CAFile:                zarfEntry.CAFile,
+				InsecureSkipTLSverify: zarfEntry.InsecureSkipTLSVerify,
+				PassCredentialsAll:    zarfEntry.PassCredentialsAll,
+			}
+			break
+		}
+	}
+
+	return entry
+}
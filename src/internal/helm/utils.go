@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/types"
 
 	"github.com/defenseunicorns/zarf/src/internal/message"
@@ -14,6 +15,8 @@ import (
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/getter"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 
 	"helm.sh/helm/v3/pkg/chart/loader"
 )
@@ -45,18 +48,26 @@ func loadChartFromTarball(options ChartOptions) (*chart.Chart, error) {
 }
 
 // parseChartValues reads the context of the chart values into an interface if it exists
-func parseChartValues(options ChartOptions) (map[string]any, error) {
-	valueOpts := &values.Options{}
-
+func parseChartValues(options ChartOptions, loadedChart *chart.Chart) (map[string]any, error) {
+	var valuesPaths []string
 	for idx, file := range options.Chart.ValuesFiles {
 		path := StandardName(filepath.Join(options.BasePath, "values"), options.Chart) + "-" + strconv.Itoa(idx)
 		// If we are overriding the chart path, assuming this is for zarf prepare
 		if options.ChartLoadOverride != "" {
 			path = file
 		}
-		valueOpts.ValueFiles = append(valueOpts.ValueFiles, path)
+		valuesPaths = append(valuesPaths, path)
+	}
+
+	switch options.Chart.ValuesMerge {
+	case ValuesMergeOverride:
+		return overrideValuesFiles(valuesPaths)
+	case ValuesMergeDeep:
+		return deepMergeValuesFiles(loadedChart, valuesPaths)
 	}
 
+	valueOpts := &values.Options{ValueFiles: valuesPaths}
+
 	httpProvider := getter.Provider{
 		Schemes: []string{"http", "https"},
 		New:     getter.NewHTTPGetter,
@@ -74,8 +85,17 @@ func createActionConfig(namespace string, spinner *message.Spinner) (*action.Con
 	actionConfig := new(action.Configuration)
 	settings := cli.New()
 
+	// Helm builds its own K8s config, so it needs to be told to skip the proxy along with the rest of Zarf
+	restClientGetter := settings.RESTClientGetter()
+	if configFlags, ok := restClientGetter.(*genericclioptions.ConfigFlags); ok && config.CommonOptions.NoProxyCluster {
+		configFlags.WrapConfigFn = func(restConfig *rest.Config) *rest.Config {
+			restConfig.Proxy = config.GetClusterProxy()
+			return restConfig
+		}
+	}
+
 	// Setup K8s connection
-	err := actionConfig.Init(settings.RESTClientGetter(), namespace, "", spinner.Updatef)
+	err := actionConfig.Init(restClientGetter, namespace, "", spinner.Updatef)
 
 	return actionConfig, err
 }
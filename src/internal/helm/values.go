@@ -0,0 +1,106 @@
+package helm
+
+import (
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// Supported values for a chart's valuesMerge field, an empty value behaves like ValuesMergeHelm
+const (
+	ValuesMergeHelm     = "helm"
+	ValuesMergeOverride = "override"
+	ValuesMergeDeep     = "deep"
+)
+
+// overrideValuesFiles combines values files so the last file to set a top-level key replaces it entirely
+func overrideValuesFiles(valuesPaths []string) (map[string]any, error) {
+	message.Debugf("helm.overrideValuesFiles(%#v)", valuesPaths)
+
+	merged := map[string]any{}
+	for _, path := range valuesPaths {
+		fileValues, err := chartutil.ReadValuesFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the values file %s: %w", path, err)
+		}
+		for key, value := range fileValues {
+			merged[key] = value
+		}
+	}
+
+	return merged, nil
+}
+
+// deepMergeValuesFiles merges values files over the chart defaults and removes every key explicitly set to null
+// The chart and subchart defaults are updated in place so Helm can't add the removed keys back when it coalesces values
+func deepMergeValuesFiles(loadedChart *chart.Chart, valuesPaths []string) (map[string]any, error) {
+	message.Debugf("helm.deepMergeValuesFiles(%s, %#v)", loadedChart.Name(), valuesPaths)
+
+	merged := map[string]any{}
+	for _, path := range valuesPaths {
+		fileValues, err := chartutil.ReadValuesFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the values file %s: %w", path, err)
+		}
+		mergeValues(merged, fileValues)
+	}
+
+	deleteNullValues(loadedChart, merged)
+
+	return merged, nil
+}
+
+// mergeValues recursively merges src into dst, keeping nulls so they can be applied to the chart defaults later
+func mergeValues(dst, src map[string]any) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// deleteNullValues removes the keys set to null in values from both values and the defaults of the chart and its subcharts
+func deleteNullValues(loadedChart *chart.Chart, values map[string]any) {
+	pruneNulls(loadedChart.Values, values)
+
+	for _, dependency := range loadedChart.Dependencies() {
+		if dependencyValues, ok := values[dependency.Name()].(map[string]any); ok {
+			deleteNullValues(dependency, dependencyValues)
+		}
+	}
+
+	stripNulls(values)
+}
+
+// pruneNulls deletes every key from defaults that is set to null at the same path in values
+func pruneNulls(defaults, values map[string]any) {
+	for key, value := range values {
+		if value == nil {
+			delete(defaults, key)
+			continue
+		}
+
+		valuesMap, valuesIsMap := value.(map[string]any)
+		defaultsMap, defaultsIsMap := defaults[key].(map[string]any)
+		if valuesIsMap && defaultsIsMap {
+			pruneNulls(defaultsMap, valuesMap)
+		}
+	}
+}
+
+// stripNulls removes the null markers from values once they have been applied
+func stripNulls(values map[string]any) {
+	for key, value := range values {
+		if value == nil {
+			delete(values, key)
+		} else if valuesMap, ok := value.(map[string]any); ok {
+			stripNulls(valuesMap)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package images
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// artifactNameAnnotation is the OCI layout annotation each artifact is found by on deploy
+const artifactNameAnnotation = "org.opencontainers.image.ref.name"
+
+// PullArtifacts saves OCI artifacts like policy bundles to an OCI layout, which keeps the media types they were pushed with unlike an image tarball
+func PullArtifacts(layoutPath string, artifacts []types.ZarfArtifact) error {
+	message.Debugf("images.PullArtifacts(%s, %#v)", layoutPath, artifacts)
+
+	spinner := message.NewProgressSpinner("Loading %d OCI artifacts", len(artifacts))
+	defer spinner.Stop()
+
+	artifactLayout, err := layout.Write(layoutPath, empty.Index)
+	if err != nil {
+		return fmt.Errorf("unable to create the artifact layout: %w", err)
+	}
+
+	pullOptions := crane.GetOptions(config.GetCraneOptions()...).Remote
+	for _, artifact := range artifacts {
+		spinner.Updatef("Pulling %s", artifact.Source)
+
+		ref, err := name.ParseReference(artifact.Source)
+		if err != nil {
+			return fmt.Errorf("invalid artifact reference %s: %w", artifact.Source, err)
+		}
+		descriptor, err := remote.Get(ref, pullOptions...)
+		if err != nil {
+			return fmt.Errorf("unable to pull %s: %w", artifact.Source, err)
+		}
+
+		if err := appendArtifact(artifactLayout, descriptor, artifact.Name); err != nil {
+			return fmt.Errorf("unable to save %s: %w", artifact.Source, err)
+		}
+	}
+
+	spinner.Successf("Loaded %d OCI artifacts", len(artifacts))
+	return nil
+}
+
+// PushArtifactsToZarfRegistry pushes the OCI artifacts of a component to the path their source has in the registry, without a checksum since nothing mutates their references
+func PushArtifactsToZarfRegistry(ctx context.Context, layoutPath string, artifacts []types.ZarfArtifact) ([]types.DeployedImage, error) {
+	message.Debugf("images.PushArtifactsToZarfRegistry(%s, %#v)", layoutPath, artifacts)
+
+	index, err := layout.ImageIndexFromPath(layoutPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the artifacts in the package: %w", err)
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	descriptors := make(map[string]v1.Descriptor)
+	for _, descriptor := range indexManifest.Manifests {
+		descriptors[descriptor.Annotations[artifactNameAnnotation]] = descriptor
+	}
+
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	pushOptions, err := getRegistryPushCraneOptions(registryInfo)
+	if err != nil {
+		return nil, err
+	}
+	remoteOptions := crane.GetOptions(append(pushOptions, crane.WithContext(ctx))...).Remote
+
+	spinner := message.NewProgressSpinner("Pushing %d OCI artifacts", len(artifacts))
+	defer spinner.Stop()
+
+	var pushed []types.DeployedImage
+	for _, artifact := range artifacts {
+		descriptor, ok := descriptors[artifact.Name]
+		if !ok {
+			return pushed, fmt.Errorf("the package doesn't hold the %s artifact", artifact.Name)
+		}
+
+		offlineName, err := utils.SwapHostWithPushPath(artifact.Source, registryUrl, registryInfo.PushPath, registryInfo.Project, false)
+		if err != nil {
+			return pushed, err
+		}
+		ref, err := name.ParseReference(offlineName)
+		if err != nil {
+			return pushed, err
+		}
+
+		spinner.Updatef("Pushing %s", offlineName)
+		message.Debugf("remote.Write() %s -> %s", artifact.Source, offlineName)
+		if err := writeArtifact(ref, index, descriptor, remoteOptions); err != nil {
+			return pushed, fmt.Errorf("unable to push %s: %w", artifact.Source, err)
+		}
+
+		pushed = append(pushed, newDeployedImage(artifact.Source, offlineName, registryUrl, descriptor.Digest.String()))
+	}
+
+	spinner.Successf("Pushed %d OCI artifacts", len(artifacts))
+	return pushed, nil
+}
+
+// appendArtifact adds an artifact to the layout under its name, as an index when it has one manifest for each platform
+func appendArtifact(artifactLayout layout.Path, descriptor *remote.Descriptor, artifactName string) error {
+	annotations := layout.WithAnnotations(map[string]string{artifactNameAnnotation: artifactName})
+	if descriptor.MediaType.IsIndex() {
+		index, err := descriptor.ImageIndex()
+		if err != nil {
+			return err
+		}
+		return artifactLayout.AppendIndex(index, annotations)
+	}
+
+	img, err := descriptor.Image()
+	if err != nil {
+		return err
+	}
+	return artifactLayout.AppendImage(img, annotations)
+}
+
+// writeArtifact pushes an artifact from the layout to the reference
+func writeArtifact(ref name.Reference, index v1.ImageIndex, descriptor v1.Descriptor, remoteOptions []remote.Option) error {
+	if descriptor.MediaType.IsIndex() {
+		artifactIndex, err := index.ImageIndex(descriptor.Digest)
+		if err != nil {
+			return err
+		}
+		return remote.WriteIndex(ref, artifactIndex, remoteOptions...)
+	}
+
+	img, err := index.Image(descriptor.Digest)
+	if err != nil {
+		return err
+	}
+	return remote.Write(ref, img, remoteOptions...)
+}
@@ -1,9 +1,15 @@
 package images
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 )
 
 func Copy(src string, dest string) {
@@ -11,3 +17,28 @@ func Copy(src string, dest string) {
 		message.Fatal(err, "Unable to copy the image")
 	}
 }
+
+// CopyFromTarball pushes a single image out of a package image tarball to any registry
+func CopyFromTarball(imageTarballPath string, src string, dest string, insecure bool) error {
+	message.Debugf("images.CopyFromTarball(%s, %s, %s)", imageTarballPath, src, dest)
+
+	img, err := crane.LoadTag(imageTarballPath, src, config.GetCraneOptions()...)
+	if err != nil {
+		// Give the user the list of images they could have asked for
+		if manifest, manifestErr := tarball.LoadManifest(func() (io.ReadCloser, error) { return os.Open(imageTarballPath) }); manifestErr == nil {
+			var available []string
+			for _, descriptor := range manifest {
+				available = append(available, descriptor.RepoTags...)
+			}
+			return fmt.Errorf("unable to find %s in the package, available images are: %s", src, strings.Join(available, ", "))
+		}
+		return fmt.Errorf("unable to find %s in the package: %w", src, err)
+	}
+
+	pushOptions := []crane.Option{config.GetCraneKeychainOption()}
+	if insecure {
+		pushOptions = append(pushOptions, crane.Insecure)
+	}
+
+	return crane.Push(img, dest, pushOptions...)
+}
@@ -0,0 +1,103 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/docker/docker/client"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+)
+
+// loadImage pulls an image from its registry or, for docker-daemon: and podman: sources, loads it from the local daemon
+// Like the SBOM tooling, images that can't be pulled are looked for in the local Docker daemon before giving up
+func loadImage(src string) (v1.Image, error) {
+	imageName, prefix := utils.SplitDaemonSource(src)
+	if prefix != "" {
+		return loadDaemonImage(imageName, prefix)
+	}
+
+	img, err := crane.Pull(src, config.GetCraneOptions()...)
+	if err == nil {
+		return img, nil
+	}
+
+	daemonImg, daemonErr := loadDaemonImage(src, utils.DockerDaemonPrefix)
+	if daemonErr != nil {
+		message.Debugf("Unable to load %s from the Docker daemon either: %s", src, daemonErr.Error())
+		return nil, err
+	}
+
+	message.Warnf("Unable to pull %s from its registry, using the image in the local Docker daemon instead: %s", src, err.Error())
+	return daemonImg, nil
+}
+
+// loadDaemonImage loads an image from the Docker or Podman daemon, which has to hold it for the architecture of the package
+func loadDaemonImage(imageName, prefix string) (v1.Image, error) {
+	message.Debugf("images.loadDaemonImage(%s, %s)", imageName, prefix)
+
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	daemonClient, err := getDaemonClient(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the %s daemon: %w", getDaemonName(prefix), err)
+	}
+
+	img, err := daemon.Image(ref, daemon.WithClient(daemonClient), daemon.WithContext(context.TODO()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s from the %s daemon: %w", imageName, getDaemonName(prefix), err)
+	}
+
+	// A daemon only holds the image for the platform it was built for, so it can't stand in for another architecture
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if configFile.Architecture != "" && configFile.Architecture != config.GetArch() {
+		return nil, fmt.Errorf("the %s daemon has %s for %s but the package is for %s", getDaemonName(prefix), imageName, configFile.Architecture, config.GetArch())
+	}
+
+	return img, nil
+}
+
+// getDaemonClient returns a client for the daemon, Podman is reached through its Docker compatible API socket
+func getDaemonClient(prefix string) (*client.Client, error) {
+	opts := []client.Opt{client.FromEnv}
+	if prefix == utils.PodmanDaemonPrefix {
+		opts = append(opts, client.WithHost(getPodmanHost()))
+	}
+	return client.NewClientWithOpts(opts...)
+}
+
+// getPodmanHost returns the address of the Podman API socket, preferring CONTAINER_HOST and then the rootless socket of the user
+func getPodmanHost() string {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host
+	}
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+
+	return "unix:///run/podman/podman.sock"
+}
+
+func getDaemonName(prefix string) string {
+	if prefix == utils.PodmanDaemonPrefix {
+		return "Podman"
+	}
+	return "Docker"
+}
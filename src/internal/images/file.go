@@ -0,0 +1,82 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/pkg/oci/remote"
+)
+
+// PullSignedFile writes a file stored in the Zarf registry as a single layer artifact (e.g. with cosign upload blob) to out
+// The artifact needs a cosign signature that verifies against the key, and is pulled by the digest that was verified
+func PullSignedFile(reference, key string, out io.Writer) error {
+	message.Debugf("images.PullSignedFile(%s, %s)", reference, key)
+
+	spinner := message.NewProgressSpinner("Pulling the signed file %s from the Zarf registry", reference)
+	defer spinner.Stop()
+
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	pullOptions, err := getRegistryPullCraneOptions(registryInfo)
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	remoteOptions := append(crane.GetOptions(pullOptions...).Remote, remote.WithContext(ctx))
+
+	ref, err := name.ParseReference(fmt.Sprintf("%s/%s", registryUrl, reference))
+	if err != nil {
+		return err
+	}
+
+	checkOpts, err := getSignatureCheckOpts(ctx, []string{key})
+	if err != nil {
+		return err
+	}
+	checkOpts[0].RegistryClientOpts = []ociremote.Option{ociremote.WithRemoteOptions(remoteOptions...)}
+
+	// Resolve the tag once so the file pulled is the one whose signature was verified
+	digest, err := ociremote.ResolveDigest(ref, checkOpts[0].RegistryClientOpts...)
+	if err != nil {
+		return err
+	}
+
+	spinner.Updatef("Verifying the signature of %s", reference)
+	if _, _, err := cosign.VerifyImageSignatures(ctx, digest, checkOpts[0]); err != nil {
+		return fmt.Errorf("unable to verify the signature of %s: %w", reference, err)
+	}
+
+	img, err := remote.Image(digest, remoteOptions...)
+	if err != nil {
+		return err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+	if len(layers) != 1 {
+		return errors.New("the artifact must have a single layer holding the file")
+	}
+	reader, err := layers[0].Compressed()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(out, reader); err != nil {
+		return err
+	}
+
+	spinner.Successf("Pulled %s and verified its signature", reference)
+	return nil
+}
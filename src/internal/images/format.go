@@ -0,0 +1,177 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	ggcrTypes "github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// The manifest formats images can be converted to before they are pushed
+const (
+	ManifestFormatDocker = "docker"
+	ManifestFormatOCI    = "oci"
+)
+
+// The kinds of layers, which each manifest format gives its own media type
+type layerKind int
+
+const (
+	layerCompressed layerKind = iota
+	layerForeign
+	layerUncompressed
+	layerUncompressedForeign
+)
+
+var layerKinds = map[ggcrTypes.MediaType]layerKind{
+	ggcrTypes.DockerLayer:                    layerCompressed,
+	ggcrTypes.DockerForeignLayer:             layerForeign,
+	ggcrTypes.DockerUncompressedLayer:        layerUncompressed,
+	ggcrTypes.OCILayer:                       layerCompressed,
+	ggcrTypes.OCIRestrictedLayer:             layerForeign,
+	ggcrTypes.OCIUncompressedLayer:           layerUncompressed,
+	ggcrTypes.OCIUncompressedRestrictedLayer: layerUncompressedForeign,
+}
+
+// Docker manifests have no uncompressed foreign layer, which is left out of its map
+var layerMediaTypes = map[string]map[layerKind]ggcrTypes.MediaType{
+	ManifestFormatDocker: {
+		layerCompressed:   ggcrTypes.DockerLayer,
+		layerForeign:      ggcrTypes.DockerForeignLayer,
+		layerUncompressed: ggcrTypes.DockerUncompressedLayer,
+	},
+	ManifestFormatOCI: {
+		layerCompressed:          ggcrTypes.OCILayer,
+		layerForeign:             ggcrTypes.OCIRestrictedLayer,
+		layerUncompressed:        ggcrTypes.OCIUncompressedLayer,
+		layerUncompressedForeign: ggcrTypes.OCIUncompressedRestrictedLayer,
+	},
+}
+
+// distributableLayer hides the URLs and foreign media type of a non-distributable layer so it is uploaded like any other layer
+type distributableLayer struct {
+	v1.Layer
+	mediaType ggcrTypes.MediaType
+}
+
+func (layer distributableLayer) MediaType() (ggcrTypes.MediaType, error) {
+	return layer.mediaType, nil
+}
+
+// getManifestFormat returns the format of the manifest of an image
+func getManifestFormat(img v1.Image) (string, error) {
+	mediaType, err := img.MediaType()
+	if err != nil {
+		return "", err
+	}
+	if mediaType == ggcrTypes.OCIManifestSchema1 {
+		return ManifestFormatOCI, nil
+	}
+	return ManifestFormatDocker, nil
+}
+
+// getOtherManifestFormat returns the format to retry a push in when the registry rejects the format of the image
+func getOtherManifestFormat(format string) string {
+	if format == ManifestFormatOCI {
+		return ManifestFormatDocker
+	}
+	return ManifestFormatOCI
+}
+
+// normalizeImage converts the image to the manifest format (keeping its own when empty) and, when asked, makes its foreign layers regular ones
+// The image is returned as is when nothing changes, otherwise the converted image has a new digest
+func normalizeImage(img v1.Image, format string, pushForeignLayers bool) (v1.Image, error) {
+	current, err := getManifestFormat(img)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = current
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	hasForeignLayers := false
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return nil, err
+		}
+		if !mediaType.IsDistributable() {
+			hasForeignLayers = true
+		}
+	}
+
+	if format == current && !(pushForeignLayers && hasForeignLayers) {
+		return img, nil
+	}
+
+	manifestType, configType := ggcrTypes.DockerManifestSchema2, ggcrTypes.DockerConfigJSON
+	if format == ManifestFormatOCI {
+		manifestType, configType = ggcrTypes.OCIManifestSchema1, ggcrTypes.OCIConfigJSON
+	}
+
+	var additions []mutate.Addendum
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return nil, err
+		}
+		kind, ok := layerKinds[mediaType]
+		if !ok {
+			return nil, fmt.Errorf("layers of type %s can't be converted", mediaType)
+		}
+
+		if pushForeignLayers && (kind == layerForeign || kind == layerUncompressedForeign) {
+			if kind == layerForeign {
+				kind = layerCompressed
+			} else {
+				kind = layerUncompressed
+			}
+			layer = distributableLayer{Layer: layer, mediaType: layerMediaTypes[format][kind]}
+		}
+
+		converted, ok := layerMediaTypes[format][kind]
+		if !ok {
+			return nil, fmt.Errorf("layers of type %s have no %s equivalent", mediaType, format)
+		}
+		additions = append(additions, mutate.Addendum{Layer: layer, MediaType: converted})
+	}
+
+	configFile, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	// The config is set after the layers since appending them adds history the original config already has
+	converted, err := mutate.Append(mutate.ConfigMediaType(mutate.MediaType(empty.Image, manifestType), configType), additions...)
+	if err != nil {
+		return nil, err
+	}
+	return mutate.ConfigFile(converted, configFile)
+}
+
+// isManifestRejected returns whether a push failed because the registry doesn't accept the format of the manifest
+func isManifestRejected(err error) bool {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) {
+		return false
+	}
+	if transportErr.StatusCode == http.StatusUnsupportedMediaType {
+		return true
+	}
+	for _, diagnostic := range transportErr.Errors {
+		if diagnostic.Code == transport.ManifestInvalidErrorCode || diagnostic.Code == transport.UnsupportedErrorCode {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package images
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// SplitPinnedImage splits an image pinned to a digest (name:tag@sha256:...) into its tagged name and its digest
+// The last value is false for images that don't carry both a tag and a digest
+func SplitPinnedImage(src string) (string, string, bool) {
+	tagged, digest, found := strings.Cut(src, "@")
+	if !found {
+		return src, "", false
+	}
+
+	// Only a colon after the last slash is a tag, one before it is a registry port
+	if !strings.Contains(tagged[strings.LastIndex(tagged, "/")+1:], ":") {
+		return src, "", false
+	}
+
+	return tagged, digest, true
+}
+
+// getTarballTag returns the name an image is stored under in the package image tarball
+// Pinned images are stored by their tag and images loaded from a daemon by the name they were built with
+func getTarballTag(src string) string {
+	imageName, _ := utils.SplitDaemonSource(src)
+	tagged, _, _ := SplitPinnedImage(imageName)
+	return tagged
+}
+
+// GetTarballTag returns the tag an image is stored under in the package image tarball
+// Images referenced only by a digest are stored under the digest-only tag of their repository
+func GetTarballTag(src string) (name.Tag, error) {
+	ref, err := name.ParseReference(getTarballTag(src))
+	if err != nil {
+		return name.Tag{}, err
+	}
+
+	switch ref := ref.(type) {
+	case name.Tag:
+		return ref, nil
+	case name.Digest:
+		return ref.Repository.Tag("digest-only"), nil
+	}
+	return name.Tag{}, fmt.Errorf("image reference %s wasn't a tag or digest", src)
+}
+
+// PinDigest resolves the tag of an image to the digest of the image create pulls for it and returns it as name:tag@sha256:...
+// Images that already have a digest, or that are loaded from a daemon and have no registry digest, are returned as is
+func PinDigest(src string) (string, error) {
+	message.Debugf("images.PinDigest(%s)", src)
+
+	if _, prefix := utils.SplitDaemonSource(src); prefix != "" {
+		return src, nil
+	}
+
+	image, err := utils.ParseImageURL(src)
+	if err != nil {
+		return "", err
+	}
+	if image.Digest != "" {
+		return src, nil
+	}
+
+	digest, err := crane.Digest(src, config.GetCraneOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the digest of %s: %w", src, err)
+	}
+
+	if image.Tag == "" {
+		src += ":latest"
+	}
+	return fmt.Sprintf("%s@%s", src, digest), nil
+}
@@ -0,0 +1,92 @@
+package images
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// LoadPolicy reads an image policy file
+func LoadPolicy(path string) (types.ImagePolicy, error) {
+	var policy types.ImagePolicy
+	if err := utils.ReadYaml(path, &policy); err != nil {
+		return policy, fmt.Errorf("unable to read the image policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// CheckPolicy returns a description of every way the images break the policy
+// Repositories are matched in their fully qualified form (such as docker.io/library/nginx) and untagged images are checked as latest
+func CheckPolicy(policy types.ImagePolicy, images []string) []string {
+	message.Debugf("images.CheckPolicy(%#v, %#v)", policy, images)
+
+	var violations []string
+	for _, image := range images {
+		parsed, err := utils.ParseImageURL(image)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s can't be parsed: %s", image, err.Error()))
+			continue
+		}
+
+		if len(policy.Allow) > 0 && matchesAny(policy.Allow, parsed.Name) == "" {
+			violations = append(violations, fmt.Sprintf("%s is not from an allowed repository", image))
+		}
+
+		if pattern := matchesAny(policy.Deny, parsed.Name); pattern != "" {
+			violations = append(violations, fmt.Sprintf("%s is from a denied repository (%s)", image, pattern))
+		}
+
+		tag := parsed.Tag
+		if tag == "" && parsed.Digest == "" {
+			tag = "latest"
+		}
+		if tag != "" {
+			if pattern := matchesAny(policy.DenyTags, tag); pattern != "" {
+				violations = append(violations, fmt.Sprintf("%s uses a denied tag (%s)", image, pattern))
+			}
+		}
+	}
+
+	sort.Strings(violations)
+	return violations
+}
+
+// matchesAny returns the first pattern that matches the value or an empty string when none do
+func matchesAny(patterns []string, value string) string {
+	for _, pattern := range patterns {
+		if globToRegexp(pattern).MatchString(value) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// globToRegexp turns a glob pattern into an anchored regular expression
+// A * matches within one path segment, a ** matches across segments and a ? matches a single character
+func globToRegexp(pattern string) *regexp.Regexp {
+	var expression strings.Builder
+	expression.WriteString("^")
+	for idx := 0; idx < len(pattern); idx++ {
+		switch pattern[idx] {
+		case '*':
+			if idx+1 < len(pattern) && pattern[idx+1] == '*' {
+				expression.WriteString(".*")
+				idx++
+			} else {
+				expression.WriteString("[^/]*")
+			}
+		case '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(pattern[idx])))
+		}
+	}
+	expression.WriteString("$")
+
+	return regexp.MustCompile(expression.String())
+}
@@ -0,0 +1,157 @@
+package images
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// imageProgress adds up the bytes moved for every image into one progress bar that also shows the image being worked on
+// It is safe to use from the goroutines of concurrent pushes
+type imageProgress struct {
+	lock     sync.Mutex
+	bar      *message.ProgressBar
+	action   string
+	sizes    map[string]int64
+	moved    map[string]int64
+	total    int64
+	complete int64
+	finished int
+}
+
+func newImageProgress(action string, sizes map[string]int64, total int64) *imageProgress {
+	progress := &imageProgress{
+		action: action,
+		sizes:  sizes,
+		moved:  make(map[string]int64),
+		total:  total,
+	}
+	progress.bar = message.NewProgressBar(total, progress.title(""))
+	return progress
+}
+
+// add records that n more bytes of the image have been moved
+func (p *imageProgress) add(src string, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// Manifests and configs aren't in the sizes, so keep the counts from running past them
+	if remaining := p.sizes[src] - p.moved[src]; n > remaining {
+		n = remaining
+	}
+	if n <= 0 {
+		return
+	}
+	p.moved[src] += n
+	p.complete += n
+	p.bar.Update(p.complete, p.title(src))
+}
+
+// finish marks the image as done, counting any bytes that were skipped because the destination already had them
+func (p *imageProgress) finish(src string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if remaining := p.sizes[src] - p.moved[src]; remaining > 0 {
+		p.moved[src] += remaining
+		p.complete += remaining
+	}
+	p.finished++
+	p.bar.Update(p.complete, p.title(src))
+}
+
+// title shows the overall byte count and, when given, how far along the image is
+func (p *imageProgress) title(src string) string {
+	title := fmt.Sprintf("%s %d images (%d done, %s of %s)", p.action, len(p.sizes), p.finished,
+		utils.ByteFormat(float64(p.complete), 2), utils.ByteFormat(float64(p.total), 2))
+	if src != "" {
+		title += fmt.Sprintf(" - %s (%s of %s)", src,
+			utils.ByteFormat(float64(p.moved[src]), 2), utils.ByteFormat(float64(p.sizes[src]), 2))
+	}
+	return title
+}
+
+// trackUpdates feeds the cumulative updates of a remote write into the progress until done is closed
+func (p *imageProgress) trackUpdates(src string, updates <-chan v1.Update, done <-chan struct{}) {
+	var last int64
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if update.Complete > last {
+				p.add(src, update.Complete-last)
+				last = update.Complete
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// progressImage reports the bytes of its layers to the progress as they are read
+type progressImage struct {
+	v1.Image
+	src      string
+	progress *imageProgress
+}
+
+// Layers wraps the layers of the image so reading them is counted
+func (i *progressImage) Layers() ([]v1.Layer, error) {
+	layers, err := i.Image.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := make([]v1.Layer, len(layers))
+	for idx, layer := range layers {
+		wrapped[idx] = &progressLayer{Layer: layer, src: i.src, progress: i.progress}
+	}
+	return wrapped, nil
+}
+
+// LayerByDigest wraps the layer so reading it is counted
+func (i *progressImage) LayerByDigest(hash v1.Hash) (v1.Layer, error) {
+	layer, err := i.Image.LayerByDigest(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &progressLayer{Layer: layer, src: i.src, progress: i.progress}, nil
+}
+
+// progressLayer counts the bytes of its compressed contents as they are read
+type progressLayer struct {
+	v1.Layer
+	src      string
+	progress *imageProgress
+}
+
+// Compressed returns a reader of the layer that reports each read to the progress
+func (l *progressLayer) Compressed() (io.ReadCloser, error) {
+	reader, err := l.Layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	return &progressReader{ReadCloser: reader, src: l.src, progress: l.progress}, nil
+}
+
+type progressReader struct {
+	io.ReadCloser
+	src      string
+	progress *imageProgress
+}
+
+func (r *progressReader) Read(data []byte) (int, error) {
+	n, err := r.ReadCloser.Read(data)
+	r.progress.add(r.src, int64(n))
+	return n, err
+}
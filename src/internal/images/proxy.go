@@ -0,0 +1,99 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// PullThroughZarfRegistry checks that the Zarf registry serves the images of the package from the upstream registry it is a pull-through cache of
+// A pull-through cache refuses pushes, so the images are requested through it instead, which also caches them before any node pulls them
+func PullThroughZarfRegistry(ctx context.Context, imageTarballPath string, buildImageList []string) ([]types.DeployedImage, error) {
+	message.Debugf("images.PullThroughZarfRegistry(%s, %s)", imageTarballPath, buildImageList)
+
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	pullOptions, err := getRegistryPullCraneOptions(registryInfo)
+	if err != nil {
+		return nil, err
+	}
+	pullOptions = append(pullOptions, crane.WithContext(ctx))
+
+	spinner := message.NewProgressSpinner("Requesting %d images through the Zarf registry from %s", len(buildImageList), registryInfo.ProxyURL)
+	defer spinner.Stop()
+
+	var (
+		resolved []types.DeployedImage
+		failures []string
+		listed   = make(map[string]bool)
+	)
+	for _, src := range buildImageList {
+		if listed[src] {
+			continue
+		}
+		listed[src] = true
+
+		spinner.Updatef("Requesting %s through the Zarf registry", src)
+		resolvedImage, err := resolveProxiedImage(imageTarballPath, src, registryUrl, pullOptions)
+		if err != nil {
+			message.Debugf("Unable to request %s through the Zarf registry: %s", src, err.Error())
+			failures = append(failures, fmt.Sprintf("%s: %s", src, err.Error()))
+			continue
+		}
+		resolved = append(resolved, resolvedImage)
+	}
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return resolved, fmt.Errorf("the upstream registry %s did not serve %d of %d images:\n  - %s", registryInfo.ProxyURL, len(failures), len(listed), strings.Join(failures, "\n  - "))
+	}
+
+	spinner.Successf("The Zarf registry serves all %d images from %s", len(listed), registryInfo.ProxyURL)
+	return resolved, nil
+}
+
+// resolveProxiedImage requests the manifest of an image through the pull-through cache and compares it to the image in the package
+// The image keeps its path without a checksum, since that is the path the upstream registry serves it under
+func resolveProxiedImage(imageTarballPath, src, registryUrl string, pullOptions []crane.Option) (types.DeployedImage, error) {
+	tagged, pinnedDigest, pinned := SplitPinnedImage(src)
+
+	offlineName, err := utils.SwapHostWithoutChecksum(src, registryUrl)
+	if err != nil {
+		return types.DeployedImage{}, err
+	}
+
+	// A manifest GET rather than a HEAD makes the cache fetch and keep the manifest
+	message.Debugf("crane.Manifest() %s", offlineName)
+	manifest, err := crane.Manifest(offlineName, pullOptions...)
+	if err != nil {
+		return types.DeployedImage{}, err
+	}
+	digest, _, err := v1.SHA256(bytes.NewReader(manifest))
+	if err != nil {
+		return types.DeployedImage{}, err
+	}
+
+	if pinned && digest.String() != pinnedDigest {
+		return types.DeployedImage{}, fmt.Errorf("the upstream registry serves %s for %s, but it was pinned to %s", digest.String(), tagged, pinnedDigest)
+	}
+
+	// The upstream copy of a tag may have moved since the package was created, which is worth knowing but is what the nodes will run
+	if img, err := crane.LoadTag(imageTarballPath, getTarballTag(src), config.GetCraneOptions()...); err == nil {
+		if packaged, err := img.Digest(); err == nil && packaged.String() != digest.String() {
+			message.Warnf("The upstream registry serves %s for %s, which differs from %s in the package", digest.String(), src, packaged.String())
+		}
+	}
+
+	return newDeployedImage(src, offlineName, registryUrl, digest.String()), nil
+}
@@ -0,0 +1,125 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// PruneZarfRegistry deletes the tags in the Zarf registry whose images none of the deployed images point to, returning them as repository:tag
+// The signatures and attestations of the deployed images and the seed registry image are kept, a dry run only returns what would be deleted
+func PruneZarfRegistry(deployedImages []types.DeployedImage, dryRun bool) ([]string, error) {
+	message.Debugf("images.PruneZarfRegistry(%#v, %t)", deployedImages, dryRun)
+
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	pushOptions, err := getRegistryPushCraneOptions(registryInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deleting goes by digest, so every tag of a digest a deployed image uses is kept
+	kept := make(map[string]map[string]bool)
+	keep := func(reference, digest string) error {
+		ref, err := name.ParseReference(fmt.Sprintf("%s/%s", registryUrl, reference))
+		if err != nil {
+			return err
+		}
+		repository := ref.Context().RepositoryStr()
+		if kept[repository] == nil {
+			kept[repository] = make(map[string]bool)
+		}
+		kept[repository][digest] = true
+		return nil
+	}
+	for _, image := range deployedImages {
+		if err := keep(image.Reference, image.Digest); err != nil {
+			return nil, fmt.Errorf("invalid image reference %s: %w", image.Reference, err)
+		}
+	}
+
+	// The seed image runs the registry itself but is pushed by init outside of any component
+	seedImage := fmt.Sprintf("library/%s:%s", config.ZarfSeedImage, config.ZarfSeedTag)
+	if digest, err := crane.Digest(fmt.Sprintf("%s/%s", registryUrl, seedImage), pushOptions...); err == nil {
+		if err := keep(seedImage, digest); err != nil {
+			return nil, err
+		}
+	} else {
+		message.Debugf("Unable to find the seed image %s in the registry: %s", seedImage, err.Error())
+	}
+
+	repositories, err := crane.Catalog(registryUrl, pushOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the repositories in the registry: %w", err)
+	}
+	sort.Strings(repositories)
+
+	var pruned []string
+	for _, repository := range repositories {
+		tags, err := crane.ListTags(fmt.Sprintf("%s/%s", registryUrl, repository), pushOptions...)
+		if err != nil {
+			return pruned, fmt.Errorf("unable to list the tags of %s: %w", repository, err)
+		}
+		sort.Strings(tags)
+
+		// Every digest is resolved before deleting any, since deleting a digest also removes the other tags that point to it
+		var prunedDigests []string
+		prunedTags := make(map[string][]string)
+		for _, tag := range tags {
+			if isKeptArtifact(tag, kept[repository]) {
+				continue
+			}
+
+			digest, err := crane.Digest(fmt.Sprintf("%s/%s:%s", registryUrl, repository, tag), pushOptions...)
+			if err != nil {
+				return pruned, fmt.Errorf("unable to get the digest of %s:%s: %w", repository, tag, err)
+			}
+			if kept[repository][digest] {
+				continue
+			}
+
+			if len(prunedTags[digest]) == 0 {
+				prunedDigests = append(prunedDigests, digest)
+			}
+			prunedTags[digest] = append(prunedTags[digest], fmt.Sprintf("%s:%s", repository, tag))
+		}
+
+		for _, digest := range prunedDigests {
+			if !dryRun {
+				message.Debugf("crane.Delete() %s/%s@%s", registryUrl, repository, digest)
+				if err := crane.Delete(fmt.Sprintf("%s/%s@%s", registryUrl, repository, digest), pushOptions...); err != nil {
+					var transportErr *transport.Error
+					if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusMethodNotAllowed {
+						return pruned, fmt.Errorf("the registry doesn't allow deletes, run zarf init again to upgrade it: %w", err)
+					}
+					return pruned, fmt.Errorf("unable to delete %s@%s: %w", repository, digest, err)
+				}
+			}
+			pruned = append(pruned, prunedTags[digest]...)
+		}
+	}
+
+	return pruned, nil
+}
+
+// isKeptArtifact returns whether a tag holds the cosign signatures or attestations of a kept digest
+func isKeptArtifact(tag string, keptDigests map[string]bool) bool {
+	for _, suffix := range []string{signatureSuffix, attestationSuffix} {
+		if strings.HasSuffix(tag, suffix) {
+			digest := strings.Replace(strings.TrimSuffix(tag, suffix), "-", ":", 1)
+			return keptDigests[digest]
+		}
+	}
+	return false
+}
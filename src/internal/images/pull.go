@@ -14,7 +14,6 @@ import (
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
-	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/logs"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -47,7 +46,7 @@ func PullAll(buildImageList []string, imageTarballPath string) map[name.Tag]v1.I
 
 	for idx, src := range buildImageList {
 		spinner.Updatef("Fetching image metadata (%d of %d): %s", idx+1, imageCount, src)
-		img, err := crane.Pull(src, config.GetCraneOptions()...)
+		img, err := loadImage(src)
 		if err != nil {
 			spinner.Fatalf(err, "Unable to pull the image \"%s\"", src)
 		}
@@ -56,56 +55,67 @@ func PullAll(buildImageList []string, imageTarballPath string) map[name.Tag]v1.I
 		imageMap[src] = img
 	}
 
+	// Flag unexpectedly large images before they are written into the package
+	WarnLargeImages(imageMap, config.CreateOptions.ImageSizeWarningMB)
+
 	spinner.Updatef("Creating image tarball (this will take a while)")
 
+	// Add up the layers of each image, counting layers shared between images once for the total
+	sizes := make(map[string]int64)
+	seenLayers := make(map[v1.Hash]bool)
+	var total int64
+	for src, img := range imageMap {
+		manifest, err := img.Manifest()
+		if err != nil {
+			spinner.Fatalf(err, "Unable to read the manifest for %s", src)
+		}
+		for _, layer := range manifest.Layers {
+			sizes[src] += layer.Size
+			if !seenLayers[layer.Digest] {
+				seenLayers[layer.Digest] = true
+				total += layer.Size
+			}
+		}
+	}
+
 	tagToImage := map[name.Tag]v1.Image{}
+	tagToSrc := map[name.Tag]string{}
 
 	for src, img := range imageMap {
-		ref, err := name.ParseReference(src)
+		// Pinned images are pulled by their digest but stored by their tag so each keeps its own name in the tarball
+		tag, err := GetTarballTag(src)
 		if err != nil {
 			spinner.Fatalf(err, "parsing ref %q", src)
 		}
-
-		tag, ok := ref.(name.Tag)
-		if !ok {
-			d, ok := ref.(name.Digest)
-			if !ok {
-				spinner.Fatalf(nil, "image reference %s wasn't a tag or digest", src)
-			}
-			tag = d.Repository.Tag("digest-only")
-		}
 		tagToImage[tag] = img
+		tagToSrc[tag] = src
 	}
 	spinner.Success()
 
-	progress := make(chan v1.Update, 200)
+	// Count the layers of each image as they are written so the progress can show which image is being pulled
+	progress := newImageProgress("Pulling", sizes, total)
+	tarballImages := map[name.Tag]v1.Image{}
+	for tag, img := range tagToImage {
+		tarballImages[tag] = &progressImage{Image: img, src: tagToSrc[tag], progress: progress}
+	}
+
+	updates := make(chan v1.Update, 200)
 
 	go func() {
-		_ = tarball.MultiWriteToFile(imageTarballPath, tagToImage, tarball.WithProgress(progress))
+		_ = tarball.MultiWriteToFile(imageTarballPath, tarballImages, tarball.WithProgress(updates))
 	}()
 
-	var progressBar *message.ProgressBar
-	var title string
-
-	for update := range progress {
+	// The layer readers drive the progress bar, the tarball updates report when it is done or fails
+	for update := range updates {
 		switch {
 		case update.Error != nil && errors.Is(update.Error, io.EOF):
-			progressBar.Success("Pulling %d images (%s)", len(imageMap), utils.ByteFormat(float64(update.Total), 2))
+			progress.bar.Success("Pulling %d images (%s)", len(imageMap), utils.ByteFormat(float64(update.Total), 2))
 			return tagToImage
 		case update.Error != nil && strings.HasPrefix(update.Error.Error(), "archive/tar: missed writing "):
 			// Handle potential image cache corruption with a more helpful error. See L#54 in libexec/src/archive/tar/writer.go
 			message.Fatalf(update.Error, "potential image cache corruption: %s of %v bytes - try clearing cache with \"zarf tools clear-cache\"", update.Error.Error(), update.Total)
 		case update.Error != nil:
 			message.Fatalf(update.Error, "error writing image tarball: %s", update.Error.Error())
-		default:
-			title = fmt.Sprintf("Pulling %d images (%s of %s)", len(imageMap),
-				utils.ByteFormat(float64(update.Complete), 2),
-				utils.ByteFormat(float64(update.Total), 2),
-			)
-			if progressBar == nil {
-				progressBar = message.NewProgressBar(update.Total, title)
-			}
-			progressBar.Update(update.Complete, title)
 		}
 	}
 
@@ -186,3 +196,18 @@ func FormatCraneOCILayout(ociPath string) error {
 
 	return nil
 }
+
+// GetConfigDigest returns the digest of an image's config, which identifies an image no matter how its manifest was written
+func GetConfigDigest(src string) (string, error) {
+	img, err := loadImage(src)
+	if err != nil {
+		return "", err
+	}
+
+	configName, err := img.ConfigName()
+	if err != nil {
+		return "", err
+	}
+
+	return configName.String(), nil
+}
@@ -1,68 +1,274 @@
 package images
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
 	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 )
 
 // PushToZarfRegistry pushes a provided image into the configured Zarf registry
 // This function will optionally shorten the image name while appending a checksum of the original image name
-func PushToZarfRegistry(imageTarballPath string, buildImageList []string, addChecksum bool) error {
+// Images are pushed --oci-concurrency at a time, each uploading that many layers at once, and images or layers the registry
+// already has are skipped. The images that were pushed are returned even when others fail so a retry only needs the failed ones
+func PushToZarfRegistry(ctx context.Context, imageTarballPath string, buildImageList []string, addChecksum bool) ([]types.DeployedImage, error) {
 	message.Debugf("images.PushToZarfRegistry(%s, %s)", imageTarballPath, buildImageList)
 
-	registryUrl := ""
-	if config.GetContainerRegistryInfo().InternalRegistry {
-		// Establish a registry tunnel to send the images to the zarf registry
-		tunnel := k8s.NewZarfTunnel()
-		tunnel.Connect(k8s.ZarfRegistry, false)
-		defer tunnel.Close()
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
 
-		registryUrl = tunnel.Endpoint()
-	} else {
-		registryUrl = config.GetContainerRegistryInfo().Address
-
-		// If this is a serviceURL, create a port-forward tunnel to that resource
-		if tunnel, err := k8s.NewTunnelFromServiceURL(registryUrl); err != nil {
-			message.Debug(err)
-		} else {
-			tunnel.Connect("", false)
-			defer tunnel.Close()
-			registryUrl = tunnel.Endpoint()
-		}
+	concurrency := config.DeployOptions.OCIConcurrency
+	if concurrency < 1 {
+		concurrency = config.DefaultOCIConcurrency
 	}
 
-	spinner := message.NewProgressSpinner("Storing images in the zarf registry")
-	defer spinner.Stop()
-
-	pushOptions := config.GetCraneAuthOption(config.GetContainerRegistryInfo().PushUsername, config.GetContainerRegistryInfo().PushPassword)
+	pushOptions, err := getRegistryPushCraneOptions(registryInfo)
+	if err != nil {
+		return nil, err
+	}
+	pushOptions = append(pushOptions, withJobs(concurrency), crane.WithContext(ctx))
 	message.Debugf("crane pushOptions = %#v", pushOptions)
 
+	// Each image only needs to be pushed once even if it is listed more than once
+	var imageList []string
+	listed := make(map[string]bool)
 	for _, src := range buildImageList {
-		spinner.Updatef("Updating image %s", src)
-		img, err := crane.LoadTag(imageTarballPath, src, config.GetCraneOptions()...)
-		if err != nil {
-			return err
+		if !listed[src] {
+			listed[src] = true
+			imageList = append(imageList, src)
 		}
-		offlineName := ""
-		if addChecksum {
-			offlineName, err = utils.SwapHost(src, registryUrl)
-		} else {
-			offlineName, err = utils.SwapHostWithoutChecksum(src, registryUrl)
+	}
+
+	sizes, err := GetImageSizes(imageTarballPath, imageList)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+
+	progress := newImageProgress("Pushing", sizes, total)
+
+	var (
+		lock         sync.Mutex
+		pushedImages = make(map[string]types.DeployedImage)
+		loadedImages = make(map[string]v1.Image)
+		failures     []string
+		waitGroup    sync.WaitGroup
+	)
+	limit := make(chan struct{}, concurrency)
+	for _, src := range imageList {
+		waitGroup.Add(1)
+		limit <- struct{}{}
+		go func(src string) {
+			defer waitGroup.Done()
+			defer func() { <-limit }()
+
+			pushedImage, img, err := pushImage(imageTarballPath, src, registryUrl, registryInfo, addChecksum, pushOptions, progress)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				message.Debugf("Unable to push %s: %s", src, err.Error())
+				failures = append(failures, fmt.Sprintf("%s: %s", src, err.Error()))
+				return
+			}
+			pushedImages[src] = pushedImage
+			loadedImages[src] = img
+			progress.finish(src)
+		}(src)
+	}
+	waitGroup.Wait()
+
+	// Keep the order of the image list so the deployed package records are stable
+	var pushed []types.DeployedImage
+	for _, src := range imageList {
+		if pushedImage, ok := pushedImages[src]; ok {
+			pushed = append(pushed, pushedImage)
 		}
-		if err != nil {
-			return err
+	}
+
+	if len(failures) > 0 {
+		progress.bar.Stop()
+		sort.Strings(failures)
+		return pushed, fmt.Errorf("unable to push %d of %d images:\n  - %s", len(failures), len(imageList), strings.Join(failures, "\n  - "))
+	}
+
+	progress.bar.Success("Pushed %d images (%s)", len(imageList), utils.ByteFormat(float64(total), 2))
+
+	// Flag unexpectedly large images so operators know what is filling the registry
+	WarnLargeImages(loadedImages, config.DeployOptions.ImageSizeWarningMB)
+
+	return pushed, nil
+}
+
+// pushImage pushes one image from the tarball unless the registry already has its manifest, reporting the bytes uploaded to the progress
+// Images pinned at create are checked against their digest and pushed by it, then tagged so workloads using the tag still resolve
+func pushImage(imageTarballPath, src, registryUrl string, registryInfo types.RegistryInfo, addChecksum bool, pushOptions []crane.Option, progress *imageProgress) (types.DeployedImage, v1.Image, error) {
+	tagged, pinnedDigest, pinned := SplitPinnedImage(src)
+
+	img, err := crane.LoadTag(imageTarballPath, getTarballTag(src), config.GetCraneOptions()...)
+	if err != nil {
+		return types.DeployedImage{}, nil, err
+	}
+
+	// Pinned images keep their digest here, so they are pushed by it
+	offlineName, err := utils.SwapHostWithPushPath(src, registryUrl, registryInfo.PushPath, registryInfo.Project, addChecksum)
+	if err != nil {
+		return types.DeployedImage{}, nil, err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return types.DeployedImage{}, nil, err
+	}
+
+	// A pinned image must be exactly the one that was scanned and cataloged when the package was created
+	if pinned && digest.String() != pinnedDigest {
+		return types.DeployedImage{}, nil, fmt.Errorf("the package holds %s for %s, but it was pinned to %s", digest.String(), tagged, pinnedDigest)
+	}
+
+	// Registries that only accept one manifest format or refuse foreign layers get a converted image, which has a new digest
+	if img, err = normalizeImage(img, registryInfo.ManifestFormat, registryInfo.PushForeignLayers); err != nil {
+		return types.DeployedImage{}, nil, fmt.Errorf("unable to convert the image for the registry: %w", err)
+	}
+	if digest, err = img.Digest(); err != nil {
+		return types.DeployedImage{}, nil, err
+	}
+	if pinned && digest.String() != pinnedDigest {
+		return types.DeployedImage{}, nil, fmt.Errorf("converting the image for the registry would change the digest it was pinned to")
+	}
+
+	// An earlier deployment or attempt may have pushed the image already, crane skips existing layers but not the manifest check
+	if existing, err := crane.Digest(offlineName, pushOptions...); err == nil && existing == digest.String() {
+		message.Debugf("%s is already in the registry as %s", src, offlineName)
+		recordPushedImage(img, nil)
+	} else if err := uploadImage(img, src, offlineName, pushOptions, progress); err != nil {
+		// Without a format to push in, try the other one once before giving up on a registry that rejects the manifest
+		if registryInfo.ManifestFormat != "" || pinned || !isManifestRejected(err) {
+			return types.DeployedImage{}, nil, err
+		}
+		if img, digest, err = pushInOtherManifestFormat(img, src, offlineName, registryInfo, pushOptions, progress, err); err != nil {
+			return types.DeployedImage{}, nil, err
 		}
+	}
 
-		message.Debugf("crane.Push() %s:%s -> %s)", imageTarballPath, src, offlineName)
+	pushedImage := newDeployedImage(src, offlineName, registryUrl, digest.String())
 
-		if err = crane.Push(img, offlineName, pushOptions); err != nil {
-			return err
+	if pinned {
+		image, err := utils.ParseImageURL(tagged)
+		if err != nil {
+			return types.DeployedImage{}, nil, err
 		}
+		message.Debugf("crane.Tag() %s -> %s", offlineName, image.Tag)
+		if err := crane.Tag(offlineName, image.Tag, pushOptions...); err != nil {
+			return types.DeployedImage{}, nil, fmt.Errorf("unable to tag %s as %s: %w", offlineName, image.Tag, err)
+		}
+	}
+
+	return pushedImage, img, nil
+}
+
+// pushInOtherManifestFormat retries the push of an image whose manifest the registry rejected in the other manifest format
+func pushInOtherManifestFormat(img v1.Image, src, offlineName string, registryInfo types.RegistryInfo, pushOptions []crane.Option, progress *imageProgress, rejected error) (v1.Image, v1.Hash, error) {
+	current, err := getManifestFormat(img)
+	if err != nil {
+		return nil, v1.Hash{}, err
 	}
+	format := getOtherManifestFormat(current)
 
-	spinner.Success()
+	converted, err := normalizeImage(img, format, registryInfo.PushForeignLayers)
+	if err != nil {
+		return nil, v1.Hash{}, fmt.Errorf("the registry rejected the %s manifest and converting it failed: %w: %s", current, rejected, err.Error())
+	}
+	digest, err := converted.Digest()
+	if err != nil {
+		return nil, v1.Hash{}, err
+	}
+
+	message.Warnf("The registry rejected the %s manifest of %s, pushing it as %s instead. Use 'zarf init --registry-manifest-format=%s' to skip the first attempt", current, src, format, format)
+	if err := uploadImage(converted, src, offlineName, pushOptions, progress); err != nil {
+		return nil, v1.Hash{}, fmt.Errorf("the registry rejected both the %s and %s manifests: %w", current, format, err)
+	}
+	return converted, digest, nil
+}
+
+// uploadImage writes the image to the registry, feeding the bytes it uploads into the progress and the push stats
+func uploadImage(img v1.Image, src, offlineName string, pushOptions []crane.Option, progress *imageProgress) error {
+	// The remote write only closes the updates once it starts, so stop tracking them when the push returns either way
+	updates := make(chan v1.Update, 200)
+	done := make(chan struct{})
+	go progress.trackUpdates(src, updates, done)
+	defer close(done)
+
+	// Copy the shared options so concurrent pushes don't append into the same slice
+	imagePushOptions := append(append([]crane.Option{}, pushOptions...), withProgress(updates))
+
+	// Layers the registry already has aren't read, so they count as skipped
+	tracker := newUploadTracker()
+	message.Debugf("crane.Push() %s -> %s)", src, offlineName)
+	if err := crane.Push(&trackedImage{Image: img, tracker: tracker}, offlineName, imagePushOptions...); err != nil {
+		return err
+	}
+	recordPushedImage(img, tracker.uploaded)
 	return nil
 }
+
+// withJobs sets how many layers of an image crane uploads at once
+func withJobs(jobs int) crane.Option {
+	return func(options *crane.Options) {
+		options.Remote = append(options.Remote, remote.WithJobs(jobs))
+	}
+}
+
+// withProgress sends the byte counts of the upload to the updates channel
+func withProgress(updates chan<- v1.Update) crane.Option {
+	return func(options *crane.Options) {
+		options.Remote = append(options.Remote, remote.WithProgress(updates))
+	}
+}
+
+// connectToRegistry returns an address for a Zarf registry that is reachable from this machine
+// along with a function to close any tunnel that had to be opened to reach it
+func connectToRegistry(registryInfo types.RegistryInfo) (string, func()) {
+	if registryInfo.InternalRegistry || registryInfo.InClusterExternal {
+		// Establish a registry tunnel to send the images to the registry in the cluster
+		tunnel := k8s.NewZarfTunnel()
+		tunnel.Connect(k8s.ZarfRegistry, false)
+		return tunnel.Endpoint(), tunnel.Close
+	}
+
+	registryUrl := registryInfo.Address
+
+	// If this is a serviceURL, create a port-forward tunnel to that resource
+	if tunnel, err := k8s.NewTunnelFromServiceURL(registryUrl); err != nil {
+		message.Debug(err)
+	} else {
+		tunnel.Connect("", false)
+		return tunnel.Endpoint(), tunnel.Close
+	}
+
+	return registryUrl, func() {}
+}
+
+// newDeployedImage records an image pushed to the registry under offlineName
+// The reference is kept without the registry host since tunnel ports change between runs
+func newDeployedImage(source, offlineName, registryURL, digest string) types.DeployedImage {
+	return types.DeployedImage{
+		Source:    source,
+		Reference: strings.TrimPrefix(offlineName, registryURL+"/"),
+		Digest:    digest,
+	}
+}
@@ -0,0 +1,124 @@
+package images
+
+import (
+	"io"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// PushStats adds up the layers pushed to the Zarf registry in this run and the ones skipped because the registry already had them
+type PushStats struct {
+	BytesPushed   int64
+	BytesSkipped  int64
+	LayersPushed  int
+	LayersSkipped int
+}
+
+var (
+	pushStats     PushStats
+	pushStatsLock sync.Mutex
+)
+
+// GetPushStats returns the layers pushed and skipped so far
+func GetPushStats() PushStats {
+	pushStatsLock.Lock()
+	defer pushStatsLock.Unlock()
+	return pushStats
+}
+
+// recordPushedImage counts each layer of the image as pushed when its contents were read for the upload and as skipped otherwise
+func recordPushedImage(img v1.Image, uploaded map[v1.Hash]bool) {
+	layers, err := img.Layers()
+	if err != nil {
+		return
+	}
+
+	pushStatsLock.Lock()
+	defer pushStatsLock.Unlock()
+
+	// An image can list the same layer more than once, it is only sent once
+	seen := make(map[v1.Hash]bool)
+	for _, layer := range layers {
+		digest, err := layer.Digest()
+		if err != nil || seen[digest] {
+			continue
+		}
+		seen[digest] = true
+
+		size, err := layer.Size()
+		if err != nil {
+			continue
+		}
+		if uploaded[digest] {
+			pushStats.BytesPushed += size
+			pushStats.LayersPushed++
+		} else {
+			pushStats.BytesSkipped += size
+			pushStats.LayersSkipped++
+		}
+	}
+}
+
+// uploadTracker records which layers of an image have their contents read, which crane only does for layers the registry doesn't have
+type uploadTracker struct {
+	lock     sync.Mutex
+	uploaded map[v1.Hash]bool
+}
+
+func newUploadTracker() *uploadTracker {
+	return &uploadTracker{uploaded: make(map[v1.Hash]bool)}
+}
+
+func (t *uploadTracker) add(digest v1.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.uploaded[digest] = true
+}
+
+// trackedImage reports the layers of the image that are read to the tracker
+type trackedImage struct {
+	v1.Image
+	tracker *uploadTracker
+}
+
+// Layers wraps the layers of the image so reading them is tracked
+func (i *trackedImage) Layers() ([]v1.Layer, error) {
+	layers, err := i.Image.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := make([]v1.Layer, len(layers))
+	for idx, layer := range layers {
+		wrapped[idx] = &trackedLayer{Layer: layer, tracker: i.tracker}
+	}
+	return wrapped, nil
+}
+
+// LayerByDigest wraps the layer so reading it is tracked
+func (i *trackedImage) LayerByDigest(hash v1.Hash) (v1.Layer, error) {
+	layer, err := i.Image.LayerByDigest(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &trackedLayer{Layer: layer, tracker: i.tracker}, nil
+}
+
+// trackedLayer reports its digest to the tracker once its compressed contents are opened
+type trackedLayer struct {
+	v1.Layer
+	tracker *uploadTracker
+}
+
+// Compressed records the layer as uploaded before returning its contents
+func (l *trackedLayer) Compressed() (io.ReadCloser, error) {
+	reader, err := l.Layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	if digest, err := l.Layer.Digest(); err == nil {
+		l.tracker.add(digest)
+	}
+	return reader, nil
+}
@@ -0,0 +1,120 @@
+package images
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/credentials"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+)
+
+// getRegistryCraneOptions returns the crane options to reach a Zarf registry with the given credentials, using any custom CA or TLS setting it was registered with
+// and skipping the proxy with --no-proxy-cluster
+func getRegistryCraneOptions(registryInfo types.RegistryInfo, username, password string) ([]crane.Option, error) {
+	options := []crane.Option{config.GetCraneAuthOption(username, password)}
+
+	if len(registryInfo.CABundle) > 0 || registryInfo.InsecureSkipVerify || config.CommonOptions.NoProxyCluster {
+		transport, err := utils.NewHTTPTransport(registryInfo.CABundle, registryInfo.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("unable to use the registry CA bundle: %w", err)
+		}
+		transport.Proxy = config.GetClusterProxy()
+		options = append(options, crane.WithTransport(transport))
+	}
+
+	return options, nil
+}
+
+// getRegistryPushCraneOptions returns the crane options to push to a registry, getting a short-lived token when it uses a workload identity or credential helper
+func getRegistryPushCraneOptions(registryInfo types.RegistryInfo) ([]crane.Option, error) {
+	username, password, err := credentials.GetPushCredentials(registryInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRegistryCraneOptions(registryInfo, username, password)
+}
+
+// getRegistryPullCraneOptions returns the crane options to pull from a registry, getting a short-lived token when it uses a credential helper
+func getRegistryPullCraneOptions(registryInfo types.RegistryInfo) ([]crane.Option, error) {
+	username, password, err := credentials.GetPullCredentials(registryInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRegistryCraneOptions(registryInfo, username, password)
+}
+
+// ValidateRegistry probes an external registry with the provided credentials by pushing, pulling and deleting a small test image
+// This surfaces address, port, TLS and auth problems during init instead of during the first package deploy
+func ValidateRegistry(registryInfo types.RegistryInfo) error {
+	message.Debugf("images.ValidateRegistry(%s)", registryInfo.Address)
+
+	pushOptions, err := getRegistryPushCraneOptions(registryInfo)
+	if err != nil {
+		return err
+	}
+	pullOptions, err := getRegistryPullCraneOptions(registryInfo)
+	if err != nil {
+		return err
+	}
+
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	// The test image gets a repo, tag and config of its own, so deleting it by digest can't remove a manifest anything else uses
+	randomBytes := make([]byte, 6)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return err
+	}
+	probeID := hex.EncodeToString(randomBytes)
+	testName := fmt.Sprintf("zarf-connectivity-check-%s:%s", probeID, probeID)
+	testImage := fmt.Sprintf("%s/%s", registryUrl, testName)
+
+	// Registries with a push path (e.g. Harbor projects) may reject images outside of it
+	if registryInfo.PushPath != "" {
+		testImage, err = utils.SwapHostWithPushPath(testName, registryUrl, registryInfo.PushPath, registryInfo.Project, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	testConfig, err := empty.Image.ConfigFile()
+	if err != nil {
+		return err
+	}
+	testConfig.Config.Labels = map[string]string{"dev.zarf.connectivity-check": probeID}
+	testImg, err := mutate.ConfigFile(empty.Image, testConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := crane.Push(testImg, testImage, pushOptions...); err != nil {
+		return fmt.Errorf("unable to push a test image to %s with the push user, check the address, port, CA and credentials: %w", registryInfo.Address, err)
+	}
+
+	pushedDigest, err := testImg.Digest()
+	if err != nil {
+		return err
+	}
+
+	// Clean up the test image even if the pull check fails, not every registry allows deletes so only warn about it
+	defer func() {
+		if err := crane.Delete(fmt.Sprintf("%s@%s", testImage, pushedDigest), pushOptions...); err != nil {
+			message.Warnf("Unable to remove the test image %s from the registry: %s", testImage, err.Error())
+		}
+	}()
+
+	if _, err := crane.Digest(testImage, pullOptions...); err != nil {
+		return fmt.Errorf("unable to pull the test image from %s with the pull user, check the pull credentials: %w", registryInfo.Address, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/sigstore/cosign/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/pkg/signature"
+)
+
+// Cosign stores the signatures and attestations of a digest under these suffixes of the sha256-<hex> tag
+const (
+	signatureSuffix   = ".sig"
+	attestationSuffix = ".att"
+)
+
+// PullSignatures pulls the cosign signatures and attestations of the images into their own tarball
+// When keys are given every image needs a signature that verifies against one of them, and attestations that don't verify are left out
+// The returned map lists the artifacts of each image by the name they are stored under in the signature tarball
+func PullSignatures(imageTarballPath string, imageList []string, signatureTarballPath string, keys []string) (map[string][]string, error) {
+	message.Debugf("images.PullSignatures(%s, %s, %s, %s)", imageTarballPath, imageList, signatureTarballPath, keys)
+
+	spinner := message.NewProgressSpinner("Pulling the signatures of %d images", len(imageList))
+	defer spinner.Stop()
+
+	ctx := context.TODO()
+	checkOpts, err := getSignatureCheckOpts(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make(map[name.Tag]v1.Image)
+	signatures := make(map[string][]string)
+	var failures []string
+	for _, src := range imageList {
+		spinner.Updatef("Pulling the signatures of %s", src)
+		tags, err := pullImageSignatures(ctx, imageTarballPath, src, checkOpts, artifacts)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", src, err.Error()))
+			continue
+		}
+		if len(tags) > 0 {
+			signatures[src] = tags
+		}
+	}
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return nil, fmt.Errorf("unable to bundle the signatures of %d of %d images:\n  - %s", len(failures), len(imageList), strings.Join(failures, "\n  - "))
+	}
+
+	if len(artifacts) > 0 {
+		if err := tarball.MultiWriteToFile(signatureTarballPath, artifacts); err != nil {
+			return nil, fmt.Errorf("unable to write the signature tarball: %w", err)
+		}
+	}
+
+	spinner.Successf("Bundled %d signatures and attestations for %d of %d images", len(artifacts), len(signatures), len(imageList))
+	return signatures, nil
+}
+
+// getSignatureCheckOpts loads each public key into the options cosign verifies signatures with
+func getSignatureCheckOpts(ctx context.Context, keys []string) ([]*cosign.CheckOpts, error) {
+	registryOpts := []ociremote.Option{
+		ociremote.WithRemoteOptions(remote.WithAuthFromKeychain(config.GetKeychain()), remote.WithContext(ctx)),
+	}
+
+	var checkOpts []*cosign.CheckOpts
+	for _, key := range keys {
+		verifier, err := sigs.LoadPublicKey(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the public key %s: %w", key, err)
+		}
+		checkOpts = append(checkOpts, &cosign.CheckOpts{
+			RegistryClientOpts: registryOpts,
+			SigVerifier:        verifier,
+		})
+	}
+	return checkOpts, nil
+}
+
+// pullImageSignatures adds the signature artifacts of one image to the artifacts and returns their names
+// Signing a multi-arch image signs its index, so the digest the tag resolves to is checked along with the platform image in the package
+func pullImageSignatures(ctx context.Context, imageTarballPath, src string, checkOpts []*cosign.CheckOpts, artifacts map[name.Tag]v1.Image) ([]string, error) {
+	// Cosign keeps signatures in a registry, which images loaded from a daemon were never pushed to
+	if _, prefix := utils.SplitDaemonSource(src); prefix != "" {
+		message.Warnf("Skipping the signatures of %s since it is loaded from a local daemon", src)
+		return nil, nil
+	}
+
+	img, err := crane.LoadTag(imageTarballPath, getTarballTag(src), config.GetCraneOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := name.ParseReference(src)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := []string{digest.String()}
+	if descriptor, err := crane.Head(src, config.GetCraneOptions()...); err != nil {
+		message.Debugf("Unable to resolve %s, only looking for signatures of %s: %s", src, digest.String(), err.Error())
+	} else if descriptor.Digest != digest {
+		digests = append(digests, descriptor.Digest.String())
+	}
+
+	var tags []string
+	signed := false
+	for _, candidate := range digests {
+		digestRef := ref.Context().Digest(candidate)
+
+		// Without keys everything cosign stored for the digest is bundled as is
+		includeSignature := len(checkOpts) == 0
+		includeAttestation := len(checkOpts) == 0
+		for _, opts := range checkOpts {
+			if !includeSignature {
+				opts.ClaimVerifier = cosign.SimpleClaimVerifier
+				if _, _, err := cosign.VerifyImageSignatures(ctx, digestRef, opts); err == nil {
+					includeSignature = true
+				} else {
+					message.Debugf("The signatures of %s don't verify: %s", digestRef.String(), err.Error())
+				}
+			}
+			if !includeAttestation {
+				opts.ClaimVerifier = cosign.IntotoSubjectClaimVerifier
+				if _, _, err := cosign.VerifyImageAttestations(ctx, digestRef, opts); err == nil {
+					includeAttestation = true
+				} else {
+					message.Debugf("The attestations of %s don't verify: %s", digestRef.String(), err.Error())
+				}
+			}
+		}
+
+		artifactTag := strings.Replace(candidate, ":", "-", 1)
+		if includeSignature {
+			tag, found, err := pullSignatureArtifact(ref.Context().Tag(artifactTag+signatureSuffix), artifacts)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				tags = append(tags, tag)
+				signed = true
+			}
+		}
+		if includeAttestation {
+			tag, found, err := pullSignatureArtifact(ref.Context().Tag(artifactTag+attestationSuffix), artifacts)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				tags = append(tags, tag)
+			}
+		} else if len(checkOpts) > 0 {
+			if _, err := crane.Head(ref.Context().Tag(artifactTag+attestationSuffix).String(), config.GetCraneOptions()...); err == nil {
+				message.Warnf("Leaving out the attestations of %s since they don't verify against the signature keys", src)
+			}
+		}
+	}
+
+	if len(checkOpts) > 0 && !signed {
+		return nil, errors.New("no signature verifies against the signature keys")
+	}
+
+	return tags, nil
+}
+
+// pullSignatureArtifact adds a signature or attestation to the artifacts, returning false if cosign didn't store one under the tag
+func pullSignatureArtifact(tag name.Tag, artifacts map[name.Tag]v1.Image) (string, bool, error) {
+	artifact, err := crane.Pull(tag.String(), config.GetCraneOptions()...)
+	if err != nil {
+		var transportErr *transport.Error
+		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("unable to pull %s: %w", tag.String(), err)
+	}
+
+	artifacts[tag] = artifact
+	return tag.String(), true, nil
+}
+
+// PushSignaturesToZarfRegistry pushes the bundled signatures and attestations of the pushed images into the repositories they were pushed to
+// The artifacts keep their sha256-<hex> tags, so the images converted to a new digest for the registry are left without them
+func PushSignaturesToZarfRegistry(ctx context.Context, signatureTarballPath string, signatures map[string][]string, pushedImages []types.DeployedImage) error {
+	message.Debugf("images.PushSignaturesToZarfRegistry(%s, %#v, %#v)", signatureTarballPath, signatures, pushedImages)
+
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	pushOptions, err := getRegistryPushCraneOptions(registryInfo)
+	if err != nil {
+		return err
+	}
+	pushOptions = append(pushOptions, crane.WithContext(ctx))
+
+	spinner := message.NewProgressSpinner("Pushing the signatures of %d images", len(pushedImages))
+	defer spinner.Stop()
+
+	count := 0
+	for _, pushedImage := range pushedImages {
+		artifacts := signatures[pushedImage.Source]
+		if len(artifacts) == 0 {
+			continue
+		}
+
+		ref, err := name.ParseReference(fmt.Sprintf("%s/%s", registryUrl, pushedImage.Reference))
+		if err != nil {
+			return err
+		}
+
+		for _, artifact := range artifacts {
+			tag, err := name.NewTag(artifact)
+			if err != nil {
+				return err
+			}
+
+			// The signatures sign the digest in the package, which a converted image no longer has
+			if !strings.HasPrefix(tag.TagStr(), strings.Replace(pushedImage.Digest, ":", "-", 1)) {
+				message.Warnf("Not pushing %s, the image was converted to %s for the registry and the signature no longer matches it", artifact, pushedImage.Digest)
+				continue
+			}
+
+			img, err := crane.LoadTag(signatureTarballPath, artifact, config.GetCraneOptions()...)
+			if err != nil {
+				return fmt.Errorf("unable to load %s from the package: %w", artifact, err)
+			}
+
+			offlineName := ref.Context().Tag(tag.TagStr()).String()
+			spinner.Updatef("Pushing %s", offlineName)
+			message.Debugf("crane.Push() %s -> %s)", artifact, offlineName)
+			if err := crane.Push(img, offlineName, pushOptions...); err != nil {
+				return fmt.Errorf("unable to push %s: %w", artifact, err)
+			}
+			count++
+		}
+	}
+
+	spinner.Successf("Pushed %d signatures and attestations", count)
+	return nil
+}
@@ -0,0 +1,112 @@
+package images
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pterm/pterm"
+)
+
+// maxCreatedByLength keeps the layer breakdown table readable for long RUN instructions
+const maxCreatedByLength = 60
+
+// WarnLargeImages prints a warning with a per-layer size breakdown for every image larger than the threshold
+// Sizes are read from the image manifests so no layer data needs to be downloaded
+func WarnLargeImages(imageMap map[string]v1.Image, thresholdMB int) {
+	message.Debugf("images.WarnLargeImages(%d images, %d)", len(imageMap), thresholdMB)
+
+	if thresholdMB <= 0 {
+		return
+	}
+	threshold := int64(thresholdMB) * 1024 * 1024
+
+	// Sort the image names so the warnings are in a stable order
+	var imageNames []string
+	for src := range imageMap {
+		imageNames = append(imageNames, src)
+	}
+	sort.Strings(imageNames)
+
+	for _, src := range imageNames {
+		manifest, err := imageMap[src].Manifest()
+		if err != nil {
+			message.Debugf("Unable to read the manifest for %s: %s", src, err.Error())
+			continue
+		}
+
+		size := getManifestSize(manifest)
+		if size <= threshold {
+			continue
+		}
+
+		message.Warnf("The image %s is %s, which is larger than the %s image size warning threshold",
+			src, utils.ByteFormat(float64(size), 2), utils.ByteFormat(float64(threshold), 2))
+
+		_ = pterm.DefaultTable.WithHasHeader().WithData(getLayerBreakdown(imageMap[src], manifest)).Render()
+	}
+}
+
+// GetImageSizes returns the size of each image in the package image tarball from its manifest
+func GetImageSizes(imageTarballPath string, imageList []string) (map[string]int64, error) {
+	message.Debugf("images.GetImageSizes(%s, %#v)", imageTarballPath, imageList)
+
+	sizes := make(map[string]int64)
+	for _, src := range imageList {
+		img, err := crane.LoadTag(imageTarballPath, getTarballTag(src), config.GetCraneOptions()...)
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := img.Manifest()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the manifest for %s: %w", src, err)
+		}
+		sizes[src] = getManifestSize(manifest)
+	}
+
+	return sizes, nil
+}
+
+// getManifestSize adds up the config and layer sizes of an image manifest
+func getManifestSize(manifest *v1.Manifest) int64 {
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
+// getLayerBreakdown lists the size of each layer alongside the instruction that created it when the image history has one
+func getLayerBreakdown(img v1.Image, manifest *v1.Manifest) pterm.TableData {
+	var createdBy []string
+	if configFile, err := img.ConfigFile(); err == nil {
+		for _, history := range configFile.History {
+			if !history.EmptyLayer {
+				createdBy = append(createdBy, history.CreatedBy)
+			}
+		}
+	}
+
+	layerTable := pterm.TableData{{"     Layer", "Size", "Created By"}}
+	for idx, layer := range manifest.Layers {
+		instruction := ""
+		if idx < len(createdBy) {
+			instruction = createdBy[idx]
+			if len(instruction) > maxCreatedByLength {
+				instruction = instruction[:maxCreatedByLength] + "..."
+			}
+		}
+
+		layerTable = append(layerTable, []string{
+			fmt.Sprintf("     %s", layer.Digest.String()[:19]),
+			utils.ByteFormat(float64(layer.Size), 2),
+			instruction,
+		})
+	}
+
+	return layerTable
+}
@@ -0,0 +1,48 @@
+package images
+
+import (
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+)
+
+// VerifyDigests checks that each deployed image is still in the Zarf registry with the digest recorded when it was pushed
+// The returned map is keyed by the source image name and holds a nil error for images that still match
+func VerifyDigests(deployedImages []types.DeployedImage) map[string]error {
+	message.Debugf("images.VerifyDigests(%#v)", deployedImages)
+
+	results := make(map[string]error)
+	if len(deployedImages) == 0 {
+		return results
+	}
+
+	registryInfo := config.GetContainerRegistryInfo()
+	registryUrl, closeTunnel := connectToRegistry(registryInfo)
+	defer closeTunnel()
+
+	pullOptions, err := getRegistryPullCraneOptions(registryInfo)
+	if err != nil {
+		for _, image := range deployedImages {
+			results[image.Source] = err
+		}
+		return results
+	}
+
+	for _, image := range deployedImages {
+		offlineName := fmt.Sprintf("%s/%s", registryUrl, image.Reference)
+
+		digest, err := crane.Digest(offlineName, pullOptions...)
+		if err != nil {
+			results[image.Source] = fmt.Errorf("unable to get the digest for %s: %w", image.Reference, err)
+		} else if digest != image.Digest {
+			results[image.Source] = fmt.Errorf("digest for %s is %s, expected %s", image.Reference, digest, image.Digest)
+		} else {
+			results[image.Source] = nil
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,182 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+const httpPort = "8080"
+
+// inventoryImage is an InventoryImage with the package it was deployed by
+type inventoryImage struct {
+	Package string `json:"package"`
+	types.InventoryImage
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Zarf Inventory</title></head>
+<body>
+<h1>Zarf Inventory</h1>
+<table>
+<tr><th>Package</th><th>Component</th><th>Image</th><th>SBOM Digest</th></tr>
+{{range .}}<tr><td>{{.Package}}</td><td>{{.Component}}</td><td>{{.Image}}</td><td>{{.SBOMDigest}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// StartServer launches the inventory API that aggregates the images of every deployed package
+func StartServer() {
+	message.Debug("inventory.StartServer()")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/api/v1/packages", listPackages)
+	mux.HandleFunc("/api/v1/packages/", getPackage)
+	mux.HandleFunc("/api/v1/images", listImages)
+	mux.HandleFunc("/", index)
+
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", httpPort),
+		Handler: mux,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			message.Fatal(err, "Failed to start the inventory server")
+		}
+	}()
+
+	message.Infof("Server running in port: %s", httpPort)
+
+	// listen shutdown signal
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
+	<-signalChan
+
+	message.Infof("Shutdown gracefully...")
+	if err := server.Shutdown(context.Background()); err != nil {
+		message.Fatal(err, "unable to properly shutdown the inventory server")
+	}
+}
+
+// getInventories reads the inventory published by each deployed package
+func getInventories() ([]types.PackageInventory, error) {
+	configMaps, err := k8s.GetConfigMapsWithLabel(k8s.ZarfNamespace, config.ZarfInventoryLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	inventories := []types.PackageInventory{}
+	for _, configMap := range configMaps.Items {
+		var inventory types.PackageInventory
+		if err := json.Unmarshal(configMap.BinaryData["inventory"], &inventory); err != nil {
+			message.Debugf("Unable to parse the inventory in %s: %#v", configMap.Name, err)
+			continue
+		}
+		inventories = append(inventories, inventory)
+	}
+
+	sort.Slice(inventories, func(i, j int) bool {
+		return inventories[i].Name < inventories[j].Name
+	})
+
+	return inventories, nil
+}
+
+func getImages() ([]inventoryImage, error) {
+	inventories, err := getInventories()
+	if err != nil {
+		return nil, err
+	}
+
+	images := []inventoryImage{}
+	for _, inventory := range inventories {
+		for _, image := range inventory.Images {
+			images = append(images, inventoryImage{Package: inventory.Name, InventoryImage: image})
+		}
+	}
+
+	return images, nil
+}
+
+func listPackages(w http.ResponseWriter, r *http.Request) {
+	inventories, err := getInventories()
+	if err != nil {
+		message.ErrorWebf(err, w, "Unable to read the package inventories")
+		return
+	}
+
+	writeJSON(w, inventories)
+}
+
+func getPackage(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/packages/")
+
+	inventories, err := getInventories()
+	if err != nil {
+		message.ErrorWebf(err, w, "Unable to read the package inventories")
+		return
+	}
+
+	for _, inventory := range inventories {
+		if inventory.Name == name {
+			writeJSON(w, inventory)
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
+
+func listImages(w http.ResponseWriter, r *http.Request) {
+	images, err := getImages()
+	if err != nil {
+		message.ErrorWebf(err, w, "Unable to read the package inventories")
+		return
+	}
+
+	writeJSON(w, images)
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	images, err := getImages()
+	if err != nil {
+		message.ErrorWebf(err, w, "Unable to read the package inventories")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := indexTemplate.Execute(w, images); err != nil {
+		message.Debugf("Unable to render the inventory page: %#v", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		message.Debugf("Unable to write the inventory response: %#v", err)
+	}
+}
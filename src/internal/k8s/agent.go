@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/pki"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// RotateAgentTLS generates a new PKI for the zarf agent and rolls it out to the webhook, the agent TLS secret and the zarf state
+// It runs in the agent, which has neither the key of an encrypted state nor access to its store, so only the agentTLS field of the
+// zarf-state secret is changed and the credentials are left as they are
+func RotateAgentTLS() error {
+	message.Debug("k8s.RotateAgentTLS()")
+
+	secret, err := GetSecret(ZarfNamespace, ZarfStateSecretName)
+	if err != nil {
+		return fmt.Errorf("unable to load the zarf state: %w", err)
+	}
+
+	// The agent rotates its own certificate, so a failure is returned rather than exiting the webhook server
+	agentTLS, err := pki.GeneratePKIWithOptions(config.ZarfAgentHost, pki.DefaultOptions())
+	if err != nil {
+		return fmt.Errorf("unable to generate the agent TLS certificate: %w", err)
+	}
+	stateData, previousCA, err := setStateAgentTLS(secret.Data[ZarfStateDataKey], agentTLS)
+	if err != nil {
+		return fmt.Errorf("unable to update the zarf state: %w", err)
+	}
+
+	// Trust both the new and previous CA so agent pods that have not yet reloaded their cert keep serving requests
+	caBundle := bytes.Join([][]byte{agentTLS.CA, previousCA}, []byte{})
+	if err := UpdateMutatingWebhookCABundle(config.ZarfAgentWebhookName, caBundle); err != nil {
+		return fmt.Errorf("unable to update the agent webhook CA bundle: %w", err)
+	}
+
+	if err := ReplaceTLSSecret(ZarfNamespace, config.ZarfAgentTLSSecretName, agentTLS); err != nil {
+		return fmt.Errorf("unable to update the agent TLS secret: %w", err)
+	}
+
+	secret.Data[ZarfStateDataKey] = stateData
+	return UpdateSecret(secret)
+}
+
+// setStateAgentTLS replaces the agentTLS field of the json of a zarf state and returns the new json and the CA it replaced
+// The other fields are copied as they are, so encrypted credentials are never decrypted
+func setStateAgentTLS(stateData []byte, agentTLS types.GeneratedPKI) ([]byte, []byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(stateData, &fields); err != nil {
+		return nil, nil, err
+	}
+
+	var previous types.GeneratedPKI
+	if raw, ok := fields["agentTLS"]; ok {
+		if err := json.Unmarshal(raw, &previous); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	raw, err := json.Marshal(agentTLS)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields["agentTLS"] = raw
+
+	updated, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, previous.CA, nil
+}
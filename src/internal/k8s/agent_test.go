@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetStateAgentTLS(t *testing.T) {
+	// Neither the key file nor a vault token are in the agent pods
+	keyPath := filepath.Join(t.TempDir(), "missing.key")
+	t.Setenv(StateKeyFileEnv, keyPath)
+	t.Setenv("VAULT_TOKEN", "")
+
+	oldTLS := types.GeneratedPKI{CA: []byte("old-ca"), Cert: []byte("old-cert"), Key: []byte("old-key")}
+
+	tests := []struct {
+		name  string
+		state types.ZarfState
+		// load reads the credentials the way the CLI does, which has to fail without the key or store
+		load func(state *types.ZarfState) error
+	}{
+		{
+			name: "encrypted state",
+			state: types.ZarfState{
+				Distro:   "k3s",
+				AgentTLS: oldTLS,
+				RegistryInfo: types.RegistryInfo{
+					PushUsername: "zarf-push",
+					PushPassword: encryptedValuePrefix + "cHVzaA==",
+					PullPassword: "pull-password",
+				},
+				GitServer:  types.GitServerInfo{PushPassword: encryptedValuePrefix + "Z2l0"},
+				Encryption: types.StateEncryption{Provider: StateEncryptionKeyFile, Key: keyPath, DataKey: "wrapped-data-key"},
+			},
+			load: decryptStateCredentials,
+		},
+		{
+			name: "credentials in a vault store",
+			state: types.ZarfState{
+				Distro:       "k3s",
+				AgentTLS:     oldTLS,
+				RegistryInfo: types.RegistryInfo{PushUsername: "zarf-push", PullPassword: "pull-password"},
+				StateStore:   types.StateStore{Type: StateStoreVault, URL: "https://vault.example.com/v1/secret/data/zarf"},
+			},
+			load: LoadStateCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateData, err := json.Marshal(tt.state)
+			require.NoError(t, err)
+
+			loaded := tt.state
+			require.Error(t, tt.load(&loaded))
+
+			agentTLS := types.GeneratedPKI{CA: []byte("new-ca"), Cert: []byte("new-cert"), Key: []byte("new-key")}
+			updated, previousCA, err := setStateAgentTLS(stateData, agentTLS)
+			require.NoError(t, err)
+			assert.Equal(t, oldTLS.CA, previousCA)
+
+			var rotated types.ZarfState
+			require.NoError(t, json.Unmarshal(updated, &rotated))
+
+			want := tt.state
+			want.AgentTLS = agentTLS
+			assert.Equal(t, want, rotated)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"k8s.io/client-go/rest"
+)
+
+// GetClockSkew returns how far the clock of this host is ahead of the cluster API server, which is negative when it is behind
+// The server time comes from the Date header of a request, which only has second precision, so the skew is off by up to half a second
+func GetClockSkew() (time.Duration, error) {
+	message.Debug("k8s.GetClockSkew()")
+
+	restConfig, err := getRestConfig()
+	if err != nil {
+		return 0, err
+	}
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return 0, err
+	}
+
+	host := strings.TrimSuffix(restConfig.Host, "/")
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	sent := time.Now()
+	resp, err := httpClient.Get(host + "/version")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("the API server didn't send a valid Date header: %w", err)
+	}
+
+	// Compare the middle of the request with the middle of the second the server sent
+	localTime := sent.Add(received.Sub(sent) / 2)
+	return localTime.Sub(serverTime.Add(500 * time.Millisecond)), nil
+}
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 
+	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/template"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
@@ -137,9 +138,19 @@ func getRestConfig() (*rest.Config, error) {
 
 	// Build the config from the currently active kube context in the default way that the k8s client-go gets it, which
 	// is to look at the KUBECONFIG env var
-	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		clientcmd.NewDefaultClientConfigLoadingRules(),
 		&clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// The API requests and the port-forward dialer of the tunnels both use this config
+	if config.CommonOptions.NoProxyCluster {
+		restConfig.Proxy = config.GetClusterProxy()
+	}
+
+	return restConfig, nil
 }
 
 func getClientset() (*kubernetes.Clientset, error) {
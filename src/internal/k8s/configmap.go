@@ -51,6 +51,17 @@ func CreateConfigmap(namespace, name string, labels map[string]string, data map[
 	return clientset.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, createOptions)
 }
 
+// GetConfigmap returns a configmap by name
+func GetConfigmap(namespace, name string) (*corev1.ConfigMap, error) {
+	message.Debugf("k8s.GetConfigmap(%s, %s)", namespace, name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
 // DeleteConfigmap delets a confimap by name
 func DeleteConfigmap(namespace, name string) error {
 	message.Debugf("k8s.DeleteConfigmap(%s, %s)", namespace, name)
@@ -87,3 +98,15 @@ func DeleteConfigMapsByLabel(namespace string, labels map[string]string) error {
 
 	return clientset.CoreV1().ConfigMaps(namespace).DeleteCollection(context.TODO(), metaOptions, listOptions)
 }
+
+// GetConfigMapsWithLabel returns the configmaps in a namespace that have the given label key
+func GetConfigMapsWithLabel(namespace, label string) (*corev1.ConfigMapList, error) {
+	message.Debugf("k8s.GetConfigMapsWithLabel(%s, %s)", namespace, label)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: label}
+	return clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), listOptions)
+}
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// CreateDaemonSet creates a daemonset in the cluster
+func CreateDaemonSet(daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	message.Debugf("k8s.CreateDaemonSet(%s/%s)", daemonSet.Namespace, daemonSet.Name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AppsV1().DaemonSets(daemonSet.Namespace).Create(context.TODO(), daemonSet, metav1.CreateOptions{})
+}
+
+// UpdateDaemonSet updates a daemonset in the cluster, which rolls its pods when the template changes
+func UpdateDaemonSet(daemonSet *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	message.Debugf("k8s.UpdateDaemonSet(%s/%s)", daemonSet.Namespace, daemonSet.Name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AppsV1().DaemonSets(daemonSet.Namespace).Update(context.TODO(), daemonSet, metav1.UpdateOptions{})
+}
+
+// GetDaemonSet returns a daemonset by name
+func GetDaemonSet(namespace, name string) (*appsv1.DaemonSet, error) {
+	message.Debugf("k8s.GetDaemonSet(%s, %s)", namespace, name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AppsV1().DaemonSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// DeleteDaemonSet deletes a daemonset by name along with its pods
+func DeleteDaemonSet(namespace, name string) error {
+	message.Debugf("k8s.DeleteDaemonSet(%s, %s)", namespace, name)
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	return clientset.AppsV1().DaemonSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+}
+
+// GetPodsBySelector returns the pods in the namespace with all of the labels
+func GetPodsBySelector(namespace string, podLabels map[string]string) (*corev1.PodList, error) {
+	message.Debugf("k8s.GetPodsBySelector(%s, %#v)", namespace, podLabels)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labels.SelectorFromSet(podLabels).String()})
+}
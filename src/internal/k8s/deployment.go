@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetDeployment returns a deployment by name
+func GetDeployment(namespace, name string) (*appsv1.Deployment, error) {
+	message.Debugf("k8s.GetDeployment(%s, %s)", namespace, name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// UpdateDeployment updates a deployment in the cluster, which rolls its pods when the template changes
+func UpdateDeployment(deployment *appsv1.Deployment) (*appsv1.Deployment, error) {
+	message.Debugf("k8s.UpdateDeployment(%s/%s)", deployment.Namespace, deployment.Name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AppsV1().Deployments(deployment.Namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+}
+
+// WaitForDeploymentRollout waits until every replica of the deployment runs its latest template and the old pods are gone
+func WaitForDeploymentRollout(namespace, name string, timeout time.Duration) error {
+	message.Debugf("k8s.WaitForDeploymentRollout(%s, %s, %s)", namespace, name, timeout)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		deployment, err := GetDeployment(namespace, name)
+		if err != nil {
+			return err
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		status := deployment.Status
+		if status.ObservedGeneration >= deployment.Generation && status.UpdatedReplicas == replicas &&
+			status.Replicas == replicas && status.AvailableReplicas == replicas {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the deployment %s/%s did not roll out within %s, %d of %d replicas are updated", namespace, name, timeout, status.UpdatedReplicas, replicas)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
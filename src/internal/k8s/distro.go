@@ -3,6 +3,7 @@ package k8s
 import (
 	"errors"
 	"regexp"
+	"sort"
 
 	"github.com/defenseunicorns/zarf/src/internal/message"
 )
@@ -112,6 +113,31 @@ func DetectDistro() (string, error) {
 	return DistroIsUnknown, nil
 }
 
+// GetArchitectures returns the distinct architectures of the nodes in the cluster, sorted by name
+func GetArchitectures() ([]string, error) {
+	message.Debugf("k8s.GetArchitectures()")
+	nodes, err := GetNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	var architectures []string
+	for _, node := range nodes.Items {
+		arch := node.Status.NodeInfo.Architecture
+		if arch != "" && !found[arch] {
+			found[arch] = true
+			architectures = append(architectures, arch)
+		}
+	}
+	if len(architectures) == 0 {
+		return nil, errors.New("could not identify node architecture")
+	}
+
+	sort.Strings(architectures)
+	return architectures, nil
+}
+
 // GetArchitecture returns the cluster system architecture if found or an error if not
 func GetArchitecture() (string, error) {
 	message.Debugf("k8s.GetArchitecture()")
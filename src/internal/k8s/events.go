@@ -0,0 +1,137 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// The waiting reasons of containers that won't fix themselves while helm waits
+var stuckContainerReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// WatchWarnings reports Warning events and stuck containers of the pods matching the label selector in the namespaces to the handler until the returned stop function is called
+// Only the pods matching the selector and the objects that own them are tracked, anything that happened before since is ignored and each problem is only reported once
+func WatchWarnings(namespaces []string, selector string, since time.Time, handler func(string)) (func(), error) {
+	message.Debugf("k8s.WatchWarnings(%v, %s, %s)", namespaces, selector, since)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// Event timestamps only have second precision
+	since = since.Truncate(time.Second)
+
+	var lock sync.Mutex
+	reported := make(map[string]bool)
+	report := func(key, text string) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !reported[key] {
+			reported[key] = true
+			handler(text)
+		}
+	}
+
+	// Events can't be selected by label, so they are matched against the pods the selector finds and their owners
+	tracked := make(map[string]bool)
+	track := func(namespace, kind, name string) {
+		lock.Lock()
+		defer lock.Unlock()
+		tracked[fmt.Sprintf("%s/%s/%s", namespace, kind, name)] = true
+	}
+	isTracked := func(namespace, kind, name string) bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return tracked[fmt.Sprintf("%s/%s/%s", namespace, kind, name)]
+	}
+
+	var watchers []watch.Interface
+	for _, namespace := range namespaces {
+		eventWatcher, err := clientset.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		podWatcher, err := clientset.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			eventWatcher.Stop()
+			cancel()
+			return nil, err
+		}
+		watchers = append(watchers, eventWatcher, podWatcher)
+
+		go func() {
+			for result := range eventWatcher.ResultChan() {
+				event, ok := result.Object.(*corev1.Event)
+				if !ok || getEventTime(event).Before(since) || !isTracked(event.Namespace, event.InvolvedObject.Kind, event.InvolvedObject.Name) {
+					continue
+				}
+				object := fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name)
+				report(object+event.Reason+event.Message, fmt.Sprintf("%s %s: %s", object, event.Reason, event.Message))
+			}
+		}()
+
+		go func() {
+			for result := range podWatcher.ResultChan() {
+				pod, ok := result.Object.(*corev1.Pod)
+				if !ok {
+					continue
+				}
+				track(pod.Namespace, "Pod", pod.Name)
+				for _, owner := range pod.OwnerReferences {
+					track(pod.Namespace, owner.Kind, owner.Name)
+				}
+				if pod.CreationTimestamp.Time.Before(since) {
+					continue
+				}
+				statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+				for _, status := range statuses {
+					waiting := status.State.Waiting
+					if waiting == nil || !stuckContainerReasons[waiting.Reason] {
+						continue
+					}
+					object := fmt.Sprintf("Pod/%s container %s", pod.Name, status.Name)
+					report(object+waiting.Reason, fmt.Sprintf("%s %s: %s", object, waiting.Reason, waiting.Message))
+				}
+			}
+		}()
+	}
+
+	stop := func() {
+		cancel()
+		for _, watcher := range watchers {
+			watcher.Stop()
+		}
+	}
+
+	return stop, nil
+}
+
+// getEventTime returns the most recent time an event happened
+func getEventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,170 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeleteRunResources removes the temporary daemonsets, pods, services, configmaps, claims and service accounts labeled with a run ID
+// Without a run ID only the runs that haven't created anything for longer than staleAfter are cleaned up, so runs still in progress keep their resources
+// The IDs of the runs that had resources removed are returned
+func DeleteRunResources(runID string, staleAfter time.Duration) ([]string, error) {
+	message.Debugf("k8s.DeleteRunResources(%s, %s)", runID, staleAfter)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	selector := config.ZarfRunLabel
+	if runID != "" {
+		selector = fmt.Sprintf("%s=%s", config.ZarfRunLabel, runID)
+	}
+	listOptions := metav1.ListOptions{LabelSelector: selector}
+	deleteOptions := metav1.DeleteOptions{}
+
+	// Everything is listed before anything is removed, so a run is only judged by its newest resource
+	type runResource struct {
+		kind       string
+		object     metav1.Object
+		deleteFunc func(namespace, name string) error
+	}
+	var resources []runResource
+	add := func(kind string, objects []metav1.Object, deleteFunc func(namespace, name string) error) {
+		for _, object := range objects {
+			resources = append(resources, runResource{kind, object, deleteFunc})
+		}
+	}
+
+	// Daemonsets go first so they don't replace the pods removed below
+	daemonSets, err := clientset.AppsV1().DaemonSets("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	var objects []metav1.Object
+	for idx := range daemonSets.Items {
+		objects = append(objects, &daemonSets.Items[idx])
+	}
+	add("daemonset", objects, func(namespace, name string) error {
+		return clientset.AppsV1().DaemonSets(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	pods, err := clientset.CoreV1().Pods("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range pods.Items {
+		objects = append(objects, &pods.Items[idx])
+	}
+	add("pod", objects, func(namespace, name string) error {
+		return clientset.CoreV1().Pods(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	services, err := clientset.CoreV1().Services("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range services.Items {
+		objects = append(objects, &services.Items[idx])
+	}
+	add("service", objects, func(namespace, name string) error {
+		return clientset.CoreV1().Services(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	configMaps, err := clientset.CoreV1().ConfigMaps("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range configMaps.Items {
+		objects = append(objects, &configMaps.Items[idx])
+	}
+	add("configmap", objects, func(namespace, name string) error {
+		return clientset.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	claims, err := clientset.CoreV1().PersistentVolumeClaims("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range claims.Items {
+		objects = append(objects, &claims.Items[idx])
+	}
+	add("claim", objects, func(namespace, name string) error {
+		return clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	// Bindings go ahead of the roles and service accounts they refer to
+	roleBindings, err := clientset.RbacV1().RoleBindings("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range roleBindings.Items {
+		objects = append(objects, &roleBindings.Items[idx])
+	}
+	add("role binding", objects, func(namespace, name string) error {
+		return clientset.RbacV1().RoleBindings(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	roles, err := clientset.RbacV1().Roles("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range roles.Items {
+		objects = append(objects, &roles.Items[idx])
+	}
+	add("role", objects, func(namespace, name string) error {
+		return clientset.RbacV1().Roles(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	serviceAccounts, err := clientset.CoreV1().ServiceAccounts("").List(context.TODO(), listOptions)
+	if err != nil {
+		return nil, err
+	}
+	objects = nil
+	for idx := range serviceAccounts.Items {
+		objects = append(objects, &serviceAccounts.Items[idx])
+	}
+	add("service account", objects, func(namespace, name string) error {
+		return clientset.CoreV1().ServiceAccounts(namespace).Delete(context.TODO(), name, deleteOptions)
+	})
+
+	newest := make(map[string]time.Time)
+	for _, resource := range resources {
+		run := resource.object.GetLabels()[config.ZarfRunLabel]
+		if created := resource.object.GetCreationTimestamp().Time; created.After(newest[run]) {
+			newest[run] = created
+		}
+	}
+
+	runs := make(map[string]bool)
+	for _, resource := range resources {
+		run := resource.object.GetLabels()[config.ZarfRunLabel]
+		if runID == "" && time.Since(newest[run]) < staleAfter {
+			message.Debugf("Keeping the %s %s/%s of run %s, the run may still be in progress", resource.kind, resource.object.GetNamespace(), resource.object.GetName(), run)
+			continue
+		}
+
+		message.Debugf("Removing the %s %s/%s left by run %s", resource.kind, resource.object.GetNamespace(), resource.object.GetName(), run)
+		if err := resource.deleteFunc(resource.object.GetNamespace(), resource.object.GetName()); err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to remove the %s %s/%s: %w", resource.kind, resource.object.GetNamespace(), resource.object.GetName(), err)
+		}
+		runs[run] = true
+	}
+
+	var removedRuns []string
+	for run := range runs {
+		removedRuns = append(removedRuns, run)
+	}
+	return removedRuns, nil
+}
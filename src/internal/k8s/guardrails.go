@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The names of the ResourceQuota and LimitRange Zarf adds to the namespaces it creates
+const (
+	ZarfResourceQuotaName = "zarf-quota"
+	ZarfLimitRangeName    = "zarf-limit-range"
+)
+
+// HasNamespaceGuardrails returns whether the guardrails size a ResourceQuota or LimitRange at all
+func HasNamespaceGuardrails(guardrails types.ZarfNamespaceGuardrails) bool {
+	limits := guardrails.LimitRange
+	return len(guardrails.ResourceQuota) > 0 || len(limits.Default) > 0 || len(limits.DefaultRequest) > 0 || len(limits.Min) > 0 || len(limits.Max) > 0
+}
+
+// ApplyNamespaceGuardrails creates or updates the ResourceQuota and LimitRange the guardrails size in the namespace
+func ApplyNamespaceGuardrails(namespace string, guardrails types.ZarfNamespaceGuardrails) error {
+	message.Debugf("k8s.ApplyNamespaceGuardrails(%s, %#v)", namespace, guardrails)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{config.ZarfManagedByLabel: "zarf"}
+
+	if len(guardrails.ResourceQuota) > 0 {
+		hard, err := ParseResourceList(guardrails.ResourceQuota)
+		if err != nil {
+			return fmt.Errorf("invalid resourceQuota: %w", err)
+		}
+
+		quotas := clientset.CoreV1().ResourceQuotas(namespace)
+		quota, err := quotas.Get(context.TODO(), ZarfResourceQuotaName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			quota = &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: ZarfResourceQuotaName, Namespace: namespace, Labels: labels},
+				Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+			}
+			_, err = quotas.Create(context.TODO(), quota, metav1.CreateOptions{})
+		} else if err == nil {
+			quota.Spec.Hard = hard
+			_, err = quotas.Update(context.TODO(), quota, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("unable to apply the %s resource quota: %w", ZarfResourceQuotaName, err)
+		}
+	}
+
+	limits := guardrails.LimitRange
+	if len(limits.Default) > 0 || len(limits.DefaultRequest) > 0 || len(limits.Min) > 0 || len(limits.Max) > 0 {
+		item := corev1.LimitRangeItem{Type: corev1.LimitTypeContainer}
+		for _, field := range []struct {
+			name   string
+			values map[string]string
+			list   *corev1.ResourceList
+		}{
+			{"default", limits.Default, &item.Default},
+			{"defaultRequest", limits.DefaultRequest, &item.DefaultRequest},
+			{"min", limits.Min, &item.Min},
+			{"max", limits.Max, &item.Max},
+		} {
+			if len(field.values) == 0 {
+				continue
+			}
+			if *field.list, err = ParseResourceList(field.values); err != nil {
+				return fmt.Errorf("invalid limitRange.%s: %w", field.name, err)
+			}
+		}
+
+		limitRanges := clientset.CoreV1().LimitRanges(namespace)
+		limitRange, err := limitRanges.Get(context.TODO(), ZarfLimitRangeName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			limitRange = &corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{Name: ZarfLimitRangeName, Namespace: namespace, Labels: labels},
+				Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}},
+			}
+			_, err = limitRanges.Create(context.TODO(), limitRange, metav1.CreateOptions{})
+		} else if err == nil {
+			limitRange.Spec.Limits = []corev1.LimitRangeItem{item}
+			_, err = limitRanges.Update(context.TODO(), limitRange, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("unable to apply the %s limit range: %w", ZarfLimitRangeName, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseResourceList converts quantities keyed by resource name, such as cpu: 500m, into a ResourceList
+func ParseResourceList(values map[string]string) (corev1.ResourceList, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make(corev1.ResourceList, len(values))
+	for _, name := range names {
+		quantity, err := resource.ParseQuantity(values[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid quantity for %s: %w", values[name], name, err)
+		}
+		list[corev1.ResourceName(name)] = quantity
+	}
+	return list, nil
+}
@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// How long a leader keeps the lease without renewing it, and how often the others try to take it over
+const (
+	leaseDuration = 15 * time.Second
+	leaseRenewal  = 10 * time.Second
+	leaseRetry    = 2 * time.Second
+)
+
+// RunAsLeader runs the function while this process holds the named lease in the zarf namespace, so only one replica of a deployment runs it at a time
+// The context given to the function is cancelled when the lease is lost, after which this process tries to take it over again until ctx is done
+func RunAsLeader(ctx context.Context, name string, run func(ctx context.Context)) error {
+	message.Debugf("k8s.RunAsLeader(%s)", name)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	// Pods get their name as their hostname, which tells the replicas apart
+	identity, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("unable to get the identity for the %s lease: %w", name, err)
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Name: name, Namespace: ZarfNamespace},
+		Client:     clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   leaseRenewal,
+		RetryPeriod:     leaseRetry,
+		ReleaseOnCancel: true,
+		Name:            name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				message.Infof("%s holds the %s lease", identity, name)
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				message.Debugf("%s no longer holds the %s lease", identity, name)
+			},
+			OnNewLeader: func(leader string) {
+				message.Debugf("%s holds the %s lease", leader, name)
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Run returns once the lease is lost, so campaign again until the process shuts down
+	for ctx.Err() == nil {
+		elector.Run(ctx)
+	}
+	return nil
+}
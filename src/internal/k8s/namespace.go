@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/defenseunicorns/zarf/src/config"
@@ -73,6 +74,77 @@ func CreateNamespace(name string, namespace *corev1.Namespace) (*corev1.Namespac
 	return match, err
 }
 
+// EnableAgentInNamespace labels the namespace zarf.dev/agent=enabled so the agent mutates it in opt-in mode, unless the namespace opted out
+func EnableAgentInNamespace(name string) error {
+	message.Debugf("k8s.EnableAgentInNamespace(%s)", name)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	namespace, err := clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return enableAgentInNamespace(namespace)
+}
+
+// EnableAgentInManagedNamespaces labels every namespace Zarf manages zarf.dev/agent=enabled so the agent keeps mutating them in opt-in mode
+func EnableAgentInManagedNamespaces() error {
+	message.Debug("k8s.EnableAgentInManagedNamespaces()")
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: config.ZarfManagedByLabel + "=zarf"}
+	namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), listOptions)
+	if err != nil {
+		return err
+	}
+
+	for idx := range namespaces.Items {
+		if err := enableAgentInNamespace(&namespaces.Items[idx]); err != nil {
+			return fmt.Errorf("unable to enable the agent in the %s namespace: %w", namespaces.Items[idx].Name, err)
+		}
+	}
+	return nil
+}
+
+// enableAgentInNamespace adds the zarf.dev/agent=enabled label to the namespace
+func enableAgentInNamespace(namespace *corev1.Namespace) error {
+	// A namespace that opted out or is already enabled is left as it is
+	if _, ok := namespace.Labels[config.ZarfAgentLabel]; ok {
+		return nil
+	}
+
+	if namespace.Labels == nil {
+		namespace.Labels = make(map[string]string)
+	}
+	namespace.Labels[config.ZarfAgentLabel] = agentEnabled
+
+	_, err := UpdateNamespace(namespace)
+	return err
+}
+
+// DeleteNamespace removes a namespace along with everything still in it, without waiting for it to finish terminating
+func DeleteNamespace(name string) error {
+	message.Debugf("k8s.DeleteNamespace(%s)", name)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	err = clientset.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 func DeleteZarfNamespace() {
 	spinner := message.NewProgressSpinner("Deleting the zarf namespace from this cluster")
 	defer spinner.Stop()
@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"sort"
 	"time"
@@ -12,6 +13,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 const waitLimit = 30
@@ -78,6 +82,30 @@ func CreatePod(pod *corev1.Pod) (*corev1.Pod, error) {
 	return clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, createOptions)
 }
 
+// LabelPod adds the labels to a pod that is already in the cluster
+func LabelPod(namespace, name string, labels map[string]string) error {
+	message.Debugf("k8s.LabelPod(%s, %s, %#v)", namespace, name, labels)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	for key, value := range labels {
+		pod.Labels[key] = value
+	}
+
+	_, err = clientset.CoreV1().Pods(namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
+	return err
+}
+
 // GetAllPods returns a list of pods from the cluster for all namesapces
 func GetAllPods() (*corev1.PodList, error) {
 	return GetPods(corev1.NamespaceAll)
@@ -183,3 +211,39 @@ func WaitForPodsAndContainers(target types.ZarfContainerTarget, waitForAllPods b
 
 	return []string{}
 }
+
+// ExecInPod runs a command in a container of a running pod and returns what it wrote to stdout and stderr
+// An empty container runs the command in the only container of the pod
+func ExecInPod(namespace, podName, container string, command []string) (string, string, error) {
+	message.Debugf("k8s.ExecInPod(%s, %s, %s, %s)", namespace, podName, container, command)
+
+	restConfig, err := getRestConfig()
+	if err != nil {
+		return "", "", err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return "", "", err
+	}
+
+	request := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", request.URL())
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = executor.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The Pod Security Standards levels, from the least to the most strict
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// PodSecurityEnforceLabel is the namespace label Pod Security admission reads the enforced level from
+const PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// ComparePodSecurityLevels returns a negative number when level a is less strict than level b, 0 when they match and a positive number otherwise
+func ComparePodSecurityLevels(a, b string) int {
+	order := map[string]int{PodSecurityPrivileged: 0, PodSecurityBaseline: 1, PodSecurityRestricted: 2}
+	return order[a] - order[b]
+}
+
+// GetPodSecurityLevel returns the Pod Security Standards level enforced on pods in the namespace
+// A level set on the namespace is used when there is one, otherwise the cluster default is found with dry-run pods
+func GetPodSecurityLevel(namespaceName string) (string, error) {
+	message.Debugf("k8s.GetPodSecurityLevel(%s)", namespaceName)
+	clientset, err := getClientset()
+	if err != nil {
+		return "", err
+	}
+
+	probeNamespace := namespaceName
+	namespace, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespaceName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// The namespace will get the cluster default, which applies to any namespace without the label
+		probeNamespace = corev1.NamespaceDefault
+	} else if err != nil {
+		return "", err
+	} else if level := namespace.Labels[PodSecurityEnforceLabel]; level != "" {
+		return level, nil
+	}
+
+	// The cluster default isn't exposed by the API, so ask the admission about pods that only meet the looser levels
+	baselinePod := generatePodSecurityProbe(probeNamespace)
+	if allowed, err := isPodAllowed(baselinePod); err != nil || !allowed {
+		return PodSecurityRestricted, err
+	}
+
+	privilegedPod := generatePodSecurityProbe(probeNamespace)
+	privilegedPod.Spec.HostNetwork = true
+	if allowed, err := isPodAllowed(privilegedPod); err != nil || !allowed {
+		return PodSecurityBaseline, err
+	}
+
+	return PodSecurityPrivileged, nil
+}
+
+// generatePodSecurityProbe returns a pod that meets the baseline level but not the restricted one
+func generatePodSecurityProbe(namespace string) *corev1.Pod {
+	pod := GeneratePod("zarf-pod-security-probe", namespace)
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name:  "probe",
+			Image: "registry.k8s.io/pause:3.9",
+		},
+	}
+	return pod
+}
+
+// isPodAllowed creates the pod as a dry-run and returns false if Pod Security admission rejects it
+func isPodAllowed(pod *corev1.Pod) (bool, error) {
+	clientset, err := getClientset()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = clientset.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if errors.IsForbidden(err) && strings.Contains(err.Error(), "violates PodSecurity") {
+		message.Debug(err)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to check the pod security admission with a dry-run pod: %w", err)
+	}
+
+	return true, nil
+}
+
+// SetPodSecurityLevel labels the namespace so Pod Security admission enforces the level on its pods
+func SetPodSecurityLevel(namespaceName, level string) error {
+	message.Debugf("k8s.SetPodSecurityLevel(%s, %s)", namespaceName, level)
+
+	namespace, err := CreateNamespace(namespaceName, nil)
+	if err != nil {
+		return err
+	}
+
+	if namespace.Labels == nil {
+		namespace.Labels = make(map[string]string)
+	}
+	namespace.Labels[PodSecurityEnforceLabel] = level
+
+	_, err = UpdateNamespace(namespace)
+	return err
+}
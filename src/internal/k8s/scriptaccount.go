@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// CreateScopedServiceAccount creates a service account with a role limited to the rules in its namespace and returns a kubeconfig that authenticates as it
+// The token in the kubeconfig expires after the ttl, and the service account, role and binding are labeled with the run ID so zarf tools gc removes them if the run is interrupted
+func CreateScopedServiceAccount(namespace, name string, rules []rbacv1.PolicyRule, ttl time.Duration) ([]byte, error) {
+	message.Debugf("k8s.CreateScopedServiceAccount(%s, %s, %s)", namespace, name, ttl)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := getRestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := CreateNamespace(namespace, nil); err != nil {
+		return nil, fmt.Errorf("unable to create or read the namespace: %w", err)
+	}
+
+	// Clear out an account left behind by an interrupted run
+	if err := DeleteScopedServiceAccount(namespace, name); err != nil {
+		return nil, fmt.Errorf("unable to remove the service account left by an earlier run: %w", err)
+	}
+
+	objectMeta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			config.ZarfManagedByLabel: "zarf",
+			config.ZarfRunLabel:       config.GetRunID(),
+		},
+	}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: objectMeta}
+	if _, err := clientset.CoreV1().ServiceAccounts(namespace).Create(context.TODO(), serviceAccount, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("unable to create the service account: %w", err)
+	}
+
+	role := &rbacv1.Role{ObjectMeta: objectMeta, Rules: rules}
+	if _, err := clientset.RbacV1().Roles(namespace).Create(context.TODO(), role, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("unable to create the role: %w", err)
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: objectMeta,
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+	}
+	if _, err := clientset.RbacV1().RoleBindings(namespace).Create(context.TODO(), binding, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("unable to create the role binding: %w", err)
+	}
+
+	expirationSeconds := int64(ttl.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}
+	token, err := clientset.CoreV1().ServiceAccounts(namespace).CreateToken(context.TODO(), name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a token for the service account: %w", err)
+	}
+	message.AddSensitiveValue(token.Status.Token)
+
+	// Point the kubeconfig at the same API server this run uses
+	caData := restConfig.CAData
+	if len(caData) == 0 && restConfig.CAFile != "" {
+		if caData, err = os.ReadFile(restConfig.CAFile); err != nil {
+			return nil, fmt.Errorf("unable to read the CA of the cluster: %w", err)
+		}
+	}
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[name] = &clientcmdapi.Cluster{
+		Server:                   restConfig.Host,
+		CertificateAuthorityData: caData,
+		InsecureSkipTLSVerify:    restConfig.Insecure,
+		TLSServerName:            restConfig.ServerName,
+	}
+	kubeconfig.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: token.Status.Token}
+	kubeconfig.Contexts[name] = &clientcmdapi.Context{Cluster: name, AuthInfo: name, Namespace: namespace}
+	kubeconfig.CurrentContext = name
+
+	return clientcmd.Write(*kubeconfig)
+}
+
+// DeleteScopedServiceAccount removes a service account made by CreateScopedServiceAccount along with its role and binding
+// Objects with the same name that Zarf didn't create are left in place and returned as an error
+func DeleteScopedServiceAccount(namespace, name string) error {
+	message.Debugf("k8s.DeleteScopedServiceAccount(%s, %s)", namespace, name)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	bindings := clientset.RbacV1().RoleBindings(namespace)
+	roles := clientset.RbacV1().Roles(namespace)
+	serviceAccounts := clientset.CoreV1().ServiceAccounts(namespace)
+
+	objects := []struct {
+		kind   string
+		get    func() (metav1.Object, error)
+		delete func() error
+	}{
+		{
+			kind:   "role binding",
+			get:    func() (metav1.Object, error) { return bindings.Get(context.TODO(), name, metav1.GetOptions{}) },
+			delete: func() error { return bindings.Delete(context.TODO(), name, metav1.DeleteOptions{}) },
+		},
+		{
+			kind:   "role",
+			get:    func() (metav1.Object, error) { return roles.Get(context.TODO(), name, metav1.GetOptions{}) },
+			delete: func() error { return roles.Delete(context.TODO(), name, metav1.DeleteOptions{}) },
+		},
+		{
+			kind:   "service account",
+			get:    func() (metav1.Object, error) { return serviceAccounts.Get(context.TODO(), name, metav1.GetOptions{}) },
+			delete: func() error { return serviceAccounts.Delete(context.TODO(), name, metav1.DeleteOptions{}) },
+		},
+	}
+
+	for _, object := range objects {
+		existing, err := object.get()
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.GetLabels()[config.ZarfManagedByLabel] != "zarf" {
+			return fmt.Errorf("the %s %s/%s was not created by zarf", object.kind, namespace, name)
+		}
+		if err := object.delete(); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
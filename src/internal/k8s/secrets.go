@@ -6,8 +6,10 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/credentials"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/types"
 	corev1 "k8s.io/api/core/v1"
@@ -69,42 +71,140 @@ func GenerateSecret(namespace, name string, secretType corev1.SecretType) *corev
 func GenerateRegistryPullCreds(namespace, name string) *corev1.Secret {
 	message.Debugf("k8s.GenerateRegistryPullCreds(%s, %s)", namespace, name)
 
-	secretDockerConfig := GenerateSecret(namespace, name, corev1.SecretTypeDockerConfigJson)
-
-	// Get the registry credentials from the ZarfState secret
+	// Get the registry credentials from the ZarfState secret, or its credential helper
 	zarfState, err := LoadZarfState()
 	if err != nil {
 		message.Fatalf(err, "Unable to load the Zarf state to get the registry credentials")
 	}
-	credential := zarfState.RegistryInfo.PullPassword
-	if credential == "" {
-		message.Fatalf(nil, "Generate pull cred failed")
+
+	secretDockerConfig, err := generateRegistryPullCreds(zarfState.RegistryInfo, namespace, name)
+	if err != nil {
+		message.Fatalf(err, "Unable to create the embedded registry secret")
 	}
+	return secretDockerConfig
+}
 
-	// Auth field must be username:password and base64 encoded
-	fieldValue := zarfState.RegistryInfo.PullUsername + ":" + credential
-	authEncodedValue := base64.StdEncoding.EncodeToString([]byte(fieldValue))
+// CreateMissingRegistryPullCreds creates the registry pull secret in a namespace that doesn't have it yet, such as one that was just created
+func CreateMissingRegistryPullCreds(registryInfo types.RegistryInfo, namespace string) error {
+	message.Debugf("k8s.CreateMissingRegistryPullCreds(%s)", namespace)
 
-	registry := config.GetRegistry()
-	// Create the expected structure for the dockerconfigjson
-	dockerConfigJSON := DockerConfig{
-		Auths: DockerConfigEntry{
-			registry: DockerConfigEntryWithAuth{
-				Auth: authEncodedValue,
-			},
-		},
+	_, err := GetSecret(namespace, config.ZarfImagePullSecretName)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return err
 	}
 
-	// Convert to JSON
-	dockerConfigData, err := json.Marshal(dockerConfigJSON)
+	secret, err := generateRegistryPullCreds(registryInfo, namespace, config.ZarfImagePullSecretName)
 	if err != nil {
-		message.Fatalf(err, "Unable to create the embedded registry secret")
+		return err
+	}
+
+	// Another request for the same namespace may have created it in the meantime
+	if err := CreateSecret(secret); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// generateRegistryPullCreds returns a pull secret with the credentials of the registry
+func generateRegistryPullCreds(registryInfo types.RegistryInfo, namespace, name string) (*corev1.Secret, error) {
+	secretDockerConfig := GenerateSecret(namespace, name, corev1.SecretTypeDockerConfigJson)
+
+	username, credential, err := credentials.GetPullCredentials(registryInfo)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the registry pull credentials: %w", err)
+	}
+	if credential == "" {
+		return nil, fmt.Errorf("the registry has no pull credentials")
+	}
+	// The agent reads the state without its key, which only works once the state is saved with the pull password left unencrypted
+	if strings.HasPrefix(credential, encryptedValuePrefix) {
+		return nil, fmt.Errorf("the registry pull password is encrypted, run 'zarf tools state encrypt' again to leave it readable by the zarf agent")
+	}
+
+	// Pods reach a registry in the cluster through its service rather than the address the nodes pull from, like Flux does for OCI sources
+	registries := []string{config.GetRegistry()}
+	if host := config.GetInClusterRegistryHost(registryInfo); host != "" {
+		registries = append(registries, host)
+	}
+
+	dockerConfigData, err := generateDockerConfigJSON(username, credential, registries...)
+	if err != nil {
+		return nil, err
 	}
 
 	// Add to the secret data
 	secretDockerConfig.Data[".dockerconfigjson"] = dockerConfigData
 
-	return secretDockerConfig
+	return secretDockerConfig, nil
+}
+
+// RefreshRegistryPullCreds replaces the credentials in the registry pull secrets Zarf created in every namespace
+func RefreshRegistryPullCreds(registry, username, password string) error {
+	message.Debugf("k8s.RefreshRegistryPullCreds(%s, %s)", registry, username)
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	dockerConfigData, err := generateDockerConfigJSON(username, password, registry)
+	if err != nil {
+		return err
+	}
+
+	// The agent may only read the pull secrets by name, so each namespace is checked for one rather than listing every secret in the cluster
+	namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, namespace := range namespaces.Items {
+		secret, err := clientset.CoreV1().Secrets(namespace.Name).Get(context.TODO(), config.ZarfImagePullSecretName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			message.Debug(err)
+			failed = append(failed, namespace.Name)
+			continue
+		}
+		// Only the pull secrets Zarf created are refreshed
+		if secret.Labels[config.ZarfManagedByLabel] != "zarf" {
+			continue
+		}
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[".dockerconfigjson"] = dockerConfigData
+		if _, err := clientset.CoreV1().Secrets(namespace.Name).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			message.Debug(err)
+			failed = append(failed, namespace.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to refresh the registry pull secrets in the namespaces %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// generateDockerConfigJSON returns the .dockerconfigjson of a pull secret for the registry under each of its addresses
+func generateDockerConfigJSON(username, password string, registries ...string) ([]byte, error) {
+	// Auth field must be username:password and base64 encoded
+	fieldValue := username + ":" + password
+	authEncodedValue := base64.StdEncoding.EncodeToString([]byte(fieldValue))
+
+	// Create the expected structure for the dockerconfigjson
+	dockerConfigJSON := DockerConfig{Auths: DockerConfigEntry{}}
+	for _, registry := range registries {
+		dockerConfigJSON.Auths[registry] = DockerConfigEntryWithAuth{Auth: authEncodedValue}
+	}
+
+	return json.Marshal(dockerConfigJSON)
 }
 
 func GenerateTLSSecret(namespace, name string, conf types.GeneratedPKI) (*corev1.Secret, error) {
@@ -179,3 +279,50 @@ func CreateSecret(secret *corev1.Secret) error {
 
 	return nil
 }
+
+// UpdateSecret writes the changes to an existing secret, failing if it was changed since it was read
+func UpdateSecret(secret *corev1.Secret) error {
+	message.Debugf("k8s.UpdateSecret(%s, %s)", secret.Namespace, secret.Name)
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	if _, err := clientset.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("unable to update the secret: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegistryCredentials reads a username and password from a basic-auth secret or the first entry of a dockerconfigjson secret
+func GetRegistryCredentials(namespace, name string) (string, string, error) {
+	message.Debugf("k8s.GetRegistryCredentials(%s, %s)", namespace, name)
+	secret, err := GetSecret(namespace, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch secret.Type {
+	case corev1.SecretTypeBasicAuth:
+		return string(secret.Data[corev1.BasicAuthUsernameKey]), string(secret.Data[corev1.BasicAuthPasswordKey]), nil
+
+	case corev1.SecretTypeDockerConfigJson:
+		var dockerConfig DockerConfig
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig); err != nil {
+			return "", "", fmt.Errorf("unable to read the docker config in the secret %s/%s: %w", namespace, name, err)
+		}
+		for _, entry := range dockerConfig.Auths {
+			auth, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return "", "", fmt.Errorf("unable to decode the auth in the secret %s/%s: %w", namespace, name, err)
+			}
+			if username, password, found := strings.Cut(string(auth), ":"); found {
+				return username, password, nil
+			}
+		}
+		return "", "", fmt.Errorf("the secret %s/%s does not contain any registry credentials", namespace, name)
+	}
+
+	return "", "", fmt.Errorf("the secret %s/%s must be of type %s or %s", namespace, name, corev1.SecretTypeBasicAuth, corev1.SecretTypeDockerConfigJson)
+}
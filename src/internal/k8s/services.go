@@ -2,6 +2,8 @@ package k8s
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/message"
@@ -109,3 +111,34 @@ func GetServicesByLabelExists(namespace, label string) (*corev1.ServiceList, err
 	// Run the query with the selector and return as a ServiceList
 	return clientset.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector.String()})
 }
+
+// WaitForLoadBalancerAddress waits for the load balancer of a service to get an address, returning it as HOST:PORT with the first port of the service
+func WaitForLoadBalancerAddress(namespace, name string, timeout time.Duration) (string, error) {
+	message.Debugf("k8s.WaitForLoadBalancerAddress(%s, %s, %s)", namespace, name, timeout)
+
+	expired := time.After(timeout)
+	for {
+		service, err := GetService(namespace, name)
+		if err != nil {
+			return "", fmt.Errorf("unable to get the %s service: %w", name, err)
+		}
+
+		if len(service.Spec.Ports) > 0 {
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				host := ingress.IP
+				if ingress.Hostname != "" {
+					host = ingress.Hostname
+				}
+				if host != "" {
+					return fmt.Sprintf("%s:%d", host, service.Spec.Ports[0].Port), nil
+				}
+			}
+		}
+
+		select {
+		case <-expired:
+			return "", fmt.Errorf("timed out waiting for the load balancer of the %s service to get an address", name)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
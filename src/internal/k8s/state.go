@@ -33,7 +33,15 @@ func LoadZarfState() (types.ZarfState, error) {
 
 	_ = json.Unmarshal(secret.Data[ZarfStateDataKey], &state)
 
-	message.Debugf("ZarfState = %s", message.JsonValue(state))
+	// Decrypt the credentials that were encrypted with the data key of the state
+	if err := decryptStateCredentials(&state); err != nil {
+		return state, fmt.Errorf("unable to decrypt the zarf state credentials: %w", err)
+	}
+
+	// Fill in the credentials that are kept outside of the secret
+	if err := LoadStateCredentials(&state); err != nil {
+		return state, err
+	}
 
 	return state, nil
 }
@@ -41,7 +49,30 @@ func LoadZarfState() (types.ZarfState, error) {
 // SaveZarfState takes a given state and makepersists it to the zarf/zarf-state secret
 func SaveZarfState(state types.ZarfState) error {
 	message.Debugf("k8s.SaveZarfState()")
-	message.Debug(message.JsonValue(state))
+
+	// Move the credentials to their store so they are left out of the secret
+	store, err := NewStateStore(state.StateStore)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		credentials := make(map[string]string)
+		for key, field := range getStateCredentialFields(&state) {
+			if agentStateCredentials[key] {
+				continue
+			}
+			credentials[key] = *field
+			*field = ""
+		}
+		if err := store.Save(credentials); err != nil {
+			return fmt.Errorf("unable to save the zarf state credentials to the %s store: %w", state.StateStore.Type, err)
+		}
+	}
+
+	// Encrypt the credentials that are left in the secret
+	if err := encryptStateCredentials(&state); err != nil {
+		return fmt.Errorf("unable to encrypt the zarf state credentials: %w", err)
+	}
 
 	// Convert the data back to JSON
 	data, err := json.Marshal(state)
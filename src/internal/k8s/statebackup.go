@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// stateBackupVersion is bumped when the layout of a ZarfStateBackup changes in a way older versions can't import
+const stateBackupVersion = 1
+
+// ExportZarfState returns the zarf/zarf-state secret, with the credentials filled in from their store, and the deployed package secrets
+func ExportZarfState() (types.ZarfStateBackup, error) {
+	message.Debug("k8s.ExportZarfState()")
+
+	backup := types.ZarfStateBackup{
+		Version:    stateBackupVersion,
+		CLIVersion: config.CLIVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	state, err := LoadZarfState()
+	if err != nil {
+		return backup, err
+	}
+	if state.Distro == "" {
+		return backup, fmt.Errorf("the zarf/zarf-state secret is empty")
+	}
+	backup.State = state
+
+	secrets, err := GetSecretsWithLabel(ZarfNamespace, "package-deploy-info")
+	if err != nil {
+		return backup, err
+	}
+	for _, secret := range secrets.Items {
+		backup.Secrets = append(backup.Secrets, types.ZarfSecretBackup{
+			Name:   secret.Name,
+			Labels: secret.Labels,
+			Data:   secret.Data,
+		})
+	}
+
+	return backup, nil
+}
+
+// ImportZarfState writes the state and secrets of a backup to the cluster, replacing any that are already there
+func ImportZarfState(backup types.ZarfStateBackup) error {
+	message.Debugf("k8s.ImportZarfState(%d)", backup.Version)
+
+	if backup.Version > stateBackupVersion {
+		return fmt.Errorf("the backup was exported by Zarf %s and is newer than this version of Zarf can import", backup.CLIVersion)
+	}
+	if backup.State.Distro == "" {
+		return fmt.Errorf("the backup doesn't hold a zarf state")
+	}
+
+	// The sensitive exports of the package secrets stay encrypted with the key of the state, so it has to be here before anything is replaced
+	if info := backup.State.Encryption; info.Provider != "" {
+		if _, err := getDataKey(info); err != nil {
+			return fmt.Errorf("the backup is encrypted with the %s key %s, which is needed to import it: %w", info.Provider, info.Key, err)
+		}
+	}
+
+	if err := SaveZarfState(backup.State); err != nil {
+		return err
+	}
+
+	for _, secretBackup := range backup.Secrets {
+		secret := GenerateSecret(ZarfNamespace, secretBackup.Name, corev1.SecretTypeOpaque)
+		for key, value := range secretBackup.Labels {
+			secret.Labels[key] = value
+		}
+		secret.Data = secretBackup.Data
+		if err := ReplaceSecret(secret); err != nil {
+			return fmt.Errorf("unable to restore the secret %s: %w", secretBackup.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,423 @@
+package k8s
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// The kinds of keys the data key of the Zarf state can be encrypted with
+const (
+	StateEncryptionKeyFile      = "key-file"
+	StateEncryptionVaultTransit = "vault-transit"
+)
+
+// StateKeyFileEnv is the environment variable that overrides the path of the key file recorded in the state, the key only lives on the hosts that run Zarf
+const StateKeyFileEnv = "ZARF_STATE_KEY_FILE"
+
+// encryptedValuePrefix marks a value in the state or a package secret that is encrypted with the data key
+const encryptedValuePrefix = "zarf-encrypted:"
+
+// keyEncrypter wraps the data key of the state with a key that never leaves the host or the KMS it is kept in
+type keyEncrypter interface {
+	// WrapKey encrypts the data key
+	WrapKey(dataKey []byte) (string, error)
+	// UnwrapKey decrypts a data key encrypted by WrapKey
+	UnwrapKey(wrapped string) ([]byte, error)
+}
+
+// The data keys this run already unwrapped, keyed by their wrapped form, so a KMS is only called once per run
+var unwrappedDataKeys = make(map[string][]byte)
+var unwrappedDataKeysLock sync.Mutex
+
+// IsValidStateEncryption returns true if the data key of the state can be encrypted with the provider
+func IsValidStateEncryption(provider string) bool {
+	return provider == StateEncryptionKeyFile || provider == StateEncryptionVaultTransit
+}
+
+func newKeyEncrypter(info types.StateEncryption) (keyEncrypter, error) {
+	switch info.Provider {
+	case StateEncryptionKeyFile:
+		path := os.Getenv(StateKeyFileEnv)
+		if path == "" {
+			path = info.Key
+		}
+		return &keyFileEncrypter{path: path}, nil
+
+	case StateEncryptionVaultTransit:
+		return &vaultTransitEncrypter{url: info.Key}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported state encryption %s", info.Provider)
+}
+
+// GenerateStateKeyFile writes a new random key for the key-file state encryption to the path
+func GenerateStateKeyFile(path string) error {
+	key := make([]byte, utils.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0600)
+}
+
+// SetStateEncryption switches the state to the key in info, the existing data key is kept so values encrypted with it can still be read
+func SetStateEncryption(state *types.ZarfState, info types.StateEncryption) error {
+	message.Debugf("k8s.SetStateEncryption(%s, %s)", info.Provider, info.Key)
+
+	var dataKey []byte
+	var err error
+	if state.Encryption.DataKey != "" {
+		if dataKey, err = getDataKey(state.Encryption); err != nil {
+			return err
+		}
+	} else {
+		dataKey = make([]byte, utils.KeySize)
+		if _, err := rand.Read(dataKey); err != nil {
+			return err
+		}
+	}
+
+	encrypter, err := newKeyEncrypter(info)
+	if err != nil {
+		return err
+	}
+	if info.DataKey, err = encrypter.WrapKey(dataKey); err != nil {
+		return fmt.Errorf("unable to encrypt the data key with the %s key: %w", info.Provider, err)
+	}
+
+	unwrappedDataKeysLock.Lock()
+	unwrappedDataKeys[info.DataKey] = dataKey
+	unwrappedDataKeysLock.Unlock()
+
+	state.Encryption = info
+	return nil
+}
+
+// getDataKey returns the data key of the state decrypted with its key
+func getDataKey(info types.StateEncryption) ([]byte, error) {
+	unwrappedDataKeysLock.Lock()
+	defer unwrappedDataKeysLock.Unlock()
+
+	if dataKey, ok := unwrappedDataKeys[info.DataKey]; ok {
+		return dataKey, nil
+	}
+	if info.DataKey == "" {
+		return nil, fmt.Errorf("the state has no data key, run 'zarf tools state encrypt' to add one")
+	}
+
+	encrypter, err := newKeyEncrypter(info)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := encrypter.UnwrapKey(info.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt the data key with the %s key: %w", info.Provider, err)
+	}
+
+	unwrappedDataKeys[info.DataKey] = dataKey
+	return dataKey, nil
+}
+
+// encryptStateCredentials encrypts the credentials of the state with its data key, the state is left as it is when it isn't encrypted
+func encryptStateCredentials(state *types.ZarfState) error {
+	if state.Encryption.Provider == "" {
+		return nil
+	}
+	dataKey, err := getDataKey(state.Encryption)
+	if err != nil {
+		return err
+	}
+
+	for key, field := range getStateCredentialFields(state) {
+		if agentStateCredentials[key] {
+			continue
+		}
+		if *field, err = encryptValue(dataKey, *field); err != nil {
+			return fmt.Errorf("unable to encrypt %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// decryptStateCredentials decrypts the credentials of the state that are encrypted with its data key
+// Every credential is checked, as a state may have been saved with the ones the agent reads encrypted as well
+func decryptStateCredentials(state *types.ZarfState) error {
+	if state.Encryption.Provider == "" {
+		return nil
+	}
+	dataKey, err := getDataKey(state.Encryption)
+	if err != nil {
+		return err
+	}
+
+	for key, field := range getStateCredentialFields(state) {
+		if *field, err = decryptValue(dataKey, *field); err != nil {
+			return fmt.Errorf("unable to decrypt %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// EncryptDeployedPackage encrypts the values of the sensitive exports of a deployed package with the data key of the Zarf state
+// The package is left as it is when the state isn't encrypted, and the maps of exports it had are never changed
+func EncryptDeployedPackage(deployedPackage *types.DeployedPackage) error {
+	message.Debugf("k8s.EncryptDeployedPackage(%s)", deployedPackage.Name)
+
+	// Packages deployed to a cluster without a Zarf state have nothing to encrypt with
+	info, err := loadStateEncryption()
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil || info.Provider == "" {
+		return err
+	}
+	dataKey, err := getDataKey(info)
+	if err != nil {
+		return err
+	}
+
+	sensitive := make(map[string]bool)
+	for _, component := range deployedPackage.Data.Components {
+		for _, export := range component.Exports {
+			if export.Sensitive {
+				sensitive[export.Name] = true
+			}
+		}
+	}
+
+	// The components and their exports are copied so the caller's values stay decrypted
+	deployedComponents := make([]types.DeployedComponent, len(deployedPackage.DeployedComponents))
+	for idx, component := range deployedPackage.DeployedComponents {
+		if len(component.Exports) > 0 {
+			exports := make(map[string]string, len(component.Exports))
+			for name, value := range component.Exports {
+				if sensitive[name] {
+					if value, err = encryptValue(dataKey, value); err != nil {
+						return fmt.Errorf("unable to encrypt the export %s: %w", name, err)
+					}
+				}
+				exports[name] = value
+			}
+			component.Exports = exports
+		}
+		deployedComponents[idx] = component
+	}
+	deployedPackage.DeployedComponents = deployedComponents
+	return nil
+}
+
+// EncryptDeployedPackageSecrets encrypts the sensitive exports in the secrets of the packages already deployed, for states that were encrypted after they were deployed
+func EncryptDeployedPackageSecrets() error {
+	message.Debug("k8s.EncryptDeployedPackageSecrets()")
+
+	secrets, err := GetSecretsWithLabel(ZarfNamespace, "package-deploy-info")
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets.Items {
+		var deployedPackage types.DeployedPackage
+		if err := json.Unmarshal(secret.Data["data"], &deployedPackage); err != nil {
+			return fmt.Errorf("unable to read the secret %s: %w", secret.Name, err)
+		}
+		if err := EncryptDeployedPackage(&deployedPackage); err != nil {
+			return err
+		}
+		data, err := json.Marshal(deployedPackage)
+		if err != nil {
+			return err
+		}
+
+		packageSecret := GenerateSecret(ZarfNamespace, secret.Name, corev1.SecretTypeOpaque)
+		for key, value := range secret.Labels {
+			packageSecret.Labels[key] = value
+		}
+		packageSecret.Data = map[string][]byte{"data": data}
+		if err := ReplaceSecret(packageSecret); err != nil {
+			return fmt.Errorf("unable to update the secret %s: %w", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// DecryptDeployedPackage decrypts the values of a deployed package that are encrypted with the data key of the Zarf state
+func DecryptDeployedPackage(deployedPackage *types.DeployedPackage) error {
+	message.Debugf("k8s.DecryptDeployedPackage(%s)", deployedPackage.Name)
+
+	var dataKey []byte
+	for _, component := range deployedPackage.DeployedComponents {
+		for name, value := range component.Exports {
+			if !strings.HasPrefix(value, encryptedValuePrefix) {
+				continue
+			}
+			// Only reach out for the key once something needs it
+			if dataKey == nil {
+				info, err := loadStateEncryption()
+				if err != nil {
+					return err
+				}
+				if dataKey, err = getDataKey(info); err != nil {
+					return err
+				}
+			}
+
+			decrypted, err := decryptValue(dataKey, value)
+			if err != nil {
+				return fmt.Errorf("unable to decrypt the export %s: %w", name, err)
+			}
+			component.Exports[name] = decrypted
+		}
+	}
+	return nil
+}
+
+// loadStateEncryption reads the encryption settings of the zarf/zarf-state secret without decrypting the credentials
+func loadStateEncryption() (types.StateEncryption, error) {
+	secret, err := GetSecret(ZarfNamespace, ZarfStateSecretName)
+	if err != nil {
+		return types.StateEncryption{}, err
+	}
+
+	var state types.ZarfState
+	if err := json.Unmarshal(secret.Data[ZarfStateDataKey], &state); err != nil {
+		return types.StateEncryption{}, err
+	}
+	return state.Encryption, nil
+}
+
+// encryptValue encrypts a value with the data key, empty and already encrypted values are returned as they are
+func encryptValue(dataKey []byte, value string) (string, error) {
+	if value == "" || strings.HasPrefix(value, encryptedValuePrefix) {
+		return value, nil
+	}
+	encrypted, err := utils.EncryptWithKey([]byte(value), dataKey)
+	if err != nil {
+		return "", err
+	}
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// decryptValue decrypts a value encrypted by encryptValue, values that aren't encrypted are returned as they are
+func decryptValue(dataKey []byte, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedValuePrefix) {
+		return value, nil
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedValuePrefix))
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := utils.DecryptWithKey(encrypted, dataKey)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+// keyFileEncrypter wraps the data key with a base64 encoded key in a file on this host
+type keyFileEncrypter struct {
+	path string
+}
+
+func (encrypter *keyFileEncrypter) WrapKey(dataKey []byte) (string, error) {
+	key, err := encrypter.readKey()
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := utils.EncryptWithKey(dataKey, key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(wrapped), nil
+}
+
+func (encrypter *keyFileEncrypter) UnwrapKey(wrapped string) ([]byte, error) {
+	key, err := encrypter.readKey()
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return utils.DecryptWithKey(data, key)
+}
+
+func (encrypter *keyFileEncrypter) readKey() ([]byte, error) {
+	if encrypter.path == "" {
+		return nil, fmt.Errorf("no key file is set, set %s to its path", StateKeyFileEnv)
+	}
+	contents, err := os.ReadFile(encrypter.path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the key file %s, set %s to its path on this host: %w", encrypter.path, StateKeyFileEnv, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil || len(key) != utils.KeySize {
+		return nil, fmt.Errorf("the key file %s must hold a base64 encoded %d byte key", encrypter.path, utils.KeySize)
+	}
+	return key, nil
+}
+
+// vaultTransitEncrypter wraps the data key with a key in the Vault transit secrets engine, authenticating with the VAULT_TOKEN environment variable
+// The url is the one of the key (e.g. https://vault.example.com/v1/transit/keys/zarf), which the encrypt and decrypt endpoints are found from
+type vaultTransitEncrypter struct {
+	url string
+}
+
+func (encrypter *vaultTransitEncrypter) WrapKey(dataKey []byte) (string, error) {
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := encrypter.call("encrypt", request, &response); err != nil {
+		return "", err
+	}
+	return response.Data.Ciphertext, nil
+}
+
+func (encrypter *vaultTransitEncrypter) UnwrapKey(wrapped string) ([]byte, error) {
+	var response struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	request := map[string]string{"ciphertext": wrapped}
+	if err := encrypter.call("decrypt", request, &response); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(response.Data.Plaintext)
+}
+
+// call sends the request to the encrypt or decrypt endpoint of the key
+func (encrypter *vaultTransitEncrypter) call(operation string, request interface{}, response interface{}) error {
+	idx := strings.LastIndex(encrypter.url, "/keys/")
+	if idx < 0 {
+		return fmt.Errorf("the vault transit key %s is not in the format https://VAULT/v1/MOUNT/keys/NAME", encrypter.url)
+	}
+	url := fmt.Sprintf("%s/%s/%s", encrypter.url[:idx], operation, encrypter.url[idx+len("/keys/"):])
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	_, responseBody, err := vaultRequest(http.MethodPost, url, body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(responseBody, response); err != nil {
+		return fmt.Errorf("unable to read the vault transit response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package k8s
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// The secrets managers the credentials of the Zarf state can be kept in
+const (
+	StateStoreKubernetes     = "kubernetes"
+	StateStoreVault          = "vault"
+	StateStoreExternalSecret = "external-secret"
+)
+
+// StateStore keeps the credentials of the Zarf state outside of the zarf-state secret
+type StateStore interface {
+	// Save writes the credentials to the store
+	Save(credentials map[string]string) error
+	// Load reads the credentials from the store, credentials that were never saved are left out
+	Load() (map[string]string, error)
+}
+
+// IsValidStateStore returns true if the credentials of the Zarf state can be kept in the store type
+func IsValidStateStore(storeType string) bool {
+	return storeType == StateStoreKubernetes || storeType == StateStoreVault || storeType == StateStoreExternalSecret
+}
+
+// NewStateStore returns the store for the credentials of the Zarf state, or nil when they are kept in the zarf-state secret
+func NewStateStore(info types.StateStore) (StateStore, error) {
+	switch info.Type {
+	case "", StateStoreKubernetes:
+		return nil, nil
+
+	case StateStoreVault:
+		return &vaultStore{url: info.URL}, nil
+
+	case StateStoreExternalSecret:
+		namespace, name, found := strings.Cut(info.Secret, "/")
+		if !found {
+			return nil, fmt.Errorf("the external secret %s is not in the format NAMESPACE/NAME", info.Secret)
+		}
+		return &externalSecretStore{namespace: namespace, name: name}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported state store %s", info.Type)
+}
+
+// GetStateCredentialsLocation describes where the credentials of the state are kept without reading them
+func GetStateCredentialsLocation(info types.StateStore) string {
+	switch info.Type {
+	case StateStoreVault:
+		return fmt.Sprintf("vault %s", info.URL)
+	case StateStoreExternalSecret:
+		return fmt.Sprintf("secret %s", info.Secret)
+	}
+	return fmt.Sprintf("secret %s/%s", ZarfNamespace, ZarfStateSecretName)
+}
+
+// LoadStateCredentials replaces the credentials of the state with the ones saved in its store
+func LoadStateCredentials(state *types.ZarfState) error {
+	store, err := NewStateStore(state.StateStore)
+	if err != nil || store == nil {
+		return err
+	}
+
+	credentials, err := store.Load()
+	if err != nil {
+		return fmt.Errorf("unable to load the zarf state credentials from the %s store: %w", state.StateStore.Type, err)
+	}
+
+	for key, field := range getStateCredentialFields(state) {
+		if value, ok := credentials[key]; ok && value != "" {
+			*field = value
+		}
+	}
+
+	return nil
+}
+
+// agentStateCredentials are the credentials the Zarf agent reads from the zarf-state secret mounted into its pods
+// The agent has neither the key of the state nor access to its store, so they are left in the secret as they are
+// The agent never saves the state through SaveZarfState either, RotateAgentTLS only changes the agentTLS field of the secret
+var agentStateCredentials = map[string]bool{
+	"registry_pull_password": true,
+}
+
+// getStateCredentialFields returns the credentials of the state keyed by the name they are saved under in a store
+func getStateCredentialFields(state *types.ZarfState) map[string]*string {
+	return map[string]*string{
+		"git_push_password":       &state.GitServer.PushPassword,
+		"git_pull_password":       &state.GitServer.PullPassword,
+		"registry_push_password":  &state.RegistryInfo.PushPassword,
+		"registry_pull_password":  &state.RegistryInfo.PullPassword,
+		"registry_proxy_password": &state.RegistryInfo.ProxyPassword,
+		"registry_s3_secret_key":  &state.RegistryInfo.S3.SecretKey,
+		"registry_secret":         &state.RegistryInfo.Secret,
+		"logging_secret":          &state.LoggingSecret,
+	}
+}
+
+// vaultStore keeps the credentials in a Vault KV version 2 secret, authenticating with the VAULT_TOKEN environment variable
+type vaultStore struct {
+	url string
+}
+
+func (store *vaultStore) Save(credentials map[string]string) error {
+	body, err := json.Marshal(map[string]interface{}{"data": credentials})
+	if err != nil {
+		return err
+	}
+
+	_, _, err = vaultRequest(http.MethodPost, store.url, body)
+	return err
+}
+
+func (store *vaultStore) Load() (map[string]string, error) {
+	status, body, err := vaultRequest(http.MethodGet, store.url, nil)
+	if status == http.StatusNotFound {
+		// Nothing has been saved yet
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return nil, fmt.Errorf("unable to read the vault secret: %w", err)
+	}
+
+	return secret.Data.Data, nil
+}
+
+// vaultRequest calls the Vault API and returns the status code along with the body
+func vaultRequest(method, url string, body []byte) (int, []byte, error) {
+	message.Debugf("k8s.vaultRequest(%s, %s)", method, url)
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return 0, nil, fmt.Errorf("the VAULT_TOKEN environment variable must be set to use vault")
+	}
+
+	client := &http.Client{Timeout: 20 * time.Second}
+
+	// Vaults inside the airgap are often signed by a private CA
+	if caFile := os.Getenv("VAULT_CACERT"); caFile != "" {
+		caBundle, err := os.ReadFile(caFile)
+		if err != nil {
+			return 0, nil, fmt.Errorf("unable to read the VAULT_CACERT file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(caBundle)
+		// Clone the default transport so the proxy settings still apply with the custom CA
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		client.Transport = transport
+	}
+
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	request.Header.Set("X-Vault-Token", token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := io.ReadAll(response.Body)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, nil, fmt.Errorf("got status code of %d from vault with body of: %s", response.StatusCode, string(responseBody))
+	}
+
+	return response.StatusCode, responseBody, nil
+}
+
+// externalSecretStore reads the credentials from a secret an external secrets operator syncs from the secrets manager
+// Zarf can't write to the secrets manager, so the credentials have to be added there before they are saved
+type externalSecretStore struct {
+	namespace string
+	name      string
+}
+
+func (store *externalSecretStore) Save(credentials map[string]string) error {
+	synced, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for key, value := range credentials {
+		if value != "" && synced[key] != value {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the secret %s/%s is missing or has different values for %s, add them to the secrets manager it is synced from",
+			store.namespace, store.name, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func (store *externalSecretStore) Load() (map[string]string, error) {
+	secret, err := GetSecret(store.namespace, store.name)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := make(map[string]string)
+	for key, value := range secret.Data {
+		credentials[key] = string(value)
+	}
+
+	return credentials, nil
+}
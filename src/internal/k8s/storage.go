@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The annotation that marks the StorageClass used for claims that don't name one
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// GetStorageClasses returns all of the StorageClasses in the cluster
+func GetStorageClasses() (*storagev1.StorageClassList, error) {
+	message.Debug("k8s.GetStorageClasses()")
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+}
+
+// GetDefaultStorageClass returns the StorageClass marked as the cluster default, or nil if there isn't one
+func GetDefaultStorageClass() (*storagev1.StorageClass, error) {
+	message.Debug("k8s.GetDefaultStorageClass()")
+	storageClasses, err := GetStorageClasses()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations[defaultStorageClassAnnotation] == "true" {
+			return &storageClass, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetAvailablePersistentVolumes returns the PersistentVolumes of a StorageClass that are not bound to a claim yet
+func GetAvailablePersistentVolumes(storageClassName string) ([]corev1.PersistentVolume, error) {
+	message.Debugf("k8s.GetAvailablePersistentVolumes(%s)", storageClassName)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := clientset.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var available []corev1.PersistentVolume
+	for _, volume := range volumes.Items {
+		if volume.Spec.StorageClassName == storageClassName && volume.Status.Phase == corev1.VolumeAvailable {
+			available = append(available, volume)
+		}
+	}
+
+	return available, nil
+}
+
+// CreatePersistentVolumeClaim creates a claim for a small volume from a StorageClass
+func CreatePersistentVolumeClaim(namespace, name string, labels map[string]string, storageClassName string, accessMode corev1.PersistentVolumeAccessMode, size string) (*corev1.PersistentVolumeClaim, error) {
+	message.Debugf("k8s.CreatePersistentVolumeClaim(%s, %s, %s, %s, %s)", namespace, name, storageClassName, accessMode, size)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Track the creation of this claim by zarf without changing the labels of the caller
+	claimLabels := map[string]string{config.ZarfManagedByLabel: "zarf"}
+	for key, value := range labels {
+		claimLabels[key] = value
+	}
+
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    claimLabels,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{accessMode},
+			StorageClassName: &storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: quantity,
+				},
+			},
+		},
+	}
+
+	return clientset.CoreV1().PersistentVolumeClaims(namespace).Create(context.TODO(), claim, metav1.CreateOptions{})
+}
+
+// GetPersistentVolumeClaim returns a claim by name
+func GetPersistentVolumeClaim(namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	message.Debugf("k8s.GetPersistentVolumeClaim(%s, %s)", namespace, name)
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// DeletePersistentVolumeClaim deletes a claim by name
+func DeletePersistentVolumeClaim(namespace, name string) error {
+	message.Debugf("k8s.DeletePersistentVolumeClaim(%s, %s)", namespace, name)
+	clientset, err := getClientset()
+	if err != nil {
+		return err
+	}
+
+	return clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
@@ -32,6 +32,12 @@ import (
 // Global lock to synchronize port selections
 var globalMutex sync.Mutex
 
+// The tunnels that are still open, by the ID of the run that opened them
+var (
+	openTunnels     = make(map[*Tunnel]string)
+	openTunnelsLock sync.Mutex
+)
+
 const (
 	PodResource  = "pod"
 	SvcResource  = "svc"
@@ -62,17 +68,31 @@ type Tunnel struct {
 	urlSuffix    string
 	attempt      int
 	stopChan     chan struct{}
+	stopOnce     sync.Once
 	readyChan    chan struct{}
 	spinner      *message.Spinner
+	runID        string
 }
 
 // GenerateConnectionTable will print a table of all zarf connect matches found in the cluster
 func PrintConnectTable() error {
-	list, err := GetServicesByLabelExists(v1.NamespaceAll, config.ZarfConnectLabelName)
+	connections, err := GetConnectStrings()
 	if err != nil {
 		return err
 	}
 
+	message.PrintConnectStringTable(connections)
+
+	return nil
+}
+
+// GetConnectStrings finds all of the zarf connect matches in the cluster
+func GetConnectStrings() (types.ConnectStrings, error) {
+	list, err := GetServicesByLabelExists(v1.NamespaceAll, config.ZarfConnectLabelName)
+	if err != nil {
+		return nil, err
+	}
+
 	connections := make(types.ConnectStrings)
 
 	for _, svc := range list.Items {
@@ -85,9 +105,7 @@ func PrintConnectTable() error {
 		}
 	}
 
-	message.PrintConnectStringTable(connections)
-
-	return nil
+	return connections, nil
 }
 
 // NewTunnelFromServiceURL takes a serviceURL and parses it to create a tunnel to the cluster. The string is expected to follow the following format:
@@ -135,6 +153,7 @@ func NewTunnel(namespace, resourceType, resourceName string, local, remote int)
 		resourceName: resourceName,
 		stopChan:     make(chan struct{}, 1),
 		readyChan:    make(chan struct{}, 1),
+		runID:        config.GetRunID(),
 	}
 }
 
@@ -146,6 +165,11 @@ func (tunnel *Tunnel) EnableAutoOpen() {
 	tunnel.autoOpen = true
 }
 
+// SetURLSuffix sets the path opened on the tunnel when the matched service doesn't provide one
+func (tunnel *Tunnel) SetURLSuffix(suffix string) {
+	tunnel.urlSuffix = suffix
+}
+
 func (tunnel *Tunnel) AddSpinner(spinner *message.Spinner) {
 	tunnel.spinner = spinner
 }
@@ -159,6 +183,13 @@ func (tunnel *Tunnel) Connect(target string, blocking bool) {
 		tunnel.remotePort = 5000
 		tunnel.urlSuffix = `/v2/_catalog`
 
+		// Registries that were already running in the cluster keep their own service
+		if registryInfo := getRegistryInfo(); registryInfo.InClusterService != "" {
+			tunnel.namespace, tunnel.resourceName, _ = strings.Cut(registryInfo.InClusterService, "/")
+			tunnel.resourceType = SvcResource
+			tunnel.remotePort = registryInfo.InClusterServicePort
+		}
+
 	case ZarfLogging:
 		tunnel.resourceName = "zarf-loki-stack-grafana"
 		tunnel.remotePort = 3000
@@ -221,7 +252,7 @@ func (tunnel *Tunnel) Connect(target string, blocking bool) {
 		defer tunnel.Close()
 
 		// Keep this open until an interrupt signal is received
-		c := make(chan os.Signal)
+		c := make(chan os.Signal, 1)
 		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 		go func() {
 			<-c
@@ -249,7 +280,29 @@ func (tunnel *Tunnel) HttpEndpoint() string {
 // Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine.
 func (tunnel *Tunnel) Close() {
 	message.Debug("tunnel.Close()")
-	close(tunnel.stopChan)
+	openTunnelsLock.Lock()
+	delete(openTunnels, tunnel)
+	openTunnelsLock.Unlock()
+	tunnel.stopOnce.Do(func() { close(tunnel.stopChan) })
+}
+
+// CloseRunTunnels closes the tunnels the run with this ID left open and returns how many there were
+func CloseRunTunnels(runID string) int {
+	message.Debugf("k8s.CloseRunTunnels(%s)", runID)
+	openTunnelsLock.Lock()
+	var tunnels []*Tunnel
+	for tunnel, tunnelRunID := range openTunnels {
+		if tunnelRunID == runID {
+			tunnels = append(tunnels, tunnel)
+		}
+	}
+	openTunnelsLock.Unlock()
+
+	for _, tunnel := range tunnels {
+		message.Debugf("Closing the tunnel to %s/%s left open by run %s", tunnel.namespace, tunnel.resourceName, runID)
+		tunnel.Close()
+	}
+	return len(tunnels)
 }
 
 func (tunnel *Tunnel) checkForZarfConnectLabel(name string) error {
@@ -270,8 +323,10 @@ func (tunnel *Tunnel) checkForZarfConnectLabel(name string) error {
 		// Only support a service with a single port
 		tunnel.remotePort = svc.Spec.Ports[0].TargetPort.IntValue()
 
-		// Add the url suffix too
-		tunnel.urlSuffix = svc.Annotations[config.ZarfConnectAnnotationUrl]
+		// Add the url suffix too, keeping any suffix that was already set if the service doesn't have one
+		if suffix := svc.Annotations[config.ZarfConnectAnnotationUrl]; suffix != "" {
+			tunnel.urlSuffix = suffix
+		}
 
 		message.Debugf("tunnel connection match: %s/%s on port %d", svc.Namespace, svc.Name, tunnel.remotePort)
 	}
@@ -383,6 +438,9 @@ func (tunnel *Tunnel) establish() (string, error) {
 	case <-portforwarder.Ready:
 		// Store for endpoint output
 		tunnel.localPort = localPort
+		openTunnelsLock.Lock()
+		openTunnels[tunnel] = tunnel.runID
+		openTunnelsLock.Unlock()
 		url := fmt.Sprintf("http://%s:%d%s", config.IPV4Localhost, localPort, tunnel.urlSuffix)
 		msg := fmt.Sprintf("Creating port forwarding tunnel at %s", url)
 		if tunnel.spinner == nil {
@@ -448,3 +506,16 @@ func (tunnel *Tunnel) getAttachablePodForService() (string, error) {
 
 	return servicePods[0], nil
 }
+
+// getRegistryInfo returns the registry info from the loaded state, or from the cluster when no state has been loaded yet
+func getRegistryInfo() types.RegistryInfo {
+	if registryInfo := config.GetContainerRegistryInfo(); registryInfo.Address != "" {
+		return registryInfo
+	}
+
+	state, err := LoadZarfState()
+	if err != nil {
+		message.Debug(err)
+	}
+	return state.RegistryInfo
+}
@@ -0,0 +1,206 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AgentModeAll has the agent mutate resources in every namespace that didn't opt out
+	AgentModeAll = "all"
+	// AgentModeOptIn has the agent only mutate resources in the namespaces labeled zarf.dev/agent=enabled, which Zarf adds to the namespaces it manages
+	AgentModeOptIn = "opt-in"
+
+	agentEnabled = "enabled"
+)
+
+// GetMutatingWebhook returns the MutatingWebhookConfiguration with the given name
+func GetMutatingWebhook(name string) (*admissionv1.MutatingWebhookConfiguration, error) {
+	message.Debugf("k8s.GetMutatingWebhook(%s)", name)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// UpdateMutatingWebhook updates the given MutatingWebhookConfiguration in the cluster
+func UpdateMutatingWebhook(webhook *admissionv1.MutatingWebhookConfiguration) (*admissionv1.MutatingWebhookConfiguration, error) {
+	message.Debugf("k8s.UpdateMutatingWebhook(%s)", webhook.Name)
+
+	clientset, err := getClientset()
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{})
+}
+
+// UpdateMutatingWebhookCABundle replaces the caBundle of every webhook in the given MutatingWebhookConfiguration
+func UpdateMutatingWebhookCABundle(name string, caBundle []byte) error {
+	message.Debugf("k8s.UpdateMutatingWebhookCABundle(%s)", name)
+
+	webhook, err := GetMutatingWebhook(name)
+	if err != nil {
+		return err
+	}
+
+	for idx := range webhook.Webhooks {
+		webhook.Webhooks[idx].ClientConfig.CABundle = caBundle
+	}
+
+	_, err = UpdateMutatingWebhook(webhook)
+	return err
+}
+
+// ConfigureMutatingWebhook applies the failure policy, timeout, namespace selector, ignored namespaces and mode to every webhook in the given MutatingWebhookConfiguration
+func ConfigureMutatingWebhook(name string, settings types.AgentWebhook) error {
+	message.Debugf("k8s.ConfigureMutatingWebhook(%s, %#v)", name, settings)
+
+	webhook, err := GetMutatingWebhook(name)
+	if err != nil {
+		return err
+	}
+
+	var selector *metav1.LabelSelector
+	if settings.NamespaceSelector != "" {
+		if selector, err = metav1.ParseToLabelSelector(settings.NamespaceSelector); err != nil {
+			return err
+		}
+	}
+
+	for idx := range webhook.Webhooks {
+		hook := &webhook.Webhooks[idx]
+
+		if settings.FailurePolicy != "" {
+			policy := admissionv1.FailurePolicyType(settings.FailurePolicy)
+			hook.FailurePolicy = &policy
+		}
+
+		if settings.TimeoutSeconds > 0 {
+			timeout := int32(settings.TimeoutSeconds)
+			hook.TimeoutSeconds = &timeout
+		}
+
+		if selector != nil {
+			addNamespaceSelector(hook, selector)
+		}
+
+		if len(settings.IgnoredNamespaces) > 0 {
+			ignoreNamespaces(hook, settings.IgnoredNamespaces)
+		}
+
+		setAgentMode(hook, settings.Mode)
+	}
+
+	_, err = UpdateMutatingWebhook(webhook)
+	return err
+}
+
+// addNamespaceSelector adds to the selector from the manifest so the kube-system and opt-out rules are kept
+// Each deploy of the agent applies the selector again, so a requirement replaces the one with the same key and operator
+func addNamespaceSelector(hook *admissionv1.MutatingWebhook, selector *metav1.LabelSelector) {
+	if hook.NamespaceSelector == nil {
+		hook.NamespaceSelector = &metav1.LabelSelector{}
+	}
+	if hook.NamespaceSelector.MatchLabels == nil && len(selector.MatchLabels) > 0 {
+		hook.NamespaceSelector.MatchLabels = make(map[string]string)
+	}
+	for key, value := range selector.MatchLabels {
+		hook.NamespaceSelector.MatchLabels[key] = value
+	}
+
+	var expressions []metav1.LabelSelectorRequirement
+	for _, expression := range hook.NamespaceSelector.MatchExpressions {
+		replaced := false
+		for _, requirement := range selector.MatchExpressions {
+			if expression.Key == requirement.Key && expression.Operator == requirement.Operator {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			expressions = append(expressions, expression)
+		}
+	}
+	hook.NamespaceSelector.MatchExpressions = append(expressions, selector.MatchExpressions...)
+}
+
+// ignoreNamespaces adds the namespaces to the ones the webhook leaves out by name, next to kube-system
+func ignoreNamespaces(hook *admissionv1.MutatingWebhook, namespaces []string) {
+	if hook.NamespaceSelector == nil {
+		hook.NamespaceSelector = &metav1.LabelSelector{}
+	}
+
+	for idx := range hook.NamespaceSelector.MatchExpressions {
+		expression := &hook.NamespaceSelector.MatchExpressions[idx]
+		if expression.Key == corev1.LabelMetadataName && expression.Operator == metav1.LabelSelectorOpNotIn {
+			ignored := make(map[string]bool)
+			for _, namespace := range expression.Values {
+				ignored[namespace] = true
+			}
+			for _, namespace := range namespaces {
+				if !ignored[namespace] {
+					expression.Values = append(expression.Values, namespace)
+					ignored[namespace] = true
+				}
+			}
+			return
+		}
+	}
+
+	hook.NamespaceSelector.MatchExpressions = append(hook.NamespaceSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      corev1.LabelMetadataName,
+		Operator: metav1.LabelSelectorOpNotIn,
+		Values:   namespaces,
+	})
+}
+
+// SetMutatingWebhookMode switches every webhook in the given MutatingWebhookConfiguration between mutating all namespaces and only the opted in ones
+func SetMutatingWebhookMode(name string, mode string) error {
+	message.Debugf("k8s.SetMutatingWebhookMode(%s, %s)", name, mode)
+
+	webhook, err := GetMutatingWebhook(name)
+	if err != nil {
+		return err
+	}
+
+	for idx := range webhook.Webhooks {
+		setAgentMode(&webhook.Webhooks[idx], mode)
+	}
+
+	_, err = UpdateMutatingWebhook(webhook)
+	return err
+}
+
+// setAgentMode adds the requirement for the zarf.dev/agent=enabled namespace label in opt-in mode and removes it otherwise
+// The opt-out rule from the manifest uses the same label with NotIn, so only the In rule is touched
+func setAgentMode(hook *admissionv1.MutatingWebhook, mode string) {
+	if hook.NamespaceSelector == nil {
+		hook.NamespaceSelector = &metav1.LabelSelector{}
+	}
+
+	var expressions []metav1.LabelSelectorRequirement
+	for _, expression := range hook.NamespaceSelector.MatchExpressions {
+		if expression.Key != config.ZarfAgentLabel || expression.Operator != metav1.LabelSelectorOpIn {
+			expressions = append(expressions, expression)
+		}
+	}
+
+	if mode == AgentModeOptIn {
+		expressions = append(expressions, metav1.LabelSelectorRequirement{
+			Key:      config.ZarfAgentLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{agentEnabled},
+		})
+	}
+	hook.NamespaceSelector.MatchExpressions = expressions
+}
@@ -0,0 +1,39 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAddNamespaceSelector(t *testing.T) {
+	manifestRules := []metav1.LabelSelectorRequirement{
+		{Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"kube-system"}},
+		{Key: "zarf.dev/agent", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"skip", "ignore"}},
+	}
+	hook := admissionv1.MutatingWebhook{
+		NamespaceSelector: &metav1.LabelSelector{MatchExpressions: append([]metav1.LabelSelectorRequirement{}, manifestRules...)},
+	}
+
+	selector, err := metav1.ParseToLabelSelector("team in (a, b), env=prod")
+	require.NoError(t, err)
+
+	// Every deploy of the agent applies the selector again
+	addNamespaceSelector(&hook, selector)
+	addNamespaceSelector(&hook, selector)
+
+	team := metav1.LabelSelectorRequirement{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}}
+	assert.Equal(t, map[string]string{"env": "prod"}, hook.NamespaceSelector.MatchLabels)
+	assert.Equal(t, append(append([]metav1.LabelSelectorRequirement{}, manifestRules...), team), hook.NamespaceSelector.MatchExpressions)
+
+	// A changed selector replaces the requirement on the same key
+	selector, err = metav1.ParseToLabelSelector("team in (c)")
+	require.NoError(t, err)
+	addNamespaceSelector(&hook, selector)
+
+	team.Values = []string{"c"}
+	assert.Equal(t, append(append([]metav1.LabelSelectorRequirement{}, manifestRules...), team), hook.NamespaceSelector.MatchExpressions)
+}
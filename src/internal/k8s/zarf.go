@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/message"
@@ -39,6 +40,22 @@ func GetDeployedZarfPackages() ([]types.DeployedPackage, error) {
 	return deployedPackages, nil
 }
 
+// GetDeployedPackage gets the metadata information about a single package that has been deployed to the cluster, with its sensitive exports decrypted.
+func GetDeployedPackage(packageName string) (types.DeployedPackage, error) {
+	var deployedPackage types.DeployedPackage
+
+	secret, err := GetSecret(ZarfNamespace, fmt.Sprintf("zarf-package-%s", packageName))
+	if err != nil {
+		return deployedPackage, err
+	}
+
+	if err := json.Unmarshal(secret.Data["data"], &deployedPackage); err != nil {
+		return deployedPackage, err
+	}
+	err = DecryptDeployedPackage(&deployedPackage)
+	return deployedPackage, err
+}
+
 // StripZarfLabelsAndSecretsFromNamespaces removes metadata and secrets from existing namespaces no longer manged by Zarf.
 func StripZarfLabelsAndSecretsFromNamespaces() {
 	spinner := message.NewProgressSpinner("Removing zarf metadata & secrets from existing namespaces not managed by Zarf")
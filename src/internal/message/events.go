@@ -0,0 +1,126 @@
+package message
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType is what an event reports, either a message or a change to a step like a spinner or progress bar
+type EventType string
+
+const (
+	EventDebug    EventType = "debug"
+	EventInfo     EventType = "info"
+	EventSuccess  EventType = "success"
+	EventWarning  EventType = "warning"
+	EventFatal    EventType = "fatal"
+	EventNote     EventType = "note"
+	EventQuestion EventType = "question"
+	EventHeader   EventType = "header"
+
+	EventStepStart   EventType = "step.start"
+	EventStepUpdate  EventType = "step.update"
+	EventStepSuccess EventType = "step.success"
+	EventStepWarning EventType = "step.warning"
+	EventStepFailure EventType = "step.failure"
+	EventStepStop    EventType = "step.stop"
+)
+
+// StepKind is how a step shows its progress
+type StepKind string
+
+const (
+	StepSpinner  StepKind = "spinner"
+	StepProgress StepKind = "progress"
+)
+
+// Event is one thing Zarf reports while it runs, with the step it belongs to for the events of spinners and progress bars
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+	// Step identifies the spinner or progress bar of a step event
+	Step int      `json:"step,omitempty"`
+	Kind StepKind `json:"kind,omitempty"`
+	Text string   `json:"text,omitempty"`
+	// Error is the error behind a fatal event or a failed step
+	Error string `json:"error,omitempty"`
+	// Caller is the file and line that sent a debug or fatal event
+	Caller string `json:"caller,omitempty"`
+	// Current and Total count the progress of a progress bar, usually in bytes
+	Current int64 `json:"current,omitempty"`
+	Total   int64 `json:"total,omitempty"`
+}
+
+// Renderer shows events to a user or another program
+// Events are rendered one at a time in the order they were sent
+type Renderer interface {
+	Render(event Event)
+}
+
+var renderers = []Renderer{&consoleRenderer{}}
+var renderersLock sync.Mutex
+
+var lastStepID int
+
+// SetRenderer replaces every renderer with the given one, such as to switch from the console to a JSON stream
+func SetRenderer(renderer Renderer) {
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+	renderers = []Renderer{renderer}
+}
+
+// AddRenderer sends every later event to the renderer as well, until the returned function removes it
+func AddRenderer(renderer Renderer) func() {
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+	renderers = append(renderers, renderer)
+
+	return func() {
+		renderersLock.Lock()
+		defer renderersLock.Unlock()
+		for idx, existing := range renderers {
+			if existing == renderer {
+				renderers = append(renderers[:idx:idx], renderers[idx+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// publish sends the event to every renderer, with sensitive values masked so renderers added by other packages never see them
+func publish(event Event) {
+	event.Time = time.Now()
+	event.Text = Mask(event.Text)
+	event.Error = Mask(event.Error)
+
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+	for _, renderer := range renderers {
+		renderer.Render(event)
+	}
+}
+
+// newStepID returns the ID of a new spinner or progress bar
+func newStepID() int {
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+	lastStepID++
+	return lastStepID
+}
+
+// getCaller returns the file and line of the function the given number of frames above the caller of getCaller
+func getCaller(offset int) string {
+	_, file, line, ok := runtime.Caller(offset + 1)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// sprint joins the operands of a message with spaces the way Println does
+func sprint(a ...any) string {
+	return strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+}
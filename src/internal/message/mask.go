@@ -0,0 +1,121 @@
+package message
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// sensitiveMask replaces sensitive values in output
+const sensitiveMask = "********"
+
+var sensitiveValues []string
+var sensitiveLock sync.RWMutex
+
+// MaskingWriter hides sensitive values before passing output along
+// Output that could be the start of a sensitive value is held back until the next write shows whether it is one,
+// so a value split across writes is still masked
+type MaskingWriter struct {
+	writer  io.Writer
+	pending string
+	lock    sync.Mutex
+}
+
+// AddSensitiveValue masks every later occurrence of value in Zarf's output and log file
+func AddSensitiveValue(value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	sensitiveLock.Lock()
+	defer sensitiveLock.Unlock()
+	sensitiveValues = append(sensitiveValues, value)
+}
+
+// Mask replaces any sensitive values in text
+func Mask(text string) string {
+	sensitiveLock.RLock()
+	defer sensitiveLock.RUnlock()
+
+	for _, value := range sensitiveValues {
+		text = strings.ReplaceAll(text, value, sensitiveMask)
+	}
+	return text
+}
+
+// NewMaskingWriter wraps a writer so sensitive values never reach it
+func NewMaskingWriter(writer io.Writer) *MaskingWriter {
+	return &MaskingWriter{writer: writer}
+}
+
+func (m *MaskingWriter) Write(p []byte) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	text := m.pending + string(p)
+	split := maskSplit(text)
+	m.pending = text[split:]
+
+	if split > 0 {
+		if _, err := m.writer.Write([]byte(Mask(text[:split]))); err != nil {
+			return 0, err
+		}
+	}
+	// Report the original length so callers don't treat masking as a short write
+	return len(p), nil
+}
+
+// Flush writes out any output held back because it could have been the start of a sensitive value
+func (m *MaskingWriter) Flush() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.pending == "" {
+		return nil
+	}
+	_, err := m.writer.Write([]byte(Mask(m.pending)))
+	m.pending = ""
+	return err
+}
+
+// maskSplit returns how much of text can be masked and written, holding back the end that could be the start of a sensitive value
+func maskSplit(text string) int {
+	sensitiveLock.RLock()
+	defer sensitiveLock.RUnlock()
+
+	split := len(text)
+	for _, value := range sensitiveValues {
+		longest := len(value) - 1
+		if longest > len(text) {
+			longest = len(text)
+		}
+		for n := longest; n > 0; n-- {
+			if strings.HasPrefix(value, text[len(text)-n:]) {
+				if len(text)-n < split {
+					split = len(text) - n
+				}
+				break
+			}
+		}
+	}
+
+	// Don't cut through a value that is complete, it would be written out unmasked
+	for moved := true; moved; {
+		moved = false
+		for _, value := range sensitiveValues {
+			for start := 0; start < split; {
+				idx := strings.Index(text[start:], value)
+				if idx < 0 {
+					break
+				}
+				idx += start
+				if idx < split && idx+len(value) > split {
+					split = idx
+					moved = true
+				}
+				start = idx + 1
+			}
+		}
+	}
+	return split
+}
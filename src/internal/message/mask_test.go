@@ -0,0 +1,77 @@
+package message
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskingWriter(t *testing.T) {
+	sensitiveLock.Lock()
+	original := sensitiveValues
+	sensitiveValues = []string{"hunter2", "s3cr3t"}
+	sensitiveLock.Unlock()
+	defer func() {
+		sensitiveLock.Lock()
+		sensitiveValues = original
+		sensitiveLock.Unlock()
+	}()
+
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{
+			name:   "no sensitive values",
+			writes: []string{"hello ", "world\n"},
+			want:   "hello world\n",
+		},
+		{
+			name:   "value in one write",
+			writes: []string{"password: hunter2\n"},
+			want:   "password: ********\n",
+		},
+		{
+			name:   "value split across writes",
+			writes: []string{"password: hun", "ter2\n"},
+			want:   "password: ********\n",
+		},
+		{
+			name:   "value split into single bytes",
+			writes: []string{"s", "3", "c", "r", "3", "t"},
+			want:   "********",
+		},
+		{
+			name:   "held back prefix that isn't a value",
+			writes: []string{"hunt", "ing\n"},
+			want:   "hunting\n",
+		},
+		{
+			name:   "prefix at the end is flushed",
+			writes: []string{"done hunt"},
+			want:   "done hunt",
+		},
+		{
+			name:   "value next to the start of another",
+			writes: []string{"hunter2s3c", "r3t"},
+			want:   "****************",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writer := NewMaskingWriter(&out)
+			for _, write := range tt.writes {
+				n, err := writer.Write([]byte(write))
+				require.NoError(t, err)
+				assert.Equal(t, len(write), n)
+			}
+			require.NoError(t, writer.Flush())
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
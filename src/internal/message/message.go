@@ -6,7 +6,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/pterm/pterm"
@@ -34,8 +33,14 @@ var logLevel = InfoLevel
 // Write logs to stderr and a buffer for logfile generation
 var logFile *os.File
 
+// logStream is where the log renderers write, which is stderr and the log file when there is one
+var logStream io.Writer = os.Stderr
+
 var useLogFile bool
 
+// The functions to run with the error message before a fatal error exits
+var fatalHooks []func(message string)
+
 func init() {
 	pterm.ThemeDefault.SuccessMessageStyle = *pterm.NewStyle(pterm.FgLightGreen)
 	// Customize default error.
@@ -52,7 +57,7 @@ func init() {
 	}
 
 	pterm.DefaultProgressbar.MaxWidth = 85
-	pterm.SetDefaultOutput(os.Stderr)
+	pterm.SetDefaultOutput(NewMaskingWriter(os.Stderr))
 }
 
 // UseLogfile writes output to stderr and a logfile
@@ -65,8 +70,8 @@ func UseLogFile() {
 		Error(err, "Error saving a log file")
 	} else {
 		useLogFile = true
-		logStream := io.MultiWriter(os.Stderr, logFile)
-		pterm.SetDefaultOutput(logStream)
+		logStream = io.MultiWriter(os.Stderr, logFile)
+		pterm.SetDefaultOutput(NewMaskingWriter(logStream))
 		message := fmt.Sprintf("Saving log file to %s", logFile.Name())
 		Note(message)
 	}
@@ -89,7 +94,7 @@ func Debug(payload ...any) {
 
 func Debugf(format string, a ...any) {
 	message := fmt.Sprintf(format, a...)
-	debugPrinter(3, message)
+	debugPrinter(2, message)
 }
 
 func Error(err any, message string) {
@@ -114,43 +119,58 @@ func Warn(message string) {
 }
 
 func Warnf(format string, a ...any) {
-	message := paragraph(format, a...)
-	pterm.Warning.Println(message)
+	publish(Event{Type: EventWarning, Text: fmt.Sprintf(format, a...)})
 }
 
 func Fatal(err any, message string) {
-	debugPrinter(2, err)
-	errorPrinter(2).Println(message)
-	os.Exit(1)
+	fatal(2, err, message)
 }
 
 func Fatalf(err any, format string, a ...any) {
-	debugPrinter(2, err)
-	message := paragraph(format, a...)
-	errorPrinter(2).Println(message)
+	fatal(2, err, fmt.Sprintf(format, a...))
+}
+
+// fatal reports the error and the message and exits, with the caller the given number of frames up
+func fatal(offset int, err any, message string) {
+	debugPrinter(offset+1, err)
+
+	event := Event{Type: EventFatal, Text: message, Caller: getCaller(offset)}
+	if err != nil {
+		event.Error = sprint(err)
+	}
+	publish(event)
+
+	runFatalHooks(message)
 	os.Exit(1)
 }
 
+// OnFatal runs the hook with the error message when a fatal error is about to exit, such as to record what failed
+func OnFatal(hook func(message string)) {
+	fatalHooks = append(fatalHooks, hook)
+}
+
+func runFatalHooks(message string) {
+	for _, hook := range fatalHooks {
+		hook(message)
+	}
+}
+
 func Info(message string) {
 	Infof(message)
 }
 
 func Infof(format string, a ...any) {
 	if logLevel > 0 {
-		message := paragraph(format, a...)
-		pterm.Info.Println(message)
+		publish(Event{Type: EventInfo, Text: fmt.Sprintf(format, a...)})
 	}
 }
 
 func SuccessF(format string, a ...any) {
-	message := paragraph(format, a...)
-	pterm.Success.Println(message)
+	publish(Event{Type: EventSuccess, Text: fmt.Sprintf(format, a...)})
 }
 
 func Question(text string) {
-	pterm.Println()
-	message := paragraph(text)
-	pterm.FgMagenta.Println(message)
+	publish(Event{Type: EventQuestion, Text: text})
 }
 
 func Notef(format string, a ...any) {
@@ -159,21 +179,11 @@ func Notef(format string, a ...any) {
 }
 
 func Note(text string) {
-	pterm.Println()
-	message := paragraph(text)
-	pterm.FgYellow.Println(message)
+	publish(Event{Type: EventNote, Text: text})
 }
 
 func HeaderInfof(format string, a ...any) {
-	message := fmt.Sprintf(format, a...)
-	// Ensure the text is consistent for the header width
-	padding := 85 - len(message)
-	pterm.Println()
-	pterm.DefaultHeader.
-		WithBackgroundStyle(pterm.NewStyle(pterm.BgDarkGray)).
-		WithTextStyle(pterm.NewStyle(pterm.FgLightWhite)).
-		WithMargin(2).
-		Printfln(message + strings.Repeat(" ", padding))
+	publish(Event{Type: EventHeader, Text: fmt.Sprintf(format, a...)})
 }
 
 func JsonValue(value any) string {
@@ -184,29 +194,11 @@ func JsonValue(value any) string {
 	return string(bytes)
 }
 
-func paragraph(format string, a ...any) string {
-	return pterm.DefaultParagraph.WithMaxWidth(100).Sprintf(format, a...)
+func paragraph(text string) string {
+	return pterm.DefaultParagraph.WithMaxWidth(100).Sprint(text)
 }
 
+// debugPrinter sends the payload as a debug event, with the caller the given number of frames up
 func debugPrinter(offset int, a ...any) {
-	printer := pterm.Debug.WithShowLineNumber(logLevel > 2).WithLineNumberOffset(offset)
-	now := time.Now().Format(time.RFC3339)
-	// prepend to a
-	a = append([]any{now, " - "}, a...)
-
-	printer.Println(a...)
-
-	// Always write to the log file
-	if useLogFile && !pterm.PrintDebugMessages {
-		pterm.Debug.
-			WithShowLineNumber(true).
-			WithLineNumberOffset(offset).
-			WithDebugger(false).
-			WithWriter(logFile).
-			Println(a...)
-	}
-}
-
-func errorPrinter(offset int) *pterm.PrefixPrinter {
-	return pterm.Error.WithShowLineNumber(logLevel > 2).WithLineNumberOffset(offset)
+	publish(Event{Type: EventDebug, Text: sprint(a...), Caller: getCaller(offset)})
 }
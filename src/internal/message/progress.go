@@ -2,64 +2,67 @@ package message
 
 import (
 	"fmt"
-
-	"github.com/pterm/pterm"
+	"sync/atomic"
 )
 
 type ProgressBar struct {
-	progress  *pterm.ProgressbarPrinter
+	step      int
+	total     int64
+	current   int64
 	startText string
 }
 
 func NewProgressBar(total int64, format string, a ...any) *ProgressBar {
-	var progress *pterm.ProgressbarPrinter
 	text := fmt.Sprintf("     "+format, a...)
-	if NoProgress {
-		Info(text)
-	} else {
-		progress, _ = pterm.DefaultProgressbar.
-			WithTotal(int(total)).
-			WithShowCount(false).
-			WithTitle(text).
-			WithRemoveWhenDone(true).
-			Start()
-	}
-
-	return &ProgressBar{
-		progress:  progress,
+	progress := &ProgressBar{
+		step:      newStepID(),
+		total:     total,
 		startText: text,
 	}
+	progress.publish(EventStepStart, text)
+
+	return progress
+}
+
+// publish sends an event of the step this progress bar shows, with how far it got
+func (p *ProgressBar) publish(eventType EventType, text string) {
+	publish(Event{
+		Type:    eventType,
+		Step:    p.step,
+		Kind:    StepProgress,
+		Text:    text,
+		Current: atomic.LoadInt64(&p.current),
+		Total:   p.total,
+	})
 }
 
 func (p *ProgressBar) Update(complete int64, text string) {
-	if NoProgress {
-		return
-	}
-	p.progress.UpdateTitle("     " + text)
-	chunk := int(complete) - p.progress.Current
-	p.progress.Add(chunk)
+	atomic.StoreInt64(&p.current, complete)
+	p.publish(EventStepUpdate, "     "+text)
 }
 
 func (p *ProgressBar) Write(data []byte) (int, error) {
 	n := len(data)
-	if p.progress != nil {
-		p.progress.Add(n)
-	}
+	atomic.AddInt64(&p.current, int64(n))
+	p.publish(EventStepUpdate, "")
 	return n, nil
 }
 
 func (p *ProgressBar) Success(text string, a ...any) {
-	p.Stop()
-	pterm.Success.Printfln(text, a...)
+	p.publish(EventStepSuccess, fmt.Sprintf(text, a...))
 }
 
 func (p *ProgressBar) Stop() {
-	if p.progress != nil {
-		_, _ = p.progress.Stop()
-	}
+	p.publish(EventStepStop, "")
 }
 
 func (p *ProgressBar) Fatalf(err error, format string, a ...any) {
-	p.Stop()
-	Fatalf(err, format, a...)
+	text := fmt.Sprintf(format, a...)
+	event := Event{Type: EventStepFailure, Step: p.step, Kind: StepProgress, Text: text, Current: atomic.LoadInt64(&p.current), Total: p.total}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	publish(event)
+
+	fatal(2, err, text)
 }
@@ -0,0 +1,268 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// progressEventInterval is the least time between the progress events of one progress bar in a JSON stream
+const progressEventInterval = time.Second
+
+// consoleRenderer shows events in a terminal with pterm, drawing spinners and progress bars unless NoProgress is set
+type consoleRenderer struct {
+	spinners map[int]*pterm.SpinnerPrinter
+	bars     map[int]*pterm.ProgressbarPrinter
+}
+
+func (r *consoleRenderer) Render(event Event) {
+	if r.spinners == nil {
+		r.spinners = make(map[int]*pterm.SpinnerPrinter)
+		r.bars = make(map[int]*pterm.ProgressbarPrinter)
+	}
+
+	switch event.Type {
+	case EventDebug:
+		r.debug(event)
+	case EventInfo:
+		r.info(event.Text)
+	case EventSuccess:
+		pterm.Success.Println(paragraph(event.Text))
+	case EventWarning:
+		pterm.Warning.Println(paragraph(event.Text))
+	case EventFatal:
+		pterm.Error.Println(paragraph(event.Text) + r.caller(event))
+	case EventNote:
+		pterm.Println()
+		pterm.FgYellow.Println(paragraph(event.Text))
+	case EventQuestion:
+		pterm.Println()
+		pterm.FgMagenta.Println(paragraph(event.Text))
+	case EventHeader:
+		// Ensure the text is consistent for the header width
+		padding := 85 - len(event.Text)
+		if padding < 0 {
+			padding = 0
+		}
+		pterm.Println()
+		pterm.DefaultHeader.
+			WithBackgroundStyle(pterm.NewStyle(pterm.BgDarkGray)).
+			WithTextStyle(pterm.NewStyle(pterm.FgLightWhite)).
+			WithMargin(2).
+			Println(event.Text + strings.Repeat(" ", padding))
+	case EventStepStart, EventStepUpdate, EventStepSuccess, EventStepWarning, EventStepFailure, EventStepStop:
+		if event.Kind == StepProgress {
+			r.renderProgressBar(event)
+		} else {
+			r.renderSpinner(event)
+		}
+	}
+}
+
+func (r *consoleRenderer) renderSpinner(event Event) {
+	spinner := r.spinners[event.Step]
+
+	switch event.Type {
+	case EventStepStart:
+		if NoProgress {
+			r.info(event.Text)
+			return
+		}
+		spinner, _ = pterm.DefaultSpinner.
+			WithRemoveWhenDone(false).
+			// Src: https://github.com/gernest/wow/blob/master/spin/spinners.go#L335
+			WithSequence(`  ⠋ `, `  ⠙ `, `  ⠹ `, `  ⠸ `, `  ⠼ `, `  ⠴ `, `  ⠦ `, `  ⠧ `, `  ⠇ `, `  ⠏ `).
+			Start(event.Text)
+		r.spinners[event.Step] = spinner
+	case EventStepUpdate:
+		if spinner != nil {
+			spinner.UpdateText(event.Text)
+		}
+	case EventStepSuccess:
+		if spinner != nil {
+			spinner.Success(event.Text)
+			delete(r.spinners, event.Step)
+		} else {
+			r.info(event.Text)
+		}
+	case EventStepWarning:
+		if spinner != nil {
+			spinner.Warning(event.Text)
+		} else {
+			pterm.Warning.Println(paragraph(event.Text))
+		}
+	case EventStepFailure:
+		if spinner != nil {
+			spinner.RemoveWhenDone = true
+			_ = spinner.Stop()
+			delete(r.spinners, event.Step)
+		}
+	case EventStepStop:
+		if spinner != nil && spinner.IsActive {
+			_ = spinner.Stop()
+		}
+		delete(r.spinners, event.Step)
+	}
+}
+
+func (r *consoleRenderer) renderProgressBar(event Event) {
+	bar := r.bars[event.Step]
+
+	switch event.Type {
+	case EventStepStart:
+		if NoProgress {
+			r.info(event.Text)
+			return
+		}
+		bar, _ = pterm.DefaultProgressbar.
+			WithTotal(int(event.Total)).
+			WithShowCount(false).
+			WithTitle(event.Text).
+			WithRemoveWhenDone(true).
+			Start()
+		r.bars[event.Step] = bar
+	case EventStepUpdate:
+		if bar == nil {
+			return
+		}
+		if event.Text != "" {
+			bar.UpdateTitle(event.Text)
+		}
+		bar.Add(int(event.Current) - bar.Current)
+	case EventStepSuccess:
+		r.stopProgressBar(event.Step)
+		pterm.Success.Println(event.Text)
+	case EventStepWarning:
+		pterm.Warning.Println(paragraph(event.Text))
+	case EventStepFailure, EventStepStop:
+		r.stopProgressBar(event.Step)
+	}
+}
+
+func (r *consoleRenderer) stopProgressBar(step int) {
+	if bar := r.bars[step]; bar != nil {
+		_, _ = bar.Stop()
+	}
+	delete(r.bars, step)
+}
+
+func (r *consoleRenderer) info(text string) {
+	if logLevel > 0 {
+		pterm.Info.Println(paragraph(text))
+	}
+}
+
+func (r *consoleRenderer) debug(event Event) {
+	text := fmt.Sprintf("%s - %s", event.Time.Format(time.RFC3339), event.Text)
+	pterm.Debug.Println(text + r.caller(event))
+
+	// Always write to the log file
+	if useLogFile && !pterm.PrintDebugMessages {
+		writer := NewMaskingWriter(logFile)
+		pterm.Debug.
+			WithDebugger(false).
+			WithWriter(writer).
+			Println(text + pterm.FgGray.Sprintf("\n└ (%s)", event.Caller))
+		_ = writer.Flush()
+	}
+}
+
+// caller returns the line the event was sent from when tracing
+func (r *consoleRenderer) caller(event Event) string {
+	if logLevel > DebugLevel && event.Caller != "" {
+		return pterm.FgGray.Sprintf("\n└ (%s)", event.Caller)
+	}
+	return ""
+}
+
+// plainRenderer writes each event as a line of text with its time and level, for logs that are read later rather than watched
+type plainRenderer struct{}
+
+// NewPlainRenderer returns a renderer that writes events as plain log lines to stderr and the log file
+func NewPlainRenderer() Renderer {
+	return &plainRenderer{}
+}
+
+func (r *plainRenderer) Render(event Event) {
+	// Spinners only report what they are doing at debug level, and progress bars update too often to log
+	if !shouldStream(event) || event.Type == EventStepStop || (event.Type == EventStepUpdate && event.Kind == StepProgress) {
+		return
+	}
+
+	level := map[EventType]string{
+		EventDebug:       "DEBUG",
+		EventSuccess:     "SUCCESS",
+		EventWarning:     "WARN",
+		EventFatal:       "ERROR",
+		EventNote:        "NOTE",
+		EventQuestion:    "QUESTION",
+		EventStepSuccess: "SUCCESS",
+		EventStepWarning: "WARN",
+		EventStepFailure: "ERROR",
+	}[event.Type]
+	if event.Type == EventStepUpdate {
+		level = "DEBUG"
+	} else if level == "" {
+		level = "INFO"
+	}
+
+	text := strings.TrimSpace(event.Text)
+	if event.Error != "" {
+		text = fmt.Sprintf("%s: %s", text, event.Error)
+	}
+
+	line := fmt.Sprintf("%s %-8s %s\n", event.Time.Format(time.RFC3339), level, text)
+	_, _ = fmt.Fprint(logStream, Mask(line))
+}
+
+// jsonRenderer writes each event as a line of JSON so another program can follow along
+type jsonRenderer struct {
+	lastProgress map[int]time.Time
+}
+
+// NewJSONRenderer returns a renderer that writes events as a stream of JSON objects to stderr and the log file
+func NewJSONRenderer() Renderer {
+	return &jsonRenderer{lastProgress: make(map[int]time.Time)}
+}
+
+func (r *jsonRenderer) Render(event Event) {
+	if !shouldStream(event) {
+		return
+	}
+
+	// Progress bars update on every write, so their updates are thinned out
+	if event.Type == EventStepUpdate && event.Kind == StepProgress {
+		if event.Time.Sub(r.lastProgress[event.Step]) < progressEventInterval {
+			return
+		}
+		r.lastProgress[event.Step] = event.Time
+	}
+	if event.Type == EventStepSuccess || event.Type == EventStepFailure || event.Type == EventStepStop {
+		delete(r.lastProgress, event.Step)
+	}
+
+	// Values are masked before they are escaped into JSON, where a value with quotes or brackets would no longer match
+	event.Text = Mask(strings.TrimSpace(event.Text))
+	event.Error = Mask(event.Error)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(logStream, string(data))
+}
+
+// shouldStream returns whether a log stream includes the event at the current log level
+func shouldStream(event Event) bool {
+	switch event.Type {
+	case EventDebug:
+		return logLevel >= DebugLevel
+	case EventInfo, EventStepStart, EventStepSuccess:
+		return logLevel > WarnLevel
+	case EventStepUpdate:
+		return logLevel >= DebugLevel || event.Kind == StepProgress
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useTestLogStream sends the log renderers to a buffer at the given level and masks hunter2 and p"a<ss until the returned func is called
+func useTestLogStream(level LogLevel) (*bytes.Buffer, func()) {
+	var out bytes.Buffer
+	originalStream, originalLevel := logStream, logLevel
+	logStream, logLevel = &out, level
+
+	sensitiveLock.Lock()
+	originalValues := sensitiveValues
+	sensitiveValues = []string{"hunter2", `p"a<ss`}
+	sensitiveLock.Unlock()
+
+	return &out, func() {
+		logStream, logLevel = originalStream, originalLevel
+		sensitiveLock.Lock()
+		sensitiveValues = originalValues
+		sensitiveLock.Unlock()
+	}
+}
+
+func TestPlainRenderer(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		level LogLevel
+		event Event
+		want  string
+	}{
+		{
+			name:  "info is trimmed",
+			level: InfoLevel,
+			event: Event{Time: now, Type: EventInfo, Text: "Deploying the package\n"},
+			want:  "2023-01-02T03:04:05Z INFO     Deploying the package\n",
+		},
+		{
+			name:  "info is hidden at warn level",
+			level: WarnLevel,
+			event: Event{Time: now, Type: EventInfo, Text: "Deploying the package"},
+		},
+		{
+			name:  "debug is hidden at info level",
+			level: InfoLevel,
+			event: Event{Time: now, Type: EventDebug, Text: "packager.Deploy()"},
+		},
+		{
+			name:  "debug is shown at debug level",
+			level: DebugLevel,
+			event: Event{Time: now, Type: EventDebug, Text: "packager.Deploy()"},
+			want:  "2023-01-02T03:04:05Z DEBUG    packager.Deploy()\n",
+		},
+		{
+			name:  "spinner updates are debug lines",
+			level: DebugLevel,
+			event: Event{Time: now, Type: EventStepUpdate, Kind: StepSpinner, Text: "Waiting for the registry"},
+			want:  "2023-01-02T03:04:05Z DEBUG    Waiting for the registry\n",
+		},
+		{
+			name:  "progress updates are skipped",
+			level: DebugLevel,
+			event: Event{Time: now, Type: EventStepUpdate, Kind: StepProgress, Text: "Pushing images", Current: 1, Total: 2},
+		},
+		{
+			name:  "step stops are skipped",
+			level: InfoLevel,
+			event: Event{Time: now, Type: EventStepStop, Text: "Pushing images"},
+		},
+		{
+			name:  "failures include the error",
+			level: WarnLevel,
+			event: Event{Time: now, Type: EventStepFailure, Text: "Pushing images", Error: "timed out"},
+			want:  "2023-01-02T03:04:05Z ERROR    Pushing images: timed out\n",
+		},
+		{
+			name:  "sensitive values are masked",
+			level: InfoLevel,
+			event: Event{Time: now, Type: EventWarning, Text: "Using the password hunter2"},
+			want:  "2023-01-02T03:04:05Z WARN     Using the password ********\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, restore := useTestLogStream(tt.level)
+			defer restore()
+
+			NewPlainRenderer().Render(tt.event)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestJSONRenderer(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("events are masked json lines", func(t *testing.T) {
+		out, restore := useTestLogStream(InfoLevel)
+		defer restore()
+
+		renderer := NewJSONRenderer()
+		renderer.Render(Event{Time: now, Type: EventDebug, Text: "hidden"})
+		renderer.Render(Event{Time: now, Type: EventStepFailure, Step: 3, Kind: StepSpinner, Text: "Logging in with hunter2\n", Error: "denied"})
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		require.Len(t, lines, 1)
+
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+		assert.Equal(t, Event{Time: now, Type: EventStepFailure, Step: 3, Kind: StepSpinner, Text: "Logging in with ********", Error: "denied"}, event)
+	})
+
+	t.Run("values that json escapes are masked", func(t *testing.T) {
+		out, restore := useTestLogStream(InfoLevel)
+		defer restore()
+
+		NewJSONRenderer().Render(Event{Time: now, Type: EventFatal, Text: `Logging in with p"a<ss`, Error: `bad password p"a<ss`})
+		assert.NotContains(t, out.String(), `p\"a`)
+		assert.NotContains(t, out.String(), `\u003css`)
+
+		var event Event
+		require.NoError(t, json.Unmarshal(out.Bytes(), &event))
+		assert.Equal(t, "Logging in with ********", event.Text)
+		assert.Equal(t, "bad password ********", event.Error)
+	})
+
+	t.Run("progress updates are thinned out", func(t *testing.T) {
+		out, restore := useTestLogStream(InfoLevel)
+		defer restore()
+
+		renderer := NewJSONRenderer()
+		for _, offset := range []time.Duration{0, 300 * time.Millisecond, 900 * time.Millisecond, progressEventInterval, progressEventInterval + 500*time.Millisecond} {
+			renderer.Render(Event{Time: now.Add(offset), Type: EventStepUpdate, Step: 1, Kind: StepProgress, Current: int64(offset), Total: 10})
+		}
+		renderer.Render(Event{Time: now.Add(progressEventInterval + 600*time.Millisecond), Type: EventStepSuccess, Step: 1, Kind: StepProgress})
+
+		var types []EventType
+		for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+			var event Event
+			require.NoError(t, json.Unmarshal([]byte(line), &event))
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []EventType{EventStepUpdate, EventStepUpdate, EventStepSuccess}, types)
+	})
+}
+
+// recordingRenderer keeps the events it is given, like a renderer added by another package
+type recordingRenderer struct {
+	events []Event
+}
+
+func (r *recordingRenderer) Render(event Event) {
+	r.events = append(r.events, event)
+}
+
+func TestAddRendererMasksEvents(t *testing.T) {
+	_, restore := useTestLogStream(InfoLevel)
+	defer restore()
+
+	renderersLock.Lock()
+	original := renderers
+	renderers = nil
+	renderersLock.Unlock()
+	defer func() {
+		renderersLock.Lock()
+		renderers = original
+		renderersLock.Unlock()
+	}()
+
+	recorder := &recordingRenderer{}
+	remove := AddRenderer(recorder)
+	publish(Event{Type: EventFatal, Text: "Logging in with hunter2", Error: `bad password p"a<ss`})
+	remove()
+	publish(Event{Type: EventInfo, Text: "removed"})
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, "Logging in with ********", recorder.events[0].Text)
+	assert.Equal(t, "bad password ********", recorder.events[0].Error)
+}
@@ -2,14 +2,12 @@ package message
 
 import (
 	"fmt"
-
-	"github.com/pterm/pterm"
 )
 
 var activeSpinner *Spinner
 
 type Spinner struct {
-	spinner   *pterm.SpinnerPrinter
+	step      int
 	startText string
 }
 
@@ -19,26 +17,21 @@ func NewProgressSpinner(format string, a ...any) *Spinner {
 		return activeSpinner
 	}
 
-	var spinner *pterm.SpinnerPrinter
 	text := fmt.Sprintf(format, a...)
-	if NoProgress {
-		Info(text)
-	} else {
-		spinner, _ = pterm.DefaultSpinner.
-			WithRemoveWhenDone(false).
-			// Src: https://github.com/gernest/wow/blob/master/spin/spinners.go#L335
-			WithSequence(`  ⠋ `, `  ⠙ `, `  ⠹ `, `  ⠸ `, `  ⠼ `, `  ⠴ `, `  ⠦ `, `  ⠧ `, `  ⠇ `, `  ⠏ `).
-			Start(text)
-	}
-
 	activeSpinner = &Spinner{
-		spinner:   spinner,
+		step:      newStepID(),
 		startText: text,
 	}
+	activeSpinner.publish(EventStepStart, text)
 
 	return activeSpinner
 }
 
+// publish sends an event of the step this spinner shows
+func (p *Spinner) publish(eventType EventType, text string) {
+	publish(Event{Type: eventType, Step: p.step, Kind: StepSpinner, Text: text})
+}
+
 func (p *Spinner) Write(text []byte) (int, error) {
 	size := len(text)
 	if NoProgress {
@@ -49,29 +42,22 @@ func (p *Spinner) Write(text []byte) (int, error) {
 }
 
 func (p *Spinner) Updatef(format string, a ...any) {
-	if NoProgress {
-		return
-	}
-
-	text := fmt.Sprintf(format, a...)
-	p.spinner.UpdateText(text)
+	p.publish(EventStepUpdate, fmt.Sprintf(format, a...))
 }
 
 func (p *Spinner) Debugf(format string, a ...any) {
 	if logLevel >= DebugLevel {
-		text := fmt.Sprintf("Debug: "+format, a)
+		text := fmt.Sprintf("Debug: "+format, a...)
 		if NoProgress {
 			Debug(text)
 		} else {
-			p.spinner.UpdateText(text)
+			p.publish(EventStepUpdate, text)
 		}
 	}
 }
 
 func (p *Spinner) Stop() {
-	if p.spinner != nil && p.spinner.IsActive {
-		_ = p.spinner.Stop()
-	}
+	p.publish(EventStepStop, "")
 	activeSpinner = nil
 }
 
@@ -80,22 +66,12 @@ func (p *Spinner) Success() {
 }
 
 func (p *Spinner) Successf(format string, a ...any) {
-	text := fmt.Sprintf(format, a...)
-	if p.spinner != nil {
-		p.spinner.Success(text)
-		activeSpinner = nil
-	} else {
-		Info(text)
-	}
+	p.publish(EventStepSuccess, fmt.Sprintf(format, a...))
+	activeSpinner = nil
 }
 
 func (p *Spinner) Warnf(format string, a ...any) {
-	text := fmt.Sprintf(format, a...)
-	if p.spinner != nil {
-		p.spinner.Warning(text)
-	} else {
-		Warn(text)
-	}
+	p.publish(EventStepWarning, fmt.Sprintf(format, a...))
 }
 
 func (p *Spinner) Errorf(err error, format string, a ...any) {
@@ -108,10 +84,13 @@ func (p *Spinner) Fatal(err error) {
 }
 
 func (p *Spinner) Fatalf(err error, format string, a ...any) {
-	if p.spinner != nil {
-		p.spinner.RemoveWhenDone = true
-		_ = p.spinner.Stop()
-		activeSpinner = nil
+	text := fmt.Sprintf(format, a...)
+	event := Event{Type: EventStepFailure, Step: p.step, Kind: StepSpinner, Text: text}
+	if err != nil {
+		event.Error = err.Error()
 	}
-	Fatalf(err, format, a...)
+	publish(event)
+	activeSpinner = nil
+
+	fatal(2, err, text)
 }
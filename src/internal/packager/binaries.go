@@ -0,0 +1,221 @@
+package packager
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/pterm/pterm"
+)
+
+// binaryCatalogName is the file in the bin directory that records what Zarf installed there
+const binaryCatalogName = ".zarf-catalog.json"
+
+// addComponentBinaries copies each binary into the package and returns the binaries with their checksums recorded
+func addComponentBinaries(component types.ZarfComponent, binariesPath string) []types.ZarfBinary {
+	spinner := message.NewProgressSpinner("Loading %d binaries", len(component.Binaries))
+	defer spinner.Stop()
+
+	_ = utils.CreateDirectory(binariesPath, 0700)
+
+	binaries := make([]types.ZarfBinary, len(component.Binaries))
+	for idx, binary := range component.Binaries {
+		spinner.Updatef("Loading %s", binary.Name)
+		destination := filepath.Join(binariesPath, binary.Name)
+
+		if utils.IsUrl(binary.Source) {
+			utils.DownloadToFile(binary.Source, destination, component.CosignKeyPath)
+		} else if err := utils.CreatePathAndCopy(binary.Source, destination); err != nil {
+			spinner.Fatalf(err, "Unable to copy %s", binary.Source)
+		}
+
+		// Abort packaging on an invalid shasum, otherwise record the one we computed
+		if binary.Shasum != "" {
+			utils.ValidateSha256Sum(binary.Shasum, destination)
+		} else {
+			shasum, err := utils.GetSha256Sum(destination)
+			if err != nil {
+				spinner.Fatalf(err, "Unable to compute the checksum of %s", binary.Name)
+			}
+			binary.Shasum = shasum
+		}
+
+		_ = os.Chmod(destination, 0700)
+		binaries[idx] = binary
+	}
+
+	spinner.Success()
+	return binaries
+}
+
+// installComponentBinaries installs a component's binaries into the bin directory and records them in the host catalog
+func installComponentBinaries(component types.ZarfComponent, binariesPath string) {
+	if len(component.Binaries) == 0 {
+		return
+	}
+
+	binDir := config.DeployOptions.BinDirectory
+	if binDir == "" {
+		binDir = config.ZarfDefaultBinDir
+	}
+
+	spinner := message.NewProgressSpinner("Installing %d binaries to %s", len(component.Binaries), binDir)
+	defer spinner.Stop()
+
+	if err := utils.CreateDirectory(binDir, 0755); err != nil {
+		spinner.Fatalf(err, "Unable to create the bin directory %s", binDir)
+	}
+
+	var installed []types.ZarfInstalledBinary
+	for _, binary := range component.Binaries {
+		source := filepath.Join(binariesPath, binary.Name)
+		target := filepath.Join(binDir, binary.Name)
+
+		spinner.Updatef("Validating SHASUM for %s", binary.Name)
+		utils.ValidateSha256Sum(binary.Shasum, source)
+
+		spinner.Updatef("Installing %s", target)
+		if err := installBinary(source, target); err != nil {
+			spinner.Fatalf(err, "Unable to install %s", target)
+		}
+
+		installed = append(installed, types.ZarfInstalledBinary{
+			Name:        binary.Name,
+			Version:     binary.Version,
+			Path:        target,
+			Shasum:      binary.Shasum,
+			Package:     config.GetMetaData().Name,
+			Component:   component.Name,
+			InstalledAt: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	if err := addToBinaryCatalog(binDir, installed); err != nil {
+		spinner.Fatalf(err, "Unable to update the binary catalog in %s", binDir)
+	}
+
+	spinner.Successf("Installed %d binaries to %s", len(installed), binDir)
+
+	ensureBinDirectoryOnPath(binDir)
+}
+
+// installBinary copies a binary next to its target and renames it into place so running copies are not disturbed
+func installBinary(source string, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	staging := target + ".zarf-tmp"
+	out, err := os.OpenFile(staging, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(staging)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		_ = os.Remove(staging)
+		return err
+	}
+
+	return os.Rename(staging, target)
+}
+
+// ensureBinDirectoryOnPath tells the user how to add the bin directory to their PATH when it is not already there
+// Zarf leaves shell profiles alone, since changing them would affect every user of the host
+func ensureBinDirectoryOnPath(binDir string) {
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(entry) == filepath.Clean(binDir) {
+			return
+		}
+	}
+
+	message.Warnf("%s is not on your PATH, add it with: export PATH=\"$PATH:%s\"", binDir, binDir)
+}
+
+// LoadBinaryCatalog reads the catalog of binaries Zarf has installed into a bin directory
+func LoadBinaryCatalog(binDir string) (types.ZarfBinaryCatalog, error) {
+	var catalog types.ZarfBinaryCatalog
+
+	data, err := os.ReadFile(filepath.Join(binDir, binaryCatalogName))
+	if os.IsNotExist(err) {
+		return catalog, nil
+	} else if err != nil {
+		return catalog, err
+	}
+
+	err = json.Unmarshal(data, &catalog)
+	return catalog, err
+}
+
+// addToBinaryCatalog records installed binaries, replacing any earlier entries for the same paths
+func addToBinaryCatalog(binDir string, installed []types.ZarfInstalledBinary) error {
+	catalog, err := LoadBinaryCatalog(binDir)
+	if err != nil {
+		return err
+	}
+
+	replaced := make(map[string]bool)
+	for _, binary := range installed {
+		replaced[binary.Path] = true
+	}
+
+	var binaries []types.ZarfInstalledBinary
+	for _, binary := range catalog.Binaries {
+		if !replaced[binary.Path] {
+			binaries = append(binaries, binary)
+		}
+	}
+	catalog.Binaries = append(binaries, installed...)
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(binDir, binaryCatalogName), data, 0644)
+}
+
+// ListBinaryCatalog prints the binaries Zarf has installed into a bin directory and whether they are still intact
+func ListBinaryCatalog(binDir string, packageName string) {
+	catalog, err := LoadBinaryCatalog(binDir)
+	if err != nil {
+		message.Fatalf(err, "Unable to read the binary catalog in %s", binDir)
+	}
+
+	table := pterm.TableData{{"Binary", "Version", "Package", "Component", "Path", "Installed", "Status"}}
+	for _, binary := range catalog.Binaries {
+		if packageName != "" && binary.Package != packageName {
+			continue
+		}
+
+		status := "ok"
+		if shasum, err := utils.GetSha256Sum(binary.Path); os.IsNotExist(err) {
+			status = "missing"
+		} else if err != nil || !strings.EqualFold(shasum, binary.Shasum) {
+			status = "modified"
+		}
+
+		table = append(table, []string{binary.Name, binary.Version, binary.Package, binary.Component, binary.Path, binary.InstalledAt, status})
+	}
+
+	if len(table) == 1 {
+		message.Notef("No binaries have been installed into %s by Zarf", binDir)
+		return
+	}
+
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
@@ -0,0 +1,106 @@
+package packager
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// maxClockSkew is how far the clock of this host can be from the cluster API server before Zarf warns about it
+// The certificates Zarf generates are valid from the time on this host, so a cluster that is behind sees them as not yet valid
+const maxClockSkew = time.Minute
+
+// A clock the certificates are checked against
+type preflightClock struct {
+	name string
+	now  time.Time
+}
+
+// checkClockAndCertificates warns when the clock of this host is off from the cluster or a certificate Zarf relies on is outside of its validity window
+// Disconnected hosts often have wrong clocks, which otherwise show up as TLS and webhook errors part way through a deploy
+func checkClockAndCertificates(state types.ZarfState) {
+	message.Debug("packager.checkClockAndCertificates()")
+
+	clocks := []preflightClock{{name: "this host", now: time.Now()}}
+	if skew, err := k8s.GetClockSkew(); err != nil {
+		message.Debugf("Unable to compare the clock of this host with the cluster: %s", err.Error())
+	} else {
+		clocks = append(clocks, preflightClock{name: "the cluster API server", now: time.Now().Add(-skew)})
+
+		if skew > maxClockSkew || skew < -maxClockSkew {
+			direction := "ahead of"
+			if skew < 0 {
+				direction, skew = "behind", -skew
+			}
+			message.Warnf("The clock of this host is %s %s the cluster API server. Certificates Zarf generates and the webhooks and TLS connections that use them "+
+				"can fail as not yet valid or expired until the time on this host or the cluster nodes is fixed", skew.Round(time.Second), direction)
+		}
+	}
+
+	// The certificates of the state are PEM encoded, and the ones presented by external servers are fetched from them
+	var problems []string
+	checkPEM := func(source string, data []byte) {
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				message.Debugf("Unable to parse a certificate in %s: %s", source, err.Error())
+				continue
+			}
+			problems = append(problems, checkCertificateWindow(source, cert, clocks)...)
+		}
+	}
+	checkPEM("the registry CA bundle", state.RegistryInfo.CABundle)
+	checkPEM("the registry object store CA bundle", state.RegistryInfo.S3.CABundle)
+	checkPEM("the git server CA bundle", state.GitServer.CABundle)
+	checkPEM("the Zarf agent TLS certificate", state.AgentTLS.Cert)
+
+	if !state.RegistryInfo.InternalRegistry && !state.RegistryInfo.InClusterExternal {
+		problems = append(problems, checkServerCertificates("the registry", state.RegistryInfo.Address, clocks)...)
+	}
+	if !state.GitServer.InternalServer {
+		problems = append(problems, checkServerCertificates("the git server", state.GitServer.Address, clocks)...)
+	}
+
+	for _, problem := range problems {
+		message.Warnf("%s", problem)
+	}
+}
+
+// checkServerCertificates checks the certificates an external server presents, which is skipped when it can't be reached from this host
+func checkServerCertificates(source, address string, clocks []preflightClock) []string {
+	if address == "" {
+		return nil
+	}
+	certs, err := utils.GetServerCertificates(address)
+	if err != nil {
+		message.Debugf("Unable to get the certificates of %s at %s: %s", source, address, err.Error())
+		return nil
+	}
+
+	var problems []string
+	for _, cert := range certs {
+		problems = append(problems, checkCertificateWindow(source, cert, clocks)...)
+	}
+	return problems
+}
+
+// checkCertificateWindow describes each clock the certificate isn't valid at
+func checkCertificateWindow(source string, cert *x509.Certificate, clocks []preflightClock) []string {
+	var problems []string
+	for _, clock := range clocks {
+		if clock.now.Before(cert.NotBefore) {
+			problems = append(problems, fmt.Sprintf("The certificate %s in %s isn't valid until %s, but the time on %s is %s",
+				cert.Subject.CommonName, source, cert.NotBefore.UTC().Format(time.RFC3339), clock.name, clock.now.UTC().Format(time.RFC3339)))
+		} else if clock.now.After(cert.NotAfter) {
+			problems = append(problems, fmt.Sprintf("The certificate %s in %s expired at %s, and the time on %s is %s",
+				cert.Subject.CommonName, source, cert.NotAfter.UTC().Format(time.RFC3339), clock.name, clock.now.UTC().Format(time.RFC3339)))
+		}
+	}
+	return problems
+}
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/defenseunicorns/zarf/src/types"
@@ -25,9 +26,11 @@ import (
 type componentPaths struct {
 	base           string
 	files          string
+	binaries       string
 	charts         string
 	values         string
 	repos          string
+	artifacts      string
 	manifests      string
 	dataInjections string
 }
@@ -35,9 +38,12 @@ type tempPaths struct {
 	base         string
 	injectBinary string
 	seedImage    string
+	seedImageDir string
 	images       string
+	signatures   string
 	components   string
 	sboms        string
+	intoto       string
 	zarfYaml     string
 }
 
@@ -51,9 +57,12 @@ func createPaths() tempPaths {
 
 		injectBinary: filepath.Join(basePath, "zarf-injector"),
 		seedImage:    filepath.Join(basePath, "seed-image.tar"),
+		seedImageDir: filepath.Join(basePath, "seed-image"),
 		images:       filepath.Join(basePath, "images.tar"),
+		signatures:   filepath.Join(basePath, "signatures.tar"),
 		components:   filepath.Join(basePath, "components"),
 		sboms:        filepath.Join(basePath, "sboms"),
+		intoto:       filepath.Join(basePath, "intoto"),
 		zarfYaml:     filepath.Join(basePath, "zarf.yaml"),
 	}
 }
@@ -64,14 +73,29 @@ func (t tempPaths) clean() {
 	_ = os.RemoveAll("zarf-sbom")
 }
 
+// forArch returns the paths of the images of one architecture of a multi-arch package, which each have their own tarball
+func (t tempPaths) forArch(arch string) tempPaths {
+	t.seedImage = filepath.Join(t.base, fmt.Sprintf("seed-image-%s.tar", arch))
+	t.seedImageDir = filepath.Join(t.base, fmt.Sprintf("seed-image-%s", arch))
+	t.images = filepath.Join(t.base, fmt.Sprintf("images-%s.tar", arch))
+	return t
+}
+
 func createComponentPaths(basePath string, component types.ZarfComponent) componentPaths {
-	basePath = filepath.Join(basePath, component.Name)
+	// The copies of a component for each architecture of a multi-arch package keep their assets apart
+	if config.IsMultiArch() && component.Only.Cluster.Architecture != "" {
+		basePath = filepath.Join(basePath, fmt.Sprintf("%s-%s", component.Name, component.Only.Cluster.Architecture))
+	} else {
+		basePath = filepath.Join(basePath, component.Name)
+	}
 	_ = utils.CreateDirectory(basePath, 0700)
 	return componentPaths{
 		base:           basePath,
 		files:          filepath.Join(basePath, "files"),
+		binaries:       filepath.Join(basePath, "binaries"),
 		charts:         filepath.Join(basePath, "charts"),
 		repos:          filepath.Join(basePath, "repos"),
+		artifacts:      filepath.Join(basePath, "artifacts"),
 		manifests:      filepath.Join(basePath, "manifests"),
 		dataInjections: filepath.Join(basePath, "data"),
 		values:         filepath.Join(basePath, "values"),
@@ -99,6 +123,14 @@ func confirmAction(userMessage string, sbomViewFiles []string) bool {
 		message.Note(msg)
 	}
 
+	// Variables are only resolved for deployments so operators can review them before anything changes
+	if len(config.SetVariableMap) > 0 {
+		printVariableTable()
+	}
+
+	// Show the estimated cluster capacity the package needs so operators can check it before committing
+	printResourceEstimateTable()
+
 	pterm.Println()
 
 	// Display prompt if not auto-confirmed
@@ -119,6 +151,40 @@ func confirmAction(userMessage string, sbomViewFiles []string) bool {
 	return confirmFlag
 }
 
+// printVariableTable shows the variable values that will be applied and where they came from, masking sensitive ones
+func printVariableTable() {
+	table := pterm.TableData{{"Variable", "Value", "Source"}}
+
+	shown := make(map[string]bool)
+	for _, variable := range config.GetActiveConfig().Variables {
+		value, ok := config.SetVariableMap[variable.Name]
+		if !ok {
+			continue
+		}
+		if variable.Sensitive && value != "" {
+			value = "********"
+		}
+		table = append(table, []string{variable.Name, value, config.SetVariableSources[variable.Name]})
+		shown[variable.Name] = true
+	}
+
+	// Values set for variables the package does not declare are still templated, so show them too
+	var extra []string
+	for name := range config.SetVariableMap {
+		if !shown[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		table = append(table, []string{name, config.SetVariableMap[name], config.SetVariableSources[name]})
+	}
+
+	pterm.Println()
+	message.Note("The following variable values will be applied to this deployment")
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
+
 // HandleIfURL If provided package is a URL download it to a temp directory
 func HandleIfURL(packagePath string, shasum string, insecureDeploy bool) (string, func()) {
 	// Check if the user gave us a remote package
@@ -127,6 +193,11 @@ func HandleIfURL(packagePath string, shasum string, insecureDeploy bool) (string
 		return packagePath, func() {}
 	}
 
+	// OCI packages are pulled directly by the packager and verified by their layer digests
+	if isOCIReference(packagePath) {
+		return packagePath, func() {}
+	}
+
 	// Handle case where deploying remote package validated via sget
 	if strings.HasPrefix(packagePath, "sget://") {
 		return handleSgetPackage(packagePath)
@@ -148,28 +219,37 @@ func HandleIfURL(packagePath string, shasum string, insecureDeploy bool) (string
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		message.Fatalf(nil, "Unable to download the package: bad HTTP status %s", resp.Status)
+	}
+
 	// Write the package to a local file
 	tempPath := createPaths()
 
-	localPackagePath := tempPath.base + providedURL.Path
+	localPackagePath := filepath.Join(tempPath.base, filepath.Base(providedURL.Path))
 	message.Debugf("Creating local package with the path: %s", localPackagePath)
-	packageFile, _ := os.Create(localPackagePath)
-	_, err = io.Copy(packageFile, resp.Body)
+	packageFile, err := os.Create(localPackagePath)
 	if err != nil {
+		tempPath.clean()
+		message.Fatal(err, "Unable to create the local package file")
+	}
+	defer packageFile.Close()
+
+	// Hash the package as it streams to disk so it only has to be read once
+	hasher := sha256.New()
+	progressBar := message.NewProgressBar(resp.ContentLength, "Downloading %s", filepath.Base(providedURL.Path))
+	_, err = io.Copy(io.MultiWriter(packageFile, hasher, progressBar), resp.Body)
+	if err != nil {
+		tempPath.clean()
 		message.Fatal(err, "Unable to copy the contents of the provided URL into a local file.")
 	}
+	progressBar.Success("Downloaded %s", packagePath)
 
 	// Check the shasum if necessary
 	if !insecureDeploy {
-		hasher := sha256.New()
-		_, err = io.Copy(hasher, packageFile)
-		if err != nil {
-			message.Fatal(err, "Unable to calculate the sha256 of the provided remote package.")
-		}
-
 		value := hex.EncodeToString(hasher.Sum(nil))
-		if value != shasum {
-			_ = os.Remove(localPackagePath)
+		if !strings.EqualFold(value, shasum) {
+			tempPath.clean()
 			message.Fatalf(nil, "Provided shasum (%s) of the package did not match what was downloaded (%s)\n", shasum, value)
 		}
 	}
@@ -8,14 +8,15 @@ import (
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
-	"github.com/defenseunicorns/zarf/src/internal/utils"
 	"github.com/defenseunicorns/zarf/src/types"
 	"github.com/pterm/pterm"
-	"gopkg.in/yaml.v2"
 )
 
 const horizontalRule = "───────────────────────────────────────────────────────────────────────────────────────"
 
+// requestAllComponents is the --components value that selects every optional component
+const requestAllComponents = "*"
+
 func getValidComponents(allComponents []types.ZarfComponent, requestedComponentNames []string) []types.ZarfComponent {
 	message.Debugf("packager.getValidComponents(%#v, %#v)", allComponents, requestedComponentNames)
 
@@ -43,6 +44,16 @@ func getValidComponents(allComponents []types.ZarfComponent, requestedComponentN
 		componentGroups[key] = append(componentGroups[key], component)
 	}
 
+	// Ask about every optional component at once instead of one prompt at a time
+	var optionalComponents []types.ZarfComponent
+	for _, key := range orderedKeys {
+		componentGroup := componentGroups[key]
+		if len(componentGroup) == 1 && !isRequiredOrRequested(componentGroup[0], requestedComponentNames) {
+			optionalComponents = append(optionalComponents, componentGroup[0])
+		}
+	}
+	selectedComponents := selectOptionalComponents(optionalComponents)
+
 	// Loop through each component group in original order and handle required, requested or user confirmation
 	for _, key := range orderedKeys {
 
@@ -56,9 +67,9 @@ func getValidComponents(allComponents []types.ZarfComponent, requestedComponentN
 			// First check if the component is required or requested via CLI flag
 			requested := isRequiredOrRequested(component, requestedComponentNames)
 
-			// If the user has not requested this component via CLI flag, then prompt them if not a choice group
+			// If the user has not requested this component via CLI flag, then use their selection if not a choice group
 			if !requested && !userChoicePrompt {
-				requested = confirmOptionalComponent(component)
+				requested = selectedComponents[component.Name]
 			}
 
 			if requested {
@@ -75,6 +86,14 @@ func getValidComponents(allComponents []types.ZarfComponent, requestedComponentN
 			}
 		}
 
+		// Requesting all components picks the default of each choice group without prompting
+		if userChoicePrompt && isAllRequested(requestedComponentNames) {
+			if component, ok := getDefaultComponent(componentGroup); ok {
+				validComponentsList = append(validComponentsList, component)
+				userChoicePrompt = false
+			}
+		}
+
 		// If the user has requested a choice group, then prompt them
 		if userChoicePrompt {
 			selectedComponent := confirmChoiceGroup(componentGroup)
@@ -96,6 +115,11 @@ func validateRequests(validComponentsList []types.ZarfComponent, requestedCompon
 
 	// Loop through each requested component names
 	for _, componentName := range requestedComponentNames {
+		// The wildcard is not a component name
+		if componentName == requestAllComponents {
+			continue
+		}
+
 		found := false
 		// Match on the first requested component that is a valid component
 		for _, component := range validComponentsList {
@@ -130,6 +154,11 @@ func isRequiredOrRequested(component types.ZarfComponent, requestedComponentName
 	} else {
 		// Otherwise,check if this is one of the components that has been requested
 		if len(requestedComponentNames) > 0 || config.CommonOptions.Confirm {
+			// Only one component of a choice group can be requested, so the wildcard leaves those to the group's default
+			if component.Group == "" && isAllRequested(requestedComponentNames) {
+				return true
+			}
+
 			for _, requestedComponent := range requestedComponentNames {
 				// If the component name matches one of the requested components, then return true
 				if strings.ToLower(requestedComponent) == component.Name {
@@ -143,34 +172,76 @@ func isRequiredOrRequested(component types.ZarfComponent, requestedComponentName
 	return false
 }
 
-// Confirm optional component
-func confirmOptionalComponent(component types.ZarfComponent) (confirmComponent bool) {
-	message.Debugf("packager.confirmOptionalComponent(%#v)", component)
+// isAllRequested returns true when the wildcard was given as one of the requested components
+func isAllRequested(requestedComponentNames []string) bool {
+	for _, requestedComponent := range requestedComponentNames {
+		if strings.TrimSpace(requestedComponent) == requestAllComponents {
+			return true
+		}
+	}
+	return false
+}
+
+// getDefaultComponent returns the component marked as default in a choice group
+func getDefaultComponent(componentGroup []types.ZarfComponent) (types.ZarfComponent, bool) {
+	for _, component := range componentGroup {
+		if component.Default {
+			return component, true
+		}
+	}
+	return types.ZarfComponent{}, false
+}
+
+// selectOptionalComponents lets the user toggle all of the optional components in one list and returns the chosen names
+func selectOptionalComponents(components []types.ZarfComponent) map[string]bool {
+	message.Debugf("packager.selectOptionalComponents(%#v)", components)
+
+	selected := make(map[string]bool)
+	// A profile already picked its components, so the ones it left out are skipped
+	if len(components) == 0 || config.DeployOptions.Profile != "" {
+		return selected
+	}
 
 	// Confirm flag passed, just use defaults
 	if config.CommonOptions.Confirm {
-		return component.Default
+		for _, component := range components {
+			selected[component.Name] = component.Default
+		}
+		return selected
 	}
 
 	pterm.Println(horizontalRule)
 
-	displayComponent := component
-	displayComponent.Description = ""
-	content, _ := yaml.Marshal(displayComponent)
-	utils.ColorPrintYAML(string(content))
-	if component.Description != "" {
-		message.Question(component.Description)
+	var options, defaultOptions []string
+	optionNames := make(map[string]string)
+	for _, component := range components {
+		option := component.Name
+		if component.Description != "" {
+			option = fmt.Sprintf("%s - %s", component.Name, component.Description)
+		}
+
+		options = append(options, option)
+		optionNames[option] = component.Name
+		if component.Default {
+			defaultOptions = append(defaultOptions, option)
+		}
 	}
 
-	// Since no requested components were provided, prompt the user
-	prompt := &survey.Confirm{
-		Message: fmt.Sprintf("Deploy the %s component?", component.Name),
-		Default: component.Default,
+	chosen, err := pterm.DefaultInteractiveMultiselect.
+		WithOptions(options).
+		WithDefaultOptions(defaultOptions).
+		WithMaxHeight(len(options)).
+		WithFilter(false).
+		Show("Select the optional components to deploy (enter to toggle, tab to confirm)")
+	if err != nil {
+		message.Fatalf(nil, "Component selection canceled: %s", err.Error())
 	}
-	if err := survey.AskOne(prompt, &confirmComponent); err != nil {
-		message.Fatalf(nil, "Confirm selection canceled: %s", err.Error())
+
+	for _, option := range chosen {
+		selected[optionNames[option]] = true
 	}
-	return confirmComponent
+
+	return selected
 }
 
 func confirmChoiceGroup(componentGroup []types.ZarfComponent) types.ZarfComponent {
@@ -213,6 +284,128 @@ func confirmChoiceGroup(componentGroup []types.ZarfComponent) types.ZarfComponen
 	return componentGroup[chosen]
 }
 
+// checkChoiceGroupConflicts stops a deployment that would leave two components of the same choice group in the cluster
+func checkChoiceGroupConflicts(componentsToDeploy []types.ZarfComponent, deployedPackage types.DeployedPackage) {
+	message.Debugf("packager.checkChoiceGroupConflicts(%#v, %#v)", componentsToDeploy, deployedPackage)
+
+	groups := make(map[string]string)
+	for _, component := range config.GetComponents() {
+		if component.Group != "" {
+			groups[component.Name] = component.Group
+		}
+	}
+	if len(groups) == 0 {
+		return
+	}
+
+	packageName := config.GetMetaData().Name
+
+	deploying := make(map[string]string)
+	for _, component := range componentsToDeploy {
+		if component.Group != "" {
+			deploying[component.Group] = component.Name
+		}
+	}
+
+	for _, deployed := range deployedPackage.DeployedComponents {
+		group := groups[deployed.Name]
+		if choice, ok := deploying[group]; ok && group != "" && choice != deployed.Name {
+			message.Fatalf(nil, "The %s component from the %s group is already deployed, remove it with "+
+				"\"zarf package remove %s --components %s\" before deploying %s",
+				deployed.Name, group, packageName, deployed.Name, choice)
+		}
+	}
+}
+
+// getComponentDependencies returns the components each component has to wait for
+// A component that doesn't set dependsOn waits for the component listed before it, unless that one depends on it
+// This keeps the zarf.yaml order for packages that only use dependsOn for some components
+func getComponentDependencies(components []types.ZarfComponent) map[string][]string {
+	dependencies := make(map[string][]string)
+	for _, component := range components {
+		if len(component.DependsOn) > 0 {
+			dependencies[component.Name] = component.DependsOn
+		}
+	}
+
+	for idx, component := range components {
+		if idx == 0 || len(component.DependsOn) > 0 {
+			continue
+		}
+		previous := components[idx-1].Name
+		if !dependsOn(dependencies, previous, component.Name) {
+			dependencies[component.Name] = []string{previous}
+		}
+	}
+	return dependencies
+}
+
+// getDeploymentGroups orders components into groups that each only depend on the components of earlier groups
+// Packages that do not use dependsOn keep deploying strictly in yaml order, one component per group
+func getDeploymentGroups(components []types.ZarfComponent, previouslyDeployed []types.DeployedComponent) ([][]types.ZarfComponent, error) {
+	message.Debugf("packager.getDeploymentGroups(%#v, %#v)", components, previouslyDeployed)
+
+	var groups [][]types.ZarfComponent
+
+	// Components finished by an earlier deployment attempt already satisfy their dependents
+	deployed := make(map[string]bool)
+	for _, component := range previouslyDeployed {
+		deployed[component.Name] = true
+	}
+
+	deploying := make(map[string]bool)
+	for _, component := range components {
+		deploying[component.Name] = true
+	}
+
+	for _, component := range components {
+		for _, dependency := range component.DependsOn {
+			if !deploying[dependency] && !deployed[dependency] {
+				message.Warnf("The component %s depends on %s which is not being deployed", component.Name, dependency)
+			}
+		}
+	}
+
+	dependencies := getComponentDependencies(components)
+	remaining := components
+	for len(remaining) > 0 {
+		var group, blocked []types.ZarfComponent
+
+		for _, component := range remaining {
+			ready := true
+			for _, dependency := range dependencies[component.Name] {
+				if deploying[dependency] && !deployed[dependency] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				group = append(group, component)
+			} else {
+				blocked = append(blocked, component)
+			}
+		}
+
+		if len(group) == 0 {
+			var names []string
+			for _, component := range blocked {
+				names = append(names, component.Name)
+			}
+			return groups, fmt.Errorf("circular dependsOn found between the components %s", strings.Join(names, ", "))
+		}
+
+		for _, component := range group {
+			deployed[component.Name] = true
+		}
+
+		groups = append(groups, group)
+		remaining = blocked
+	}
+
+	return groups, nil
+}
+
 func appendIfNotExists(slice []string, item string) []string {
 	message.Debugf("packager.appendIfNotExists(%#v, %s)", slice, item)
 
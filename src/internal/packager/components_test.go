@@ -0,0 +1,81 @@
+package packager
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDeploymentGroups(t *testing.T) {
+	component := func(name string, dependsOn ...string) types.ZarfComponent {
+		return types.ZarfComponent{Name: name, DependsOn: dependsOn}
+	}
+
+	tests := []struct {
+		name       string
+		components []types.ZarfComponent
+		deployed   []types.DeployedComponent
+		want       [][]string
+		wantErr    bool
+	}{
+		{
+			name:       "yaml order without dependsOn",
+			components: []types.ZarfComponent{component("a"), component("b"), component("c")},
+			want:       [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name:       "independent components share a group",
+			components: []types.ZarfComponent{component("database"), component("cache", "database"), component("queue", "database"), component("app", "cache", "queue")},
+			want:       [][]string{{"database"}, {"cache", "queue"}, {"app"}},
+		},
+		{
+			name:       "components without dependsOn follow their yaml predecessor",
+			components: []types.ZarfComponent{component("a"), component("b"), component("c", "a"), component("d")},
+			want:       [][]string{{"a"}, {"b", "c"}, {"d"}},
+		},
+		{
+			name:       "dependencies can come later in the yaml",
+			components: []types.ZarfComponent{component("app", "database"), component("database")},
+			want:       [][]string{{"database"}, {"app"}},
+		},
+		{
+			name:       "components finished earlier satisfy their dependents",
+			components: []types.ZarfComponent{component("app", "database")},
+			deployed:   []types.DeployedComponent{{Name: "database"}},
+			want:       [][]string{{"app"}},
+		},
+		{
+			name:       "dependencies that aren't deployed are ignored",
+			components: []types.ZarfComponent{component("app", "missing")},
+			want:       [][]string{{"app"}},
+		},
+		{
+			name:       "circular dependsOn",
+			components: []types.ZarfComponent{component("a", "b"), component("b", "a")},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := getDeploymentGroups(tt.components, tt.deployed)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var names [][]string
+			for _, group := range groups {
+				var groupNames []string
+				for _, component := range group {
+					groupNames = append(groupNames, component.Name)
+				}
+				names = append(names, groupNames)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
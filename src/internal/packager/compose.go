@@ -19,11 +19,23 @@ func ComposeComponents() {
 	for _, component := range config.GetComponents() {
 		if component.Import.Path == "" {
 			components = append(components, component)
+		} else if !config.IsMultiArch() {
+			components = append(components, GetComposedComponent(component, config.GetArch()))
+		} else if component.Only.Cluster.Architecture != "" {
+			// The composed component doesn't inherit the architecture of the parent, a multi-arch package needs to keep it
+			composedComponent := GetComposedComponent(component, component.Only.Cluster.Architecture)
+			composedComponent.Only.Cluster.Architecture = component.Only.Cluster.Architecture
+			components = append(components, composedComponent)
 		} else {
-			components = append(components, GetComposedComponent(component))
+			components = append(components, composeMultiArchComponent(component)...)
 		}
 	}
 
+	// A multi-arch package templates the architecture into each component itself instead of with the rest of the package variables
+	if config.IsMultiArch() {
+		components = expandMultiArchComponents(components)
+	}
+
 	// Update the parent package config with the expanded sub components.
 	// This is important when the deploy package is created.
 	config.SetComponents(components)
@@ -34,8 +46,8 @@ func ComposeComponents() {
 // For composed components, we build the tree of components starting at the root and adding children as we go;
 // this follows the composite design pattern outlined here: https://en.wikipedia.org/wiki/Composite_pattern
 // where 1 component parent is made up of 0...n composite or leaf children.
-func GetComposedComponent(parentComponent types.ZarfComponent) types.ZarfComponent {
-	message.Debugf("packager.GetComposedComponent(%+v)", parentComponent)
+func GetComposedComponent(parentComponent types.ZarfComponent, targetArch string) types.ZarfComponent {
+	message.Debugf("packager.GetComposedComponent(%+v, %s)", parentComponent, targetArch)
 
 	// Make sure the component we're trying to import cant be accessed
 	validateOrBail(&parentComponent)
@@ -45,7 +57,7 @@ func GetComposedComponent(parentComponent types.ZarfComponent) types.ZarfCompone
 
 	// Get the component that we are trying to import
 	// NOTE: This function is recursive and will continue getting the children until there are no more 'imported' components left
-	childComponent := getChildComponent(parentComponent, everGrowingComposePath)
+	childComponent := getChildComponent(parentComponent, everGrowingComposePath, targetArch)
 
 	// Merge the overrides from the child that we just received with the parent we were provided
 	mergeComponentOverrides(&childComponent, parentComponent)
@@ -53,8 +65,8 @@ func GetComposedComponent(parentComponent types.ZarfComponent) types.ZarfCompone
 	return childComponent
 }
 
-func getChildComponent(parentComponent types.ZarfComponent, everGrowingComposePath string) (childComponent types.ZarfComponent) {
-	message.Debugf("packager.getChildComponent(%+v, %s)", parentComponent, everGrowingComposePath)
+func getChildComponent(parentComponent types.ZarfComponent, everGrowingComposePath, targetArch string) (childComponent types.ZarfComponent) {
+	message.Debugf("packager.getChildComponent(%+v, %s, %s)", parentComponent, everGrowingComposePath, targetArch)
 
 	importedPackage := getSubPackage(filepath.Join(everGrowingComposePath, parentComponent.Import.Path))
 
@@ -65,7 +77,6 @@ func getChildComponent(parentComponent types.ZarfComponent, everGrowingComposePa
 		childComponentName = parentComponent.Name
 	}
 
-	targetArch := config.GetArch()
 	// Find the child component from the imported package that matches our arch
 	for _, importedComponent := range importedPackage.Components {
 		if importedComponent.Name == childComponentName {
@@ -95,7 +106,7 @@ func getChildComponent(parentComponent types.ZarfComponent, everGrowingComposePa
 		tempEverGrowingComposePath := filepath.Join(everGrowingComposePath, parentComponent.Import.Path)
 
 		// Recursively call this function to get the next layer of children
-		grandchildComponent := getChildComponent(childComponent, tempEverGrowingComposePath)
+		grandchildComponent := getChildComponent(childComponent, tempEverGrowingComposePath, targetArch)
 
 		// Merge the grandchild values into the child
 		mergeComponentOverrides(&grandchildComponent, childComponent)
@@ -118,8 +129,11 @@ func fixComposedFilepaths(parentComponent, childComponent types.ZarfComponent) t
 		childComponent.Files[fileIdx].Source = getComposedFilePath(file.Source, parentComponent.Import.Path)
 	}
 
-	// Prefix non-url composed component chart values files.
+	// Prefix non-url composed component chart values files and local chart paths.
 	for chartIdx, chart := range childComponent.Charts {
+		if chart.LocalPath != "" {
+			childComponent.Charts[chartIdx].LocalPath = getComposedFilePath(chart.LocalPath, parentComponent.Import.Path)
+		}
 		for valuesIdx, valuesFile := range chart.ValuesFiles {
 			childComponent.Charts[chartIdx].ValuesFiles[valuesIdx] = getComposedFilePath(valuesFile, parentComponent.Import.Path)
 		}
@@ -139,6 +153,11 @@ func fixComposedFilepaths(parentComponent, childComponent types.ZarfComponent) t
 		childComponent.CosignKeyPath = getComposedFilePath(childComponent.CosignKeyPath, parentComponent.Import.Path)
 	}
 
+	// Run the create time scripts next to the files they generate for the imported component
+	if len(childComponent.Scripts.Prepare) > 0 || len(childComponent.Scripts.PostPrepare) > 0 {
+		childComponent.Scripts.PrepareDir = getComposedFilePath(childComponent.Scripts.PrepareDir, parentComponent.Import.Path)
+	}
+
 	return childComponent
 }
 
@@ -159,12 +178,18 @@ func mergeComponentOverrides(target *types.ZarfComponent, override types.ZarfCom
 	target.Default = override.Default
 	target.Required = override.Required
 	target.Group = override.Group
+	target.DependsOn = override.DependsOn
 
 	// Override description if it was provided.
 	if override.Description != "" {
 		target.Description = override.Description
 	}
 
+	// Either the parent or the imported component can mark it as keeping data in a volume
+	if override.RequiresStorage {
+		target.RequiresStorage = true
+	}
+
 	// Override cosign key path if it was provided.
 	if override.CosignKeyPath != "" {
 		target.CosignKeyPath = override.CosignKeyPath
@@ -175,6 +200,13 @@ func mergeComponentOverrides(target *types.ZarfComponent, override types.ZarfCom
 	target.DataInjections = append(target.DataInjections, override.DataInjections...)
 	target.Files = append(target.Files, override.Files...)
 	target.Images = append(target.Images, override.Images...)
+	for arch, archImages := range override.ArchImages {
+		if target.ArchImages == nil {
+			target.ArchImages = make(map[string][]string)
+		}
+		target.ArchImages[arch] = append(target.ArchImages[arch], archImages...)
+	}
+	target.Artifacts = append(target.Artifacts, override.Artifacts...)
 	target.Manifests = append(target.Manifests, override.Manifests...)
 	target.Repos = append(target.Repos, override.Repos...)
 
@@ -0,0 +1,138 @@
+package packager
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/klauspost/compress/zstd"
+	"github.com/mholt/archiver/v3"
+)
+
+// The first bytes of every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// tarFormat is a tarball format that can be both streamed and unarchived
+type tarFormat interface {
+	archiver.Reader
+	archiver.Unarchiver
+}
+
+// getTarFormat picks the tarball format from the first bytes of an archive
+func getTarFormat(magic []byte) tarFormat {
+	switch {
+	case bytes.HasPrefix(magic, zstdMagic):
+		return archiver.NewTarZstd()
+	case bytes.HasPrefix(magic, gzipMagic):
+		return archiver.NewTarGz()
+	default:
+		return archiver.NewTar()
+	}
+}
+
+// unarchivePackage extracts a package tarball into destination, detecting its compression from the file contents
+func unarchivePackage(packagePath string, destination string) error {
+	message.Debugf("packager.unarchivePackage(%s, %s)", packagePath, destination)
+
+	file, err := os.Open(packagePath)
+	if err != nil {
+		return err
+	}
+
+	magic := make([]byte, len(zstdMagic))
+	_, err = io.ReadFull(file, magic)
+	_ = file.Close()
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("unable to read the package header: %w", err)
+	}
+
+	return getTarFormat(magic).Unarchive(packagePath, destination)
+}
+
+// createArchive writes the contents of sourceDir to a tarball at destination using the given compression and level (0 uses the default level)
+func createArchive(sourceDir string, destination string, compression string, level int) error {
+	message.Debugf("packager.createArchive(%s, %s, %s, %d)", sourceDir, destination, compression, level)
+
+	file, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var writer io.WriteCloser
+	switch compression {
+	case config.ZarfCompressionZstd:
+		options := []zstd.EOption{}
+		if level > 0 {
+			options = append(options, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		writer, err = zstd.NewWriter(file, options...)
+
+	case config.ZarfCompressionGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		writer, err = gzip.NewWriterLevel(file, level)
+
+	case config.ZarfCompressionNone:
+		writer = file
+
+	default:
+		err = fmt.Errorf("unknown compression %s", compression)
+	}
+	if err != nil {
+		return err
+	}
+
+	tarball := archiver.NewTar()
+	if err := tarball.Create(writer); err != nil {
+		return err
+	}
+
+	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Place everything at the root of the archive
+		name, err := filepath.Rel(sourceDir, path)
+		if err != nil || name == "." {
+			return err
+		}
+
+		var contents io.ReadCloser
+		if info.Mode().IsRegular() {
+			if contents, err = os.Open(path); err != nil {
+				return err
+			}
+			defer contents.Close()
+		}
+
+		return tarball.Write(archiver.File{
+			FileInfo: archiver.FileInfo{
+				FileInfo:   info,
+				CustomName: filepath.ToSlash(name),
+				SourcePath: path,
+			},
+			ReadCloser: contents,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tarball.Close(); err != nil {
+		return err
+	}
+
+	// The file itself is closed by the deferred close
+	if writer != file {
+		return writer.Close()
+	}
+	return nil
+}
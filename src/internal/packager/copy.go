@@ -0,0 +1,44 @@
+package packager
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/mholt/archiver/v3"
+)
+
+// CopyImageFromPackage pushes a single image from a package archive to any registry without deploying the package
+func CopyImageFromPackage(packagePath string, src string, dest string, insecure bool) error {
+	message.Debugf("packager.CopyImageFromPackage(%s, %s, %s)", packagePath, src, dest)
+
+	if utils.InvalidPath(packagePath) {
+		return fmt.Errorf("unable to find the package on the local system, expected package at %s", packagePath)
+	}
+
+	tempPath := createPaths()
+	defer tempPath.clean()
+
+	spinner := message.NewProgressSpinner("Extracting the images from %s", packagePath)
+	defer spinner.Stop()
+
+	// Only pull the image store out of the archive, the rest of the package isn't needed
+	if err := archiver.Extract(packagePath, filepath.Base(tempPath.images), tempPath.base); err != nil {
+		// A multi-arch package has an image store for each architecture, use the one for --architecture or this machine
+		tempPath = tempPath.forArch(config.GetArch())
+		if archErr := archiver.Extract(packagePath, filepath.Base(tempPath.images), tempPath.base); archErr != nil {
+			return fmt.Errorf("unable to extract the images from the package: %w", err)
+		}
+	}
+
+	spinner.Updatef("Pushing %s to %s", src, dest)
+	if err := images.CopyFromTarball(tempPath.images, src, dest, insecure); err != nil {
+		return err
+	}
+
+	spinner.Successf("Copied %s to %s", src, dest)
+	return nil
+}
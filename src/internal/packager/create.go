@@ -3,7 +3,6 @@ package packager
 import (
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -20,7 +19,6 @@ import (
 	"github.com/defenseunicorns/zarf/src/internal/utils"
 	"github.com/defenseunicorns/zarf/src/types"
 	"github.com/google/go-containerregistry/pkg/crane"
-	"github.com/mholt/archiver/v3"
 )
 
 // Create generates a zarf package tarball for consumption by
@@ -38,6 +36,12 @@ func Create(baseDir string) {
 		message.Fatal(err, "Unable to read the zarf.yaml file")
 	}
 
+	// Watch mode and signature bundling track components and images by name, which the copies for each architecture share
+	// Pinned digests are recorded in the zarf.yaml the architectures share, but each architecture pulls an image with another digest
+	if config.IsMultiArch() && (config.CreateOptions.Watch || includeSignatures() || config.CreateOptions.PinDigests) {
+		message.Fatal(nil, "The --watch, --include-signatures, --signature-key and --pin-digests flags can't be used to create a multi-arch package")
+	}
+
 	ComposeComponents()
 
 	// After components are composed, template the active package
@@ -66,34 +70,103 @@ func Create(baseDir string) {
 		os.Exit(0)
 	}
 
+	// Leave out anything that hasn't changed since the reference package when building a differential package
+	diff := differentialData{}
+	if config.CreateOptions.DifferentialPath != "" {
+		var err error
+		if diff, err = loadDifferentialData(config.CreateOptions.DifferentialPath, components); err != nil {
+			message.Fatalf(err, "Unable to build a differential package: %s", err.Error())
+		}
+
+		// Save the config again so the package records what it left out
+		if err := config.BuildConfig(configFile); err != nil {
+			message.Fatalf(err, "Unable to write the %s file", configFile)
+		}
+	}
+
+	// Only approved images may go into the package, forced violations are recorded in the package
+	enforceCreateImagePolicy(getPolicyImages(getCombinedImageList(components, diff)))
+	if len(config.GetBuildData().ImagePolicyViolations) > 0 {
+		if err := config.BuildConfig(configFile); err != nil {
+			message.Fatalf(err, "Unable to write the %s file", configFile)
+		}
+	}
+
+	// Ship the link metadata of the build pipeline so deploy can check it against an in-toto layout
+	if config.CreateOptions.InTotoLinksPath != "" {
+		if err := addInTotoLinks(tempPath, config.CreateOptions.InTotoLinksPath); err != nil {
+			message.Fatalf(err, "Unable to add the in-toto links to the package: %s", err.Error())
+		}
+		if err := config.BuildConfig(configFile); err != nil {
+			message.Fatalf(err, "Unable to write the %s file", configFile)
+		}
+	}
+
 	if config.IsZarfInitConfig() {
 		// Load seed images into their own happy little tarball for ease of import on init
-		pulledImages := images.PullAll([]string{seedImage}, tempPath.seedImage)
-		sbom.CatalogImages(pulledImages, tempPath.sboms, tempPath.seedImage)
-		ociPath := path.Join(tempPath.base, "seed-image")
-		for _, image := range pulledImages {
-			if err := crane.SaveOCI(image, ociPath); err != nil {
-				message.Fatalf(err, "Unable to save image %s as OCI", image)
+		forEachArch(tempPath, func(_ string, archPath tempPaths) {
+			pulledImages := images.PullAll([]string{seedImage}, archPath.seedImage)
+			sbom.CatalogImages(pulledImages, tempPath.sboms, archPath.seedImage)
+			for _, image := range pulledImages {
+				if err := crane.SaveOCI(image, archPath.seedImageDir); err != nil {
+					message.Fatalf(err, "Unable to save image %s as OCI", image)
+				}
 			}
-		}
 
-		if err := images.FormatCraneOCILayout(ociPath); err != nil {
-			message.Fatalf(err, "Unable to format crane OCI layout")
-		}
+			if err := images.FormatCraneOCILayout(archPath.seedImageDir); err != nil {
+				message.Fatalf(err, "Unable to format crane OCI layout")
+			}
+		})
+	}
+
+	// Remember what each component looked like before it was built so watch mode can tell what changed
+	var fingerprints map[string]string
+	if config.CreateOptions.Watch {
+		fingerprints = fingerprintComponents(components)
 	}
 
 	var combinedImageList []string
-	for _, component := range components {
-		addComponent(tempPath, component)
+	var hasBinaries, hasEstimates bool
+	for idx, component := range components {
+		components[idx] = buildComponent(tempPath, component, diff)
+		hasBinaries = hasBinaries || len(component.Binaries) > 0
+		hasEstimates = hasEstimates || len(component.Charts) > 0 || len(component.Manifests) > 0
+
 		// Combine all component images into a single entry for efficient layer reuse
-		combinedImageList = append(combinedImageList, component.Images...)
+		combinedImageList = append(combinedImageList, getPackagedImages(components[idx], diff)...)
+	}
+
+	// Save the config again so the package records the binary checksums, resource estimates and pinned digests
+	if hasBinaries || hasEstimates || config.CreateOptions.PinDigests {
+		config.SetComponents(components)
+		if err := config.BuildConfig(configFile); err != nil {
+			message.Fatalf(err, "Unable to write the %s file", configFile)
+		}
 	}
 
 	// Images are handled separately from other component assets
 	if len(combinedImageList) > 0 {
 		uniqueList := removeDuplicates(combinedImageList)
-		pulledImages := images.PullAll(uniqueList, tempPath.images)
-		sbom.CatalogImages(pulledImages, tempPath.sboms, tempPath.images)
+
+		// A multi-arch package has a tarball for each architecture with just the images of the components that deploy to it
+		forEachArch(tempPath, func(arch string, archPath tempPaths) {
+			archList := uniqueList
+			if config.IsMultiArch() {
+				archList = removeDuplicates(getArchImageList(components, arch, diff))
+			}
+			if len(archList) > 0 {
+				pulledImages := images.PullAll(archList, archPath.images)
+				sbom.CatalogImages(pulledImages, tempPath.sboms, archPath.images)
+			}
+		})
+
+		// Save the config again so the package records which signatures were bundled with the images
+		if includeSignatures() {
+			bundleImageSignatures(tempPath, uniqueList)
+			if err := config.BuildConfig(configFile); err != nil {
+				message.Fatalf(err, "Unable to write the %s file", configFile)
+			}
+		}
 	}
 
 	// In case the directory was changed, reset to prevent breaking relative target paths
@@ -102,22 +175,159 @@ func Create(baseDir string) {
 	}
 
 	packageName := filepath.Join(config.CreateOptions.OutputDirectory, config.GetPackageName())
+	writePackage(tempPath, packageName)
+
+	// Keep rebuilding the package as the author changes it
+	if config.CreateOptions.Watch {
+		packageName, _ = filepath.Abs(packageName)
+		if baseDir != "" {
+			_ = os.Chdir(baseDir)
+		}
+		watchPackage(tempPath, packageName, components, fingerprints, diff)
+		if originalDir != "" {
+			_ = os.Chdir(originalDir)
+		}
+	}
+}
+
+// buildComponent adds the assets of a component to the package and returns it with the checksums recorded along the way
+func buildComponent(tempPath tempPaths, component types.ZarfComponent, diff differentialData) types.ZarfComponent {
+	if config.CreateOptions.PinDigests && (len(component.Images) > 0 || len(component.ArchImages) > 0) {
+		component = pinComponentImages(component, diff)
+	}
+
+	addComponent(tempPath, component, diff)
+
+	if len(component.Charts) > 0 || len(component.Manifests) > 0 {
+		recordResourceEstimate(component.Name, estimateComponentResources(tempPath, component))
+	}
+
+	// Binaries are recorded with their checksums so deploy can verify and catalog them
+	if len(component.Binaries) > 0 {
+		componentPath := createComponentPaths(tempPath.components, component)
+		component.Binaries = addComponentBinaries(component, componentPath.binaries)
+	}
+
+	// Everything the prepare scripts generated is in the package now
+	runPrepareScripts(component.Scripts.PostPrepare, component)
+
+	return component
+}
 
+// runPrepareScripts runs the create time scripts of a component in its prepare directory, so the files they generate can be referenced relative to its zarf.yaml
+func runPrepareScripts(scripts []string, component types.ZarfComponent) {
+	for _, script := range scripts {
+		loopScriptUntilSuccess(script, component.Scripts, component.Scripts.PrepareDir, nil)
+	}
+}
+
+// getPackagedImages returns the images of a component that go into the package
+// Architecture-specific images are all included so the package can be deployed to any of them
+func getPackagedImages(component types.ZarfComponent, diff differentialData) []string {
+	var packagedImages []string
+	for _, image := range getAllComponentImages(component) {
+		if !diff.images[image] {
+			packagedImages = append(packagedImages, image)
+		}
+	}
+	return packagedImages
+}
+
+// pinComponentImages resolves the images of a component that go into the package to name:tag@sha256:...
+// Images left out of a differential package aren't pulled, so they keep their tags and still match the reference package
+func pinComponentImages(component types.ZarfComponent, diff differentialData) types.ZarfComponent {
+	spinner := message.NewProgressSpinner("Pinning the images of %s to their digests", component.Name)
+	defer spinner.Stop()
+
+	pin := func(imageList []string) []string {
+		pinned := make([]string, len(imageList))
+		for idx, image := range imageList {
+			pinned[idx] = image
+			if diff.images[image] {
+				continue
+			}
+			spinner.Updatef("Resolving the digest of %s", image)
+			pinnedImage, err := images.PinDigest(image)
+			if err != nil {
+				spinner.Fatalf(err, "Unable to pin %s to a digest: %s", image, err.Error())
+			}
+			pinned[idx] = pinnedImage
+		}
+		return pinned
+	}
+
+	component.Images = pin(component.Images)
+	if len(component.ArchImages) > 0 {
+		archImages := make(map[string][]string)
+		for arch, imageList := range component.ArchImages {
+			archImages[arch] = pin(imageList)
+		}
+		component.ArchImages = archImages
+	}
+
+	spinner.Success()
+	return component
+}
+
+// writePackage archives the package workspace, splitting it into parts when requested
+func writePackage(tempPath tempPaths, packageName string) {
 	_ = os.RemoveAll(packageName)
-	err := archiver.Archive([]string{tempPath.base + string(os.PathSeparator)}, packageName)
+	compression := config.GetPackageCompression()
+	if config.CreateOptions.Compression != "" && config.CreateOptions.Compression != compression {
+		message.Warnf("Init packages are always %s compressed, ignoring --compression %s", compression, config.CreateOptions.Compression)
+	}
+
+	err := createArchive(tempPath.base, packageName, compression, config.CreateOptions.CompressionLevel)
 	if err != nil {
 		message.Fatal(err, "Unable to create the package archive")
 	}
+
+	if config.CreateOptions.MaxPackageSize != "" {
+		splitPackage(packageName)
+	}
 }
 
-func addComponent(tempPath tempPaths, component types.ZarfComponent) {
+// splitPackage breaks the package archive into parts for media with file size limits
+func splitPackage(packageName string) {
+	maxSize, err := utils.ParseByteSize(config.CreateOptions.MaxPackageSize)
+	if err != nil {
+		message.Fatalf(err, "Invalid maximum package size")
+	}
+
+	info, err := os.Stat(packageName)
+	if err != nil {
+		message.Fatalf(err, "Unable to read the package archive %s", packageName)
+	}
+
+	if info.Size() <= maxSize {
+		message.Debugf("Package is %s, no need to split it", utils.ByteFormat(float64(info.Size()), 2))
+		return
+	}
+
+	spinner := message.NewProgressSpinner("Splitting the package into %s parts", config.CreateOptions.MaxPackageSize)
+	defer spinner.Stop()
+
+	// Clear out parts left behind by a previous create
+	oldParts, _ := filepath.Glob(packageName + ".part*")
+	for _, part := range oldParts {
+		_ = os.Remove(part)
+	}
+
+	parts, err := utils.SplitFile(packageName, maxSize)
+	if err != nil {
+		spinner.Fatalf(err, "Unable to split the package")
+	}
+
+	_ = os.Remove(packageName)
+	spinner.Successf("Package split into %d parts, deploy it using %s", len(parts)-1, parts[0])
+}
+
+func addComponent(tempPath tempPaths, component types.ZarfComponent, diff differentialData) {
 	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(component.Name))
 	componentPath := createComponentPaths(tempPath.components, component)
 
 	// Loop through each component prepare script and execute it
-	for _, script := range component.Scripts.Prepare {
-		loopScriptUntilSuccess(script, component.Scripts)
-	}
+	runPrepareScripts(component.Scripts.Prepare, component)
 
 	if len(component.Charts) > 0 {
 		_ = utils.CreateDirectory(componentPath.charts, 0700)
@@ -174,6 +384,13 @@ func addComponent(tempPath tempPaths, component types.ZarfComponent) {
 		}
 	}
 
+	// Artifacts keep their own media types in an OCI layout, apart from the image tarball
+	if len(component.Artifacts) > 0 {
+		if err := images.PullArtifacts(componentPath.artifacts, component.Artifacts); err != nil {
+			message.Fatalf(err, "Unable to load the OCI artifacts of the %s component", component.Name)
+		}
+	}
+
 	if len(component.DataInjections) > 0 {
 		spinner := message.NewProgressSpinner("Loading data injections")
 		defer spinner.Success()
@@ -227,6 +444,11 @@ func addComponent(tempPath tempPaths, component types.ZarfComponent) {
 		spinner := message.NewProgressSpinner("Loading %d git repos", len(component.Repos))
 		defer spinner.Success()
 		for _, url := range component.Repos {
+			if diff.repos[url] {
+				spinner.Updatef("Skipping the unchanged repo %s", url)
+				continue
+			}
+
 			// Pull all the references if there is no `@` in the string
 			_, err := git.Pull(url, componentPath.repos, spinner)
 			if err != nil {
@@ -0,0 +1,235 @@
+package packager
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// registryCADaemonSet copies the CA given to zarf init into the containerd config of every node so they can pull from the Zarf registry over TLS
+const registryCADaemonSet = "zarf-registry-ca"
+
+// registryCATimeout is how long every node gets to copy the CA
+const registryCATimeout = 3 * time.Minute
+
+// containerdCertsDir is the config_path containerd reads the CAs of registries from, with a directory for each registry host
+const containerdCertsDir = "/etc/containerd/certs.d"
+
+// replaceCustomTLSSecret saves a certificate given to zarf init to the secret it is served from, along with its CA for the nodes
+func replaceCustomTLSSecret(name string, pki types.GeneratedPKI) error {
+	secret, err := k8s.GenerateTLSSecret(k8s.ZarfNamespace, name, pki)
+	if err != nil {
+		return err
+	}
+	secret.Data["ca.crt"] = pki.CA
+
+	return k8s.ReplaceSecret(secret)
+}
+
+// registryCANeeded is true when the nodes pull from the Zarf registry on localhost, which Zarf has to make them trust
+// A load balancer or ingress is reached on an address the nodes have to be set up to trust before zarf init
+func registryCANeeded(registryInfo types.RegistryInfo) bool {
+	return registryInfo.CustomTLS && registryInfo.ServiceType == string(corev1.ServiceTypeNodePort)
+}
+
+// seedRegistryCA copies the CA of the Zarf registry to every node before the seed registry is served with its certificate
+// The image comes from the injector since nothing else can serve it yet
+func seedRegistryCA() {
+	if !registryCANeeded(config.GetContainerRegistryInfo()) {
+		return
+	}
+
+	spinner := message.NewProgressSpinner("Copying the CA of the Zarf registry to every node")
+	defer spinner.Stop()
+
+	if err := distributeRegistryCA(config.GetSeedRegistry(), spinner); err != nil {
+		spinner.Fatalf(err, "Unable to copy the CA of the Zarf registry to the nodes, containerd must read its registry config from %s: %s", containerdCertsDir, err.Error())
+	}
+	spinner.Successf("Every node trusts the CA of the Zarf registry")
+}
+
+// distributeRegistryCA runs the daemonset that copies the CA of the Zarf registry to every node with the seed image from the given registry and waits for it
+// It stays in the cluster so nodes that join later trust the registry as well
+func distributeRegistryCA(imageRegistry string, spinner *message.Spinner) error {
+	image := fmt.Sprintf("%s/library/%s:%s", imageRegistry, config.ZarfSeedImage, config.ZarfSeedTag)
+	daemonSet := generateRegistryCADaemonSet(image, config.GetRegistry())
+
+	existing, err := k8s.GetDaemonSet(k8s.ZarfNamespace, registryCADaemonSet)
+	if err == nil {
+		existing.Spec.Template = daemonSet.Spec.Template
+		_, err = k8s.UpdateDaemonSet(existing)
+	} else {
+		_, err = k8s.CreateDaemonSet(daemonSet)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to apply the %s daemonset: %w", registryCADaemonSet, err)
+	}
+
+	timeout := time.After(registryCATimeout)
+	for {
+		copied, nodes, err := countRegistryCANodes()
+		if err == nil {
+			spinner.Updatef("%d of %d nodes trust the CA of the Zarf registry", copied, nodes)
+			if nodes > 0 && copied == nodes {
+				return nil
+			}
+		}
+
+		select {
+		case <-timeout:
+			return fmt.Errorf("%d of %d nodes copied the CA within %s", copied, nodes, registryCATimeout)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// countRegistryCANodes returns how many of the nodes the daemonset wants to run on have a ready pod of its latest template
+func countRegistryCANodes() (int, int, error) {
+	daemonSet, err := k8s.GetDaemonSet(k8s.ZarfNamespace, registryCADaemonSet)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	nodes := int(daemonSet.Status.DesiredNumberScheduled)
+	// The pods of the previous template don't count until the rollout replaces them
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return 0, nodes, nil
+	}
+
+	copied := daemonSet.Status.UpdatedNumberScheduled
+	if daemonSet.Status.NumberReady < copied {
+		copied = daemonSet.Status.NumberReady
+	}
+	return int(copied), nodes, nil
+}
+
+// checkRegistryCertificateHost warns when the certificate of the Zarf registry isn't valid for the address the nodes pull from
+func checkRegistryCertificateHost(address string) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	block, _ := pem.Decode(config.InitOptions.RegistryTLS.Cert)
+	if block == nil {
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		message.Debugf("Unable to parse the certificate of the Zarf registry: %s", err.Error())
+		return
+	}
+
+	if err := cert.VerifyHostname(host); err != nil {
+		message.Warnf("The certificate of the Zarf registry isn't valid for %s, so the nodes can't pull from it until it is replaced: %s", host, err.Error())
+	}
+}
+
+// generateRegistryCADaemonSet returns a daemonset that copies the CA in the registry TLS secret to the containerd config of each node for the registry address
+// It has to run as root to write to the host, but needs no capabilities since the directories it writes to are owned by root
+func generateRegistryCADaemonSet(image, registryAddress string) *appsv1.DaemonSet {
+	podLabels := map[string]string{
+		"app": registryCADaemonSet,
+	}
+
+	root := int64(0)
+	nonRoot := false
+	noEscalation := false
+	readOnly := true
+	noToken := false
+	hostPathType := corev1.HostPathDirectoryOrCreate
+
+	script := fmt.Sprintf(`set -e
+mkdir -p "/certs.d/%[1]s"
+cp /zarf-ca/ca.crt "/certs.d/%[1]s/ca.crt"
+trap 'exit 0' TERM
+while true; do sleep 3600 & wait $!; done`, registryAddress)
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registryCADaemonSet,
+			Namespace: k8s.ZarfNamespace,
+			Labels: map[string]string{
+				config.ZarfManagedByLabel: "zarf",
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app":            registryCADaemonSet,
+						"zarf.dev/agent": "ignore",
+					},
+				},
+				Spec: corev1.PodSpec{
+					PriorityClassName: "system-node-critical",
+					Containers: []corev1.Container{{
+						Name:            "copy-ca",
+						Image:           image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Command:         []string{"/bin/sh", "-c", script},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "ca", MountPath: "/zarf-ca", ReadOnly: true},
+							{Name: "certs", MountPath: "/certs.d"},
+						},
+						SecurityContext: &corev1.SecurityContext{
+							RunAsUser:                &root,
+							RunAsNonRoot:             &nonRoot,
+							AllowPrivilegeEscalation: &noEscalation,
+							ReadOnlyRootFilesystem:   &readOnly,
+							Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+							SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("1m"),
+								corev1.ResourceMemory: resource.MustParse("4Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("10m"),
+								corev1.ResourceMemory: resource.MustParse("16Mi"),
+							},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{
+							Name: "ca",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: config.ZarfRegistryTLSSecretName,
+									Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+								},
+							},
+						},
+						{
+							Name: "certs",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: containerdCertsDir, Type: &hostPathType},
+							},
+						},
+					},
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+					// Every node pulls from the registry, including ones with taints that only some workloads tolerate
+					Tolerations:                  []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					AutomountServiceAccountToken: &noToken,
+				},
+			},
+		},
+	}
+}
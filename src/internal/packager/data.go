@@ -26,14 +26,27 @@ func handleDataInjection(wg *sync.WaitGroup, data types.ZarfDataInjection, compo
 		return
 	}
 
+	err := runDeployPhase(DeployPhaseData, func(ctx context.Context) error {
+		return injectData(ctx, data, componentPath)
+	})
+	if err != nil {
+		message.Warnf("Unable to inject data into %s: %s", data.Target.Path, err.Error())
+	}
+}
+
+// injectData waits for the target pod(s) and copies the data and the completion marker into them
+func injectData(ctx context.Context, data types.ZarfDataInjection, componentPath componentPaths) error {
 	tarCompressFlag := ""
 	if data.Compress {
 		tarCompressFlag = "z"
 	}
 
-iterator:
-	// The eternal loop because some data injections can take a very long time
+	// The eternal loop because some data injections can take a very long time, bounded only by the data phase timeout
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		message.Debugf("Attempting to inject data into %s", data.Target)
 		source := filepath.Join(componentPath.dataInjections, filepath.Base(data.Target.Path))
 
@@ -51,10 +64,9 @@ iterator:
 
 			// Must create the target directory before trying to change to it for untar
 			mkdirExec := fmt.Sprintf("%s -- mkdir -p %s", kubectlExec, data.Target.Path)
-			_, _, err := utils.ExecCommandWithContext(context.TODO(), true, "sh", "-c", mkdirExec)
+			_, _, err := utils.ExecCommandWithContext(ctx, true, "sh", "-c", mkdirExec)
 			if err != nil {
-				message.Warnf("Unable to create the data injection target directory %s in pod %s", data.Target.Path, pod)
-				break iterator
+				return fmt.Errorf("unable to create the data injection target directory %s in pod %s: %w", data.Target.Path, pod, err)
 			}
 
 			cpPodExec := fmt.Sprintf("%s -C %s . | %s -- %s",
@@ -65,23 +77,22 @@ iterator:
 			)
 
 			// Do the actual data injection
-			_, _, err = utils.ExecCommandWithContext(context.TODO(), true, "sh", "-c", cpPodExec)
+			_, _, err = utils.ExecCommandWithContext(ctx, true, "sh", "-c", cpPodExec)
 			if err != nil {
-				message.Warnf("Error copying data into the pod %#v: %#v\n", pod, err)
-				break iterator
-			} else {
-				// Leave a marker in the target container for pods to track the sync action
-				cpPodExec := fmt.Sprintf("%s -C %s %s | %s -- %s",
-					tarExec,
-					componentPath.dataInjections,
-					config.GetDataInjectionMarker(),
-					kubectlExec,
-					untarExec,
-				)
-				_, _, err = utils.ExecCommandWithContext(context.TODO(), true, "sh", "-c", cpPodExec)
-				if err != nil {
-					message.Warnf("Error saving the zarf sync completion file after injection into pod %#v\n", pod)
-				}
+				return fmt.Errorf("unable to copy data into the pod %s: %w", pod, err)
+			}
+
+			// Leave a marker in the target container for pods to track the sync action
+			cpPodExec = fmt.Sprintf("%s -C %s %s | %s -- %s",
+				tarExec,
+				componentPath.dataInjections,
+				config.GetDataInjectionMarker(),
+				kubectlExec,
+				untarExec,
+			)
+			_, _, err = utils.ExecCommandWithContext(ctx, true, "sh", "-c", cpPodExec)
+			if err != nil {
+				message.Warnf("Error saving the zarf sync completion file after injection into pod %#v\n", pod)
 			}
 		}
 
@@ -97,7 +108,6 @@ iterator:
 		// Cleanup now to reduce disk pressure
 		_ = os.RemoveAll(source)
 
-		// Return to stop the loop
-		return
+		return nil
 	}
 }
@@ -1,6 +1,7 @@
 package packager
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -20,35 +21,86 @@ import (
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/template"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
-	"github.com/mholt/archiver/v3"
-	"github.com/otiai10/copy"
 	"github.com/pterm/pterm"
 	corev1 "k8s.io/api/core/v1"
 )
 
-var valueTemplate template.Values
 var connectStrings = make(types.ConnectStrings)
 
+// Set when the init components being deployed need a working StorageClass
+var initNeedsStorage bool
+
+// The Pod Security Standards level needed by each of the init components being deployed
+var initPodSecurityLevels map[string]string
+
+// The image policy violations accepted with --force-image-policy, saved with the deployed package
+var imagePolicyViolations []string
+
 // Deploy attempts to deploy a Zarf package that is define within the global DeployOptions struct
 func Deploy() {
 	message.Debug("packager.Deploy()")
 
+	startCommandResult("deploy")
+
 	tempPath := createPaths()
 	defer tempPath.clean()
 
+	// The --deadline covers everything from here on, including any prompts
+	startDeployDeadline()
+
 	spinner := message.NewProgressSpinner("Preparing zarf package %s", config.DeployOptions.PackagePath)
 	defer spinner.Stop()
 
-	// Make sure the user gave us a package we can work with
-	if utils.InvalidPath(config.DeployOptions.PackagePath) {
-		spinner.Fatalf(nil, "Unable to find the package on the local system, expected package at %s", config.DeployOptions.PackagePath)
-	}
+	var err error
+	if isOCIReference(config.DeployOptions.PackagePath) {
+		// Pull the package layers straight into the temp directory
+		spinner.Updatef("Pulling the package from %s, this may take a few moments", config.DeployOptions.PackagePath)
+		err = runDeployPhase(DeployPhaseExtract, func(ctx context.Context) error {
+			return pullPackage(ctx, config.DeployOptions.PackagePath, tempPath.base)
+		})
+		if err != nil {
+			spinner.Fatalf(err, "Unable to pull the package from %s", config.DeployOptions.PackagePath)
+		}
+	} else if config.DeployOptions.PackagePath == stdinPackagePath {
+		// Stream the archive from stdin so it never has to be written to disk in full, a consumed stream can't be retried
+		spinner.Updatef("Extracting the package from stdin, this may take a few moments")
+		extractStart := time.Now()
+		err = runBeforeDeadline(DeployPhaseExtract, getPhaseDeadline(DeployPhaseExtract), func(ctx context.Context) error {
+			return extractFromReader(ctx, os.Stdin, tempPath.base)
+		})
+		recordPhaseDuration(DeployPhaseExtract, extractStart)
+		if err != nil {
+			spinner.Fatalf(err, "Unable to extract the package from stdin")
+		}
+	} else {
+		// Reassemble multi-part packages before extracting them
+		if indexPath := utils.GetSplitIndexPath(config.DeployOptions.PackagePath); indexPath != "" {
+			spinner.Updatef("Reassembling the multi-part package %s", indexPath)
+			reassembledDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+			if err != nil {
+				spinner.Fatalf(err, "Unable to create a directory to reassemble the package")
+			}
+			defer os.RemoveAll(reassembledDir)
 
-	// Extract the archive
-	spinner.Updatef("Extracting the package, this may take a few moments")
-	err := archiver.Unarchive(config.DeployOptions.PackagePath, tempPath.base)
-	if err != nil {
-		spinner.Fatalf(err, "Unable to extract the package contents")
+			if config.DeployOptions.PackagePath, err = utils.ReassembleSplitFile(indexPath, reassembledDir); err != nil {
+				spinner.Fatalf(err, "Unable to reassemble the multi-part package")
+			}
+		}
+
+		// Make sure the user gave us a package we can work with
+		if utils.InvalidPath(config.DeployOptions.PackagePath) {
+			spinner.Fatalf(nil, "Unable to find the package on the local system, expected package at %s", config.DeployOptions.PackagePath)
+		}
+
+		// Extract the archive
+		spinner.Updatef("Extracting the package, this may take a few moments")
+		err = runDeployPhase(DeployPhaseExtract, func(_ context.Context) error {
+			// The archive is extracted in one go, so a timeout only applies once it is done
+			return unarchivePackage(config.DeployOptions.PackagePath, tempPath.base)
+		})
+		if err != nil {
+			spinner.Fatalf(err, "Unable to extract the package contents")
+		}
 	}
 
 	// Load the config from the extracted archive zarf.yaml
@@ -58,6 +110,30 @@ func Deploy() {
 		spinner.Fatalf(err, "Invalid or unreadable zarf.yaml file in %s", tempPath.base)
 	}
 
+	// A multi-arch package only deploys the components and images for the architecture of the cluster
+	if config.IsMultiArch() {
+		arch, err := selectMultiArch()
+		if err != nil {
+			spinner.Fatalf(err, "Unable to deploy this multi-arch package: %s", err.Error())
+		}
+		spinner.Updatef("Selecting the %s components and images of the multi-arch package", arch)
+		config.CliArch = arch
+		if err := config.LoadConfig(configPath, true); err != nil {
+			spinner.Fatalf(err, "Invalid or unreadable zarf.yaml file in %s", tempPath.base)
+		}
+		tempPath = tempPath.forArch(arch)
+	}
+
+	setResultPackage(config.GetMetaData().Name, config.GetMetaData().Version)
+
+	// Only packages built by the pipeline the layout describes may deploy
+	if layoutPath := config.DeployOptions.InTotoLayoutPath; layoutPath != "" {
+		spinner.Updatef("Verifying the in-toto links of the package against %s", layoutPath)
+		if err := verifyInTotoLayout(tempPath, layoutPath, config.DeployOptions.InTotoLayoutKeys); err != nil {
+			spinner.Fatalf(err, "Unable to verify the build of the package: %s", err.Error())
+		}
+	}
+
 	if config.IsZarfInitConfig() {
 		// If init config, make sure things are ready
 		utils.RunPreflightChecks()
@@ -65,6 +141,11 @@ func Deploy() {
 
 	spinner.Success()
 
+	if version := config.GetBuildData().DifferentialPackageVersion; version != "" {
+		message.Warnf("This is a differential package, version %s of %s must already be deployed to provide the images and repos it left out",
+			version, config.GetMetaData().Name)
+	}
+
 	// If SBOM files exist, temporary place them in the deploy directory
 	sbomViewFiles, _ := filepath.Glob(filepath.Join(tempPath.sboms, "sbom-viewer-*"))
 	err = writeSBOMFiles(sbomViewFiles)
@@ -73,32 +154,35 @@ func Deploy() {
 		// Don't stop the deployment, let the user decide if they want to continue the deployment
 	}
 
+	// A profile has to exist in this package, its description is shown before the variables are set
+	if name := config.DeployOptions.Profile; name != "" {
+		profile, ok := config.GetProfile(name)
+		if !ok {
+			var available []string
+			for _, p := range config.GetActiveConfig().Profiles {
+				available = append(available, p.Name)
+			}
+			message.Fatalf(nil, "The profile %s is not in this package, the available profiles are: %s", name, strings.Join(available, ", "))
+		}
+		if profile.Description != "" {
+			message.Notef("Deploying the %s profile: %s", profile.Name, profile.Description)
+		}
+	}
+
+	// Set variables and prompt if --confirm is not set, this happens first so the values can be reviewed before confirming
+	if err := config.SetActiveVariables(); err != nil {
+		message.Fatalf(err, "Unable to set variables in config: %s", err.Error())
+	}
+
 	// Confirm the overall package deployment
 	confirm := confirmAction("Deploy", sbomViewFiles)
 
 	// Don't continue unless the user says so
 	if !confirm {
+		finishCommandResult(resultCancelled, "")
 		return
 	}
 
-	// Generate a secret that describes the package that is being deployed
-	secretName := fmt.Sprintf("zarf-package-%s", config.GetActiveConfig().Metadata.Name)
-	deployedPackageSecret := k8s.GenerateSecret("zarf", secretName, corev1.SecretTypeOpaque)
-	deployedPackageSecret.Labels["package-deploy-info"] = config.GetActiveConfig().Metadata.Name
-	deployedPackageSecret.StringData = make(map[string]string)
-
-	installedZarfPackage := types.DeployedPackage{
-		Name:               config.GetActiveConfig().Metadata.Name,
-		CLIVersion:         config.CLIVersion,
-		Data:               config.GetActiveConfig(),
-		DeployedComponents: make([]types.DeployedComponent, 0),
-	}
-
-	// Set variables and prompt if --confirm is not set
-	if err := config.SetActiveVariables(); err != nil {
-		message.Fatalf(err, "Unable to set variables in config: %s", err.Error())
-	}
-
 	// Verify the components requested all exist
 	components := config.GetComponents()
 	componentOptions := config.DeployOptions.Components
@@ -106,6 +190,8 @@ func Deploy() {
 	// Init packages use a different component list
 	if config.IsZarfInitConfig() {
 		componentOptions = config.InitOptions.Components
+	} else if profile, ok := config.GetProfile(config.DeployOptions.Profile); ok {
+		componentOptions = strings.Join(profile.Components, ",")
 	}
 
 	// The component list is comma-delimited list
@@ -116,186 +202,385 @@ func Deploy() {
 
 	// Get a list of all the components we are deploying and actually deploy them
 	componentsToDeploy := getValidComponents(components, requestedComponents)
-	deployedComponents, err := deployComponents(tempPath, componentsToDeploy)
+
+	if config.IsZarfInitConfig() {
+		initNeedsStorage = initComponentsNeedStorage(componentsToDeploy)
+		initPodSecurityLevels = getInitPodSecurityLevels(componentsToDeploy)
+	}
+
+	// Check what an earlier deployment of this package left behind
+	if !config.IsZarfInitConfig() && packageUsesK8s() {
+		if previousDeployment, err := k8s.GetDeployedPackage(config.GetMetaData().Name); err == nil {
+			// Only one component of a choice group can be in the cluster at a time
+			checkChoiceGroupConflicts(componentsToDeploy, previousDeployment)
+
+			// Files placed on the host by the earlier deployment already have backups of what they replaced
+			for _, component := range previousDeployment.DeployedComponents {
+				for _, file := range component.Files {
+					previousHostFiles[file.Path] = file.Backup
+				}
+			}
+
+			// Resumed components won't collect their connect strings again
+			if config.DeployOptions.Resume {
+				for name, connectString := range previousDeployment.ConnectStrings {
+					connectStrings[name] = connectString
+				}
+			}
+		}
+	}
+
+	// Skip anything an earlier attempt already finished when resuming
+	pendingComponents := componentsToDeploy
+	var resumedComponents []types.DeployedComponent
+	if config.DeployOptions.Resume {
+		resumedComponents, pendingComponents = getResumableComponents(componentsToDeploy)
+		restoreExports(resumedComponents, componentsToDeploy)
+	}
+
+	// Every value a component imports has to be exported by one of the selected components deployed before it
+	if err := validateImports(pendingComponents, resumedComponents); err != nil {
+		message.Fatalf(err, "Invalid component selection: %s", err.Error())
+	}
+
+	// Check the images against the image policy again before any of them are pushed
+	var policyImages []string
+	for _, component := range pendingComponents {
+		policyImages = append(policyImages, withoutDifferentialMissing(getComponentImages(component), config.GetBuildData().DifferentialMissingImages)...)
+	}
+	if imagePolicyViolations, err = enforceImagePolicy(config.DeployOptions.ImagePolicyPath, config.DeployOptions.ForceImagePolicy, policyImages); err != nil {
+		message.Fatalf(err, "The package images don't meet the image policy: %s", err.Error())
+	}
+
+	deployedComponents, err := deployComponents(tempPath, pendingComponents, resumedComponents)
 	if err != nil {
 		message.Errorf(err, "Unable to deploy all the components of this Zarf Package.")
 	}
-	installedZarfPackage.DeployedComponents = deployedComponents
+	deployErr := err
+
+	// Clean up any tunnels and temporary resources this run left in the cluster
+	if packageUsesK8s() {
+		if tunnels := k8s.CloseRunTunnels(config.GetRunID()); tunnels > 0 {
+			message.Debugf("Closed %d tunnels this deployment left open", tunnels)
+		}
+		if _, err := k8s.DeleteRunResources(config.GetRunID(), 0); err != nil {
+			message.Errorf(err, "Unable to remove the temporary resources of this deployment, remove them with 'zarf tools gc --run %s'", config.GetRunID())
+		}
+	}
 
 	// Notify all the things about the successful deployment
 	message.SuccessF("Zarf deployment complete")
 	pterm.Println()
 	printTablesForDeployment(componentsToDeploy)
+	printDeployMetrics()
 
 	// Save deployed package information to k8s
 	// Note: Not all packages need k8s; check if k8s is being used before saving the secret
 	if packageUsesK8s() {
-		stateData, _ := json.Marshal(installedZarfPackage)
-		deployedPackageSecret.Data = map[string][]byte{"data": stateData}
-		k8s.ReplaceSecret(deployedPackageSecret)
+		if err := saveDeployedPackage(deployedComponents); err != nil {
+			message.Errorf(err, "Unable to save the deployed package information to the cluster")
+		}
+
+		// Publish the image inventory so it can be aggregated by the inventory component
+		if err := publishInventory(tempPath, componentsToDeploy); err != nil {
+			message.Errorf(err, "Unable to publish the image inventory for this package")
+		}
+	}
+
+	if deployErr != nil {
+		finishCommandResult(resultFailed, deployErr.Error())
+	} else {
+		finishCommandResult(resultSucceeded, "")
 	}
 }
 
-// deployComponents loops through a list of ZarfComponents and deploys them
-func deployComponents(tempPath tempPaths, componentsToDeploy []types.ZarfComponent) ([]types.DeployedComponent, error) {
-	// When pushing images, the default behavior is to add a shasum of the url to the image name
-	deployedComponents := []types.DeployedComponent{}
+// deployComponents deploys a list of ZarfComponents one at a time, each after the components it depends on
+// Components finished by an earlier attempt are passed in as resumedComponents and kept at the front of the deployed list
+func deployComponents(tempPath tempPaths, componentsToDeploy []types.ZarfComponent, resumedComponents []types.DeployedComponent) ([]types.DeployedComponent, error) {
+	deployedComponents := append([]types.DeployedComponent{}, resumedComponents...)
 	config.SetDeployingComponents(deployedComponents)
-	// Deploy all the components
-	for _, component := range componentsToDeploy {
-		deployedComponent := types.DeployedComponent{Name: component.Name}
-		addShasumToImg := true
-
-		// If this is an init-package and we are using an external registry, don't deploy the components to stand up an internal registry
-		// TODO: Figure out a better way to do this (I don't like how these components are still `required` according to the yaml definition)
-		if (config.IsZarfInitConfig() && config.InitOptions.RegistryInfo.Address != "") &&
-			(component.Name == "zarf-seed-registry" || component.Name == "zarf-injector" || component.Name == "zarf-registry") {
-			message.Notef("Not deploying the component (%s) since external registry information was provided during `zarf init`", component.Name)
-			continue
-		}
-
-		// Do somewhat custom pre-configuration for the seed and agent components
-		if config.IsZarfInitConfig() && component.Name == "zarf-seed-registry" && config.InitOptions.RegistryInfo.Address == "" {
-			// The zarf-seed-registry component is responsible for seeding the state and finding a pod to inject a registry into
-			seedZarfState(tempPath)
-			runInjectionMadness(tempPath)
-		} else if config.IsZarfInitConfig() && component.Name == "zarf-agent" {
-			// The zarf-agent cannot mutate itself, so don't change the img url
-			addShasumToImg = false
-
-			// If we are using an external registry, we will need to seed the ZarfState as part of the zarf-agent component
-			if !config.GetContainerRegistryInfo().InternalRegistry {
-				seedZarfState(tempPath)
-			}
-		}
 
-		// Actually deploy the component
-		installedCharts := deployComponent(tempPath, component, addShasumToImg)
+	deploymentGroups, err := getDeploymentGroups(componentsToDeploy, resumedComponents)
+	if err != nil {
+		return deployedComponents, err
+	}
 
-		// Do cleanup for when we inject the seed registry during initialization
-		if config.IsZarfInitConfig() && component.Name == "zarf-seed-registry" {
-			err := postSeedRegistry(tempPath)
+	for _, group := range deploymentGroups {
+		for _, component := range group {
+			deployedComponent, err := deployComponentInGroup(tempPath, component)
 			if err != nil {
-				message.Warnf("Unable to seed the Zarf registry")
-				return deployedComponents, fmt.Errorf("unable to seed the Zarf Registry: %w", err)
+				return deployedComponents, err
+			}
+			if deployedComponent != nil {
+				deployedComponents = append(deployedComponents, *deployedComponent)
+				config.SetDeployingComponents(deployedComponents)
+				checkpointDeployment(deployedComponents)
 			}
 		}
-
-		// Deploy the component
-		deployedComponent.InstalledCharts = installedCharts
-		deployedComponents = append(deployedComponents, deployedComponent)
-		config.SetDeployingComponents(deployedComponents)
 	}
+
 	config.ClearDeployingComponents()
 	return deployedComponents, nil
 }
 
+// deployComponentInGroup deploys a single component and records how it ended for the --result-file
+// A nil DeployedComponent is returned when the component was intentionally skipped
+func deployComponentInGroup(tempPath tempPaths, component types.ZarfComponent) (*types.DeployedComponent, error) {
+	startComponentResult(component.Name)
+
+	deployedComponent, err := deployComponentWithInitSteps(tempPath, component)
+	switch {
+	case err != nil:
+		finishComponentResult(component.Name, resultFailed)
+	case deployedComponent == nil:
+		finishComponentResult(component.Name, resultSkipped)
+	default:
+		finishComponentResult(component.Name, resultDeployed)
+	}
+	return deployedComponent, err
+}
+
+// deployComponentWithInitSteps handles the init package special cases around deploying a single component
+func deployComponentWithInitSteps(tempPath tempPaths, component types.ZarfComponent) (*types.DeployedComponent, error) {
+	// When pushing images, the default behavior is to add a shasum of the url to the image name
+	addShasumToImg := true
+
+	usingExistingRegistry := isUsingExistingRegistry()
+
+	// If this is an init-package and we are using an existing registry, don't deploy the components to stand up an internal registry
+	if skipsForExistingRegistry(component) {
+		message.Notef("Not deploying the component (%s) since existing registry information was provided during `zarf init`", component.Name)
+		return nil, nil
+	}
+
+	// Do somewhat custom pre-configuration for the seed and agent components
+	if config.IsZarfInitConfig() && component.Name == "zarf-seed-registry" && !usingExistingRegistry {
+		// The zarf-seed-registry component is responsible for seeding the state and finding a pod to inject a registry into
+		seedZarfState(tempPath)
+		runInjectionMadness(tempPath)
+		// The nodes have to trust the CA before the seed registry is served with its certificate
+		seedRegistryCA()
+	} else if config.IsZarfInitConfig() && component.Name == "zarf-agent" {
+		// The zarf-agent cannot mutate itself, so don't change the img url
+		addShasumToImg = false
+
+		// If we are using an existing registry, we will need to seed the ZarfState as part of the zarf-agent component
+		if usingExistingRegistry {
+			seedZarfState(tempPath)
+		}
+	}
+
+	// Actually deploy the component
+	deployedComponent := deployComponent(tempPath, component, addShasumToImg)
+
+	// Apply the user-provided webhook settings now that the agent has been deployed
+	if config.IsZarfInitConfig() && component.Name == "zarf-agent" {
+		if err := k8s.ConfigureMutatingWebhook(config.ZarfAgentWebhookName, config.GetState().AgentWebhook); err != nil {
+			return nil, fmt.Errorf("unable to configure the Zarf agent webhook: %w", err)
+		}
+		// The namespaces Zarf deployed packages to opt in so their resources are still mutated, the zarf namespace keeps its zarf.dev/agent=ignore label
+		if config.GetState().AgentWebhook.Mode == k8s.AgentModeOptIn {
+			if err := k8s.EnableAgentInManagedNamespaces(); err != nil {
+				return nil, fmt.Errorf("unable to enable the Zarf agent in the namespaces Zarf manages: %w", err)
+			}
+		}
+	}
+
+	// Do cleanup for when we inject the seed registry during initialization
+	if config.IsZarfInitConfig() && component.Name == "zarf-seed-registry" {
+		err := postSeedRegistry(tempPath)
+		if err != nil {
+			message.Warnf("Unable to seed the Zarf registry")
+			return nil, fmt.Errorf("unable to seed the Zarf Registry: %w", err)
+		}
+	}
+
+	// The permanent registry pulls its own image, so make sure no node can garbage collect it
+	if config.IsZarfInitConfig() && component.Name == "zarf-registry" {
+		verifyRegistryImageKept()
+	}
+
+	return &deployedComponent, nil
+}
+
+// isUsingExistingRegistry returns whether zarf init was given a registry to use instead of deploying one
+// External registries and registries already running in the cluster aren't deployed by Zarf
+func isUsingExistingRegistry() bool {
+	return config.InitOptions.RegistryInfo.Address != "" || config.InitOptions.RegistryInfo.InClusterService != ""
+}
+
+// isInternalRegistryComponent returns whether a component of the init package stands up the internal registry
+// TODO: Figure out a better way to do this (I don't like how these components are still `required` according to the yaml definition)
+func isInternalRegistryComponent(component types.ZarfComponent) bool {
+	return config.IsZarfInitConfig() &&
+		(component.Name == "zarf-seed-registry" || component.Name == "zarf-injector" || component.Name == "zarf-registry")
+}
+
+// skipsForExistingRegistry returns whether a component isn't deployed because zarf init was given an existing registry
+func skipsForExistingRegistry(component types.ZarfComponent) bool {
+	return isUsingExistingRegistry() && isInternalRegistryComponent(component)
+}
+
 // Deploy a Zarf Component
-func deployComponent(tempPath tempPaths, component types.ZarfComponent, addShasumToImgs bool) []types.InstalledChart {
-	var installedCharts []types.InstalledChart
+func deployComponent(tempPath tempPaths, component types.ZarfComponent, addShasumToImgs bool) types.DeployedComponent {
+	deployedComponent := types.DeployedComponent{Name: component.Name}
 	message.Debugf("packager.deployComponent(%#v, %#v", tempPath, component)
 
 	// Toggles for general deploy operations
 	componentPath := createComponentPaths(tempPath.components, component)
 
+	// Fill in the values this component imports from the components deployed before it
+	component = applyComponentImports(component)
+	component.NamespaceGuardrails = templateNamespaceGuardrails(component)
+
 	// All components now require a name
 	message.HeaderInfof("📦 %s COMPONENT", strings.ToUpper(component.Name))
 
-	hasImages := len(component.Images) > 0
+	hasImages := len(component.Images) > 0 || len(component.ArchImages) > 0
 	hasCharts := len(component.Charts) > 0
 	hasManifests := len(component.Manifests) > 0
 	hasRepos := len(component.Repos) > 0
+	hasArtifacts := len(component.Artifacts) > 0
 	hasDataInjections := len(component.DataInjections) > 0
 
 	// Run the 'before' scripts and move files before we do anything else
-	runComponentScripts(component.Scripts.Before, component.Scripts)
-	processComponentFiles(component.Files, componentPath.files, tempPath.base)
+	runComponentScripts(component.Scripts.Before, component, componentPath)
+	deployedComponent.Files = processComponentFiles(component.Files, componentPath.files, tempPath.base)
+	installComponentBinaries(component, componentPath.binaries)
 
 	// Generate a value template
-	valueTemplate = template.Generate()
-	if !valueTemplate.Ready() && (hasImages || hasCharts || hasManifests || hasRepos) {
+	valueTemplate := template.Generate()
+	if !valueTemplate.Ready() && (hasImages || hasCharts || hasManifests || hasRepos || hasArtifacts) {
 		valueTemplate = getUpdatedValueTemplate(component)
 	}
 
 	/* Install all the parts of the component */
+	// Differential packages don't carry what was already shipped with the reference package
+	buildData := config.GetBuildData()
+	componentImages := withoutDifferentialMissing(getComponentImages(component), buildData.DifferentialMissingImages)
+	componentRepos := withoutDifferentialMissing(component.Repos, buildData.DifferentialMissingRepos)
+
 	if hasImages {
-		pushImagesToRegistry(tempPath, component.Images, addShasumToImgs)
+		deployedComponent.Images = pushImagesToRegistry(tempPath, componentImages, addShasumToImgs, pullsThroughRegistryProxy(component))
+		prePullLargeImages(tempPath, component.Name, deployedComponent.Images)
+	}
+
+	if hasArtifacts {
+		deployedComponent.Artifacts = pushArtifactsToRegistry(componentPath.artifacts, component.Artifacts)
 	}
 
 	if hasRepos {
-		pushReposToRepository(componentPath.repos, component.Repos)
+		pushReposToRepository(componentPath.repos, componentRepos)
 	}
 
 	if hasDataInjections {
+		deployedComponent.DataInjectionMarker = config.GetDataInjectionMarker()
 		waitGroup := sync.WaitGroup{}
 		defer waitGroup.Wait()
 		performDataInjections(&waitGroup, componentPath, component.DataInjections)
 	}
 
 	if hasCharts || hasManifests {
-		installedCharts = installChartAndManifests(componentPath, component)
+		chartsStart := time.Now()
+		deployedComponent.InstalledCharts = installChartAndManifests(componentPath, component, valueTemplate)
+		recordPhaseDuration(DeployPhaseCharts, chartsStart)
 	}
 
 	// Run the 'after' scripts after all other attributes of the component has been deployed
-	runComponentScripts(component.Scripts.After, component.Scripts)
+	runComponentScripts(component.Scripts.After, component, componentPath)
 
-	return installedCharts
+	// Publish this component's exports now that everything they could depend on is in place
+	deployedComponent.Exports = collectComponentExports(component)
+
+	return deployedComponent
+}
+
+// getComponentImages returns the component's images along with any images specific to the cluster architecture
+func getComponentImages(component types.ZarfComponent) []string {
+	componentImages := append([]string{}, component.Images...)
+
+	arch := config.GetState().Architecture
+	if arch == "" {
+		arch = config.GetArch()
+	}
+
+	return append(componentImages, component.ArchImages[arch]...)
 }
 
-// Run scripts that a component has provided
-func runComponentScripts(scripts []string, componentScript types.ZarfComponentScripts) {
+// Run scripts that a component has provided, as its temporary service account if it has one
+func runComponentScripts(scripts []string, component types.ZarfComponent, componentPath componentPaths) {
+	if len(scripts) == 0 {
+		return
+	}
+
+	var env []string
+	if component.Scripts.ServiceAccount.Namespace != "" {
+		var cleanup func()
+		env, cleanup = startScriptServiceAccount(component, scripts, componentPath)
+		defer cleanup()
+	}
+
 	for _, script := range scripts {
-		loopScriptUntilSuccess(script, componentScript)
+		loopScriptUntilSuccess(script, component.Scripts, "", env)
 	}
 }
 
 // Move files onto the host of the machine performing the deployment
-func processComponentFiles(componentFiles []types.ZarfFile, sourceLocation, tempPathBase string) {
-	var spinner message.Spinner
-	if len(componentFiles) > 0 {
-		spinner = *message.NewProgressSpinner("Copying %d files", len(componentFiles))
-		defer spinner.Stop()
+// Everything is staged and verified first so a failure part way through leaves the host as it was
+func processComponentFiles(componentFiles []types.ZarfFile, sourceLocation, tempPathBase string) []types.DeployedFile {
+	if len(componentFiles) == 0 {
+		return nil
+	}
+
+	spinner := message.NewProgressSpinner("Copying %d files", len(componentFiles))
+	defer spinner.Stop()
+
+	transaction := hostFileTransaction{}
+	fail := func(err error, format string, a ...any) {
+		transaction.rollback()
+		spinner.Fatalf(err, format, a...)
 	}
 
 	for index, file := range componentFiles {
 		spinner.Updatef("Loading %s", file.Target)
 		sourceFile := filepath.Join(sourceLocation, strconv.Itoa(index))
 
-		// If a shasum is specified check it again on deployment as well
-		if file.Shasum != "" {
-			spinner.Updatef("Validating SHASUM for %s", file.Target)
-			utils.ValidateSha256Sum(file.Shasum, sourceFile)
-		}
-
-		// Replace temp target directories
+		// Replace temp target directories, these are cleaned up with the deployment so they are not recorded
+		isTempTarget := strings.Contains(file.Target, "###ZARF_TEMP###")
 		file.Target = strings.Replace(file.Target, "###ZARF_TEMP###", tempPathBase, 1)
 
-		// Copy the file to the destination
-		spinner.Updatef("Saving %s", file.Target)
-		err := copy.Copy(sourceFile, file.Target)
-		if err != nil {
-			spinner.Fatalf(err, "Unable to copy the contents of %s", file.Target)
+		// Stage the file next to its target
+		spinner.Updatef("Staging %s", file.Target)
+		if err := transaction.stageFile(sourceFile, file.Target, file.Shasum, !isTempTarget); err != nil {
+			fail(err, "Unable to stage the contents of %s", file.Target)
 		}
 
-		// Loop over all symlinks and create them
+		// Stage all of the symlinks to it
 		for _, link := range file.Symlinks {
-			spinner.Updatef("Adding symlink %s->%s", link, file.Target)
-			// Try to remove the filepath if it exists
-			_ = os.RemoveAll(link)
-			// Make sure the parent directory exists
-			_ = utils.CreateFilePath(link)
-			// Create the symlink
-			err := os.Symlink(file.Target, link)
-			if err != nil {
-				spinner.Fatalf(err, "Unable to create the symbolic link %s -> %s", link, file.Target)
+			spinner.Updatef("Staging symlink %s->%s", link, file.Target)
+			if err := transaction.stageSymlink(link, file.Target); err != nil {
+				fail(err, "Unable to stage the symbolic link %s -> %s", link, file.Target)
 			}
 		}
 
 		// Cleanup now to reduce disk pressure
 		_ = os.RemoveAll(sourceFile)
 	}
+
+	// If a shasum is specified check it again on deployment as well
+	spinner.Updatef("Validating the staged files")
+	if err := transaction.verify(); err != nil {
+		fail(err, "Unable to validate the staged files: %s", err.Error())
+	}
+
+	spinner.Updatef("Moving the staged files into place")
+	if err := transaction.commit(); err != nil {
+		fail(err, "Unable to move the staged files into place, all changes were rolled back: %s", err.Error())
+	}
+
 	spinner.Success()
 
+	return transaction.finish()
 }
 
 // Fetch the current ZarfState from the k8s cluster and generate a valueTemplate from the state values
@@ -324,27 +609,131 @@ func getUpdatedValueTemplate(component types.ZarfComponent) template.Values {
 			state.Architecture)
 	}
 
+	if err := validateClusterArchitecture(); err != nil {
+		spinner.Fatalf(err, err.Error())
+	}
+
 	spinner.Success()
 
+	checkClockAndCertificates(state)
+
 	return valueTemplate
 }
 
+// validateClusterArchitecture makes sure a package with images was built for the architecture of at least one node in the cluster
+// Packages without images carry nothing architecture specific for the cluster, so they deploy anywhere
+func validateClusterArchitecture() error {
+	hasImages := false
+	for _, component := range config.GetComponents() {
+		hasImages = hasImages || len(component.Images) > 0 || len(component.ArchImages) > 0
+	}
+	if !hasImages {
+		return nil
+	}
+
+	architectures, err := k8s.GetArchitectures()
+	if err != nil {
+		message.Debugf("Unable to read the node architectures: %s", err.Error())
+		return nil
+	}
+
+	packageArch := config.GetArch()
+	for _, arch := range architectures {
+		if arch == packageArch {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("this package was built for %s, but the cluster nodes are %s, create the package again with --architecture %s",
+		packageArch, strings.Join(architectures, ", "), architectures[0])
+}
+
+// pullsThroughRegistryProxy returns whether the images of a component come from the upstream of a Zarf registry running as a pull-through cache
+// The registry components themselves are pushed into the seed registry, which always takes pushes
+func pullsThroughRegistryProxy(component types.ZarfComponent) bool {
+	if config.GetContainerRegistryInfo().ProxyURL == "" {
+		return false
+	}
+	return !config.IsZarfInitConfig() || (component.Name != "zarf-seed-registry" && component.Name != "zarf-registry")
+}
+
 // Push all of the components images to the configured container registry
-func pushImagesToRegistry(tempPath tempPaths, componentImages []string, addShasumToImg bool) {
+// Through a pull-through cache the images are requested from its upstream registry instead
+func pushImagesToRegistry(tempPath tempPaths, componentImages []string, addShasumToImg bool, pullThroughProxy bool) []types.DeployedImage {
 	if len(componentImages) == 0 {
-		return
+		return nil
 	}
 
-	// Try image push up to 3 times
-	for retry := 0; retry < 3; retry++ {
-		if err := images.PushToZarfRegistry(tempPath.images, componentImages, addShasumToImg); err != nil {
-			message.Errorf(err, "Unable to push images to the Registry, retrying in 5 seconds...")
-			time.Sleep(5 * time.Second)
-			continue
+	// Registry pods that lost their own image can't take pushes, and only zarf init can seed the image again
+	if !config.IsZarfInitConfig() && config.GetContainerRegistryInfo().InternalRegistry {
+		if err := checkRegistryImagePulls(); err != nil {
+			message.Warnf("Unable to start every Zarf registry pod: %s", err.Error())
+		}
+	}
+
+	// Retries only push the images that failed in the attempts before them
+	pushed := make(map[string]types.DeployedImage)
+	err := runDeployPhase(DeployPhaseImages, func(ctx context.Context) error {
+		var remaining []string
+		for _, image := range componentImages {
+			if _, ok := pushed[image]; !ok {
+				remaining = append(remaining, image)
+			}
+		}
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		var pushedImages []types.DeployedImage
+		var err error
+		if pullThroughProxy {
+			pushedImages, err = images.PullThroughZarfRegistry(ctx, tempPath.images, remaining)
 		} else {
-			break
+			pushedImages, err = images.PushToZarfRegistry(ctx, tempPath.images, remaining, addShasumToImg)
+		}
+
+		for _, image := range pushedImages {
+			pushed[image.Source] = image
+		}
+		return err
+	})
+	if err != nil {
+		message.Fatalf(err, "Unable to push images to the Registry")
+	}
+
+	var pushedImages []types.DeployedImage
+	for _, image := range componentImages {
+		if pushedImage, ok := pushed[image]; ok {
+			pushedImages = append(pushedImages, pushedImage)
+			delete(pushed, image)
 		}
 	}
+
+	// A pull-through cache can't take the signatures, the upstream registry has to carry them
+	if !pullThroughProxy {
+		pushImageSignatures(tempPath, pushedImages)
+	}
+	return pushedImages
+}
+
+// Push the OCI artifacts of a component to the configured container registry so the controllers that reference them can pull them
+// A pull-through cache fetches them from their source the first time they are pulled instead
+func pushArtifactsToRegistry(artifactsPath string, artifacts []types.ZarfArtifact) []types.DeployedImage {
+	if config.GetContainerRegistryInfo().ProxyURL != "" {
+		message.Debugf("Not pushing %d OCI artifacts to the pull-through cache", len(artifacts))
+		return nil
+	}
+
+	var pushedArtifacts []types.DeployedImage
+	err := runDeployPhase(DeployPhaseImages, func(ctx context.Context) error {
+		var err error
+		pushedArtifacts, err = images.PushArtifactsToZarfRegistry(ctx, artifactsPath, artifacts)
+		return err
+	})
+	if err != nil {
+		message.Fatalf(err, "Unable to push the OCI artifacts to the Registry")
+	}
+	return pushedArtifacts
 }
 
 // Push all of the components git repos to the configured git server
@@ -353,16 +742,12 @@ func pushReposToRepository(reposPath string, repos []string) {
 		return
 	}
 
-	// Try repo push up to 3 times
-	for retry := 0; retry < 3; retry++ {
-		// Push all the repos from the extracted archive
-		if err := git.PushAllDirectories(reposPath); err != nil {
-			message.Errorf(err, "Unable to push repos to the Git Server, retrying in 5 seconds...")
-			time.Sleep(5 * time.Second)
-			continue
-		} else {
-			break
-		}
+	// Push all the repos from the extracted archive
+	err := runDeployPhase(DeployPhaseRepos, func(ctx context.Context) error {
+		return git.PushAllDirectories(ctx, reposPath, repos)
+	})
+	if err != nil {
+		message.Fatalf(err, "Unable to push repos to the Git Server")
 	}
 }
 
@@ -379,7 +764,7 @@ func performDataInjections(waitGroup *sync.WaitGroup, componentPath componentPat
 }
 
 // Install all Helm charts and raw k8s manifests into the k8s cluster
-func installChartAndManifests(componentPath componentPaths, component types.ZarfComponent) []types.InstalledChart {
+func installChartAndManifests(componentPath componentPaths, component types.ZarfComponent, valueTemplate template.Values) []types.InstalledChart {
 	installedCharts := []types.InstalledChart{}
 
 	for _, chart := range component.Charts {
@@ -394,6 +779,9 @@ func installChartAndManifests(componentPath componentPaths, component types.Zarf
 			BasePath:  componentPath.base,
 			Chart:     chart,
 			Component: component,
+			Attempts:  getPhaseRetries(DeployPhaseCharts) + 1,
+			Deadline:  getPhaseDeadline(DeployPhaseCharts),
+			Timeout:   getPhaseTimeout(DeployPhaseCharts),
 		})
 		installedCharts = append(installedCharts, types.InstalledChart{Namespace: chart.Namespace, ChartName: installedChartName})
 
@@ -416,7 +804,8 @@ func installChartAndManifests(componentPath componentPaths, component types.Zarf
 		}
 
 		// Iterate over any connectStrings and add to the main map
-		addedConnectStrings, installedChartName := helm.GenerateChart(componentPath.manifests, manifest, component)
+		addedConnectStrings, installedChartName := helm.GenerateChart(componentPath.manifests, manifest, component,
+			getPhaseRetries(DeployPhaseCharts)+1, getPhaseDeadline(DeployPhaseCharts), getPhaseTimeout(DeployPhaseCharts))
 		installedCharts = append(installedCharts, types.InstalledChart{Namespace: manifest.Namespace, ChartName: installedChartName})
 
 		// Iterate over any connectStrings and add to the main map
@@ -497,11 +886,44 @@ func printTablesForDeployment(componentsToDeploy []types.ZarfComponent) {
 	}
 }
 
+// saveDeployedPackage writes the secret that describes the package and the components deployed from it
+func saveDeployedPackage(deployedComponents []types.DeployedComponent) error {
+	secretName := fmt.Sprintf("zarf-package-%s", config.GetActiveConfig().Metadata.Name)
+	deployedPackageSecret := k8s.GenerateSecret("zarf", secretName, corev1.SecretTypeOpaque)
+	deployedPackageSecret.Labels["package-deploy-info"] = config.GetActiveConfig().Metadata.Name
+
+	installedZarfPackage := types.DeployedPackage{
+		Name:               config.GetActiveConfig().Metadata.Name,
+		CLIVersion:         config.CLIVersion,
+		DeployedAt:         time.Now().Format(time.RFC1123Z),
+		Data:               config.GetActiveConfig(),
+		DeployedComponents: deployedComponents,
+		ConnectStrings:     connectStrings,
+
+		ImagePolicyViolations: imagePolicyViolations,
+	}
+
+	// Sensitive exports are encrypted when the Zarf state is
+	if err := k8s.EncryptDeployedPackage(&installedZarfPackage); err != nil {
+		return err
+	}
+
+	stateData, err := json.Marshal(installedZarfPackage)
+	if err != nil {
+		return err
+	}
+	deployedPackageSecret.Data = map[string][]byte{"data": stateData}
+
+	return k8s.ReplaceSecret(deployedPackageSecret)
+}
+
 func packageUsesK8s() bool {
 	for _, component := range config.GetComponents() {
 		// If the component is using anything that depends on the cluster, return true
 		if len(component.Charts) > 0 ||
 			len(component.Images) > 0 ||
+			len(component.ArchImages) > 0 ||
+			len(component.Artifacts) > 0 ||
 			len(component.Repos) > 0 ||
 			len(component.Manifests) > 0 {
 			return true
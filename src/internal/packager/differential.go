@@ -0,0 +1,194 @@
+package packager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/mholt/archiver/v3"
+	"k8s.io/utils/strings/slices"
+)
+
+// differentialData tracks the images and repos a differential package leaves out because the reference package already has them
+type differentialData struct {
+	images map[string]bool
+	repos  map[string]bool
+}
+
+// loadDifferentialData compares the components against a previously built package to find the images and repos that have not changed
+func loadDifferentialData(referencePath string, components []types.ZarfComponent) (differentialData, error) {
+	message.Debugf("packager.loadDifferentialData(%s)", referencePath)
+
+	diff := differentialData{images: make(map[string]bool), repos: make(map[string]bool)}
+
+	if utils.InvalidPath(referencePath) {
+		return diff, fmt.Errorf("unable to find the reference package at %s", referencePath)
+	}
+
+	spinner := message.NewProgressSpinner("Comparing against the reference package %s", referencePath)
+	defer spinner.Stop()
+
+	tempPath := createPaths()
+	defer tempPath.clean()
+
+	// Only the package config and image store are needed from the reference package
+	if err := archiver.Extract(referencePath, filepath.Base(tempPath.zarfYaml), tempPath.base); err != nil {
+		return diff, fmt.Errorf("unable to extract the zarf.yaml from the reference package: %w", err)
+	}
+
+	var referencePackage types.ZarfPackage
+	if err := utils.ReadYaml(tempPath.zarfYaml, &referencePackage); err != nil {
+		return diff, fmt.Errorf("unable to read the zarf.yaml from the reference package: %w", err)
+	}
+
+	if referencePackage.Metadata.Name != config.GetMetaData().Name {
+		return diff, fmt.Errorf("the reference package is for %s, not %s", referencePackage.Metadata.Name, config.GetMetaData().Name)
+	}
+
+	var referenceRepos []string
+	for _, component := range referencePackage.Components {
+		referenceRepos = append(referenceRepos, component.Repos...)
+	}
+
+	// Repos pinned to a ref that the reference package already carried don't need to be shipped again
+	for _, component := range components {
+		for _, repo := range component.Repos {
+			if strings.Contains(repo, "@") && slices.Contains(referenceRepos, repo) {
+				diff.repos[repo] = true
+			}
+		}
+	}
+
+	// A multi-arch package has an image tarball for each architecture, which are compared with the platform of that architecture
+	// An image is only left out when it is unchanged for every architecture that pulls it
+	changed := make(map[string]bool)
+	var compareErr error
+	forEachArch(tempPath, func(arch string, archPath tempPaths) {
+		if compareErr != nil {
+			return
+		}
+
+		referenceDigests, err := loadReferenceDigests(referencePath, archPath)
+		if err != nil {
+			compareErr = err
+			return
+		}
+
+		archImages := getArchImageList(components, arch, differentialData{})
+		if !config.IsMultiArch() {
+			archImages = nil
+			for _, component := range components {
+				archImages = append(archImages, getAllComponentImages(component)...)
+			}
+		}
+
+		// Images are unchanged when the config digest upstream matches the one already in the reference package
+		for _, image := range archImages {
+			if changed[image] || diff.images[image] {
+				continue
+			}
+
+			tag, err := images.GetTarballTag(image)
+			if err != nil {
+				compareErr = fmt.Errorf("unable to parse the image %s: %w", image, err)
+				return
+			}
+			referenceDigest, ok := referenceDigests[tag.Name()]
+			if !ok {
+				changed[image] = true
+				continue
+			}
+
+			spinner.Updatef("Comparing image %s for %s", image, arch)
+			digest, err := images.GetConfigDigest(image)
+			if err != nil {
+				compareErr = fmt.Errorf("unable to get the digest for %s: %w", image, err)
+				return
+			}
+			if digest != referenceDigest {
+				changed[image] = true
+			}
+		}
+	})
+	if compareErr != nil {
+		return diff, compareErr
+	}
+
+	for _, component := range components {
+		for _, image := range getAllComponentImages(component) {
+			if !changed[image] {
+				diff.images[image] = true
+			}
+		}
+	}
+
+	// Record what was left out so deploy knows to expect it in the cluster already
+	build := config.GetBuildData()
+	build.DifferentialPackageVersion = referencePackage.Metadata.Version
+	for image := range diff.images {
+		build.DifferentialMissingImages = append(build.DifferentialMissingImages, image)
+	}
+	for repo := range diff.repos {
+		build.DifferentialMissingRepos = append(build.DifferentialMissingRepos, repo)
+	}
+	sort.Strings(build.DifferentialMissingImages)
+	sort.Strings(build.DifferentialMissingRepos)
+	config.SetBuildData(build)
+
+	spinner.Successf("Leaving out %d unchanged images and %d unchanged repos", len(diff.images), len(diff.repos))
+	return diff, nil
+}
+
+// loadReferenceDigests returns the config digests of the images in the reference package tarball, keyed by their normalized tag
+func loadReferenceDigests(referencePath string, archPath tempPaths) (map[string]string, error) {
+	referenceDigests := make(map[string]string)
+	if err := archiver.Extract(referencePath, filepath.Base(archPath.images), archPath.base); err != nil {
+		message.Debugf("The reference package has no %s: %s", filepath.Base(archPath.images), err.Error())
+		return referenceDigests, nil
+	}
+
+	manifest, err := tarball.LoadManifest(func() (io.ReadCloser, error) { return os.Open(archPath.images) })
+	if err != nil {
+		return referenceDigests, fmt.Errorf("unable to read the images in the reference package: %w", err)
+	}
+	for _, descriptor := range manifest {
+		for _, repoTag := range descriptor.RepoTags {
+			tag, err := name.NewTag(repoTag)
+			if err != nil {
+				return referenceDigests, fmt.Errorf("unable to parse the reference image %s: %w", repoTag, err)
+			}
+			referenceDigests[tag.Name()] = descriptor.Config
+		}
+	}
+	return referenceDigests, nil
+}
+
+// getAllComponentImages returns the component images along with the images for every architecture
+func getAllComponentImages(component types.ZarfComponent) []string {
+	componentImages := append([]string{}, component.Images...)
+	for _, archImages := range component.ArchImages {
+		componentImages = append(componentImages, archImages...)
+	}
+	return componentImages
+}
+
+// withoutDifferentialMissing drops the items a differential package left out because they shipped with the reference package
+func withoutDifferentialMissing(items []string, missing []string) []string {
+	var included []string
+	for _, item := range items {
+		if !slices.Contains(missing, item) {
+			included = append(included, item)
+		}
+	}
+	return included
+}
@@ -0,0 +1,248 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/helm"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// resourceEstimate adds up the requests of the resources a component creates
+type resourceEstimate struct {
+	cpu     resource.Quantity
+	memory  resource.Quantity
+	storage resource.Quantity
+}
+
+// estimateComponentResources renders the packaged charts and manifests of a component and adds up what they request from the cluster
+// Resources that can't be rendered or parsed are left out of the estimate with a warning rather than failing the create
+func estimateComponentResources(tempPath tempPaths, component types.ZarfComponent) types.ZarfResourceEstimate {
+	message.Debugf("packager.estimateComponentResources(%s)", component.Name)
+
+	componentPath := createComponentPaths(tempPath.components, component)
+
+	var resources []*unstructured.Unstructured
+	for _, chart := range component.Charts {
+		template, err := helm.TemplateChart(helm.ChartOptions{
+			BasePath:  componentPath.base,
+			Chart:     chart,
+			Component: component,
+		})
+		if err != nil {
+			message.Warnf("Unable to render the chart %s to estimate its resources: %s", chart.Name, err.Error())
+			continue
+		}
+		yamls, _ := k8s.SplitYAML([]byte(template))
+		resources = append(resources, yamls...)
+	}
+
+	if len(component.Manifests) > 0 {
+		_ = filepath.Walk(componentPath.manifests, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				message.Warnf("Unable to read the manifest %s to estimate its resources: %s", path, err.Error())
+				return nil
+			}
+			yamls, _ := k8s.SplitYAML(contents)
+			resources = append(resources, yamls...)
+			return nil
+		})
+	}
+
+	var estimate resourceEstimate
+	for _, object := range resources {
+		if err := estimate.add(object); err != nil {
+			message.Debugf("Leaving %s %s out of the resource estimate: %s", object.GetKind(), object.GetName(), err.Error())
+		}
+	}
+
+	return types.ZarfResourceEstimate{
+		CPU:     formatQuantity(estimate.cpu),
+		Memory:  formatQuantity(estimate.memory),
+		Storage: formatQuantity(estimate.storage),
+	}
+}
+
+// add counts the requests of one resource, multiplying pod templates by their replicas
+// DaemonSets are counted once since the number of nodes isn't known until deploy
+func (e *resourceEstimate) add(object *unstructured.Unstructured) error {
+	contents := object.UnstructuredContent()
+
+	switch object.GetKind() {
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &deployment); err != nil {
+			return fmt.Errorf("could not parse deployment: %w", err)
+		}
+		e.addPod(deployment.Spec.Template.Spec, getReplicas(deployment.Spec.Replicas))
+
+	case "StatefulSet":
+		var statefulSet appsv1.StatefulSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &statefulSet); err != nil {
+			return fmt.Errorf("could not parse statefulset: %w", err)
+		}
+		replicas := getReplicas(statefulSet.Spec.Replicas)
+		e.addPod(statefulSet.Spec.Template.Spec, replicas)
+		for _, claim := range statefulSet.Spec.VolumeClaimTemplates {
+			e.addClaim(claim.Spec, replicas)
+		}
+
+	case "ReplicaSet":
+		var replicaSet appsv1.ReplicaSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &replicaSet); err != nil {
+			return fmt.Errorf("could not parse replicaset: %w", err)
+		}
+		e.addPod(replicaSet.Spec.Template.Spec, getReplicas(replicaSet.Spec.Replicas))
+
+	case "DaemonSet":
+		var daemonSet appsv1.DaemonSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &daemonSet); err != nil {
+			return fmt.Errorf("could not parse daemonset: %w", err)
+		}
+		e.addPod(daemonSet.Spec.Template.Spec, 1)
+
+	case "Job":
+		var job batchv1.Job
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &job); err != nil {
+			return fmt.Errorf("could not parse job: %w", err)
+		}
+		e.addPod(job.Spec.Template.Spec, getReplicas(job.Spec.Parallelism))
+
+	case "CronJob":
+		var cronJob batchv1.CronJob
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &cronJob); err != nil {
+			return fmt.Errorf("could not parse cronjob: %w", err)
+		}
+		e.addPod(cronJob.Spec.JobTemplate.Spec.Template.Spec, getReplicas(cronJob.Spec.JobTemplate.Spec.Parallelism))
+
+	case "Pod":
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &pod); err != nil {
+			return fmt.Errorf("could not parse pod: %w", err)
+		}
+		e.addPod(pod.Spec, 1)
+
+	case "PersistentVolumeClaim":
+		var claim corev1.PersistentVolumeClaim
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(contents, &claim); err != nil {
+			return fmt.Errorf("could not parse persistentvolumeclaim: %w", err)
+		}
+		e.addClaim(claim.Spec, 1)
+	}
+
+	return nil
+}
+
+// addPod counts the requests of a pod the way the scheduler does, the larger of its containers combined or its largest init container
+func (e *resourceEstimate) addPod(pod corev1.PodSpec, replicas int64) {
+	var cpu, memory resource.Quantity
+	for _, container := range pod.Containers {
+		cpu.Add(*container.Resources.Requests.Cpu())
+		memory.Add(*container.Resources.Requests.Memory())
+	}
+	for _, container := range pod.InitContainers {
+		if initCPU := container.Resources.Requests.Cpu(); initCPU.Cmp(cpu) > 0 {
+			cpu = initCPU.DeepCopy()
+		}
+		if initMemory := container.Resources.Requests.Memory(); initMemory.Cmp(memory) > 0 {
+			memory = initMemory.DeepCopy()
+		}
+	}
+
+	for i := int64(0); i < replicas; i++ {
+		e.cpu.Add(cpu)
+		e.memory.Add(memory)
+	}
+}
+
+// addClaim counts the storage requested by a volume claim
+func (e *resourceEstimate) addClaim(claim corev1.PersistentVolumeClaimSpec, replicas int64) {
+	storage := claim.Resources.Requests.Storage()
+	for i := int64(0); i < replicas; i++ {
+		e.storage.Add(*storage)
+	}
+}
+
+// getReplicas returns the replica count of a workload, which defaults to one when it isn't set
+func getReplicas(replicas *int32) int64 {
+	if replicas == nil {
+		return 1
+	}
+	return int64(*replicas)
+}
+
+// formatQuantity returns the quantity as a string, leaving out ones that are zero
+func formatQuantity(quantity resource.Quantity) string {
+	if quantity.IsZero() {
+		return ""
+	}
+	return quantity.String()
+}
+
+// recordResourceEstimate saves the estimate of a component in the build data of the package
+func recordResourceEstimate(componentName string, estimate types.ZarfResourceEstimate) {
+	build := config.GetBuildData()
+	if estimate == (types.ZarfResourceEstimate{}) {
+		delete(build.ResourceEstimates, componentName)
+	} else {
+		if build.ResourceEstimates == nil {
+			build.ResourceEstimates = make(map[string]types.ZarfResourceEstimate)
+		}
+		build.ResourceEstimates[componentName] = estimate
+	}
+	config.SetBuildData(build)
+}
+
+// printResourceEstimateTable shows what each component of the package and the package as a whole request from the cluster
+func printResourceEstimateTable() {
+	estimates := config.GetBuildData().ResourceEstimates
+	if len(estimates) == 0 {
+		return
+	}
+
+	var componentNames []string
+	for name := range estimates {
+		componentNames = append(componentNames, name)
+	}
+	sort.Strings(componentNames)
+
+	var total resourceEstimate
+	table := pterm.TableData{{"     Component", "CPU Requests", "Memory Requests", "Storage"}}
+	for _, name := range componentNames {
+		estimate := estimates[name]
+		table = append(table, []string{"     " + name, estimate.CPU, estimate.Memory, estimate.Storage})
+		addQuantity(&total.cpu, estimate.CPU)
+		addQuantity(&total.memory, estimate.Memory)
+		addQuantity(&total.storage, estimate.Storage)
+	}
+	table = append(table, []string{"     Total", formatQuantity(total.cpu), formatQuantity(total.memory), formatQuantity(total.storage)})
+
+	message.Note("Estimated cluster resources requested by this package (DaemonSets are counted once, not per node):")
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
+
+// addQuantity adds a recorded quantity to the total, skipping ones that can't be parsed
+func addQuantity(total *resource.Quantity, value string) {
+	if value == "" {
+		return
+	}
+	if quantity, err := resource.ParseQuantity(value); err == nil {
+		total.Add(quantity)
+	}
+}
@@ -0,0 +1,209 @@
+package packager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/template"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// validateImports makes sure every value the components import is exported by a component that finishes deploying before them
+// Components finished by an earlier attempt are passed in as resumedComponents and their exports are always available
+func validateImports(components []types.ZarfComponent, resumedComponents []types.DeployedComponent) error {
+	message.Debugf("packager.validateImports(%#v, %#v)", components, resumedComponents)
+
+	exporters := make(map[string]string)
+	finished := make(map[string]bool)
+	for _, component := range resumedComponents {
+		finished[component.Name] = true
+		for name := range component.Exports {
+			exporters[name] = component.Name
+		}
+	}
+
+	dependencies := getComponentDependencies(components)
+	for _, component := range components {
+		for _, export := range component.Exports {
+			exporters[export.Name] = component.Name
+		}
+	}
+
+	var problems []string
+	for _, component := range components {
+		for _, name := range component.Imports {
+			exporter, ok := exporters[name]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("%s imports %s which none of the selected components export", component.Name, name))
+			case finished[exporter]:
+				continue
+			case exporter == component.Name:
+				problems = append(problems, fmt.Sprintf("%s imports %s which it exports itself", component.Name, name))
+			case !dependsOn(dependencies, component.Name, exporter):
+				problems = append(problems, fmt.Sprintf("%s imports %s from %s which doesn't deploy before it", component.Name, name, exporter))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("the component imports can't be satisfied: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// dependsOn returns true if the component depends on the other component directly or through its dependencies
+func dependsOn(dependencies map[string][]string, component string, other string) bool {
+	visited := make(map[string]bool)
+	pending := append([]string{}, dependencies[component]...)
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if name == other {
+			return true
+		}
+		if !visited[name] {
+			visited[name] = true
+			pending = append(pending, dependencies[name]...)
+		}
+	}
+	return false
+}
+
+// restoreExports makes the values exported by components an earlier attempt finished available to the pending ones
+// The values of sensitive exports are hidden again, since the attempt that exported them was another run
+func restoreExports(resumedComponents []types.DeployedComponent, components []types.ZarfComponent) {
+	sensitive := getSensitiveExports(components)
+	for _, component := range resumedComponents {
+		for name, value := range component.Exports {
+			if sensitive[name] {
+				message.AddSensitiveValue(value)
+			}
+			config.SetExportedValue(name, value)
+		}
+	}
+}
+
+// getSensitiveExports returns the names of the values the components export as sensitive
+func getSensitiveExports(components []types.ZarfComponent) map[string]bool {
+	sensitive := make(map[string]bool)
+	for _, component := range components {
+		for _, export := range component.Exports {
+			if export.Sensitive {
+				sensitive[export.Name] = true
+			}
+		}
+	}
+	return sensitive
+}
+
+// applyComponentImports templates the package variables, constants and the values the component imports into its scripts, exports, files and data injections
+// The source of a data injection is packaged under the name of its path, so the path itself isn't templated
+func applyComponentImports(component types.ZarfComponent) types.ZarfComponent {
+	apply := func(text string) string {
+		return template.ApplyComponentVariables(text, component.Imports)
+	}
+
+	scripts := component.Scripts
+	scripts.Before = make([]string, len(component.Scripts.Before))
+	for idx, script := range component.Scripts.Before {
+		scripts.Before[idx] = apply(script)
+	}
+	scripts.After = make([]string, len(component.Scripts.After))
+	for idx, script := range component.Scripts.After {
+		scripts.After[idx] = apply(script)
+	}
+	component.Scripts = scripts
+
+	exports := make([]types.ZarfComponentExport, len(component.Exports))
+	for idx, export := range component.Exports {
+		export.Value = apply(export.Value)
+		export.Script = apply(export.Script)
+		exports[idx] = export
+	}
+	component.Exports = exports
+
+	files := make([]types.ZarfFile, len(component.Files))
+	for idx, file := range component.Files {
+		file.Target = apply(file.Target)
+		symlinks := make([]string, len(file.Symlinks))
+		for linkIdx, link := range file.Symlinks {
+			symlinks[linkIdx] = apply(link)
+		}
+		file.Symlinks = symlinks
+		files[idx] = file
+	}
+	component.Files = files
+
+	dataInjections := make([]types.ZarfDataInjection, len(component.DataInjections))
+	for idx, data := range component.DataInjections {
+		data.Target.Namespace = apply(data.Target.Namespace)
+		data.Target.Selector = apply(data.Target.Selector)
+		data.Target.Container = apply(data.Target.Container)
+		dataInjections[idx] = data
+	}
+	component.DataInjections = dataInjections
+
+	return component
+}
+
+// collectComponentExports resolves the values the component exports and publishes them for the components deployed after it
+func collectComponentExports(component types.ZarfComponent) map[string]string {
+	if len(component.Exports) == 0 {
+		return nil
+	}
+
+	exported := make(map[string]string)
+	for _, export := range component.Exports {
+		value := export.Value
+
+		if export.Script != "" {
+			output, err := runExportScript(export.Script, component.Scripts)
+			if err != nil {
+				message.Fatalf(err, "Unable to get the value of %s exported by the %s component", export.Name, component.Name)
+			}
+			value = output
+		}
+
+		if export.Sensitive {
+			message.AddSensitiveValue(value)
+		}
+
+		message.Debugf("The %s component exported %s", component.Name, export.Name)
+		config.SetExportedValue(export.Name, value)
+		exported[export.Name] = value
+	}
+
+	return exported
+}
+
+// runExportScript runs the script once and returns its trimmed output
+func runExportScript(script string, scripts types.ZarfComponentScripts) (string, error) {
+	// Default timeout is 5 minutes
+	if scripts.TimeoutSeconds < 1 {
+		scripts.TimeoutSeconds = 300
+	}
+
+	script, err := scriptMutation(script)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(scripts.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	shell, shellArgs := getShell()
+	output, errOut, err := utils.ExecCommandWithContext(ctx, false, shell, shellArgs, script)
+	if err != nil {
+		message.Debug(output, errOut)
+		return "", fmt.Errorf("script \"%s\" failed: %w", script, err)
+	}
+
+	return strings.TrimSpace(output), nil
+}
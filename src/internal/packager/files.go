@@ -0,0 +1,239 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/otiai10/copy"
+)
+
+// Suffixes of the paths kept next to a file while it is placed on the host
+const (
+	hostFileStagingSuffix  = ".zarf-staging"
+	hostFileRollbackSuffix = ".zarf-rollback"
+	hostFileBackupSuffix   = ".zarf-backup"
+)
+
+// previousHostFiles maps the host files an earlier deployment of this package placed to the backups of what they replaced
+var previousHostFiles = make(map[string]string)
+
+// hostFileChange is a file or symlink staged next to the path it will replace on the host
+type hostFileChange struct {
+	target    string
+	staged    string
+	rollback  string
+	shasum    string
+	link      string
+	record    bool
+	replaced  bool
+	committed bool
+}
+
+// hostFileTransaction places a component's files and symlinks on the host all together or not at all
+type hostFileTransaction struct {
+	changes []*hostFileChange
+}
+
+func (t *hostFileTransaction) add(target string, record bool) *hostFileChange {
+	change := &hostFileChange{
+		target:   target,
+		staged:   target + hostFileStagingSuffix,
+		rollback: target + hostFileRollbackSuffix,
+		record:   record,
+	}
+
+	// Clear out anything left behind by an interrupted deployment
+	_ = os.RemoveAll(change.staged)
+
+	t.changes = append(t.changes, change)
+	return change
+}
+
+// stageFile copies a file next to its target without touching the target yet
+func (t *hostFileTransaction) stageFile(source string, target string, shasum string, record bool) error {
+	change := t.add(target, record)
+	change.shasum = shasum
+	return copy.Copy(source, change.staged)
+}
+
+// stageSymlink creates a symlink next to where it will be placed without touching that path yet
+func (t *hostFileTransaction) stageSymlink(link string, target string) error {
+	change := t.add(link, true)
+	change.link = target
+	if err := utils.CreateFilePath(link); err != nil {
+		return err
+	}
+	return os.Symlink(target, change.staged)
+}
+
+// verify makes sure everything was staged intact before anything on the host is replaced
+func (t *hostFileTransaction) verify() error {
+	for _, change := range t.changes {
+		if _, err := os.Lstat(change.staged); err != nil {
+			return fmt.Errorf("unable to find the staged copy of %s: %w", change.target, err)
+		}
+
+		if change.shasum != "" {
+			shasum, err := utils.GetSha256Sum(change.staged)
+			if err != nil {
+				return fmt.Errorf("unable to compute the checksum of the staged copy of %s: %w", change.target, err)
+			}
+			if !strings.EqualFold(shasum, change.shasum) {
+				return fmt.Errorf("the staged copy of %s has the checksum %s, expected %s", change.target, shasum, change.shasum)
+			}
+		}
+	}
+	return nil
+}
+
+// commit moves each staged path into place, setting aside whatever was there so it can be restored
+func (t *hostFileTransaction) commit() error {
+	for _, change := range t.changes {
+		if _, err := os.Lstat(change.target); err == nil {
+			_ = os.RemoveAll(change.rollback)
+			if err := os.Rename(change.target, change.rollback); err != nil {
+				return fmt.Errorf("unable to set aside the existing %s: %w", change.target, err)
+			}
+			change.replaced = true
+		}
+
+		if err := os.Rename(change.staged, change.target); err != nil {
+			return fmt.Errorf("unable to move %s into place: %w", change.target, err)
+		}
+		change.committed = true
+	}
+	return nil
+}
+
+// rollback undoes every change in reverse order, restoring whatever each one replaced
+func (t *hostFileTransaction) rollback() {
+	for idx := len(t.changes) - 1; idx >= 0; idx-- {
+		change := t.changes[idx]
+
+		if change.committed {
+			_ = os.RemoveAll(change.target)
+		}
+		if change.replaced {
+			if err := os.Rename(change.rollback, change.target); err != nil {
+				message.Warnf("Unable to restore %s from %s", change.target, change.rollback)
+			}
+		}
+		_ = os.RemoveAll(change.staged)
+	}
+}
+
+// finish keeps whatever the committed changes replaced as backups and returns the files to record for package remove
+func (t *hostFileTransaction) finish() []types.DeployedFile {
+	var files []types.DeployedFile
+
+	for _, change := range t.changes {
+		backup, placedBefore := previousHostFiles[change.target]
+
+		if change.replaced {
+			if placedBefore {
+				// An earlier deployment placed what was just replaced, so the backup of the original is already recorded
+				_ = os.RemoveAll(change.rollback)
+			} else {
+				backup = change.target + hostFileBackupSuffix
+				_ = os.RemoveAll(backup)
+				if err := os.Rename(change.rollback, backup); err != nil {
+					message.Warnf("Unable to keep a backup of the file replaced at %s", change.target)
+					backup = ""
+				}
+			}
+		}
+
+		if change.record {
+			file := types.DeployedFile{Path: change.target, Backup: backup, Host: getHostID(), Link: change.link}
+			// Directories have no checksum, so package remove leaves them in place
+			if change.link == "" {
+				if shasum, err := utils.GetSha256Sum(change.target); err == nil {
+					file.Shasum = shasum
+				}
+			}
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// removeHostFiles removes the files a component placed on the host, restoring any files they replaced
+// Only files placed on this host that are unchanged since are removed, anything else is left in place along with its backup
+func removeHostFiles(files []types.DeployedFile) error {
+	message.Debugf("packager.removeHostFiles(%#v)", files)
+
+	for idx := len(files) - 1; idx >= 0; idx-- {
+		file := files[idx]
+
+		if err := checkHostFile(file); err != nil {
+			message.Warnf("Leaving %s in place, %s", file.Path, err.Error())
+			continue
+		}
+
+		if err := os.Remove(file.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove %s: %w", file.Path, err)
+		}
+
+		if file.Backup != "" {
+			if _, err := os.Lstat(file.Backup); err != nil {
+				message.Warnf("Unable to find the backup %s to restore %s from", file.Backup, file.Path)
+				continue
+			}
+			if err := os.Rename(file.Backup, file.Path); err != nil {
+				return fmt.Errorf("unable to restore %s from %s: %w", file.Path, file.Backup, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkHostFile returns why a recorded file can't be removed from this host, or nil when it is still what the component placed here
+func checkHostFile(file types.DeployedFile) error {
+	if file.Host == "" {
+		return fmt.Errorf("it was placed by a version of Zarf that didn't record which host it was placed on")
+	}
+	if file.Host != getHostID() {
+		return fmt.Errorf("it was placed on another host")
+	}
+
+	info, err := os.Lstat(file.Path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir():
+		return fmt.Errorf("it is a directory")
+
+	case info.Mode()&os.ModeSymlink != 0:
+		if link, err := os.Readlink(file.Path); err != nil || link != file.Link {
+			return fmt.Errorf("it is not the symlink that was placed")
+		}
+
+	default:
+		if file.Shasum == "" {
+			return fmt.Errorf("it is not the file that was placed")
+		}
+		if shasum, err := utils.GetSha256Sum(file.Path); err != nil || !strings.EqualFold(shasum, file.Shasum) {
+			return fmt.Errorf("it changed since it was placed")
+		}
+	}
+	return nil
+}
+
+// getHostID identifies this machine so package remove only deletes files placed on it
+func getHostID() string {
+	if machineID, err := os.ReadFile("/etc/machine-id"); err == nil && strings.TrimSpace(string(machineID)) != "" {
+		return strings.TrimSpace(string(machineID))
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
@@ -0,0 +1,31 @@
+package packager
+
+import (
+	"github.com/defenseunicorns/zarf/src/internal/template"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// templateNamespaceGuardrails fills the package variables, constants and imported values into the sizes of the namespace guardrails
+func templateNamespaceGuardrails(component types.ZarfComponent) types.ZarfNamespaceGuardrails {
+	apply := func(values map[string]string) map[string]string {
+		if len(values) == 0 {
+			return nil
+		}
+		templated := make(map[string]string, len(values))
+		for name, value := range values {
+			templated[name] = template.ApplyComponentVariables(value, component.Imports)
+		}
+		return templated
+	}
+
+	guardrails := component.NamespaceGuardrails
+	return types.ZarfNamespaceGuardrails{
+		ResourceQuota: apply(guardrails.ResourceQuota),
+		LimitRange: types.ZarfLimitRange{
+			Default:        apply(guardrails.LimitRange.Default),
+			DefaultRequest: apply(guardrails.LimitRange.DefaultRequest),
+			Min:            apply(guardrails.LimitRange.Min),
+			Max:            apply(guardrails.LimitRange.Max),
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package packager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+)
+
+// enforceImagePolicy checks the images against the policy file and returns the violations that were forced through
+// Violations stop the run unless force is set, in which case they are returned so they can be recorded
+func enforceImagePolicy(policyPath string, force bool, imageList []string) ([]string, error) {
+	message.Debugf("packager.enforceImagePolicy(%s, %t, %#v)", policyPath, force, imageList)
+
+	if policyPath == "" || len(imageList) == 0 {
+		return nil, nil
+	}
+
+	policy, err := images.LoadPolicy(policyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := images.CheckPolicy(policy, removeDuplicates(imageList))
+	if len(violations) == 0 {
+		message.Debugf("All images meet the image policy %s", policyPath)
+		return nil, nil
+	}
+
+	if !force {
+		return nil, fmt.Errorf("%d image policy violations:\n  - %s\nremove the images or use --force-image-policy to accept them",
+			len(violations), strings.Join(violations, "\n  - "))
+	}
+
+	message.Warnf("Accepting %d image policy violations because --force-image-policy was used, they will be recorded:\n  - %s",
+		len(violations), strings.Join(violations, "\n  - "))
+	return violations, nil
+}
+
+// getPolicyImages adds the seed image of init packages to the images the components put in the package
+func getPolicyImages(packagedImages []string) []string {
+	if config.IsZarfInitConfig() {
+		return append(packagedImages, fmt.Sprintf("%s:%s", config.ZarfSeedImage, config.ZarfSeedTag))
+	}
+	return packagedImages
+}
+
+// enforceCreateImagePolicy checks the images going into the package and records any forced violations in its build data
+func enforceCreateImagePolicy(imageList []string) {
+	violations, err := enforceImagePolicy(config.CreateOptions.ImagePolicyPath, config.CreateOptions.ForceImagePolicy, imageList)
+	if err != nil {
+		message.Fatalf(err, "The package images don't meet the image policy: %s", err.Error())
+	}
+
+	build := config.GetBuildData()
+	build.ImagePolicyViolations = violations
+	config.SetBuildData(build)
+}
@@ -116,12 +116,13 @@ func createPayloadConfigmaps(tempPath tempPaths, spinner *message.Spinner) ([]st
 
 	// Chunk size has to accomdate base64 encoding & etcd 1MB limit
 	tarPath := filepath.Join(tempPath.base, "payload.tgz")
-	tarFileList, err := filepath.Glob(filepath.Join(tempPath.base, "seed-image", "*"))
+	tarFileList, err := filepath.Glob(filepath.Join(tempPath.seedImageDir, "*"))
 	if err != nil {
 		return configMaps, "", err
 	}
 	labels := map[string]string{
-		"zarf-injector": "payload",
+		"zarf-injector":     "payload",
+		config.ZarfRunLabel: config.GetRunID(),
 	}
 
 	spinner.Updatef("Creating the seed registry archive to send to the cluster")
@@ -210,7 +211,8 @@ func createInjectorConfigmap(tempPath tempPaths) error {
 	var err error
 	configData := make(map[string][]byte)
 	labels := map[string]string{
-		"zarf-injector": "payload",
+		"zarf-injector":     "payload",
+		config.ZarfRunLabel: config.GetRunID(),
 	}
 
 	// Add the injector binary data to the configmap
@@ -232,6 +234,7 @@ func createInjectorConfigmap(tempPath tempPaths) error {
 func createService() (*corev1.Service, error) {
 	service := k8s.GenerateService(k8s.ZarfNamespace, "zarf-injector")
 
+	service.Labels[config.ZarfRunLabel] = config.GetRunID()
 	service.Spec.Type = corev1.ServiceTypeNodePort
 	service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
 		Port: int32(5000),
@@ -250,8 +253,13 @@ func createService() (*corev1.Service, error) {
 func buildInjectionPod(node, image string, payloadConfigmaps []string, payloadShasum string) (*corev1.Pod, error) {
 	pod := k8s.GeneratePod("injector", k8s.ZarfNamespace)
 	executeMode := int32(0777)
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	injectorUser := int64(1000)
+	injectorGroup := int64(2000)
 
 	pod.Labels["app"] = "zarf-injector"
+	pod.Labels[config.ZarfRunLabel] = config.GetRunID()
 
 	// Ensure zarf agent doesnt break the injector on future runs
 	pod.Labels["zarf.dev/agent"] = "ignore"
@@ -262,6 +270,17 @@ func buildInjectionPod(node, image string, payloadConfigmaps []string, payloadSh
 	// Do not try to restart the pod as it will be deleted/re-created instead
 	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
 
+	// Meet the restricted Pod Security Standard, the injector only needs to write into the seed volume
+	pod.Spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot: &runAsNonRoot,
+		RunAsUser:    &injectorUser,
+		RunAsGroup:   &injectorGroup,
+		FSGroup:      &injectorGroup,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+
 	pod.Spec.Containers = []corev1.Container{
 		{
 			Name: "injector",
@@ -278,6 +297,13 @@ func buildInjectionPod(node, image string, payloadConfigmaps []string, payloadSh
 			// Call the injector with shasum of the tarball
 			Command: []string{"/zarf-init/zarf-injector", payloadShasum},
 
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+				Capabilities: &corev1.Capabilities{
+					Drop: []corev1.Capability{"ALL"},
+				},
+			},
+
 			// Shared mount between the init and regular containers
 			VolumeMounts: []corev1.VolumeMount{
 				{
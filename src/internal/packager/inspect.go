@@ -7,9 +7,12 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
 	"github.com/mholt/archiver/v3"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v2"
 )
 
 // ViewSBOM indicates if image SBOM information should be displayed when inspecting a package
@@ -69,3 +72,37 @@ func Inspect(packageName string) {
 		}
 	}
 }
+
+// InspectDeployed shows a package deployed to the cluster from the secret saved when it was deployed, so the package archive isn't needed
+func InspectDeployed(packageName string) {
+	deployedPackage, err := k8s.GetDeployedPackage(packageName)
+	if err != nil {
+		message.Fatalf(err, "Unable to find the package %s in the cluster, run 'zarf package list' to see the deployed packages", packageName)
+	}
+
+	content, err := yaml.Marshal(deployedPackage.Data)
+	if err != nil {
+		message.Fatal(err, "Unable to read the config of the deployed package")
+	}
+	utils.ColorPrintYAML(string(content))
+
+	message.Infof("The package was built with Zarf CLI version %s and deployed with version %s\n", deployedPackage.Data.Build.Version, deployedPackage.CLIVersion)
+	if deployedPackage.DeployedAt != "" {
+		message.Infof("It was last deployed at %s\n", deployedPackage.DeployedAt)
+	}
+
+	chartTable := pterm.TableData{{"     Component", "Chart", "Namespace"}}
+	for _, component := range deployedPackage.DeployedComponents {
+		for _, chart := range component.InstalledCharts {
+			chartTable = append(chartTable, []string{"     " + component.Name, chart.ChartName, chart.Namespace})
+		}
+	}
+	if len(chartTable) > 1 {
+		message.Note("Installed charts:")
+		_ = pterm.DefaultTable.WithHasHeader().WithData(chartTable).Render()
+	} else {
+		message.Note("The package has no installed charts.")
+	}
+
+	message.PrintConnectStringTable(deployedPackage.ConnectStrings)
+}
@@ -0,0 +1,94 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	intoto "github.com/in-toto/in-toto-golang/in_toto"
+)
+
+// inTotoLinkExt is the extension in-toto gives the signed link metadata of each step it records
+const inTotoLinkExt = ".link"
+
+// addInTotoLinks copies the link metadata the build pipeline recorded into the package and records their names in the build data
+func addInTotoLinks(tempPath tempPaths, linksDir string) error {
+	message.Debugf("packager.addInTotoLinks(%s)", linksDir)
+
+	links, err := filepath.Glob(filepath.Join(linksDir, "*"+inTotoLinkExt))
+	if err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return fmt.Errorf("no %s files in %s", inTotoLinkExt, linksDir)
+	}
+
+	_ = os.RemoveAll(tempPath.intoto)
+
+	var names []string
+	for _, link := range links {
+		name := filepath.Base(link)
+		if err := utils.CreatePathAndCopy(link, filepath.Join(tempPath.intoto, name)); err != nil {
+			return fmt.Errorf("unable to copy the link %s: %w", link, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	build := config.GetBuildData()
+	build.InTotoLinks = names
+	config.SetBuildData(build)
+	return nil
+}
+
+// verifyInTotoLayout checks the link metadata in the package against a signed in-toto layout, so only packages built by the approved pipeline deploy
+// The inspections of the layout run in the extracted package, where they can check the package contents against the products of the build steps
+func verifyInTotoLayout(tempPath tempPaths, layoutPath string, keyPaths []string) error {
+	message.Debugf("packager.verifyInTotoLayout(%s, %#v)", layoutPath, keyPaths)
+
+	if len(keyPaths) == 0 {
+		return fmt.Errorf("the layout %s can't be verified without the public keys of its owners, use --intoto-layout-key", layoutPath)
+	}
+	if len(config.GetBuildData().InTotoLinks) == 0 {
+		return fmt.Errorf("the package has no in-toto links, it was not built by a pipeline that records them")
+	}
+
+	var layout intoto.Metablock
+	if err := layout.Load(layoutPath); err != nil {
+		return fmt.Errorf("unable to load the layout %s: %w", layoutPath, err)
+	}
+
+	layoutKeys := make(map[string]intoto.Key)
+	for _, keyPath := range keyPaths {
+		var key intoto.Key
+		if err := key.LoadKeyDefaults(keyPath); err != nil {
+			return fmt.Errorf("unable to load the layout key %s: %w", keyPath, err)
+		}
+		layoutKeys[key.KeyID] = key
+	}
+
+	// Inspections run their commands in the working directory, which is changed back once they are done
+	linksDir, err := filepath.Abs(tempPath.intoto)
+	if err != nil {
+		return err
+	}
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(tempPath.base); err != nil {
+		return err
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	if _, err := intoto.InTotoVerify(layout, layoutKeys, linksDir, "", nil, nil, false); err != nil {
+		return fmt.Errorf("the package doesn't meet the layout %s: %w", layoutPath, err)
+	}
+
+	message.Debugf("The in-toto links of the package meet the layout %s", layoutPath)
+	return nil
+}
@@ -0,0 +1,106 @@
+package packager
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/sbom"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// publishInventory records the images (and the digests of their SBOMs) deployed by the components in a configmap for the inventory component
+// The images of components deployed earlier are kept, so deploying some components of a package doesn't drop the others
+func publishInventory(tempPath tempPaths, components []types.ZarfComponent) error {
+	message.Debugf("packager.publishInventory(%#v)", tempPath)
+
+	pkg := config.GetActiveConfig()
+	deployed := make(map[string]bool)
+	for _, component := range components {
+		deployed[component.Name] = true
+	}
+
+	inventory := loadInventory(pkg.Metadata.Name)
+	inventory.Version = pkg.Metadata.Version
+	inventory.Timestamp = pkg.Build.Timestamp
+	images := []types.InventoryImage{}
+	for _, image := range inventory.Images {
+		if !deployed[image.Component] {
+			images = append(images, image)
+		}
+	}
+
+	for _, component := range components {
+		for _, image := range getComponentImages(component) {
+			inventoryImage := types.InventoryImage{
+				Component: component.Name,
+				Image:     image,
+			}
+
+			// The SBOMs leave with the package, so their digest is what a scanner can match them by later
+			if sbomFile, err := sbom.GetSBOMFileName(image); err == nil {
+				if digest, err := utils.GetSha256Sum(filepath.Join(tempPath.sboms, sbomFile)); err == nil {
+					inventoryImage.SBOMDigest = "sha256:" + digest
+				}
+			}
+
+			images = append(images, inventoryImage)
+		}
+	}
+	inventory.Images = images
+
+	return saveInventory(inventory)
+}
+
+// removeFromInventory drops the images of a removed component from the inventory of its package
+func removeFromInventory(packageName, componentName string) error {
+	message.Debugf("packager.removeFromInventory(%s, %s)", packageName, componentName)
+
+	inventory := loadInventory(packageName)
+	images := []types.InventoryImage{}
+	for _, image := range inventory.Images {
+		if image.Component != componentName {
+			images = append(images, image)
+		}
+	}
+	if len(images) == len(inventory.Images) {
+		return nil
+	}
+	inventory.Images = images
+
+	return saveInventory(inventory)
+}
+
+// loadInventory returns the inventory the package published before, or an empty one when it has none
+func loadInventory(packageName string) types.PackageInventory {
+	inventory := types.PackageInventory{Name: packageName, Images: []types.InventoryImage{}}
+
+	configMap, err := k8s.GetConfigmap(k8s.ZarfNamespace, getInventoryName(packageName))
+	if err != nil {
+		return inventory
+	}
+	if err := json.Unmarshal(configMap.BinaryData["inventory"], &inventory); err != nil {
+		message.Debugf("Unable to parse the inventory of %s, replacing it: %#v", packageName, err)
+	}
+	inventory.Name = packageName
+	return inventory
+}
+
+func saveInventory(inventory types.PackageInventory) error {
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return err
+	}
+
+	labels := map[string]string{config.ZarfInventoryLabel: inventory.Name}
+	_, err = k8s.ReplaceConfigmap(k8s.ZarfNamespace, getInventoryName(inventory.Name), labels, map[string][]byte{"inventory": data})
+	return err
+}
+
+func getInventoryName(packageName string) string {
+	return fmt.Sprintf("zarf-inventory-%s", packageName)
+}
@@ -0,0 +1,57 @@
+package packager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/git"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/pterm/pterm"
+)
+
+// The wall time spent in each deploy phase, added up across components
+var phaseDurations = make(map[string]time.Duration)
+
+// recordPhaseDuration adds the time since a phase started to the deploy metrics
+func recordPhaseDuration(phase string, start time.Time) {
+	phaseDurations[phase] += time.Since(start)
+}
+
+// getDeployMetrics collects what this run pushed and how long each phase that ran took
+func getDeployMetrics() types.DeployMetrics {
+	pushStats := images.GetPushStats()
+	metrics := types.DeployMetrics{
+		ImageBytesPushed:   pushStats.BytesPushed,
+		ImageBytesSkipped:  pushStats.BytesSkipped,
+		ImageLayersPushed:  pushStats.LayersPushed,
+		ImageLayersSkipped: pushStats.LayersSkipped,
+		RepoBytesPushed:    git.PushedBytes(),
+	}
+
+	for _, phase := range DeployPhases {
+		if duration, ok := phaseDurations[phase]; ok {
+			metrics.Phases = append(metrics.Phases, types.PhaseDuration{Name: phase, DurationSeconds: duration.Seconds()})
+		}
+	}
+	return metrics
+}
+
+// printDeployMetrics shows what the deploy pushed, what the registry already had and where the time went
+func printDeployMetrics() {
+	metrics := getDeployMetrics()
+
+	table := pterm.TableData{
+		{"     Metric", "Value"},
+		{"     Images pushed", fmt.Sprintf("%s in %d layers", utils.ByteFormat(float64(metrics.ImageBytesPushed), 2), metrics.ImageLayersPushed)},
+		{"     Images already in the registry", fmt.Sprintf("%s in %d layers", utils.ByteFormat(float64(metrics.ImageBytesSkipped), 2), metrics.ImageLayersSkipped)},
+		{"     Repos pushed", utils.ByteFormat(float64(metrics.RepoBytesPushed), 2)},
+	}
+	for _, phase := range metrics.Phases {
+		table = append(table, []string{fmt.Sprintf("     %s phase", phase.Name), (time.Duration(phase.DurationSeconds * float64(time.Second))).Round(time.Millisecond).String()})
+	}
+
+	pterm.Println()
+	_ = pterm.DefaultTable.WithHasHeader().WithData(table).Render()
+}
@@ -0,0 +1,141 @@
+package packager
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"gopkg.in/yaml.v2"
+	"k8s.io/utils/strings/slices"
+)
+
+// The template that packages use to pick architecture-specific files, such as the injector binary
+const archTemplate = "###ZARF_PKG_ARCH###"
+
+// composeMultiArchComponent composes an imported component for each architecture of a multi-arch package
+// It stays one component when every architecture imports the same thing, otherwise each copy is limited to its architecture
+func composeMultiArchComponent(parentComponent types.ZarfComponent) []types.ZarfComponent {
+	var composed []types.ZarfComponent
+	shared := true
+	for _, arch := range config.MultiArchitectures {
+		component := GetComposedComponent(parentComponent, arch)
+		shared = shared && component.Only.Cluster.Architecture == "" && (len(composed) == 0 || reflect.DeepEqual(component, composed[0]))
+		composed = append(composed, component)
+	}
+
+	if shared {
+		return composed[:1]
+	}
+	for idx, arch := range config.MultiArchitectures {
+		composed[idx].Only.Cluster.Architecture = arch
+	}
+	return composed
+}
+
+// expandMultiArchComponents fills in ###ZARF_PKG_ARCH### for the components of a multi-arch package
+// Components that use it without being limited to an architecture are copied for each architecture so each copy gets its own files
+func expandMultiArchComponents(components []types.ZarfComponent) []types.ZarfComponent {
+	var expanded []types.ZarfComponent
+	for _, component := range components {
+		if !usesArchTemplate(component) {
+			expanded = append(expanded, component)
+			continue
+		}
+
+		architectures := config.MultiArchitectures
+		if component.Only.Cluster.Architecture != "" {
+			architectures = []string{component.Only.Cluster.Architecture}
+		}
+
+		for _, arch := range architectures {
+			// Templating unmarshals the component again, so the copies don't share any slices or maps
+			archComponent := component
+			if err := utils.ReloadYamlTemplate(&archComponent, map[string]string{archTemplate: arch}); err != nil {
+				message.Fatalf(err, "Unable to fill the architecture into the %s component: %s", component.Name, err.Error())
+			}
+			archComponent.Only.Cluster.Architecture = arch
+			expanded = append(expanded, archComponent)
+		}
+	}
+	return expanded
+}
+
+// usesArchTemplate returns whether anything in the definition of a component refers to ###ZARF_PKG_ARCH###
+func usesArchTemplate(component types.ZarfComponent) bool {
+	text, err := yaml.Marshal(component)
+	return err == nil && strings.Contains(string(text), archTemplate)
+}
+
+// forEachArch runs fn with the image paths of each architecture the package is built for
+// A multi-arch package pulls its images once for each of the MultiArchitectures, which are active while fn runs for them
+func forEachArch(tempPath tempPaths, fn func(arch string, archPath tempPaths)) {
+	if !config.IsMultiArch() {
+		fn(config.GetArch(), tempPath)
+		return
+	}
+
+	cliArch := config.CliArch
+	defer func() {
+		config.CliArch = cliArch
+	}()
+
+	for _, arch := range config.MultiArchitectures {
+		config.CliArch = arch
+		fn(arch, tempPath.forArch(arch))
+	}
+}
+
+// getArchImageList returns the images a multi-arch package pulls for one architecture, those of the components that deploy to it
+func getArchImageList(components []types.ZarfComponent, arch string, diff differentialData) []string {
+	var imageList []string
+	for _, component := range components {
+		if component.Only.Cluster.Architecture != "" && component.Only.Cluster.Architecture != arch {
+			continue
+		}
+		for _, image := range append(append([]string{}, component.Images...), component.ArchImages[arch]...) {
+			if !diff.images[image] {
+				imageList = append(imageList, image)
+			}
+		}
+	}
+	return imageList
+}
+
+// selectMultiArch picks the architecture to deploy a multi-arch package for, which is the one given with --architecture
+// or else the one the cluster was initialized with, the one its nodes run or, before there is a cluster, the one of this machine
+func selectMultiArch() (string, error) {
+	arch := config.CliArch
+	if arch == "" || arch == config.MultiArch {
+		arch = getClusterArch()
+	}
+
+	if !slices.Contains(config.MultiArchitectures, arch) {
+		return "", fmt.Errorf("this multi-arch package was built for %s, but the cluster is %s", strings.Join(config.MultiArchitectures, " and "), arch)
+	}
+	return arch, nil
+}
+
+// getClusterArch returns the architecture the images of the cluster have to be built for
+func getClusterArch() string {
+	if state, err := k8s.LoadZarfState(); err == nil && state.Architecture != "" {
+		return state.Architecture
+	}
+
+	architectures, err := k8s.GetArchitectures()
+	if err != nil {
+		message.Debugf("Unable to read the node architectures, using the architecture of this machine: %s", err.Error())
+		return runtime.GOARCH
+	}
+	for _, arch := range architectures {
+		if slices.Contains(config.MultiArchitectures, arch) {
+			return arch
+		}
+	}
+	return architectures[0]
+}
@@ -0,0 +1,232 @@
+package packager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/google/go-containerregistry/pkg/crane"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	ggcrTypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/mholt/archiver/v3"
+)
+
+const (
+	ociPrefix = "oci://"
+
+	// Media types used to describe a Zarf package stored as an OCI artifact
+	zarfConfigMediaType    ggcrTypes.MediaType = "application/vnd.zarf.config.v1+json"
+	zarfFileLayerMediaType ggcrTypes.MediaType = "application/vnd.zarf.layer.v1.file"
+	zarfDirLayerMediaType  ggcrTypes.MediaType = "application/vnd.zarf.layer.v1.tar"
+
+	ociTitleAnnotation       = "org.opencontainers.image.title"
+	ociDescriptionAnnotation = "org.opencontainers.image.description"
+	ociVersionAnnotation     = "org.opencontainers.image.version"
+)
+
+// fileLayer is an uncompressed file on disk pushed as-is into an OCI layer
+type fileLayer struct {
+	path      string
+	mediaType ggcrTypes.MediaType
+	digest    v1.Hash
+	size      int64
+}
+
+func newFileLayer(path string, mediaType ggcrTypes.MediaType) (v1.Layer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	digest, size, err := v1.SHA256(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return partial.CompressedToLayer(&fileLayer{path: path, mediaType: mediaType, digest: digest, size: size})
+}
+
+func (l *fileLayer) Digest() (v1.Hash, error) { return l.digest, nil }
+
+// DiffID is the same as the digest since the layer is not compressed
+func (l *fileLayer) DiffID() (v1.Hash, error) { return l.digest, nil }
+
+func (l *fileLayer) Compressed() (io.ReadCloser, error) { return os.Open(l.path) }
+
+func (l *fileLayer) Size() (int64, error) { return l.size, nil }
+
+func (l *fileLayer) MediaType() (ggcrTypes.MediaType, error) { return l.mediaType, nil }
+
+// isOCIReference returns true if the given package path points to an OCI registry
+func isOCIReference(packagePath string) bool {
+	return strings.HasPrefix(packagePath, ociPrefix)
+}
+
+func getOCICraneOptions(insecure bool) []crane.Option {
+	options := []crane.Option{config.GetCraneKeychainOption()}
+	if insecure {
+		options = append(options, crane.Insecure)
+	}
+	return options
+}
+
+// Publish pushes a Zarf package archive to an OCI registry, storing each top-level package entry as its own layer
+func Publish(packagePath, reference string, insecure bool) {
+	message.Debugf("packager.Publish(%s, %s, %t)", packagePath, reference, insecure)
+
+	tempPath := createPaths()
+	defer tempPath.clean()
+
+	spinner := message.NewProgressSpinner("Publishing %s to %s", packagePath, reference)
+	defer spinner.Stop()
+
+	if utils.InvalidPath(packagePath) {
+		spinner.Fatalf(nil, "Unable to find the package on the local system, expected package at %s", packagePath)
+	}
+
+	spinner.Updatef("Extracting the package, this may take a few moments")
+	packageDir := filepath.Join(tempPath.base, "package")
+	if err := archiver.Unarchive(packagePath, packageDir); err != nil {
+		spinner.Fatalf(err, "Unable to extract the package contents")
+	}
+
+	var pkg types.ZarfPackage
+	if err := utils.ReadYaml(filepath.Join(packageDir, config.ZarfYAML), &pkg); err != nil {
+		spinner.Fatalf(err, "Unable to read the zarf.yaml file from the package")
+	}
+
+	entries, err := os.ReadDir(packageDir)
+	if err != nil {
+		spinner.Fatalf(err, "Unable to read the package contents")
+	}
+
+	img := mutate.MediaType(empty.Image, ggcrTypes.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, zarfConfigMediaType)
+
+	for _, entry := range entries {
+		spinner.Updatef("Preparing layer %s", entry.Name())
+
+		layerPath := filepath.Join(packageDir, entry.Name())
+		mediaType := zarfFileLayerMediaType
+
+		// Directories are stored as tarballs so they can be extracted back in place on pull
+		if entry.IsDir() {
+			tarPath := filepath.Join(tempPath.base, entry.Name()+".tar")
+			if err := archiver.Archive([]string{layerPath}, tarPath); err != nil {
+				spinner.Fatalf(err, "Unable to archive %s", entry.Name())
+			}
+			layerPath = tarPath
+			mediaType = zarfDirLayerMediaType
+		}
+
+		layer, err := newFileLayer(layerPath, mediaType)
+		if err != nil {
+			spinner.Fatalf(err, "Unable to create the layer for %s", entry.Name())
+		}
+
+		img, err = mutate.Append(img, mutate.Addendum{
+			Layer:       layer,
+			MediaType:   mediaType,
+			Annotations: map[string]string{ociTitleAnnotation: entry.Name()},
+		})
+		if err != nil {
+			spinner.Fatalf(err, "Unable to add the layer for %s", entry.Name())
+		}
+	}
+
+	img = mutate.Annotations(img, map[string]string{
+		ociTitleAnnotation:       pkg.Metadata.Name,
+		ociDescriptionAnnotation: pkg.Metadata.Description,
+		ociVersionAnnotation:     pkg.Metadata.Version,
+	}).(v1.Image)
+
+	spinner.Updatef("Pushing the package to %s", reference)
+	if err := crane.Push(img, strings.TrimPrefix(reference, ociPrefix), getOCICraneOptions(insecure)...); err != nil {
+		spinner.Fatalf(err, "Unable to push the package to %s", reference)
+	}
+
+	digest, _ := img.Digest()
+	spinner.Successf("Published %s to %s@%s", pkg.Metadata.Name, reference, digest)
+}
+
+// pullPackage downloads a Zarf package stored as an OCI artifact into the given directory, verifying every layer digest
+func pullPackage(ctx context.Context, reference, destination string) error {
+	message.Debugf("packager.pullPackage(%s, %s)", reference, destination)
+
+	pullOptions := append(getOCICraneOptions(config.DeployOptions.OCIInsecure), crane.WithContext(ctx))
+	img, err := crane.Pull(strings.TrimPrefix(reference, ociPrefix), pullOptions...)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return err
+	}
+
+	if manifest.Config.MediaType != zarfConfigMediaType {
+		return fmt.Errorf("%s is not a Zarf package (config media type %s)", reference, manifest.Config.MediaType)
+	}
+
+	for _, descriptor := range manifest.Layers {
+		title := descriptor.Annotations[ociTitleAnnotation]
+		if title == "" || filepath.Base(title) != title {
+			return fmt.Errorf("invalid layer title %q in %s", title, reference)
+		}
+
+		layer, err := img.LayerByDigest(descriptor.Digest)
+		if err != nil {
+			return err
+		}
+
+		// Remote layers verify their digest as they are read
+		reader, err := layer.Compressed()
+		if err != nil {
+			return err
+		}
+
+		switch descriptor.MediaType {
+		case zarfFileLayerMediaType:
+			err = writeLayer(reader, filepath.Join(destination, title))
+
+		case zarfDirLayerMediaType:
+			tarPath := filepath.Join(destination, title+".tar")
+			if err = writeLayer(reader, tarPath); err == nil {
+				err = archiver.Unarchive(tarPath, destination)
+				_ = os.Remove(tarPath)
+			}
+
+		default:
+			err = fmt.Errorf("unknown layer media type %s for %s", descriptor.MediaType, title)
+		}
+
+		_ = reader.Close()
+		if err != nil {
+			return fmt.Errorf("unable to pull the layer %s: %w", title, err)
+		}
+	}
+
+	return nil
+}
+
+func writeLayer(reader io.Reader, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+	return err
+}
@@ -0,0 +1,144 @@
+package packager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+)
+
+// The phases of a deployment that can be given their own --retries and --timeout budgets
+const (
+	DeployPhaseExtract = "extract"
+	DeployPhaseImages  = "images"
+	DeployPhaseRepos   = "repos"
+	DeployPhaseCharts  = "charts"
+	DeployPhaseData    = "data"
+
+	// AllDeployPhases sets a budget for every phase that is not given one of its own
+	AllDeployPhases = "all"
+)
+
+// DeployPhases lists the phases in the order they run within a component
+var DeployPhases = []string{DeployPhaseExtract, DeployPhaseImages, DeployPhaseRepos, DeployPhaseCharts, DeployPhaseData}
+
+// Keep the retry behavior Zarf had before the budgets were configurable
+var defaultPhaseRetries = map[string]int{
+	DeployPhaseExtract: 0,
+	DeployPhaseImages:  2,
+	DeployPhaseRepos:   2,
+	DeployPhaseCharts:  3,
+	DeployPhaseData:    0,
+}
+
+const phaseRetryDelay = 5 * time.Second
+
+// deployDeadline is when the whole deployment must be finished by, the zero time means there is no deadline
+var deployDeadline time.Time
+
+// startDeployDeadline starts the clock on the --deadline for the deployment
+func startDeployDeadline() {
+	if config.DeployOptions.Deadline == "" {
+		return
+	}
+
+	deadline, err := time.ParseDuration(config.DeployOptions.Deadline)
+	if err != nil {
+		message.Fatalf(err, "Invalid --deadline %s", config.DeployOptions.Deadline)
+	}
+
+	deployDeadline = time.Now().Add(deadline)
+	message.Debugf("The deployment must finish by %s", deployDeadline.Format(time.RFC3339))
+}
+
+// getPhaseBudget returns the value set for a phase, falling back to the value set for all phases
+func getPhaseBudget(budgets map[string]string, phase string) (string, bool) {
+	if value, ok := budgets[phase]; ok {
+		return value, true
+	}
+	value, ok := budgets[AllDeployPhases]
+	return value, ok
+}
+
+// getPhaseRetries returns how many times a phase is retried after its first attempt fails
+func getPhaseRetries(phase string) int {
+	if value, ok := getPhaseBudget(config.DeployOptions.Retries, phase); ok {
+		if retries, err := strconv.Atoi(value); err == nil {
+			return retries
+		}
+	}
+	return defaultPhaseRetries[phase]
+}
+
+// getPhaseTimeout returns the --timeout of a phase, 0 means it has none
+func getPhaseTimeout(phase string) time.Duration {
+	if value, ok := getPhaseBudget(config.DeployOptions.Timeouts, phase); ok {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			return timeout
+		}
+	}
+	return 0
+}
+
+// getPhaseDeadline returns when a phase starting now must finish by, the zero time means there is no limit
+func getPhaseDeadline(phase string) time.Time {
+	deadline := deployDeadline
+
+	if timeout := getPhaseTimeout(phase); timeout > 0 {
+		phaseDeadline := time.Now().Add(timeout)
+		if deadline.IsZero() || phaseDeadline.Before(deadline) {
+			deadline = phaseDeadline
+		}
+	}
+
+	return deadline
+}
+
+// runDeployPhase runs a phase until it succeeds, runs out of retries, or passes its timeout or the deploy deadline
+func runDeployPhase(phase string, run func(ctx context.Context) error) error {
+	message.Debugf("packager.runDeployPhase(%s)", phase)
+	defer recordPhaseDuration(phase, time.Now())
+
+	retries := getPhaseRetries(phase)
+	deadline := getPhaseDeadline(phase)
+
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			message.Errorf(err, "The %s phase failed, retrying in %s (retry %d of %d)...", phase, phaseRetryDelay, attempt, retries)
+			time.Sleep(phaseRetryDelay)
+		}
+
+		if err = runBeforeDeadline(phase, deadline, run); err == nil || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("the %s phase failed after %d attempts: %w", phase, retries+1, err)
+}
+
+// runBeforeDeadline runs a phase with a context that is cancelled once the deadline passes
+// The phase stops when the work it is doing honors the context, otherwise the deadline is only checked once it returns
+func runBeforeDeadline(phase string, deadline time.Time, run func(ctx context.Context) error) error {
+	if deadline.IsZero() {
+		return run(context.Background())
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	// Don't start work that has no time left to finish
+	if ctx.Err() != nil {
+		return fmt.Errorf("the %s phase could not start before its timeout or the deploy deadline: %w", phase, ctx.Err())
+	}
+
+	err := run(ctx)
+	if ctx.Err() != nil {
+		return fmt.Errorf("the %s phase did not finish before its timeout or the deploy deadline: %w", phase, ctx.Err())
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package packager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// The least strict Pod Security Standards level the pods of each init component need
+// The upstream charts of the git server, registry and logging stack can't be configured to meet the restricted level
+var initComponentPodSecurity = map[string]string{
+	"zarf-injector":      k8s.PodSecurityRestricted,
+	"zarf-seed-registry": k8s.PodSecurityBaseline,
+	"zarf-registry":      k8s.PodSecurityBaseline,
+	"zarf-agent":         k8s.PodSecurityRestricted,
+	"git-server":         k8s.PodSecurityBaseline,
+	"logging":            k8s.PodSecurityPrivileged,
+}
+
+// getInitPodSecurityLevels returns the Pod Security Standards level needed by each of the init components being deployed
+func getInitPodSecurityLevels(components []types.ZarfComponent) map[string]string {
+	levels := make(map[string]string)
+	for _, component := range components {
+		level, ok := initComponentPodSecurity[component.Name]
+		if !ok {
+			continue
+		}
+		// The registry components aren't deployed when using an existing registry
+		if component.Name == "zarf-injector" || component.Name == "zarf-seed-registry" || component.Name == "zarf-registry" {
+			if config.InitOptions.RegistryInfo.Address != "" || config.InitOptions.RegistryInfo.InClusterService != "" {
+				continue
+			}
+		}
+		levels[component.Name] = level
+	}
+
+	// The daemonset that copies the CA of the registry to the nodes writes to their containerd config
+	registryInfo := config.InitOptions.RegistryInfo
+	if _, ok := levels["zarf-seed-registry"]; ok && len(config.InitOptions.RegistryTLS.Cert) > 0 {
+		if registryInfo.ServiceType == "" || registryInfo.ServiceType == "NodePort" {
+			levels[registryCADaemonSet] = k8s.PodSecurityPrivileged
+		}
+	}
+	return levels
+}
+
+// validatePodSecurity makes sure Pod Security admission won't reject the pods of the init components
+// Components that need a looser level than the one enforced either stop the init or, with --pod-security-exemption, get it on the zarf namespace
+func validatePodSecurity(levels map[string]string, spinner *message.Spinner) error {
+	message.Debugf("packager.validatePodSecurity(%#v)", levels)
+
+	spinner.Updatef("Checking the Pod Security Standards enforced on the zarf namespace")
+
+	enforced, err := k8s.GetPodSecurityLevel(k8s.ZarfNamespace)
+	if err != nil {
+		return err
+	}
+
+	needed := enforced
+	var violations []string
+	for name, level := range levels {
+		if k8s.ComparePodSecurityLevels(level, enforced) < 0 {
+			violations = append(violations, fmt.Sprintf("%s (%s)", name, level))
+			if k8s.ComparePodSecurityLevels(level, needed) < 0 {
+				needed = level
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	sort.Strings(violations)
+
+	if !config.InitOptions.PodSecurityExemption {
+		return fmt.Errorf("the %s Pod Security Standard is enforced on the zarf namespace and would reject the components %s, "+
+			"deselect them or use --pod-security-exemption to enforce the %s level on the zarf namespace only",
+			enforced, strings.Join(violations, ", "), needed)
+	}
+
+	message.Warnf("Enforcing the %s Pod Security Standard on the zarf namespace for the components %s", needed, strings.Join(violations, ", "))
+	return k8s.SetPodSecurityLevel(k8s.ZarfNamespace, needed)
+}
@@ -76,7 +76,8 @@ func FindImages(baseDir, repoHelmChartPath string) {
 		if repoHelmChartPath != "" {
 			// Also process git repos that have helm charts
 			for _, repo := range component.Repos {
-				matches := strings.Split(repo, "@")
+				repoURL, _, _ := strings.Cut(repo, "!")
+				matches := strings.Split(repoURL, "@")
 				if len(matches) < 2 {
 					message.Warnf("Cannot convert git repo %s to helm chart without a version tag", repo)
 					continue
@@ -112,6 +113,9 @@ func FindImages(baseDir, repoHelmChartPath string) {
 					path := helm.DownloadChartFromGit(chart, componentPath.charts)
 					// track the actual chart path
 					chartNames[chart.Name] = path
+				} else if chart.LocalPath != "" {
+					path := helm.CreateChartFromLocalFiles(chart, componentPath.charts)
+					chartNames[chart.Name] = path
 				} else {
 					helm.DownloadPublishedChart(chart, componentPath.charts)
 				}
@@ -0,0 +1,222 @@
+package packager
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// prePullTimeout is how long every node gets to pull the large images before the deployment moves on without them
+const prePullTimeout = 15 * time.Minute
+
+// prePullCommand doesn't exist in the images, so the containers fail to start once their image is pulled instead of running it
+const prePullCommand = "/zarf-pre-pull"
+
+// The waiting reasons of a container whose image is still being pulled (or can't be)
+var prePullWaitingReasons = map[string]bool{
+	"":                  true,
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+}
+
+var prePullNameRegex = regexp.MustCompile(`[^a-z0-9\-]+`)
+
+// prePullLargeImages warms the image cache of every node with the pushed images of at least --pre-pull-size megabytes
+// Large images pulled through a slow registry NodePort can otherwise outlast the rollout timeouts of the charts that use them
+func prePullLargeImages(tempPath tempPaths, componentName string, pushedImages []types.DeployedImage) {
+	thresholdMB := config.DeployOptions.PrePullSizeMB
+	if thresholdMB <= 0 || len(pushedImages) == 0 {
+		return
+	}
+
+	spinner := message.NewProgressSpinner("Pre-pulling the large images of %s on every node", componentName)
+	defer spinner.Stop()
+
+	var sources []string
+	for _, image := range pushedImages {
+		sources = append(sources, image.Source)
+	}
+	sizes, err := images.GetImageSizes(tempPath.images, sources)
+	if err != nil {
+		spinner.Errorf(err, "Unable to read the image sizes, skipping the pre-pull")
+		return
+	}
+
+	registryAddress := config.GetContainerRegistryInfo().Address
+	threshold := int64(thresholdMB) * 1024 * 1024
+	var largeImages []string
+	for _, image := range pushedImages {
+		if sizes[image.Source] >= threshold {
+			largeImages = append(largeImages, fmt.Sprintf("%s/%s", registryAddress, image.Reference))
+		}
+	}
+	if len(largeImages) == 0 {
+		spinner.Successf("No images of %s are larger than %s", componentName, utils.ByteFormat(float64(threshold), 2))
+		return
+	}
+	sort.Strings(largeImages)
+
+	name := "zarf-pre-pull-" + strings.Trim(prePullNameRegex.ReplaceAllString(strings.ToLower(componentName), "-"), "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+
+	if err := runPrePull(name, largeImages, spinner); err != nil {
+		spinner.Warnf("Unable to pre-pull the large images of %s, workloads will pull them as they schedule: %s", componentName, err.Error())
+		return
+	}
+
+	spinner.Successf("Pre-pulled %d large images of %s on every node", len(largeImages), componentName)
+}
+
+// runPrePull creates a daemonset with a container per image, waits for every node to pull them and removes it
+func runPrePull(name string, imageList []string, spinner *message.Spinner) error {
+	// Clear out a daemonset left behind by an interrupted deployment
+	_ = k8s.DeleteDaemonSet(k8s.ZarfNamespace, name)
+
+	daemonSet := generatePrePullDaemonSet(name, imageList)
+	if _, err := k8s.CreateDaemonSet(daemonSet); err != nil {
+		return fmt.Errorf("unable to create the pre-pull daemonset: %w", err)
+	}
+	defer func() {
+		_ = k8s.DeleteDaemonSet(k8s.ZarfNamespace, name)
+	}()
+
+	timeout := time.After(prePullTimeout)
+	for {
+		pulled, nodes, err := countPrePulledNodes(name, daemonSet.Spec.Selector.MatchLabels)
+		if err == nil {
+			spinner.Updatef("%d of %d nodes have pulled the %d large images", pulled, nodes, len(imageList))
+			if nodes > 0 && pulled == nodes {
+				return nil
+			}
+		}
+
+		select {
+		case <-timeout:
+			return fmt.Errorf("%d of %d nodes pulled the images within %s", pulled, nodes, prePullTimeout)
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// countPrePulledNodes returns how many of the nodes the daemonset wants to run on have pulled all of the images
+func countPrePulledNodes(name string, podLabels map[string]string) (int, int, error) {
+	daemonSet, err := k8s.GetDaemonSet(k8s.ZarfNamespace, name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pods, err := k8s.GetPodsBySelector(k8s.ZarfNamespace, podLabels)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pulled := 0
+	for _, pod := range pods.Items {
+		if len(pod.Status.ContainerStatuses) < len(pod.Spec.Containers) {
+			continue
+		}
+		allPulled := true
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && prePullWaitingReasons[status.State.Waiting.Reason] && status.ImageID == "" {
+				allPulled = false
+				break
+			}
+		}
+		if allPulled {
+			pulled++
+		}
+	}
+
+	return pulled, int(daemonSet.Status.DesiredNumberScheduled), nil
+}
+
+// generatePrePullDaemonSet returns a daemonset that runs on every node with a container for each image
+// The containers never run the images, they only need the kubelet to pull them, and meet the restricted Pod Security Standard
+func generatePrePullDaemonSet(name string, imageList []string) *appsv1.DaemonSet {
+	podLabels := map[string]string{
+		"app":               name,
+		config.ZarfRunLabel: config.GetRunID(),
+	}
+
+	nonRoot := true
+	noEscalation := false
+	noToken := false
+	user := int64(65534)
+	containerSecurity := &corev1.SecurityContext{
+		RunAsNonRoot:             &nonRoot,
+		RunAsUser:                &user,
+		AllowPrivilegeEscalation: &noEscalation,
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+
+	var containers []corev1.Container
+	for idx, image := range imageList {
+		containers = append(containers, corev1.Container{
+			Name:            fmt.Sprintf("image-%d", idx),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{prePullCommand},
+			SecurityContext: containerSecurity,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1m"),
+					corev1.ResourceMemory: resource.MustParse("1Mi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("16Mi"),
+				},
+			},
+		})
+	}
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: k8s.ZarfNamespace,
+			Labels: map[string]string{
+				config.ZarfManagedByLabel: "zarf",
+				config.ZarfRunLabel:       config.GetRunID(),
+			},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					Containers:       containers,
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+					// Warm every node, including ones with taints that only some workloads tolerate
+					Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot:   &nonRoot,
+						SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+					},
+					AutomountServiceAccountToken: &noToken,
+				},
+			},
+		},
+	}
+}
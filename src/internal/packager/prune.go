@@ -0,0 +1,196 @@
+package packager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The registry command that frees the blobs no manifest refers to anymore
+var registryGarbageCollect = []string{"registry", "garbage-collect", "--delete-untagged", "/etc/docker/registry/config.yml"}
+
+// The deployment of the Zarf registry, which all of its replicas belong to
+const registryDeploymentName = "zarf-docker-registry"
+
+// registryReadOnlyEnv turns on the maintenance mode of the registry that rejects pushes and deletes but keeps serving pulls
+var registryReadOnlyEnv = corev1.EnvVar{Name: "REGISTRY_STORAGE_MAINTENANCE_READONLY", Value: `{"enabled": true}`}
+
+// How long the registry replicas get to restart in or out of read-only mode
+const registryRolloutTimeout = 5 * time.Minute
+
+// PruneRegistry deletes the images in the Zarf registry that no deployed package uses and runs the registry garbage collector to free their storage
+// A dry run only lists the images that would be deleted
+func PruneRegistry(dryRun bool) error {
+	message.Debugf("packager.PruneRegistry(%t)", dryRun)
+
+	state, err := k8s.LoadZarfState()
+	if err != nil || state.Distro == "" {
+		return fmt.Errorf("unable to load the zarf/zarf-state secret, did you remember to run zarf init first?")
+	}
+	config.InitState(state)
+
+	if !state.RegistryInfo.InternalRegistry {
+		registry := state.RegistryInfo.Address
+		if state.RegistryInfo.InClusterExternal {
+			registry = state.RegistryInfo.InClusterService
+		}
+		return fmt.Errorf("zarf only prunes the registry it deployed, use the garbage collection of %s instead", registry)
+	}
+
+	// The images of a pull-through cache are managed by the registry itself, which drops them once they expire
+	if state.RegistryInfo.ProxyURL != "" {
+		return fmt.Errorf("the Zarf registry is a pull-through cache of %s and expires the images it caches on its own", state.RegistryInfo.ProxyURL)
+	}
+
+	deployedPackages, err := k8s.GetDeployedZarfPackages()
+	if err != nil {
+		return fmt.Errorf("unable to get the deployed packages: %w", err)
+	}
+
+	// Without the recorded images of every component there is no telling which images are still in use
+	var deployedImages []types.DeployedImage
+	for _, deployedPackage := range deployedPackages {
+		for _, deployedComponent := range deployedPackage.DeployedComponents {
+			component := getDeployedComponentDefinition(deployedPackage.Data, deployedComponent.Name)
+			if len(deployedComponent.Images) == 0 && len(getComponentImages(component)) > 0 {
+				return fmt.Errorf("no images were recorded when the %s component of the %s package was deployed, deploy it again before pruning", deployedComponent.Name, deployedPackage.Name)
+			}
+			deployedImages = append(deployedImages, deployedComponent.Images...)
+			deployedImages = append(deployedImages, deployedComponent.Artifacts...)
+		}
+	}
+
+	spinner := message.NewProgressSpinner("Pruning the images no deployed package uses from the Zarf registry")
+	defer spinner.Stop()
+
+	pruned, err := images.PruneZarfRegistry(deployedImages, dryRun)
+	if err != nil {
+		spinner.Stop()
+		printPrunedImages(pruned)
+		return err
+	}
+
+	if dryRun {
+		spinner.Successf("%d images would be pruned from the Zarf registry", len(pruned))
+		printPrunedImages(pruned)
+		return nil
+	}
+
+	// The registry only frees the layers of deleted images when its garbage collector runs, which reads the storage of the registry pod
+	// Blobs pushed while it runs could be collected before their manifest lands, so every replica serves read-only until it is done
+	spinner.Updatef("Switching the Zarf registry to read-only mode")
+	if err := setRegistryReadOnly(true); err != nil {
+		return fmt.Errorf("unable to switch the registry to read-only mode: %w", err)
+	}
+	defer func() {
+		spinner.Updatef("Switching the Zarf registry back to read-write mode")
+		if err := setRegistryReadOnly(false); err != nil {
+			message.Errorf(err, "Unable to switch the registry back to read-write mode, run zarf init again to restore it")
+		}
+	}()
+
+	spinner.Updatef("Running the registry garbage collector")
+	pod, err := getReadOnlyRegistryPod()
+	if err != nil {
+		return err
+	}
+
+	stdout, stderr, err := k8s.ExecInPod(k8s.ZarfNamespace, pod, "", registryGarbageCollect)
+	message.Debug(stdout)
+	if err != nil {
+		return fmt.Errorf("unable to garbage collect the registry: %w: %s", err, stderr)
+	}
+
+	spinner.Successf("Pruned %d images from the Zarf registry", len(pruned))
+	printPrunedImages(pruned)
+	return nil
+}
+
+func printPrunedImages(pruned []string) {
+	if len(pruned) == 0 {
+		return
+	}
+
+	pruneTable := pterm.TableData{{"     Image"}}
+	for _, image := range pruned {
+		pruneTable = append(pruneTable, []string{"     " + image})
+	}
+	_ = pterm.DefaultTable.WithHasHeader().WithData(pruneTable).Render()
+}
+
+// setRegistryReadOnly restarts every replica of the Zarf registry in or out of read-only mode and waits for them
+// The next zarf init also leaves read-only mode, since helm resets the environment of the registry
+func setRegistryReadOnly(readOnly bool) error {
+	deployment, err := k8s.GetDeployment(k8s.ZarfNamespace, registryDeploymentName)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for idx := range deployment.Spec.Template.Spec.Containers {
+		container := &deployment.Spec.Template.Spec.Containers[idx]
+		var env []corev1.EnvVar
+		for _, variable := range container.Env {
+			if variable.Name != registryReadOnlyEnv.Name {
+				env = append(env, variable)
+			}
+		}
+		if readOnly {
+			env = append(env, registryReadOnlyEnv)
+		}
+		if len(env) != len(container.Env) || readOnly != isRegistryReadOnly(container.Env) {
+			changed = true
+		}
+		container.Env = env
+	}
+	if !changed {
+		return nil
+	}
+
+	if _, err := k8s.UpdateDeployment(deployment); err != nil {
+		return err
+	}
+	return k8s.WaitForDeploymentRollout(k8s.ZarfNamespace, registryDeploymentName, registryRolloutTimeout)
+}
+
+// isRegistryReadOnly returns true if the environment of a registry container turns on read-only mode
+func isRegistryReadOnly(env []corev1.EnvVar) bool {
+	for _, variable := range env {
+		if variable == registryReadOnlyEnv {
+			return true
+		}
+	}
+	return false
+}
+
+// getReadOnlyRegistryPod returns a running registry pod that was restarted in read-only mode
+// The replicas share their storage, so the garbage collector only runs in one of them
+func getReadOnlyRegistryPod() (string, error) {
+	deployment, err := k8s.GetDeployment(k8s.ZarfNamespace, registryDeploymentName)
+	if err != nil {
+		return "", err
+	}
+
+	pods, err := k8s.GetPodsBySelector(k8s.ZarfNamespace, deployment.Spec.Selector.MatchLabels)
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if isRegistryReadOnly(container.Env) {
+				return pod.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unable to find a running registry pod in read-only mode to garbage collect")
+}
@@ -0,0 +1,120 @@
+package packager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// registryImageKeeper is the daemonset of the zarf-registry component that keeps the registry image in use on every node
+const registryImageKeeper = "zarf-registry-image-keeper"
+
+// registryImageKeeperTimeout is how long every node gets to start the image keeper after init deploys it
+const registryImageKeeperTimeout = 3 * time.Minute
+
+// The labels the docker-registry chart gives the pods of the Zarf registry
+var registryPodLabels = map[string]string{
+	"app":     "docker-registry",
+	"release": "zarf-docker-registry",
+}
+
+// The waiting reasons of a container whose image can't be pulled
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+}
+
+// verifyRegistryImageKept warns about the nodes that don't run the image keeper
+// The registry pulls its own image from itself, so a node whose kubelet garbage collects the image can't start the registry again
+func verifyRegistryImageKept() {
+	spinner := message.NewProgressSpinner("Verifying every node keeps the Zarf registry image")
+	defer spinner.Stop()
+
+	timeout := time.After(registryImageKeeperTimeout)
+	for {
+		missing, err := getNodesWithoutRegistryImageKeeper()
+		if err == nil && len(missing) == 0 {
+			spinner.Successf("Every node keeps the Zarf registry image")
+			return
+		}
+		if err == nil {
+			spinner.Updatef("Waiting for %d nodes to keep the Zarf registry image", len(missing))
+		}
+
+		select {
+		case <-timeout:
+			if err != nil {
+				spinner.Warnf("Unable to verify the nodes keep the Zarf registry image: %s", err.Error())
+			} else {
+				spinner.Warnf("The nodes %s don't keep the Zarf registry image. If they garbage collect it, the registry can't restart on them until zarf init is run again with the same init package",
+					strings.Join(missing, ", "))
+			}
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// getNodesWithoutRegistryImageKeeper returns the names of the nodes that don't have a running image keeper pod
+func getNodesWithoutRegistryImageKeeper() ([]string, error) {
+	if _, err := k8s.GetDaemonSet(k8s.ZarfNamespace, registryImageKeeper); err != nil {
+		return nil, fmt.Errorf("unable to find the %s daemonset: %w", registryImageKeeper, err)
+	}
+
+	nodes, err := k8s.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := k8s.GetPodsBySelector(k8s.ZarfNamespace, map[string]string{"app": registryImageKeeper})
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]bool)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			kept[pod.Spec.NodeName] = true
+		}
+	}
+
+	var missing []string
+	for _, node := range nodes.Items {
+		if !kept[node.Name] {
+			missing = append(missing, node.Name)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
+
+// checkRegistryImagePulls returns an error when registry pods can't pull the registry image, which is gone from their nodes
+func checkRegistryImagePulls() error {
+	pods, err := k8s.GetPodsBySelector(k8s.ZarfNamespace, registryPodLabels)
+	if err != nil {
+		return err
+	}
+
+	var failedNodes []string
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Waiting != nil && imagePullFailureReasons[status.State.Waiting.Reason] {
+				failedNodes = append(failedNodes, pod.Spec.NodeName)
+				break
+			}
+		}
+	}
+	if len(failedNodes) == 0 {
+		return nil
+	}
+	sort.Strings(failedNodes)
+
+	return fmt.Errorf("the Zarf registry can't pull its own image on the nodes %s, which likely garbage collected it. Run zarf init again with the same init package to seed the registry image again",
+		strings.Join(failedNodes, ", "))
+}
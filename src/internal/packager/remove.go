@@ -3,94 +3,307 @@ package packager
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/AlecAivazis/survey/v2"
 	"github.com/defenseunicorns/zarf/src/config"
 	"github.com/defenseunicorns/zarf/src/internal/helm"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/pterm/pterm"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/strings/slices"
 )
 
 // Remove removes a package that was already deployed onto a cluster, uninstalling all installed helm charts
-func Remove(packageName string) error {
-	// Create temp paths to temporarily extract the package into
-	tempPath := createPaths()
-	defer tempPath.clean()
+// The plan of what will be removed is shown first and has to be confirmed, a dry run only shows the plan
+func Remove(packageName string, dryRun bool) error {
+	message.Debugf("packager.Remove(%s, %t)", packageName, dryRun)
 
-	spinner := message.NewProgressSpinner("Removing zarf package %s", packageName)
-	defer spinner.Stop()
+	startCommandResult("remove")
 
-	// Get the secret for the deployed package
+	// Get the list of components the package had deployed
 	secretName := fmt.Sprintf("zarf-package-%s", packageName)
-	packageSecret, err := k8s.GetSecret("zarf", secretName)
+	deployedPackage, err := k8s.GetDeployedPackage(packageName)
 	if err != nil {
-		spinner.Errorf(err, "Unable to get the secret for the package we are attempting to remove")
+		return fmt.Errorf("unable to load the secret for the package we are attempting to remove: %w", err)
+	}
 
-		return err
+	// If components were provided, just remove the things we were asked to remove
+	var requestedComponents []string
+	if config.DeployOptions.Components != "" {
+		requestedComponents = strings.Split(config.DeployOptions.Components, ",")
 	}
 
-	// Get the list of components the package had deployed
-	packages := types.DeployedPackage{}
-	err = json.Unmarshal(packageSecret.Data["data"], &packages)
+	plan, err := getRemovalPlan(deployedPackage, requestedComponents)
 	if err != nil {
-		spinner.Errorf(err, "Unable to load the secret for the package we are attempting to remove")
-
 		return err
 	}
+	printRemovalPlan(packageName, plan)
+	setResultPackage(packageName, deployedPackage.Data.Metadata.Version)
+
+	if dryRun {
+		for _, name := range plan.components {
+			startComponentResult(name)
+			finishComponentResult(name, resultPlanned)
+		}
+		finishCommandResult(resultDryRun, "")
+		return nil
+	}
+	if !confirmRemoval(plan) {
+		finishCommandResult(resultCancelled, "")
+		return nil
+	}
+
+	spinner := message.NewProgressSpinner("Removing zarf package %s", packageName)
+	defer spinner.Stop()
+
+	// Remove components in the reverse order of their dependencies so dependents go first
+	for _, name := range plan.components {
+		i := getDeployedComponentIndex(deployedPackage, name)
+		installedComponent := deployedPackage.DeployedComponents[i]
+		startComponentResult(name)
 
-	// If components were provided; just remove the things we were asked to remove and return
-	requestedComponents := strings.Split(config.DeployOptions.Components, ",")
-	if len(requestedComponents) > 0 && requestedComponents[0] != "" {
-		for i := len(packages.DeployedComponents) - 1; i >= 0; i-- {
-			installedComponent := packages.DeployedComponents[i]
+		for j := len(installedComponent.InstalledCharts) - 1; j >= 0; j-- {
+			installedChart := installedComponent.InstalledCharts[j]
+			spinner.Updatef("Uninstalling chart (%s) from the (%s) component", installedChart.ChartName, installedComponent.Name)
 
-			if slices.Contains(requestedComponents, installedComponent.Name) {
-				for _, installedChart := range installedComponent.InstalledCharts {
-					helm.RemoveChart(installedChart.Namespace, installedChart.ChartName, spinner)
+			if err := helm.RemoveChart(installedChart.Namespace, installedChart.ChartName, spinner); err != nil {
+				message.Errorf(err, "Unable to remove the installed helm chart (%s) from the namespace (%s) of component (%s) (were dependent components removed first?)",
+					installedChart.ChartName, installedChart.Namespace, installedComponent.Name)
+
+				// Record what is still installed so a later remove can pick up where this one stopped
+				installedComponent.InstalledCharts = installedComponent.InstalledCharts[:j+1]
+				deployedPackage.DeployedComponents[i] = installedComponent
+				if err := updatePackageSecret(secretName, deployedPackage); err != nil {
+					message.Warnf("Unable to update the %s package secret: %#v", secretName, err)
 				}
 
-				// Remove the component we just removed from the array
-				packages.DeployedComponents = append(packages.DeployedComponents[:i], packages.DeployedComponents[i+1:]...)
+				return err
 			}
+		}
 
-			if len(packages.DeployedComponents) == 0 {
-				// All the installed components were deleted, there for this package is no longer actually deployed
-				_ = k8s.DeleteSecret(packageSecret)
-			} else {
-				// Save the new secret with the removed components removed from the secret
-				newPackageSecret := k8s.GenerateSecret("zarf", secretName, corev1.SecretTypeOpaque)
-				newPackageSecret.Labels["package-deploy-info"] = config.GetActiveConfig().Metadata.Name
-				newPackageSecretData, _ := json.Marshal(packages)
-				newPackageSecret.Data["data"] = newPackageSecretData
-				err = k8s.ReplaceSecret(newPackageSecret)
-				if err != nil {
-					message.Warnf("Unable to replace the %s package secret: %#v", secretName, err)
-				}
+		// Put back anything the component's files replaced on this host
+		if len(installedComponent.Files) > 0 {
+			spinner.Updatef("Removing the files placed on the host by the (%s) component", installedComponent.Name)
+			if err := removeHostFiles(installedComponent.Files); err != nil {
+				message.Errorf(err, "Unable to remove the files placed on the host by the (%s) component", installedComponent.Name)
+				return err
 			}
 		}
-	} else {
-		// Loop through all the installed components and remove them
-		for i := len(packages.DeployedComponents) - 1; i >= 0; i-- {
-			installedComponent := packages.DeployedComponents[i]
 
-			// This component was installed onto the cluster. Prompt the user to see if they would like to remove it!
-			for _, installedChart := range installedComponent.InstalledCharts {
-				spinner.Updatef("Uninstalling chart (%s) from the (%s) component", installedChart.ChartName, installedComponent.Name)
+		// Remove the component we just removed from the array
+		deployedPackage.DeployedComponents = append(deployedPackage.DeployedComponents[:i], deployedPackage.DeployedComponents[i+1:]...)
+		if err := removeFromInventory(packageName, name); err != nil {
+			message.Warnf("Unable to remove the images of the (%s) component from the image inventory: %s", name, err.Error())
+		}
+		finishComponentResult(name, resultRemoved)
+	}
 
-				err = helm.RemoveChart(installedChart.Namespace, installedChart.ChartName, spinner)
-				if err != nil {
-					message.Errorf(err, "Unable to remove the installed helm chart (%s) from the namespace (%s) of component (%s) (were dependent components removed first?)",
-						installedChart.ChartName, installedChart.Namespace, installedComponent.Name)
+	// The namespaces Zarf created for the removed charts aren't needed once none of their releases are left
+	for _, namespace := range plan.emptyNamespaces {
+		spinner.Updatef("Removing the %s namespace", namespace)
+		if err := k8s.DeleteNamespace(namespace); err != nil {
+			message.Warnf("Unable to remove the %s namespace: %s", namespace, err.Error())
+		}
+	}
 
-					return err
-				}
-			}
+	if len(deployedPackage.DeployedComponents) == 0 {
+		// All the installed components were deleted, therefore this package is no longer actually deployed
+		spinner.Updatef("Removing the %s package secret", secretName)
+		if err := k8s.DeleteSecret(k8s.GenerateSecret(k8s.ZarfNamespace, secretName, corev1.SecretTypeOpaque)); err != nil {
+			spinner.Errorf(err, "Unable to remove the %s package secret", secretName)
+			return err
+		}
+
+		if err := k8s.DeleteConfigmap(k8s.ZarfNamespace, getInventoryName(packageName)); err != nil {
+			message.Warnf("Unable to remove the image inventory for %s: %#v", packageName, err)
 		}
-		k8s.DeleteSecret(packageSecret)
+	} else if err := updatePackageSecret(secretName, deployedPackage); err != nil {
+		// Save the new secret with the removed components removed from the secret
+		spinner.Errorf(err, "Unable to update the %s package secret", secretName)
+		return err
 	}
 
+	spinner.Success()
+	finishCommandResult(resultSucceeded, "")
 	return nil
 }
+
+// updatePackageSecret replaces the deployed package secret with the given package data
+func updatePackageSecret(secretName string, deployedPackage types.DeployedPackage) error {
+	message.Debugf("packager.updatePackageSecret(%s)", secretName)
+
+	packageSecret := k8s.GenerateSecret(k8s.ZarfNamespace, secretName, corev1.SecretTypeOpaque)
+	packageSecret.Labels["package-deploy-info"] = deployedPackage.Name
+
+	// The package was decrypted when it was loaded, so its sensitive exports are encrypted again
+	if err := k8s.EncryptDeployedPackage(&deployedPackage); err != nil {
+		return err
+	}
+
+	packageSecretData, err := json.Marshal(deployedPackage)
+	if err != nil {
+		return err
+	}
+	packageSecret.Data["data"] = packageSecretData
+
+	return k8s.ReplaceSecret(packageSecret)
+}
+
+// removalPlan is what removing a package, or some of its components, takes out of the cluster and off the host
+type removalPlan struct {
+	// components are the names of the deployed components to remove, dependents before the components they depend on
+	components []string
+	// emptyNamespaces are the namespaces Zarf created that are left without any helm release once the components are removed
+	emptyNamespaces []string
+	// deployedComponents are the removed components by name
+	deployedComponents map[string]types.DeployedComponent
+}
+
+// getRemovalPlan orders the requested components, or every deployed component, so each is removed before the components it depends on
+// Removing a component that a component staying behind depends on is refused
+func getRemovalPlan(deployedPackage types.DeployedPackage, requestedComponents []string) (removalPlan, error) {
+	plan := removalPlan{deployedComponents: make(map[string]types.DeployedComponent)}
+
+	var removing []types.ZarfComponent
+	var staying []types.DeployedComponent
+	for _, deployedComponent := range deployedPackage.DeployedComponents {
+		if len(requestedComponents) > 0 && !slices.Contains(requestedComponents, deployedComponent.Name) {
+			staying = append(staying, deployedComponent)
+			continue
+		}
+		removing = append(removing, getDeployedComponentDefinition(deployedPackage.Data, deployedComponent.Name))
+		plan.deployedComponents[deployedComponent.Name] = deployedComponent
+	}
+
+	for _, deployedComponent := range staying {
+		for _, dependency := range getDeployedComponentDefinition(deployedPackage.Data, deployedComponent.Name).DependsOn {
+			if _, ok := plan.deployedComponents[dependency]; ok {
+				return plan, fmt.Errorf("the %s component depends on %s, remove it as well or leave %s deployed", deployedComponent.Name, dependency, dependency)
+			}
+		}
+	}
+
+	// Removal runs the deployment order backwards, the components staying behind count as already deployed
+	groups, err := getDeploymentGroups(removing, staying)
+	if err != nil {
+		return plan, err
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		for j := len(groups[i]) - 1; j >= 0; j-- {
+			plan.components = append(plan.components, groups[i][j].Name)
+		}
+	}
+
+	plan.emptyNamespaces = getEmptiedNamespaces(plan.deployedComponents)
+	return plan, nil
+}
+
+// getEmptiedNamespaces returns the namespaces Zarf created whose helm releases are all removed with the components
+func getEmptiedNamespaces(deployedComponents map[string]types.DeployedComponent) []string {
+	removedReleases := make(map[string]map[string]bool)
+	for _, deployedComponent := range deployedComponents {
+		for _, installedChart := range deployedComponent.InstalledCharts {
+			if removedReleases[installedChart.Namespace] == nil {
+				removedReleases[installedChart.Namespace] = make(map[string]bool)
+			}
+			removedReleases[installedChart.Namespace][installedChart.ChartName] = true
+		}
+	}
+
+	namespaces, err := k8s.GetNamespaces()
+	if err != nil {
+		message.Debugf("Unable to get the namespaces, leaving them in place: %s", err.Error())
+		return nil
+	}
+
+	var emptied []string
+	for _, namespace := range namespaces.Items {
+		removed, ok := removedReleases[namespace.Name]
+		if !ok || namespace.Name == k8s.ZarfNamespace || namespace.Labels[config.ZarfManagedByLabel] != "zarf" {
+			continue
+		}
+
+		releases, err := helm.GetReleaseNames(namespace.Name)
+		if err != nil {
+			message.Debugf("Unable to list the releases in the %s namespace, leaving it in place: %s", namespace.Name, err.Error())
+			continue
+		}
+
+		empty := true
+		for _, release := range releases {
+			empty = empty && removed[release]
+		}
+		if empty {
+			emptied = append(emptied, namespace.Name)
+		}
+	}
+
+	sort.Strings(emptied)
+	return emptied
+}
+
+// printRemovalPlan shows every helm release, host file and namespace the removal takes out, in the order they are removed
+func printRemovalPlan(packageName string, plan removalPlan) {
+	planTable := pterm.TableData{{"     Component", "Removes", "Name"}}
+	for _, name := range plan.components {
+		deployedComponent := plan.deployedComponents[name]
+		for j := len(deployedComponent.InstalledCharts) - 1; j >= 0; j-- {
+			installedChart := deployedComponent.InstalledCharts[j]
+			planTable = append(planTable, []string{"     " + name, "helm release", fmt.Sprintf("%s/%s", installedChart.Namespace, installedChart.ChartName)})
+		}
+		for _, file := range deployedComponent.Files {
+			if err := checkHostFile(file); err != nil {
+				planTable = append(planTable, []string{"     " + name, "host file (kept: " + err.Error() + ")", file.Path})
+			} else {
+				planTable = append(planTable, []string{"     " + name, "host file", file.Path})
+			}
+		}
+		if len(deployedComponent.InstalledCharts) == 0 && len(deployedComponent.Files) == 0 {
+			planTable = append(planTable, []string{"     " + name, "record only", "-"})
+		}
+	}
+	for _, namespace := range plan.emptyNamespaces {
+		planTable = append(planTable, []string{"     -", "namespace", namespace})
+	}
+
+	message.Notef("Removing the zarf package %s takes out the following, in this order", packageName)
+	_ = pterm.DefaultTable.WithHasHeader().WithData(planTable).Render()
+	pterm.Println()
+}
+
+// confirmRemoval asks to go ahead with the removal unless --confirm was given
+func confirmRemoval(plan removalPlan) bool {
+	if config.CommonOptions.Confirm {
+		return true
+	}
+
+	target := "the cluster"
+	for _, deployedComponent := range plan.deployedComponents {
+		if len(deployedComponent.Files) > 0 {
+			target = "the cluster and this host"
+		}
+	}
+
+	var confirmFlag bool
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("Remove these from %s?", target),
+	}
+	if err := survey.AskOne(prompt, &confirmFlag); err != nil {
+		message.Fatalf(nil, "Confirm selection canceled: %s", err.Error())
+	}
+	return confirmFlag
+}
+
+// getDeployedComponentIndex returns where the named component is in the deployed components of the package
+func getDeployedComponentIndex(deployedPackage types.DeployedPackage, name string) int {
+	for idx, deployedComponent := range deployedPackage.DeployedComponents {
+		if deployedComponent.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,175 @@
+package packager
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// The ways a command or one of its components can end in the --result-file
+const (
+	resultSucceeded = "succeeded"
+	resultFailed    = "failed"
+	resultCancelled = "cancelled"
+	resultDryRun    = "dry-run"
+
+	resultRunning  = "running"
+	resultDeployed = "deployed"
+	resultRemoved  = "removed"
+	resultSkipped  = "skipped"
+	resultPlanned  = "planned"
+)
+
+// The summary of the running command written to the --result-file, collected as it runs
+var (
+	commandResult     *types.CommandResult
+	commandResultLock sync.Mutex
+	commandStart      time.Time
+	componentStarts   map[string]time.Time
+)
+
+// startCommandResult begins collecting the summary of a deploy, remove or init when --result-file is set
+// A fatal error still writes the file, with the error that stopped the command
+func startCommandResult(command string) {
+	if config.CommonOptions.ResultFile == "" {
+		return
+	}
+
+	commandStart = time.Now()
+	componentStarts = make(map[string]time.Time)
+	commandResult = &types.CommandResult{
+		Command:   command,
+		Status:    resultRunning,
+		StartedAt: commandStart.Format(time.RFC3339),
+	}
+
+	message.OnFatal(func(text string) {
+		finishCommandResult(resultFailed, text)
+	})
+}
+
+// setResultPackage records the package the command runs on, a deploy of the init package is recorded as an init
+func setResultPackage(name, version string) {
+	commandResultLock.Lock()
+	defer commandResultLock.Unlock()
+	if commandResult == nil {
+		return
+	}
+
+	commandResult.Package = name
+	commandResult.Version = version
+	if config.IsZarfInitConfig() && commandResult.Command == "deploy" {
+		commandResult.Command = "init"
+	}
+}
+
+// startComponentResult records that a component started, it counts as failed if the command stops before it finishes
+func startComponentResult(name string) {
+	commandResultLock.Lock()
+	defer commandResultLock.Unlock()
+	if commandResult == nil {
+		return
+	}
+
+	componentStarts[name] = time.Now()
+	commandResult.Components = append(commandResult.Components, types.ComponentResult{Name: name, Status: resultRunning})
+}
+
+// finishComponentResult records how a started component ended
+func finishComponentResult(name, status string) {
+	commandResultLock.Lock()
+	defer commandResultLock.Unlock()
+	if commandResult == nil {
+		return
+	}
+
+	for idx, component := range commandResult.Components {
+		if component.Name == name && component.Status == resultRunning {
+			commandResult.Components[idx].Status = status
+			commandResult.Components[idx].DurationSeconds = time.Since(componentStarts[name]).Seconds()
+		}
+	}
+}
+
+// finishCommandResult writes the summary to the --result-file once, the first way the command ends is the one recorded
+func finishCommandResult(status, errorText string) {
+	commandResultLock.Lock()
+	defer commandResultLock.Unlock()
+	if commandResult == nil {
+		return
+	}
+
+	finishedAt := time.Now()
+	commandResult.Status = status
+	commandResult.Error = errorText
+	commandResult.FinishedAt = finishedAt.Format(time.RFC3339)
+	commandResult.DurationSeconds = finishedAt.Sub(commandStart).Seconds()
+
+	for idx, component := range commandResult.Components {
+		if component.Status == resultRunning {
+			commandResult.Components[idx].Status = resultFailed
+			commandResult.Components[idx].DurationSeconds = finishedAt.Sub(componentStarts[component.Name]).Seconds()
+		}
+	}
+
+	if commandResult.Command != "remove" {
+		// A cancelled or dry run deploy never pushed anything
+		if status == resultSucceeded || status == resultFailed {
+			metrics := getDeployMetrics()
+			commandResult.Metrics = &metrics
+		}
+
+		commandResult.ConnectStrings = make(types.ConnectStrings, len(connectStrings))
+		for name, connectString := range connectStrings {
+			commandResult.ConnectStrings[name] = connectString
+		}
+	}
+
+	// Only where the generated credentials are kept is recorded, the file may be kept as a pipeline artifact
+	if commandResult.Command == "init" && status == resultSucceeded {
+		state := config.GetState()
+		location := k8s.GetStateCredentialsLocation(state.StateStore)
+		if state.RegistryInfo.InternalRegistry {
+			commandResult.Credentials = append(commandResult.Credentials, types.CredentialLocation{Name: "registry push and pull users", Location: location})
+		}
+		if state.GitServer.InternalServer && isDeployedInResult("git-server") {
+			commandResult.Credentials = append(commandResult.Credentials, types.CredentialLocation{Name: "git server push and pull users", Location: location})
+		}
+		if isDeployedInResult("logging") {
+			commandResult.Credentials = append(commandResult.Credentials, types.CredentialLocation{Name: "logging admin user", Location: location})
+		}
+	}
+
+	if err := writeCommandResult(commandResult); err != nil {
+		message.Errorf(err, "Unable to write the result file %s", config.CommonOptions.ResultFile)
+	}
+	commandResult = nil
+}
+
+// isDeployedInResult returns whether the command deployed the component, the caller holds the commandResultLock
+func isDeployedInResult(name string) bool {
+	for _, component := range commandResult.Components {
+		if component.Name == name && component.Status == resultDeployed {
+			return true
+		}
+	}
+	return false
+}
+
+func writeCommandResult(result *types.CommandResult) error {
+	contents, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := utils.CreateFilePath(config.CommonOptions.ResultFile); err != nil {
+		return err
+	}
+	return os.WriteFile(config.CommonOptions.ResultFile, contents, 0644)
+}
@@ -0,0 +1,57 @@
+package packager
+
+import (
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// checkpointDeployment saves the components deployed so far so a failed deployment can be resumed with --resume
+func checkpointDeployment(deployedComponents []types.DeployedComponent) {
+	// Init packages are still building the cluster state, so they are not checkpointed
+	if config.IsZarfInitConfig() || !packageUsesK8s() {
+		return
+	}
+
+	if err := saveDeployedPackage(deployedComponents); err != nil {
+		// Don't fail the deployment over a missed checkpoint, the next one or the final save will catch up
+		message.Debugf("Unable to checkpoint the deployment: %s", err.Error())
+	}
+}
+
+// getResumableComponents splits the components to deploy into the ones an earlier attempt already finished and the ones still pending
+func getResumableComponents(components []types.ZarfComponent) ([]types.DeployedComponent, []types.ZarfComponent) {
+	message.Debugf("packager.getResumableComponents(%#v)", components)
+
+	deployedPackage, err := k8s.GetDeployedPackage(config.GetActiveConfig().Metadata.Name)
+	if errors.IsNotFound(err) {
+		message.Warnf("No earlier deployment of %s was found to resume, deploying all components", config.GetActiveConfig().Metadata.Name)
+		return nil, components
+	} else if err != nil {
+		message.Fatalf(err, "Unable to load the secret for the deployment we are attempting to resume: %s", err.Error())
+	}
+
+	// Only resume the exact same package, anything else could leave components from two versions behind
+	if deployedPackage.Data.Metadata.Version != config.GetActiveConfig().Metadata.Version {
+		message.Fatalf(nil, "Unable to resume, the cluster has version %s of this package but version %s is being deployed",
+			deployedPackage.Data.Metadata.Version, config.GetActiveConfig().Metadata.Version)
+	}
+
+	finished := make(map[string]bool)
+	for _, component := range deployedPackage.DeployedComponents {
+		finished[component.Name] = true
+	}
+
+	var pending []types.ZarfComponent
+	for _, component := range components {
+		if finished[component.Name] {
+			message.Notef("Skipping the component (%s) since it was already deployed", component.Name)
+			continue
+		}
+		pending = append(pending, component)
+	}
+
+	return deployedPackage.DeployedComponents, pending
+}
@@ -0,0 +1,67 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// minScriptTokenTTL is the shortest lifetime the Kubernetes API allows for a service account token
+const minScriptTokenTTL = 10 * time.Minute
+
+// startScriptServiceAccount creates the temporary service account the scripts of a component run as and returns the environment that points them at it
+// The returned function removes the service account, which zarf tools gc also does if the deployment is interrupted
+func startScriptServiceAccount(component types.ZarfComponent, scripts []string, componentPath componentPaths) ([]string, func()) {
+	serviceAccount := component.Scripts.ServiceAccount
+	name := fmt.Sprintf("zarf-scripts-%s", strings.ToLower(component.Name))
+
+	spinner := message.NewProgressSpinner("Creating the service account %s/%s for the component scripts", serviceAccount.Namespace, name)
+	defer spinner.Stop()
+
+	var rules []rbacv1.PolicyRule
+	for _, rule := range serviceAccount.Rules {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     rule.APIGroups,
+			Resources:     rule.Resources,
+			ResourceNames: rule.ResourceNames,
+			Verbs:         rule.Verbs,
+		})
+	}
+
+	// The token only needs to outlive the scripts, which each stop at their timeout
+	timeoutSeconds := component.Scripts.TimeoutSeconds
+	if timeoutSeconds < 1 {
+		timeoutSeconds = 300
+	}
+	ttl := time.Duration(len(scripts)*timeoutSeconds)*time.Second + time.Minute
+	if ttl < minScriptTokenTTL {
+		ttl = minScriptTokenTTL
+	}
+
+	kubeconfig, err := k8s.CreateScopedServiceAccount(serviceAccount.Namespace, name, rules, ttl)
+	if err != nil {
+		spinner.Fatalf(err, "Unable to create the service account for the scripts of the %s component: %s", component.Name, err.Error())
+	}
+
+	kubeconfigPath := filepath.Join(componentPath.base, "scripts-kubeconfig")
+	if err := os.WriteFile(kubeconfigPath, kubeconfig, 0600); err != nil {
+		spinner.Fatalf(err, "Unable to write the kubeconfig for the scripts of the %s component", component.Name)
+	}
+
+	spinner.Successf("Created the service account %s/%s for the component scripts", serviceAccount.Namespace, name)
+
+	cleanup := func() {
+		_ = os.Remove(kubeconfigPath)
+		if err := k8s.DeleteScopedServiceAccount(serviceAccount.Namespace, name); err != nil {
+			message.Errorf(err, "Unable to remove the service account %s/%s, remove it with 'zarf tools gc'", serviceAccount.Namespace, name)
+		}
+	}
+	return []string{"KUBECONFIG=" + kubeconfigPath}, cleanup
+}
@@ -9,11 +9,13 @@ import (
 	"time"
 
 	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/template"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
 	"github.com/defenseunicorns/zarf/src/types"
 )
 
-func loopScriptUntilSuccess(script string, scripts types.ZarfComponentScripts) {
+// loopScriptUntilSuccess runs a script with env (KEY=VALUE) added to its environment until it succeeds or times out
+func loopScriptUntilSuccess(script string, scripts types.ZarfComponentScripts, dir string, env []string) {
 	spinner := message.NewProgressSpinner("Waiting for command \"%s\"", script)
 	defer spinner.Success()
 
@@ -30,7 +32,7 @@ func loopScriptUntilSuccess(script string, scripts types.ZarfComponentScripts) {
 
 	script, err := scriptMutation(script)
 	if err != nil {
-		spinner.Errorf(err, "Error mutating script: %s", script)
+		spinner.Fatalf(err, "Unable to prepare the script \"%s\": %s", script, err.Error())
 	}
 
 	spinner.Updatef("Waiting for command \"%s\" (timeout: %d seconds)", script, scripts.TimeoutSeconds)
@@ -45,17 +47,8 @@ func loopScriptUntilSuccess(script string, scripts types.ZarfComponentScripts) {
 		default:
 			ctx, cancel = context.WithTimeout(context.Background(), duration)
 
-			var shell string
-			var shellArgs string
-
-			if runtime.GOOS == "windows" {
-				shell = "powershell"
-				shellArgs = "-Command"
-			} else {
-				shell = "sh"
-				shellArgs = "-c"
-			}
-			output, errOut, err := utils.ExecCommandWithContext(ctx, scripts.ShowOutput, shell, shellArgs, script)
+			shell, shellArgs := getShell()
+			output, errOut, err := utils.ExecCommandWithContextDirAndEnv(ctx, dir, env, scripts.ShowOutput, shell, shellArgs, script)
 
 			defer cancel()
 
@@ -80,6 +73,14 @@ func loopScriptUntilSuccess(script string, scripts types.ZarfComponentScripts) {
 	}
 }
 
+// getShell returns the shell scripts are run with and the argument that passes it a command
+func getShell() (string, string) {
+	if runtime.GOOS == "windows" {
+		return "powershell", "-Command"
+	}
+	return "sh", "-c"
+}
+
 // Perform some basic string mutations to make scripts more useful
 func scriptMutation(script string) (string, error) {
 
@@ -88,6 +89,13 @@ func scriptMutation(script string) (string, error) {
 		return script, err
 	}
 
+	// Fill in any package variables and constants
+	script = template.ApplyVariables(script)
+	script, err = template.ApplyShellVariables(script)
+	if err != nil {
+		return script, err
+	}
+
 	// Try to patch the zarf binary path in case the name isn't exactly "./zarf"
 	script = strings.ReplaceAll(script, "./zarf ", binaryPath+" ")
 
@@ -1,16 +1,20 @@
 package packager
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/git"
 	"github.com/defenseunicorns/zarf/src/internal/images"
 	"github.com/defenseunicorns/zarf/src/internal/k8s"
 	"github.com/defenseunicorns/zarf/src/internal/message"
 	"github.com/defenseunicorns/zarf/src/internal/pki"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
 	"github.com/defenseunicorns/zarf/src/types"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func seedZarfState(tempPath tempPaths) {
@@ -37,7 +41,11 @@ func seedZarfState(tempPath tempPaths) {
 	// Attempt to load an existing state prior to init
 	// NOTE: We are ignoring the error here because we don't really expect a state to exist yet
 	spinner.Updatef("Checking cluster for existing Zarf deployment")
-	state, _ := k8s.LoadZarfState()
+	state, err := k8s.LoadZarfState()
+	if err != nil && state.Distro != "" {
+		// An existing state that can't be decrypted must not be mistaken for a new cluster and get new credentials
+		spinner.Fatalf(err, "Unable to load the existing Zarf state: %s", err.Error())
+	}
 
 	// If the distro isn't populated in the state, assume this is a new cluster
 	if state.Distro == "" {
@@ -108,8 +116,75 @@ func seedZarfState(tempPath tempPaths) {
 		state.StorageClass = config.InitOptions.StorageClass
 	}
 
+	// Catch a StorageClass that can't provide volumes now instead of after a long image push
+	if initNeedsStorage {
+		if err := validateStorageClass(state.StorageClass, spinner); err != nil {
+			spinner.Fatalf(err, "Unable to use the StorageClass for the stateful init components: %s", err.Error())
+		}
+	}
+
+	// Catch Pod Security admission rejecting the init components before anything is deployed
+	if err := validatePodSecurity(initPodSecurityLevels, spinner); err != nil {
+		spinner.Fatalf(err, "Unable to deploy the init components under the enforced Pod Security Standard: %s", err.Error())
+	}
+
+	state.AgentWebhook = config.InitOptions.AgentWebhook
+	if config.InitOptions.StateStore.Type != "" {
+		state.StateStore = config.InitOptions.StateStore
+	}
 	state.GitServer = fillInEmptyGitServerValues(config.InitOptions.GitServer)
-	state.RegistryInfo = fillInEmptyContainerRegistryValues(config.InitOptions.RegistryInfo)
+	if config.InitOptions.RegistryInfo.InClusterService != "" {
+		spinner.Updatef("Looking up the registry service %s", config.InitOptions.RegistryInfo.InClusterService)
+		if state.RegistryInfo, err = fillInClusterRegistryValues(config.InitOptions.RegistryInfo); err != nil {
+			spinner.Fatalf(err, "Unable to use the in-cluster registry: %s", err.Error())
+		}
+	} else {
+		state.RegistryInfo = fillInEmptyContainerRegistryValues(config.InitOptions.RegistryInfo)
+	}
+
+	// The registry can keep its images on a different StorageClass than the rest of the init components
+	if registryClass := state.RegistryInfo.Deployment.StorageClass; initNeedsStorage && registryClass != "" && registryClass != state.StorageClass {
+		if err := validateStorageClass(registryClass, spinner); err != nil {
+			spinner.Fatalf(err, "Unable to use the StorageClass for the registry: %s", err.Error())
+		}
+	}
+
+	// Certificates from the CA of the organization replace the plain HTTP of the Zarf registry and git server ingress
+	if len(config.InitOptions.RegistryTLS.Cert) > 0 && state.RegistryInfo.InternalRegistry {
+		state.RegistryInfo.CustomTLS = true
+		state.RegistryInfo.CABundle = config.InitOptions.RegistryTLS.CA
+		if state.RegistryInfo.NodePort > 0 {
+			state.RegistryInfo.Address = fmt.Sprintf("https://%s:%d", config.IPV4Localhost, state.RegistryInfo.NodePort)
+		}
+	}
+	if len(config.InitOptions.GitTLS.Cert) > 0 && state.GitServer.InternalServer {
+		state.GitServer.CustomTLS = true
+		state.GitServer.CABundle = config.InitOptions.GitTLS.CA
+	}
+
+	// Encrypt the credentials with the given key, an existing data key is kept so the values already encrypted with it can still be read
+	if config.InitOptions.StateEncryption.Provider != "" {
+		spinner.Updatef("Encrypting the Zarf state with the %s key", config.InitOptions.StateEncryption.Provider)
+		if err := k8s.SetStateEncryption(&state, config.InitOptions.StateEncryption); err != nil {
+			spinner.Fatalf(err, "Unable to encrypt the Zarf state: %s", err.Error())
+		}
+	}
+
+	// Zarf can't write to secrets managers synced by an external secrets operator, so their credentials replace the generated ones
+	if state.StateStore.Type == k8s.StateStoreExternalSecret {
+		spinner.Updatef("Loading the credentials from the external secret %s", state.StateStore.Secret)
+		if err := k8s.LoadStateCredentials(&state); err != nil {
+			spinner.Fatalf(err, "Unable to load the credentials from the external secret: %s", err.Error())
+		}
+	}
+
+	// Make sure an external registry is usable before it is written into the state, the nodeport of one in the cluster isn't reachable from here
+	if !state.RegistryInfo.InternalRegistry && !state.RegistryInfo.InClusterExternal {
+		spinner.Updatef("Validating the connection to the external registry %s", state.RegistryInfo.Address)
+		if err := images.ValidateRegistry(state.RegistryInfo); err != nil {
+			spinner.Fatalf(err, "Unable to use the external registry: %s", err.Error())
+		}
+	}
 
 	spinner.Success()
 
@@ -118,8 +193,51 @@ func seedZarfState(tempPath tempPaths) {
 		message.Fatal(err, "Unable to save the Zarf state data back to the cluster")
 	}
 
+	// The registry mounts the CA of its object store from a secret, since its chart has no other way to trust one
+	if len(state.RegistryInfo.S3.CABundle) > 0 {
+		caSecret := k8s.GenerateSecret(k8s.ZarfNamespace, config.ZarfRegistryS3CASecretName, corev1.SecretTypeOpaque)
+		caSecret.Data["ca.crt"] = state.RegistryInfo.S3.CABundle
+		if err := k8s.ReplaceSecret(caSecret); err != nil {
+			message.Fatal(err, "Unable to save the CA of the registry object store to the cluster")
+		}
+	}
+
+	// The registry reads the credentials of its upstream registry from a secret instead of its helm values
+	if state.RegistryInfo.ProxyURL != "" {
+		proxySecret := k8s.GenerateSecret(k8s.ZarfNamespace, config.ZarfRegistryProxySecretName, corev1.SecretTypeOpaque)
+		proxySecret.StringData = map[string]string{
+			"proxyUsername": state.RegistryInfo.ProxyUsername,
+			"proxyPassword": state.RegistryInfo.ProxyPassword,
+		}
+		if err := k8s.ReplaceSecret(proxySecret); err != nil {
+			message.Fatal(err, "Unable to save the credentials of the upstream registry to the cluster")
+		}
+	}
+
+	// The registry and the git server ingress are served from secrets holding the certificates zarf init was given
+	if state.RegistryInfo.CustomTLS {
+		if err := replaceCustomTLSSecret(config.ZarfRegistryTLSSecretName, config.InitOptions.RegistryTLS); err != nil {
+			message.Fatal(err, "Unable to save the certificate of the registry to the cluster")
+		}
+	}
+	if state.GitServer.CustomTLS {
+		if err := replaceCustomTLSSecret(config.ZarfGitTLSSecretName, config.InitOptions.GitTLS); err != nil {
+			message.Fatal(err, "Unable to save the certificate of the git server to the cluster")
+		}
+	}
+
 	// Load state for the rest of the operations
 	config.InitState(state)
+
+	// A wrong clock breaks the certificates Zarf generates or was given, so warn before anything uses them
+	checkClockAndCertificates(state)
+
+	// The Zarf git server creates its read-only user when it is deployed, external servers that manage users get it now
+	if !state.GitServer.InternalServer && git.HasReadOnlyUser(state.GitServer) {
+		if err := git.CreateReadOnlyUser(); err != nil {
+			message.Errorf(err, "Unable to create the read-only user on the git server")
+		}
+	}
 }
 
 func postSeedRegistry(tempPath tempPaths) error {
@@ -143,17 +261,79 @@ func postSeedRegistry(tempPath tempPaths) error {
 
 	// Push the seed images into to Zarf registry
 	seedImage := fmt.Sprintf("%s:%s", config.ZarfSeedImage, config.ZarfSeedTag)
-	err := images.PushToZarfRegistry(tempPath.seedImage, []string{seedImage}, false)
+	if _, err := images.PushToZarfRegistry(context.TODO(), tempPath.seedImage, []string{seedImage}, false); err != nil {
+		return err
+	}
+
+	// The nodes pull the permanent registry image from the load balancer, which only has an address once the seed registry is exposed
+	state := config.GetState()
+	if state.RegistryInfo.ServiceType == string(corev1.ServiceTypeLoadBalancer) {
+		spinner := message.NewProgressSpinner("Waiting for the load balancer of the Zarf registry to get an address")
+		defer spinner.Stop()
+
+		address, err := k8s.WaitForLoadBalancerAddress(k8s.ZarfNamespace, "zarf-docker-registry", 5*time.Minute)
+		if err != nil {
+			return err
+		}
+		state.RegistryInfo.Address = address
+		if err := k8s.SaveZarfState(state); err != nil {
+			return fmt.Errorf("unable to save the address of the registry load balancer to the Zarf state: %w", err)
+		}
+		config.InitState(state)
+		spinner.Successf("The nodes pull from the Zarf registry at %s", address)
+
+		if state.RegistryInfo.CustomTLS {
+			checkRegistryCertificateHost(address)
+		}
+	}
+
+	// The injector the CA daemonset pulled its image from is gone, so move it to the seed image in the Zarf registry
+	if registryCANeeded(state.RegistryInfo) {
+		spinner := message.NewProgressSpinner("Moving the CA daemonset of the Zarf registry to the seed image in the registry")
+		defer spinner.Stop()
 
-	return err
+		if err := distributeRegistryCA(config.GetRegistry(), spinner); err != nil {
+			spinner.Warnf("Unable to move the CA daemonset to the Zarf registry, nodes that join the cluster later may not trust it: %s", err.Error())
+		} else {
+			spinner.Success()
+		}
+	}
+
+	return nil
 }
 
 func fillInEmptyContainerRegistryValues(containerRegistry types.RegistryInfo) types.RegistryInfo {
 	// Set default url if an external registry was not provided
 	if containerRegistry.Address == "" {
 		containerRegistry.InternalRegistry = true
-		containerRegistry.NodePort = config.ZarfInClusterContainerRegistryNodePort
-		containerRegistry.Address = fmt.Sprintf("http://%s:%d", config.IPV4Localhost, containerRegistry.NodePort)
+
+		// Without a nodeport the nodes pull from the address of the ingress, or the load balancer once it has one
+		switch corev1.ServiceType(containerRegistry.ServiceType) {
+		case corev1.ServiceTypeClusterIP:
+			containerRegistry.NodePort = 0
+			containerRegistry.Address = containerRegistry.IngressHost
+		case corev1.ServiceTypeLoadBalancer:
+			containerRegistry.NodePort = 0
+		default:
+			containerRegistry.ServiceType = string(corev1.ServiceTypeNodePort)
+			if containerRegistry.NodePort == 0 {
+				containerRegistry.NodePort = config.ZarfInClusterContainerRegistryNodePort
+			}
+			containerRegistry.Address = fmt.Sprintf("http://%s:%d", config.IPV4Localhost, containerRegistry.NodePort)
+		}
+		containerRegistry.Deployment = fillInEmptyRegistryDeploymentValues(containerRegistry.Deployment)
+		if containerRegistry.S3.Bucket != "" && containerRegistry.S3.Region == "" {
+			containerRegistry.S3.Region = config.ZarfRegistryS3Region
+		}
+	}
+
+	// Registries pushed to with a workload identity get their tokens when they are used and nodes pull with their own cloud identity
+	// Registries with a credential helper get both push and pull tokens when they are used
+	if containerRegistry.PushIdentity != "" || containerRegistry.CredentialHelper != "" {
+		if containerRegistry.Secret == "" {
+			containerRegistry.Secret = utils.RandomString(config.ZarfGeneratedSecretLen)
+		}
+		return containerRegistry
 	}
 
 	// Generate a push-user password if not provided by init flag
@@ -186,12 +366,86 @@ func fillInEmptyContainerRegistryValues(containerRegistry types.RegistryInfo) ty
 	return containerRegistry
 }
 
+// fillInEmptyRegistryDeploymentValues sizes the Zarf registry with the defaults for anything zarf init wasn't given
+func fillInEmptyRegistryDeploymentValues(deployment types.RegistryDeployment) types.RegistryDeployment {
+	defaults := map[*string]string{
+		&deployment.PVCSize:       config.ZarfRegistryPVCSize,
+		&deployment.PVCAccessMode: config.ZarfRegistryPVCAccessMode,
+		&deployment.CPURequest:    config.ZarfRegistryCPURequest,
+		&deployment.MemoryRequest: config.ZarfRegistryMemoryRequest,
+		&deployment.CPULimit:      config.ZarfRegistryCPULimit,
+		&deployment.MemoryLimit:   config.ZarfRegistryMemoryLimit,
+	}
+	for field, value := range defaults {
+		if *field == "" {
+			*field = value
+		}
+	}
+
+	if deployment.Replicas < 1 {
+		deployment.Replicas = 1
+	}
+
+	return deployment
+}
+
+// fillInClusterRegistryValues points the state at a registry that was running in the cluster before Zarf
+// Nodes pull through its nodeport like they do from the Zarf registry, but the users come from the registry itself and Zarf never manages it
+func fillInClusterRegistryValues(containerRegistry types.RegistryInfo) (types.RegistryInfo, error) {
+	namespace, name, _ := strings.Cut(containerRegistry.InClusterService, "/")
+	service, err := k8s.GetService(namespace, name)
+	if err != nil {
+		return containerRegistry, fmt.Errorf("unable to find the registry service %s: %w", containerRegistry.InClusterService, err)
+	}
+
+	// The nodes can only pull through a nodeport, which the API server allocates from whatever range the cluster uses
+	if service.Spec.Type != corev1.ServiceTypeNodePort {
+		return containerRegistry, fmt.Errorf("the registry service %s must be of type NodePort so the nodes can pull from it", containerRegistry.InClusterService)
+	}
+
+	// Use the port behind the provided nodeport, or the first port of the service
+	for _, port := range service.Spec.Ports {
+		if containerRegistry.NodePort == 0 || int(port.NodePort) == containerRegistry.NodePort {
+			containerRegistry.InClusterServicePort = int(port.Port)
+			containerRegistry.NodePort = int(port.NodePort)
+			break
+		}
+	}
+	if containerRegistry.InClusterServicePort == 0 {
+		return containerRegistry, fmt.Errorf("the registry service %s does not expose the nodeport %d", containerRegistry.InClusterService, containerRegistry.NodePort)
+	}
+
+	containerRegistry.InternalRegistry = false
+	containerRegistry.InClusterExternal = true
+	containerRegistry.Address = fmt.Sprintf("http://%s:%d", config.IPV4Localhost, containerRegistry.NodePort)
+
+	// If a pull-user wasn't provided, use the same credentials as the push user
+	if containerRegistry.PullUsername == "" {
+		containerRegistry.PullUsername = containerRegistry.PushUsername
+	}
+	if containerRegistry.PullPassword == "" {
+		containerRegistry.PullPassword = containerRegistry.PushPassword
+	}
+
+	if containerRegistry.Secret == "" {
+		containerRegistry.Secret = utils.RandomString(config.ZarfGeneratedSecretLen)
+	}
+
+	return containerRegistry, nil
+}
+
 // Fill in empty GitServerInfo values with the defaults
 func fillInEmptyGitServerValues(gitServer types.GitServerInfo) types.GitServerInfo {
 	// Set default svc url if an external repository was not provided
 	if gitServer.Address == "" {
 		gitServer.Address = config.ZarfInClusterGitServiceURL
 		gitServer.InternalServer = true
+		gitServer.Provider = git.ProviderGitea
+		if gitServer.ServiceType == "" {
+			gitServer.ServiceType = string(corev1.ServiceTypeClusterIP)
+		}
+	} else if gitServer.Provider == "" {
+		gitServer.Provider = git.ProviderHTTP
 	}
 
 	// Generate a push-user password if not provided by init flag
@@ -0,0 +1,53 @@
+package packager
+
+import (
+	"context"
+	"os"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// includeSignatures returns whether create should bundle image signatures, which giving keys to verify them against implies
+func includeSignatures() bool {
+	return config.CreateOptions.IncludeSignatures || len(config.CreateOptions.SignatureKeys) > 0
+}
+
+// bundleImageSignatures pulls the signatures and attestations of the packaged images and records them in the build data
+func bundleImageSignatures(tempPath tempPaths, imageList []string) {
+	_ = os.RemoveAll(tempPath.signatures)
+
+	build := config.GetBuildData()
+	build.ImageSignatures = nil
+	if len(imageList) > 0 {
+		signatures, err := images.PullSignatures(tempPath.images, imageList, tempPath.signatures, config.CreateOptions.SignatureKeys)
+		if err != nil {
+			message.Fatalf(err, "Unable to bundle the image signatures")
+		}
+		if len(signatures) > 0 {
+			build.ImageSignatures = signatures
+		}
+	}
+	config.SetBuildData(build)
+}
+
+// pushImageSignatures pushes the bundled signatures of the images next to them so policy engines in the cluster can verify them
+func pushImageSignatures(tempPath tempPaths, pushedImages []types.DeployedImage) {
+	signatures := config.GetBuildData().ImageSignatures
+	hasSignatures := false
+	for _, image := range pushedImages {
+		hasSignatures = hasSignatures || len(signatures[image.Source]) > 0
+	}
+	if !hasSignatures {
+		return
+	}
+
+	err := runDeployPhase(DeployPhaseImages, func(ctx context.Context) error {
+		return images.PushSignaturesToZarfRegistry(ctx, tempPath.signatures, signatures, pushedImages)
+	})
+	if err != nil {
+		message.Fatalf(err, "Unable to push the image signatures to the Registry")
+	}
+}
@@ -0,0 +1,162 @@
+package packager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+// The provisioner of StorageClasses that only bind to PersistentVolumes created ahead of time
+const noProvisioner = "kubernetes.io/no-provisioner"
+
+// The test claim made to prove a StorageClass can bind volumes for the stateful init components
+const (
+	storageTestClaimName = "zarf-storage-check"
+	storageTestClaimSize = "1Mi"
+	storageTestTimeout   = 2 * time.Minute
+)
+
+// initComponentsNeedStorage returns true when any of the init components being deployed keep data in a PersistentVolume, as marked by requiresStorage
+// A registry that keeps its images in an object store has no volume, and one zarf init doesn't deploy has nothing to keep
+func initComponentsNeedStorage(components []types.ZarfComponent) bool {
+	registryHasVolume := !isUsingExistingRegistry() && config.InitOptions.RegistryInfo.S3.Bucket == ""
+	for _, component := range components {
+		if component.RequiresStorage && (registryHasVolume || !isInternalRegistryComponent(component)) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateStorageClass makes sure the stateful init components will be able to get their volumes before anything is pushed
+func validateStorageClass(storageClassName string, spinner *message.Spinner) error {
+	message.Debugf("packager.validateStorageClass(%s)", storageClassName)
+
+	spinner.Updatef("Validating the StorageClass for the stateful init components")
+
+	storageClass, err := getStorageClass(storageClassName)
+	if err != nil {
+		return err
+	}
+
+	// Static provisioning needs volumes to exist already
+	if storageClass.Provisioner == noProvisioner {
+		volumes, err := k8s.GetAvailablePersistentVolumes(storageClass.Name)
+		if err != nil {
+			return fmt.Errorf("unable to list the PersistentVolumes of the StorageClass %s: %w", storageClass.Name, err)
+		}
+		if len(volumes) == 0 {
+			return fmt.Errorf("the StorageClass %s has no dynamic provisioner and no available PersistentVolumes, "+
+				"create ReadWriteOnce PersistentVolumes for it first or choose another class with --storage-class", storageClass.Name)
+		}
+		if !hasAccessMode(volumes, corev1.ReadWriteOnce) {
+			return fmt.Errorf("none of the available PersistentVolumes of the StorageClass %s support ReadWriteOnce", storageClass.Name)
+		}
+	}
+
+	if !config.InitOptions.StorageClassCheck {
+		return nil
+	}
+
+	// Claims on WaitForFirstConsumer classes stay pending until a pod uses them, which can't be tested before the registry exists
+	if storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+		message.Notef("The StorageClass %s binds volumes when the first pod uses them, skipping the test claim", storageClass.Name)
+		return nil
+	}
+
+	return checkStorageClassBinds(storageClass.Name, spinner)
+}
+
+// getStorageClass finds the named StorageClass, or the cluster default when no name is given
+func getStorageClass(storageClassName string) (*storagev1.StorageClass, error) {
+	if storageClassName == "" {
+		storageClass, err := k8s.GetDefaultStorageClass()
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the default StorageClass: %w", err)
+		}
+		if storageClass == nil {
+			return nil, fmt.Errorf("the cluster has no default StorageClass, choose one with --storage-class (available: %s)", listStorageClasses())
+		}
+		return storageClass, nil
+	}
+
+	storageClasses, err := k8s.GetStorageClasses()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the StorageClasses: %w", err)
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Name == storageClassName {
+			return &storageClass, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the StorageClass %s does not exist, choose one with --storage-class (available: %s)", storageClassName, listStorageClasses())
+}
+
+// listStorageClasses names the StorageClasses in the cluster for error messages
+func listStorageClasses() string {
+	storageClasses, err := k8s.GetStorageClasses()
+	if err != nil || len(storageClasses.Items) == 0 {
+		return "none"
+	}
+
+	var names []string
+	for _, storageClass := range storageClasses.Items {
+		names = append(names, storageClass.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func hasAccessMode(volumes []corev1.PersistentVolume, accessMode corev1.PersistentVolumeAccessMode) bool {
+	for _, volume := range volumes {
+		for _, mode := range volume.Spec.AccessModes {
+			if mode == accessMode {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkStorageClassBinds makes a small test claim and waits for it to bind
+func checkStorageClassBinds(storageClassName string, spinner *message.Spinner) error {
+	spinner.Updatef("Waiting for a test claim on the StorageClass %s to bind", storageClassName)
+
+	if _, err := k8s.CreateNamespace(k8s.ZarfNamespace, nil); err != nil {
+		return fmt.Errorf("unable to create the zarf namespace: %w", err)
+	}
+
+	// Clear out a claim left behind by an interrupted check
+	_ = k8s.DeletePersistentVolumeClaim(k8s.ZarfNamespace, storageTestClaimName)
+
+	labels := map[string]string{config.ZarfRunLabel: config.GetRunID()}
+	if _, err := k8s.CreatePersistentVolumeClaim(k8s.ZarfNamespace, storageTestClaimName, labels, storageClassName, corev1.ReadWriteOnce, storageTestClaimSize); err != nil {
+		return fmt.Errorf("unable to create a test claim on the StorageClass %s: %w", storageClassName, err)
+	}
+	defer func() {
+		_ = k8s.DeletePersistentVolumeClaim(k8s.ZarfNamespace, storageTestClaimName)
+	}()
+
+	timeout := time.After(storageTestTimeout)
+	for {
+		claim, err := k8s.GetPersistentVolumeClaim(k8s.ZarfNamespace, storageTestClaimName)
+		if err == nil && claim.Status.Phase == corev1.ClaimBound {
+			return nil
+		}
+
+		select {
+		case <-timeout:
+			return fmt.Errorf("a ReadWriteOnce test claim on the StorageClass %s did not bind within %s, "+
+				"check the provisioner with 'kubectl describe pvc %s -n %s' or choose another class with --storage-class",
+				storageClassName, storageTestTimeout, storageTestClaimName, k8s.ZarfNamespace)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
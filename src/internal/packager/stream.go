@@ -0,0 +1,101 @@
+package packager
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+)
+
+// stdinPackagePath is the package path used to read a package archive from stdin
+const stdinPackagePath = "-"
+
+// The first bytes of every zstd frame
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// extractFromReader streams a (optionally zstd or gzip compressed) package tarball into the destination without staging the archive on disk
+func extractFromReader(ctx context.Context, in io.Reader, destination string) error {
+	message.Debugf("packager.extractFromReader(%s)", destination)
+
+	reader := bufio.NewReader(in)
+
+	// Short streams are left for the tar reader to reject
+	magic, _ := reader.Peek(len(zstdMagic))
+	tarReader := getTarFormat(magic)
+
+	if err := tarReader.Open(reader, 0); err != nil {
+		return err
+	}
+	defer tarReader.Close()
+
+	destination = filepath.Clean(destination)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		file, err := tarReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		header, ok := file.Header.(*tar.Header)
+		if !ok {
+			_ = file.Close()
+			return fmt.Errorf("unexpected archive header for %s", file.Name())
+		}
+
+		// Don't allow entries to escape the destination directory
+		path := filepath.Join(destination, header.Name)
+		if !strings.HasPrefix(path, destination+string(os.PathSeparator)) {
+			_ = file.Close()
+			return fmt.Errorf("illegal file path in package archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = utils.CreateDirectory(path, 0700)
+
+		case tar.TypeReg:
+			err = writeStreamedFile(file, path, file.Mode())
+
+		case tar.TypeSymlink, tar.TypeLink:
+			// A link could point anywhere on the host, and packages built by zarf package create don't have any
+			err = fmt.Errorf("the package archive has a link %s, which a streamed deploy doesn't support", header.Name)
+
+		default:
+			message.Warnf("Skipping the archive entry %s of unsupported type %c", header.Name, header.Typeflag)
+		}
+
+		_ = file.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeStreamedFile(reader io.Reader, path string, mode os.FileMode) error {
+	if err := utils.CreateDirectory(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
+	return err
+}
@@ -6,11 +6,15 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/helm"
 	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/template"
 	"github.com/defenseunicorns/zarf/src/internal/utils"
 	"github.com/defenseunicorns/zarf/src/types"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Run performs config validations and runs message.Fatal() on errors
@@ -34,17 +38,88 @@ func Run() {
 	}
 
 	uniqueNames := make(map[string]bool)
+	uniqueArchNames := make(map[string]bool)
+	groupDefaults := make(map[string]string)
 
 	for _, component := range components {
-		// ensure component name is unique
-		if _, ok := uniqueNames[component.Name]; ok {
+		// ensure component name is unique, a multi-arch package can have a copy of a component for each architecture
+		archName := component.Name
+		if config.IsMultiArch() {
+			archName = fmt.Sprintf("%s/%s", component.Name, component.Only.Cluster.Architecture)
+		}
+		if _, ok := uniqueArchNames[archName]; ok {
 			message.Fatalf(nil, "Component names must be unique")
 		}
+		uniqueArchNames[archName] = true
 		uniqueNames[component.Name] = true
 
+		// ensure a choice group has at most one default
+		if component.Group != "" && component.Default {
+			if existing, ok := groupDefaults[component.Group]; ok && existing != component.Name {
+				message.Fatalf(nil, "Components %s and %s cannot both be the default of the choice group %s", existing, component.Name, component.Group)
+			}
+			groupDefaults[component.Group] = component.Name
+		}
+
 		validateComponent(component)
 	}
 
+	// ensure exported value names are unique so every import has a single source
+	exporters := make(map[string]string)
+	for _, component := range components {
+		for _, export := range component.Exports {
+			if existing, ok := exporters[export.Name]; ok && existing != component.Name {
+				message.Fatalf(nil, "Components %s and %s cannot both export %s", existing, component.Name, export.Name)
+			}
+			exporters[export.Name] = component.Name
+		}
+	}
+
+	for _, component := range components {
+		for _, name := range component.Imports {
+			exporter, ok := exporters[name]
+			if !ok {
+				message.Fatalf(nil, "Component %s imports %s which no component in this package exports", component.Name, name)
+			}
+			if exporter == component.Name {
+				message.Fatalf(nil, "Component %s cannot import %s which it exports itself", component.Name, name)
+			}
+		}
+
+		for _, dependency := range component.DependsOn {
+			if dependency == component.Name {
+				message.Fatalf(nil, "Component %s cannot depend on itself", component.Name)
+			}
+			if !uniqueNames[dependency] {
+				message.Fatalf(nil, "Component %s depends on %s which is not a component in this package", component.Name, dependency)
+			}
+		}
+
+		if err := validateScriptVariables(component); err != nil {
+			message.Fatalf(err, "Invalid script in the %s component: %s", component.Name, err.Error())
+		}
+	}
+
+	// ensure a profile only selects components and presets variables that are in this package
+	declaredVariables := make(map[string]bool)
+	for _, variable := range config.GetActiveConfig().Variables {
+		declaredVariables[variable.Name] = true
+	}
+	componentGroups := make(map[string]string)
+	for _, component := range components {
+		componentGroups[component.Name] = component.Group
+	}
+	uniqueProfiles := make(map[string]bool)
+	for _, profile := range config.GetActiveConfig().Profiles {
+		if uniqueProfiles[profile.Name] {
+			message.Fatalf(nil, "Profile names must be unique")
+		}
+		uniqueProfiles[profile.Name] = true
+
+		if err := validateProfile(profile, componentGroups, declaredVariables); err != nil {
+			message.Fatalf(err, "Invalid profile %s: %s", profile.Name, err.Error())
+		}
+	}
 }
 
 func oneIfNotEmpty(testString string) int {
@@ -75,6 +150,37 @@ func validateComponent(component types.ZarfComponent) {
 			message.Fatalf(err, "Invalid manifest definition in the %s component: %s (%s)", component.Name, manifest.Name, err.Error())
 		}
 	}
+	for _, export := range component.Exports {
+		if err := validateExport(export); err != nil {
+			message.Fatalf(err, "Invalid export definition in the %s component: %s (%s)", component.Name, export.Name, err.Error())
+		}
+	}
+	artifactNames := make(map[string]bool)
+	for _, artifact := range component.Artifacts {
+		if err := validateArtifact(artifact); err != nil {
+			message.Fatalf(err, "Invalid artifact definition in the %s component: %s (%s)", component.Name, artifact.Name, err.Error())
+		}
+		if artifactNames[artifact.Name] {
+			message.Fatalf(nil, "Component %s has more than one artifact named %s", component.Name, artifact.Name)
+		}
+		artifactNames[artifact.Name] = true
+	}
+	binaryNames := make(map[string]bool)
+	for _, binary := range component.Binaries {
+		if err := validateBinary(binary); err != nil {
+			message.Fatalf(err, "Invalid binary definition in the %s component: %s (%s)", component.Name, binary.Name, err.Error())
+		}
+		if binaryNames[binary.Name] {
+			message.Fatalf(nil, "Component %s installs the binary %s more than once", component.Name, binary.Name)
+		}
+		binaryNames[binary.Name] = true
+	}
+	if err := validateNamespaceGuardrails(component.NamespaceGuardrails); err != nil {
+		message.Fatalf(err, "Invalid namespace guardrails in the %s component: %s", component.Name, err.Error())
+	}
+	if err := validateScriptServiceAccount(component.Scripts.ServiceAccount); err != nil {
+		message.Fatalf(err, "Invalid scripts service account in the %s component: %s", component.Name, err.Error())
+	}
 }
 
 func validatePackageName(subject string) error {
@@ -88,6 +194,35 @@ func validatePackageName(subject string) error {
 	return nil
 }
 
+func validateProfile(profile types.ZarfPackageProfile, componentGroups map[string]string, declaredVariables map[string]bool) error {
+	if !regexp.MustCompile(`^[a-z0-9\-]+$`).MatchString(profile.Name) {
+		return fmt.Errorf("profile name '%s' must be all lowercase and contain no special characters except -", profile.Name)
+	}
+
+	selectedGroups := make(map[string]string)
+	for _, name := range profile.Components {
+		group, ok := componentGroups[name]
+		if !ok {
+			return fmt.Errorf("the component %s is not in this package", name)
+		}
+		if group == "" {
+			continue
+		}
+		if existing, ok := selectedGroups[group]; ok {
+			return fmt.Errorf("the components %s and %s are both in the choice group %s", existing, name, group)
+		}
+		selectedGroups[group] = name
+	}
+
+	for key := range profile.Variables {
+		if !declaredVariables[strings.ToUpper(key)] {
+			return fmt.Errorf("the variable %s is not declared by this package", strings.ToUpper(key))
+		}
+	}
+
+	return nil
+}
+
 func validatePackageVariable(subject types.ZarfPackageVariable) error {
 	isAllCapsUnderscore := regexp.MustCompile(`^[A-Z_]+$`).MatchString
 
@@ -110,6 +245,104 @@ func validatePackageConstant(subject types.ZarfPackageConstant) error {
 	return nil
 }
 
+func validateExport(export types.ZarfComponentExport) error {
+	isAllCapsUnderscore := regexp.MustCompile(`^[A-Z_]+$`).MatchString
+
+	// ensure the export name is only capitals and underscores
+	if !isAllCapsUnderscore(export.Name) {
+		return fmt.Errorf("export name '%s' must be all uppercase and contain no special characters except _", export.Name)
+	}
+
+	// ensure the value comes from exactly one place
+	if oneIfNotEmpty(export.Value)+oneIfNotEmpty(export.Script) != 1 {
+		return fmt.Errorf("export %s must have either a value or a script", export.Name)
+	}
+
+	return nil
+}
+
+// validateScriptVariables checks the ${ZARF_VAR_*} and ${ZARF_CONST_*} the scripts of a component use are declared by the package
+// Variables are only set on deploy, so prepare scripts can only use constants
+func validateScriptVariables(component types.ZarfComponent) error {
+	constants := make(map[string]bool)
+	for _, constant := range config.GetActiveConfig().Constants {
+		constants["ZARF_CONST_"+constant.Name] = true
+	}
+	declared := make(map[string]bool)
+	for name := range constants {
+		declared[name] = true
+	}
+	for _, variable := range config.GetActiveConfig().Variables {
+		declared["ZARF_VAR_"+variable.Name] = true
+	}
+
+	check := func(scripts []string, available map[string]bool) error {
+		for _, script := range scripts {
+			for _, reference := range template.GetShellVariableReferences(script) {
+				if !available[reference] {
+					return fmt.Errorf("\"%s\" uses ${%s} which is not available to it, write $${%s} to pass it to the shell", script, reference, reference)
+				}
+			}
+		}
+		return nil
+	}
+
+	prepareScripts := append(append([]string{}, component.Scripts.Prepare...), component.Scripts.PostPrepare...)
+	if err := check(prepareScripts, constants); err != nil {
+		return err
+	}
+	deployScripts := append(append([]string{}, component.Scripts.Before...), component.Scripts.After...)
+	for _, export := range component.Exports {
+		if export.Script != "" {
+			deployScripts = append(deployScripts, export.Script)
+		}
+	}
+	return check(deployScripts, declared)
+}
+
+// validateNamespaceGuardrails checks the sizes of the namespace guardrails are quantities, sizes that use a template are checked on deploy
+func validateNamespaceGuardrails(guardrails types.ZarfNamespaceGuardrails) error {
+	for field, values := range map[string]map[string]string{
+		"resourceQuota":             guardrails.ResourceQuota,
+		"limitRange.default":        guardrails.LimitRange.Default,
+		"limitRange.defaultRequest": guardrails.LimitRange.DefaultRequest,
+		"limitRange.min":            guardrails.LimitRange.Min,
+		"limitRange.max":            guardrails.LimitRange.Max,
+	} {
+		for name, value := range values {
+			if strings.Contains(value, "###") {
+				continue
+			}
+			if _, err := resource.ParseQuantity(value); err != nil {
+				return fmt.Errorf("%s.%s: %s is not a valid quantity", field, name, value)
+			}
+		}
+	}
+	return nil
+}
+
+// validateScriptServiceAccount checks the service account of the deploy scripts has a namespace and rules that say what it can do
+func validateScriptServiceAccount(serviceAccount types.ZarfScriptServiceAccount) error {
+	if serviceAccount.Namespace == "" {
+		if len(serviceAccount.Rules) > 0 {
+			return fmt.Errorf("a namespace is required to limit the rules to")
+		}
+		return nil
+	}
+	if !regexp.MustCompile(`^[a-z0-9]([a-z0-9\-]*[a-z0-9])?$`).MatchString(serviceAccount.Namespace) {
+		return fmt.Errorf("namespace '%s' must be all lowercase and contain no special characters except -", serviceAccount.Namespace)
+	}
+	if len(serviceAccount.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	for idx, rule := range serviceAccount.Rules {
+		if len(rule.Resources) == 0 || len(rule.Verbs) == 0 {
+			return fmt.Errorf("rule %d must have resources and verbs", idx)
+		}
+	}
+	return nil
+}
+
 func validateChart(chart types.ZarfChart) error {
 	intro := fmt.Sprintf("chart %s", chart.Name)
 
@@ -141,6 +374,76 @@ func validateChart(chart types.ZarfChart) error {
 		return fmt.Errorf("%s must include a chart version", intro)
 	}
 
+	// Local charts are unpacked chart directories relative to the zarf.yaml
+	if chart.LocalPath != "" {
+		if _, err := os.Stat(filepath.Join(chart.LocalPath, "Chart.yaml")); err != nil {
+			return fmt.Errorf("%s localPath %s must be a chart directory containing a Chart.yaml", intro, chart.LocalPath)
+		}
+	}
+
+	// Must use a known values merge strategy
+	switch chart.ValuesMerge {
+	case "", helm.ValuesMergeHelm, helm.ValuesMergeOverride, helm.ValuesMergeDeep:
+	default:
+		return fmt.Errorf("%s has an unknown valuesMerge %s, must be one of helm, override or deep", intro, chart.ValuesMerge)
+	}
+
+	// Waiting for jobs requires waiting on the chart
+	if chart.WaitForJobs && chart.NoWait {
+		return fmt.Errorf("%s cannot set both noWait and waitForJobs", intro)
+	}
+
+	// Timeout must be a positive duration
+	if chart.Timeout != "" {
+		if timeout, err := time.ParseDuration(chart.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("%s has an invalid timeout %s, must be a positive duration such as 5m", intro, chart.Timeout)
+		}
+	}
+
+	if chart.Retries < 0 {
+		return fmt.Errorf("%s cannot have negative retries", intro)
+	}
+
+	// Atomic releases wait on the chart and clean up every failed attempt
+	if chart.Atomic && chart.NoWait {
+		return fmt.Errorf("%s cannot set both noWait and atomic", intro)
+	}
+	if chart.Atomic && chart.KeepFailed {
+		return fmt.Errorf("%s cannot set both atomic and keepFailed", intro)
+	}
+
+	return nil
+}
+
+func validateBinary(binary types.ZarfBinary) error {
+	intro := fmt.Sprintf("binary %s", binary.Name)
+
+	// The name becomes the file name on the host so keep it to a plain file name
+	if !regexp.MustCompile(`^[a-zA-Z0-9_.\-]+$`).MatchString(binary.Name) || binary.Name == "." || binary.Name == ".." {
+		return fmt.Errorf("%s must have a name made of letters, numbers, _, . or -", intro)
+	}
+
+	// Must have a source
+	if binary.Source == "" {
+		return fmt.Errorf("%s must include a source", intro)
+	}
+
+	return nil
+}
+
+func validateArtifact(artifact types.ZarfArtifact) error {
+	isAllCapsUnderscore := regexp.MustCompile(`^[A-Z_]+$`).MatchString
+
+	// The name becomes the ###ZARF_ARTIFACT_NAME### template of its reference
+	if !isAllCapsUnderscore(artifact.Name) {
+		return fmt.Errorf("artifact name '%s' must be all uppercase and contain no special characters except _", artifact.Name)
+	}
+
+	// Must have a source
+	if artifact.Source == "" {
+		return fmt.Errorf("artifact %s must include a source", artifact.Name)
+	}
+
 	return nil
 }
 
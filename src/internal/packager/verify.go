@@ -0,0 +1,128 @@
+package packager
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/git"
+	"github.com/defenseunicorns/zarf/src/internal/helm"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/k8s"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Verify re-validates a package that was already deployed onto a cluster and prints a pass/fail matrix of every check
+func Verify(packageName string) error {
+	message.Debugf("packager.Verify(%s)", packageName)
+
+	spinner := message.NewProgressSpinner("Verifying zarf package %s", packageName)
+	defer spinner.Stop()
+
+	// Get the secret for the deployed package
+	deployedPackage, err := k8s.GetDeployedPackage(packageName)
+	if err != nil {
+		return fmt.Errorf("unable to load the secret for the package we are attempting to verify: %w", err)
+	}
+
+	state, err := k8s.LoadZarfState()
+	if err != nil || state.Distro == "" {
+		return fmt.Errorf("unable to load the zarf/zarf-state secret, did you remember to run zarf init first?")
+	}
+	config.InitState(state)
+
+	verifyTable := pterm.TableData{
+		{"     Component", "Check", "Target", "Result"},
+	}
+	failures := 0
+
+	addResult := func(component, check, target string, err error) {
+		result := pterm.FgGreen.Sprint("PASS")
+		if err != nil {
+			failures++
+			result = pterm.FgRed.Sprintf("FAIL: %s", err.Error())
+		}
+		verifyTable = append(verifyTable, []string{"     " + component, check, target, result})
+	}
+
+	for _, deployedComponent := range deployedPackage.DeployedComponents {
+		component := getDeployedComponentDefinition(deployedPackage.Data, deployedComponent.Name)
+
+		spinner.Updatef("Verifying the images for the (%s) component", deployedComponent.Name)
+		if len(deployedComponent.Images) == 0 && len(getComponentImages(component)) > 0 {
+			addResult(deployedComponent.Name, "image", "*", fmt.Errorf("no image digests were recorded when this component was deployed"))
+		}
+		imageResults := images.VerifyDigests(deployedComponent.Images)
+		for _, image := range deployedComponent.Images {
+			addResult(deployedComponent.Name, "image", image.Source, imageResults[image.Source])
+		}
+
+		spinner.Updatef("Verifying the git repos for the (%s) component", deployedComponent.Name)
+		repoResults := git.VerifyRepos(component.Repos)
+		for _, repo := range component.Repos {
+			addResult(deployedComponent.Name, "repo", repo, repoResults[repo])
+		}
+
+		spinner.Updatef("Verifying the helm releases for the (%s) component", deployedComponent.Name)
+		for _, installedChart := range deployedComponent.InstalledCharts {
+			target := fmt.Sprintf("%s/%s", installedChart.Namespace, installedChart.ChartName)
+			status, err := helm.GetReleaseStatus(installedChart.Namespace, installedChart.ChartName, spinner)
+			if err == nil && status != release.StatusDeployed {
+				err = fmt.Errorf("release is %s", status)
+			}
+			addResult(deployedComponent.Name, "chart", target, err)
+		}
+
+		spinner.Updatef("Verifying the data injections for the (%s) component", deployedComponent.Name)
+		for _, data := range component.DataInjections {
+			target := fmt.Sprintf("%s/%s:%s", data.Target.Namespace, data.Target.Selector, data.Target.Path)
+			addResult(deployedComponent.Name, "data", target, verifyDataInjection(data, deployedComponent.DataInjectionMarker))
+		}
+	}
+
+	spinner.Success()
+
+	// Print out the matrix for the user
+	_ = pterm.DefaultTable.WithHasHeader().WithData(verifyTable).Render()
+
+	if failures > 0 {
+		return fmt.Errorf("%d verification check(s) failed for the package %s", failures, packageName)
+	}
+
+	return nil
+}
+
+// getDeployedComponentDefinition returns the component definition recorded with a deployed package
+func getDeployedComponentDefinition(pkg types.ZarfPackage, name string) types.ZarfComponent {
+	for _, component := range pkg.Components {
+		if component.Name == name {
+			return component
+		}
+	}
+	return types.ZarfComponent{Name: name}
+}
+
+// verifyDataInjection checks that the data injection completion marker is still present in every matching pod
+func verifyDataInjection(data types.ZarfDataInjection, marker string) error {
+	if marker == "" {
+		return fmt.Errorf("no data injection marker was recorded when this component was deployed")
+	}
+
+	pods := k8s.WaitForPodsAndContainers(data.Target, false)
+	if len(pods) < 1 {
+		return fmt.Errorf("no running pods matched the target")
+	}
+
+	markerPath := path.Join(data.Target.Path, marker)
+	for _, pod := range pods {
+		command := []string{"test", "-f", markerPath}
+		if _, _, err := k8s.ExecInPod(data.Target.Namespace, pod, data.Target.Container, command); err != nil {
+			return fmt.Errorf("the injection marker %s is missing from pod %s", markerPath, pod)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package packager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/defenseunicorns/zarf/src/config"
+	"github.com/defenseunicorns/zarf/src/internal/images"
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/internal/packager/validate"
+	"github.com/defenseunicorns/zarf/src/internal/sbom"
+	"github.com/defenseunicorns/zarf/src/internal/utils"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// How often the package directory is checked for changes in watch mode
+const watchInterval = 2 * time.Second
+
+// watchPackage rebuilds the components whose definition or local sources change and writes a new package after each rebuild
+// The workspace of the first build is reused so unchanged charts, repos and images are not fetched again
+func watchPackage(tempPath tempPaths, packageName string, builtComponents []types.ZarfComponent, fingerprints map[string]string, diff differentialData) {
+	cwd, _ := os.Getwd()
+	message.Notef("Watching %s for changes, press Ctrl+C to stop", cwd)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	built := make(map[string]types.ZarfComponent)
+	for _, component := range builtComponents {
+		built[component.Name] = component
+	}
+	packagedImages := getCombinedImageList(builtComponents, diff)
+
+	for {
+		select {
+		case <-interrupt:
+			return
+		case <-time.After(watchInterval):
+		}
+
+		// Reload the package definition so edits to the zarf.yaml are picked up
+		if err := config.LoadConfig(config.ZarfYAML, false); err != nil {
+			message.Warnf("Unable to read the zarf.yaml file, waiting for the next change: %s", err.Error())
+			continue
+		}
+		ComposeComponents()
+		if err := config.FillActiveTemplate(); err != nil {
+			message.Warnf("Unable to fill variables in the zarf.yaml file, waiting for the next change: %s", err.Error())
+			continue
+		}
+		components := config.GetComponents()
+
+		currentFingerprints := fingerprintComponents(components)
+		var changed []string
+		for _, component := range components {
+			if currentFingerprints[component.Name] != fingerprints[component.Name] {
+				changed = append(changed, component.Name)
+			}
+		}
+		for name := range fingerprints {
+			if _, ok := currentFingerprints[name]; !ok {
+				changed = append(changed, name)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+
+		message.HeaderInfof("🔁 REBUILDING %s", strings.ToUpper(strings.Join(changed, ", ")))
+		validate.Run()
+
+		var packageComponents []types.ZarfComponent
+		for _, component := range components {
+			if currentFingerprints[component.Name] != fingerprints[component.Name] {
+				// Start the component over so removed assets don't linger in the package
+				_ = os.RemoveAll(filepath.Join(tempPath.components, component.Name))
+				built[component.Name] = buildComponent(tempPath, component, diff)
+			}
+			packageComponents = append(packageComponents, built[component.Name])
+		}
+		for name := range fingerprints {
+			if _, ok := currentFingerprints[name]; !ok {
+				_ = os.RemoveAll(filepath.Join(tempPath.components, name))
+				delete(built, name)
+			}
+		}
+		fingerprints = currentFingerprints
+
+		// Images share one tarball so it is only pulled again when the image list changes
+		combinedImages := getCombinedImageList(packageComponents, diff)
+		enforceCreateImagePolicy(getPolicyImages(combinedImages))
+		if strings.Join(combinedImages, ",") != strings.Join(packagedImages, ",") {
+			_ = os.RemoveAll(tempPath.images)
+			_ = os.RemoveAll(tempPath.sboms)
+			if len(combinedImages) > 0 {
+				pulledImages := images.PullAll(combinedImages, tempPath.images)
+				sbom.CatalogImages(pulledImages, tempPath.sboms, tempPath.images)
+			}
+			if includeSignatures() {
+				bundleImageSignatures(tempPath, combinedImages)
+			}
+			packagedImages = combinedImages
+		}
+
+		config.SetComponents(packageComponents)
+		if err := config.BuildConfig(tempPath.zarfYaml); err != nil {
+			message.Fatalf(err, "Unable to write the %s file", tempPath.zarfYaml)
+		}
+
+		writePackage(tempPath, packageName)
+		message.SuccessF("Rebuilt %s, watching for more changes", packageName)
+	}
+}
+
+// getCombinedImageList returns the sorted, de-duplicated images of all the components in the package
+func getCombinedImageList(components []types.ZarfComponent, diff differentialData) []string {
+	var combinedImageList []string
+	for _, component := range components {
+		combinedImageList = append(combinedImageList, getPackagedImages(component, diff)...)
+	}
+	uniqueList := removeDuplicates(combinedImageList)
+	sort.Strings(uniqueList)
+	return uniqueList
+}
+
+// fingerprintComponents returns a hash per component of its definition and the local files it pulls in
+func fingerprintComponents(components []types.ZarfComponent) map[string]string {
+	fingerprints := make(map[string]string)
+	for _, component := range components {
+		hasher := sha256.New()
+
+		definition, _ := json.Marshal(component)
+		hasher.Write(definition)
+
+		for _, path := range getLocalSources(component) {
+			_ = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					// Sources that don't exist yet still change the fingerprint when they show up
+					fmt.Fprintf(hasher, "%s:missing\n", path)
+					return nil
+				}
+				if info, err := entry.Info(); err == nil && !entry.IsDir() {
+					fmt.Fprintf(hasher, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+				}
+				return nil
+			})
+		}
+
+		fingerprints[component.Name] = hex.EncodeToString(hasher.Sum(nil))
+	}
+	return fingerprints
+}
+
+// getLocalSources returns the paths on this machine that a component copies into the package
+func getLocalSources(component types.ZarfComponent) []string {
+	var sources []string
+
+	for _, chart := range component.Charts {
+		if chart.LocalPath != "" {
+			sources = append(sources, chart.LocalPath)
+		}
+		sources = append(sources, chart.ValuesFiles...)
+	}
+
+	for _, manifest := range component.Manifests {
+		sources = append(sources, manifest.Files...)
+		for _, kustomization := range manifest.Kustomizations {
+			if !utils.IsUrl(kustomization) {
+				sources = append(sources, kustomization)
+			}
+		}
+	}
+
+	for _, file := range component.Files {
+		if !utils.IsUrl(file.Source) {
+			sources = append(sources, file.Source)
+		}
+	}
+
+	for _, binary := range component.Binaries {
+		if !utils.IsUrl(binary.Source) {
+			sources = append(sources, binary.Source)
+		}
+	}
+
+	for _, data := range component.DataInjections {
+		sources = append(sources, data.Source)
+	}
+
+	return sources
+}
@@ -1,13 +1,19 @@
 package pki
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"math/big"
 	"net"
+	"strings"
 	"time"
 
 	"github.com/defenseunicorns/zarf/src/config"
@@ -24,53 +30,134 @@ const org = "Zarf Cluster"
 // 13 months is the max length allowed by browsers
 const validFor = time.Hour * 24 * 375
 
+// Options change the keys and certificates GeneratePKIWithOptions creates
+type Options struct {
+	// RSABits is the size of the RSA keys, it is ignored when ECDSACurve is set
+	RSABits int
+	// ECDSACurve creates ECDSA keys on the curve (P256, P384 or P521) instead of RSA keys
+	ECDSACurve string
+	// ValidFor is how long the certificates are valid, a certificate never outlives the CA that signs it
+	ValidFor time.Duration
+	// Organization replaces the organization of the CA and host certificates
+	Organization string
+	// CommonName is the common name of the host certificate, the host when it is empty
+	CommonName string
+	// CACert and CAKey are a PEM encoded CA that signs an intermediate CA instead of Zarf creating a new root CA
+	CACert []byte
+	CAKey  []byte
+}
+
+// DefaultOptions returns the options GeneratePKI uses
+func DefaultOptions() Options {
+	return Options{RSABits: rsaBits, ValidFor: validFor}
+}
+
 // GeneratePKI create a CA and signed server keypair
 func GeneratePKI(host string, dnsNames ...string) types.GeneratedPKI {
+	results, err := GeneratePKIWithOptions(host, DefaultOptions(), dnsNames...)
+	if err != nil {
+		message.Fatalf(err, "Unable to generate the PKI for %s", host)
+	}
+	return results
+}
+
+// GeneratePKIWithOptions creates a signed server keypair along with the CA that signs it
+// When the options have a CA, the keypair is signed by a new intermediate CA under it and the intermediate follows the host certificate in Cert
+func GeneratePKIWithOptions(host string, options Options, dnsNames ...string) (types.GeneratedPKI, error) {
 	results := types.GeneratedPKI{}
 
-	ca, caKey, err := generateCA(validFor)
-	if err != nil {
-		message.Fatal(err, "Unable to generate the ephemeral CA")
+	if options.ValidFor <= 0 {
+		return results, fmt.Errorf("the certificates must be valid for longer than 0s")
+	}
+	if options.ECDSACurve == "" && options.RSABits < rsaBits {
+		return results, fmt.Errorf("RSA keys must be at least %d bits", rsaBits)
+	}
+	if (len(options.CACert) > 0) != (len(options.CAKey) > 0) {
+		return results, fmt.Errorf("an intermediate CA needs both the certificate and the key of the CA that signs it")
 	}
 
-	hostCert, hostKey, err := generateCert(host, ca, caKey, validFor, dnsNames...)
-	if err != nil {
-		message.Fatalf(err, "Unable to generate the cert for %s", host)
+	var ca *x509.Certificate
+	var caKey crypto.Signer
+	var err error
+	if len(options.CACert) > 0 {
+		root, rootKey, err := parseCA(options.CACert, options.CAKey)
+		if err != nil {
+			return results, fmt.Errorf("unable to use the CA: %w", err)
+		}
+		if ca, caKey, err = generateIntermediateCA(root, rootKey, options); err != nil {
+			return results, fmt.Errorf("unable to generate the intermediate CA: %w", err)
+		}
+		results.CA = options.CACert
+	} else {
+		if ca, caKey, err = generateCA(options); err != nil {
+			return results, fmt.Errorf("unable to generate the ephemeral CA: %w", err)
+		}
+		results.CA = pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: ca.Raw,
+		})
 	}
 
-	results.CA = pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: ca.Raw,
-	})
+	hostCert, hostKey, err := generateCert(host, ca, caKey, options, dnsNames...)
+	if err != nil {
+		return results, fmt.Errorf("unable to generate the cert for %s: %w", host, err)
+	}
 
 	results.Cert = pem.EncodeToMemory(&pem.Block{
 		Type:  "CERTIFICATE",
 		Bytes: hostCert.Raw,
 	})
 
-	results.Key = pem.EncodeToMemory(&pem.Block{
-		Type:  "RSA PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(hostKey),
-	})
+	// Servers present the intermediate along with their certificate so clients only need the root
+	if len(options.CACert) > 0 {
+		results.Cert = append(results.Cert, pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: ca.Raw,
+		})...)
+	}
 
-	return results
+	if results.Key, err = encodePrivateKey(hostKey); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// CertExpiresWithin returns true if the given PEM encoded certificate expires before now + window
+func CertExpiresWithin(certPEM []byte, window time.Duration) (bool, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false, fmt.Errorf("unable to decode the PEM certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false, err
+	}
+
+	return time.Now().Add(window).After(cert.NotAfter), nil
 }
 
 // newCertificate creates a new template
-func newCertificate(validFor time.Duration) *x509.Certificate {
+func newCertificate(options Options) (*x509.Certificate, error) {
 	notBefore := time.Now()
-	notAfter := notBefore.Add(validFor)
+	notAfter := notBefore.Add(options.ValidFor)
 
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
-		message.Fatalf(err, "failed to generate the certificate serial number")
+		return nil, fmt.Errorf("failed to generate the certificate serial number: %w", err)
+	}
+
+	organization := org
+	if options.Organization != "" {
+		organization = options.Organization
 	}
 
 	return &x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
-			Organization: []string{org},
+			Organization: []string{organization},
 		},
 		NotBefore: notBefore,
 		NotAfter:  notAfter,
@@ -78,49 +165,134 @@ func newCertificate(validFor time.Duration) *x509.Certificate {
 		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
+	}, nil
+}
+
+// newPrivateKey creates a new RSA private key, or an ECDSA one when the options have a curve
+func newPrivateKey(options Options) (crypto.Signer, error) {
+	switch strings.ToUpper(strings.ReplaceAll(options.ECDSACurve, "-", "")) {
+	case "":
+		return rsa.GenerateKey(rand.Reader, options.RSABits)
+	case "P256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "P384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "P521":
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported ECDSA curve %s, valid options are: P256, P384, P521", options.ECDSACurve)
 	}
 }
 
-// newPrivateKey creates a new private key
-func newPrivateKey() (*rsa.PrivateKey, error) {
-	return rsa.GenerateKey(rand.Reader, rsaBits)
+// encodePrivateKey PEM encodes an RSA key as PKCS1 and an ECDSA key as SEC1
+func encodePrivateKey(key crypto.Signer) ([]byte, error) {
+	switch privateKey := key.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: der,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// parseCA reads a PEM encoded CA certificate and the key that belongs to it
+func parseCA(certPEM, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ca, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ca.IsCA || ca.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, nil, fmt.Errorf("%s is not a CA that can sign certificates", ca.Subject.CommonName)
+	}
+	if time.Now().After(ca.NotAfter) {
+		return nil, nil, fmt.Errorf("%s expired at %s", ca.Subject.CommonName, ca.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	caKey, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported private key type %T", keyPair.PrivateKey)
+	}
+	return ca, caKey, nil
 }
 
 // generateCA creates a new CA certificate, saves the certificate
 // and returns the x509 certificate and crypto private key. This
 // private key should never be saved to disk, but rather used to
 // immediately generate further certificates.
-func generateCA(validFor time.Duration) (*x509.Certificate, *rsa.PrivateKey, error) {
-	template := newCertificate(validFor)
+func generateCA(options Options) (*x509.Certificate, crypto.Signer, error) {
+	template, err := newCertificate(options)
+	if err != nil {
+		return nil, nil, err
+	}
 	template.IsCA = true
 	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
 	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
 	template.Subject.CommonName = "ca.private.zarf.dev"
-	template.Subject.Organization = []string{"Zarf Community"}
+	if options.Organization == "" {
+		template.Subject.Organization = []string{"Zarf Community"}
+	}
 
-	priv, err := newPrivateKey()
+	priv, err := newPrivateKey(options)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
+	return signCertificate(template, template, priv, priv)
+}
+
+// generateIntermediateCA creates a CA signed by the given CA that can only sign host certificates.
+// Like the root CA, its private key is only used to sign the host certificate and is never saved.
+func generateIntermediateCA(ca *x509.Certificate, caKey crypto.Signer, options Options) (*x509.Certificate, crypto.Signer, error) {
+	template, err := newCertificate(options)
 	if err != nil {
 		return nil, nil, err
 	}
+	template.IsCA = true
+	template.MaxPathLenZero = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	template.Subject.CommonName = "intermediate.private.zarf.dev"
+	if options.Organization == "" {
+		template.Subject.Organization = []string{"Zarf Community"}
+	}
 
-	ca, err := x509.ParseCertificate(derBytes)
+	if template.NotAfter.After(ca.NotAfter) {
+		message.Warnf("The CA %s expires at %s, so the certificates signed under it are only valid until then", ca.Subject.CommonName, ca.NotAfter.UTC().Format(time.RFC3339))
+		template.NotAfter = ca.NotAfter
+	}
+
+	priv, err := newPrivateKey(options)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return ca, priv, nil
+	return signCertificate(template, ca, priv, caKey)
 }
 
 // generateCert generates a new certificate for the given host using the
 // provided certificate authority. The cert and key files are stored in
 // the provided files.
-func generateCert(host string, ca *x509.Certificate, caKey *rsa.PrivateKey, validFor time.Duration, dnsNames ...string) (*x509.Certificate, *rsa.PrivateKey, error) {
-	template := newCertificate(validFor)
+func generateCert(host string, ca *x509.Certificate, caKey crypto.Signer, options Options, dnsNames ...string) (*x509.Certificate, crypto.Signer, error) {
+	template, err := newCertificate(options)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	template.IPAddresses = append(template.IPAddresses, net.ParseIP(config.IPV4Localhost))
 
@@ -133,13 +305,31 @@ func generateCert(host string, ca *x509.Certificate, caKey *rsa.PrivateKey, vali
 	}
 
 	template.Subject.CommonName = host
+	if options.CommonName != "" {
+		template.Subject.CommonName = options.CommonName
+	}
+
+	// A certificate can't be valid for longer than the CA that signs it
+	if template.NotAfter.After(ca.NotAfter) {
+		template.NotAfter = ca.NotAfter
+	}
 
-	privateKey, err := newPrivateKey()
+	privateKey, err := newPrivateKey(options)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, template, ca, privateKey.Public(), caKey)
+	// Key encipherment only applies to RSA keys
+	if _, ok := privateKey.(*ecdsa.PrivateKey); ok {
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+	}
+
+	return signCertificate(template, ca, privateKey, caKey)
+}
+
+// signCertificate creates the certificate for the public half of the key, signed by the parent
+func signCertificate(template, parent *x509.Certificate, key, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer, error) {
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -149,5 +339,5 @@ func generateCert(host string, ca *x509.Certificate, caKey *rsa.PrivateKey, vali
 		return nil, nil, err
 	}
 
-	return cert, privateKey, nil
+	return cert, key, nil
 }
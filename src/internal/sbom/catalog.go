@@ -137,12 +137,23 @@ func (builder *Builder) createImageSBOM(tag name.Tag) ([]byte, error) {
 	return jsonData, nil
 }
 
-func (builder *Builder) getNormalizedTag(tag name.Tag) string {
+// getNormalizedTag returns the image tag with the characters that can't be in a file name replaced
+func getNormalizedTag(tag name.Tag) string {
 	return transformRegex.ReplaceAllString(tag.String(), "_")
 }
 
+// GetSBOMFileName returns the name of the SBOM json file generated for the given image
+func GetSBOMFileName(image string) (string, error) {
+	tag, err := name.NewTag(image, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	return getNormalizedTag(tag) + ".json", nil
+}
+
 func (builder *Builder) createSBOMFile(name string, tag name.Tag) (*os.File, error) {
-	file := fmt.Sprintf(name, builder.getNormalizedTag(tag))
+	file := fmt.Sprintf(name, getNormalizedTag(tag))
 	path := filepath.Join(builder.dir, file)
 	return os.Create(path)
 }
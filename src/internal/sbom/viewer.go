@@ -64,7 +64,7 @@ func (builder *Builder) generateImageListJSON(tagToImage map[name.Tag]v1.Image)
 	var imageList []string
 
 	for tag := range tagToImage {
-		normalized := builder.getNormalizedTag(tag)
+		normalized := getNormalizedTag(tag)
 		imageList = append(imageList, normalized)
 	}
 
@@ -3,6 +3,9 @@ package template
 import (
 	"encoding/base64"
 	"fmt"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/defenseunicorns/zarf/src/types"
@@ -12,6 +15,12 @@ import (
 	"github.com/defenseunicorns/zarf/src/internal/utils"
 )
 
+// imageKeyPattern matches the characters that can't be part of an ###ZARF_IMAGE_NAME### key
+var imageKeyPattern = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// shellVariablePattern matches ${ZARF_VAR_NAME} and ${ZARF_CONST_NAME} in scripts, along with the extra $ that escapes them
+var shellVariablePattern = regexp.MustCompile(`(\$?)\$\{ZARF_(VAR|CONST)_([A-Z0-9_]+)\}`)
+
 type Values struct {
 	state        types.ZarfState
 	seedRegistry string
@@ -77,6 +86,7 @@ func (values Values) Apply(component types.ZarfComponent, path string) {
 	}
 
 	builtinMap := map[string]string{
+		"ARCHITECTURE":       values.state.Architecture,
 		"STORAGE_CLASS":      values.state.StorageClass,
 		"REGISTRY":           values.registry,
 		"NODEPORT":           fmt.Sprintf("%d", values.state.RegistryInfo.NodePort),
@@ -99,15 +109,114 @@ func (values Values) Apply(component types.ZarfComponent, path string) {
 		builtinMap["AGENT_KEY"] = base64.StdEncoding.EncodeToString(values.agentTLS.Key)
 		builtinMap["AGENT_CA"] = base64.StdEncoding.EncodeToString(values.agentTLS.CA)
 
+		// States from before the replicas could be set keep the two replicas the agent always had
+		agentReplicas := values.state.AgentWebhook.Replicas
+		if agentReplicas < 1 {
+			agentReplicas = config.ZarfAgentReplicas
+		}
+		builtinMap["AGENT_REPLICAS"] = strconv.Itoa(agentReplicas)
+
+		// The agent image is pushed without a checksum, but still follows the push path of the registry
+		if len(component.Images) > 0 {
+			registryInfo := values.state.RegistryInfo
+			agentImage, err := utils.SwapHostWithPushPath(component.Images[0], values.registry, registryInfo.PushPath, registryInfo.Project, false)
+			if err != nil {
+				message.Fatalf(err, "Unable to get the name of the agent image in the registry")
+			}
+			builtinMap["AGENT_IMAGE"] = agentImage
+		}
+
 	case "zarf-seed-registry", "zarf-registry":
 		builtinMap["SEED_REGISTRY"] = values.seedRegistry
 		builtinMap["HTPASSWD"] = values.secret.htpasswd
 		builtinMap["REGISTRY_SECRET"] = values.secret.registrySecret
 
+		// The seed registry has to take the pushed seed image, so only the permanent registry becomes a pull-through cache
+		registryInfo := values.state.RegistryInfo
+		deployment := registryInfo.Deployment
+
+		// The seed registry values file keeps it at a single replica
+		builtinMap["REGISTRY_PVC_SIZE"] = deployment.PVCSize
+		builtinMap["REGISTRY_PVC_ACCESS_MODE"] = deployment.PVCAccessMode
+		builtinMap["REGISTRY_STORAGE_CLASS"] = deployment.StorageClass
+		if deployment.StorageClass == "" {
+			builtinMap["REGISTRY_STORAGE_CLASS"] = values.state.StorageClass
+		}
+		builtinMap["REGISTRY_REPLICAS"] = strconv.Itoa(deployment.Replicas)
+		builtinMap["REGISTRY_HPA_ENABLED"] = strconv.FormatBool(deployment.MaxReplicas > 0)
+		builtinMap["REGISTRY_HPA_MAX"] = strconv.Itoa(deployment.MaxReplicas)
+		builtinMap["REGISTRY_CPU_REQUEST"] = deployment.CPURequest
+		builtinMap["REGISTRY_MEMORY_REQUEST"] = deployment.MemoryRequest
+		builtinMap["REGISTRY_CPU_LIMIT"] = deployment.CPULimit
+		builtinMap["REGISTRY_MEMORY_LIMIT"] = deployment.MemoryLimit
+
+		// The chart only sets the nodeport on a NodePort service
+		builtinMap["REGISTRY_SERVICE_TYPE"] = registryInfo.ServiceType
+		builtinMap["REGISTRY_INGRESS_ENABLED"] = strconv.FormatBool(registryInfo.IngressHost != "")
+		builtinMap["REGISTRY_INGRESS_HOST"] = registryInfo.IngressHost
+
+		// A certificate given to zarf init is served by the ingress when there is one, otherwise by the registry itself
+		builtinMap["REGISTRY_TLS_SECRET"] = ""
+		builtinMap["REGISTRY_INGRESS_TLS"] = "[]"
+		if registryInfo.CustomTLS {
+			if registryInfo.ServiceType == "ClusterIP" {
+				builtinMap["REGISTRY_INGRESS_TLS"] = fmt.Sprintf(`[{"secretName": %q, "hosts": [%q]}]`, config.ZarfRegistryTLSSecretName, registryInfo.IngressHost)
+			} else {
+				builtinMap["REGISTRY_TLS_SECRET"] = config.ZarfRegistryTLSSecretName
+			}
+		}
+
+		// Both registries keep their images in the object store so the permanent one starts with what was pushed to the seed
+		s3 := registryInfo.S3
+		builtinMap["REGISTRY_STORAGE_DRIVER"] = "filesystem"
+		if s3.Bucket != "" {
+			builtinMap["REGISTRY_STORAGE_DRIVER"] = "s3"
+		}
+		builtinMap["REGISTRY_PERSISTENCE_ENABLED"] = strconv.FormatBool(s3.Bucket == "")
+		builtinMap["REGISTRY_S3_ENDPOINT"] = s3.Endpoint
+		builtinMap["REGISTRY_S3_SECURE"] = strconv.FormatBool(!strings.HasPrefix(s3.Endpoint, "http://"))
+		builtinMap["REGISTRY_S3_REGION"] = s3.Region
+		builtinMap["REGISTRY_S3_BUCKET"] = s3.Bucket
+		builtinMap["REGISTRY_S3_ACCESS_KEY"] = s3.AccessKey
+		builtinMap["REGISTRY_S3_SECRET_KEY"] = s3.SecretKey
+
+		builtinMap["REGISTRY_PROXY_ENABLED"] = strconv.FormatBool(component.Name == "zarf-registry" && registryInfo.ProxyURL != "")
+		builtinMap["REGISTRY_PROXY_URL"] = registryInfo.ProxyURL
+		builtinMap["REGISTRY_PROXY_SECRET"] = config.ZarfRegistryProxySecretName
+
+	case "git-server":
+		// A headless service can't be exposed on the nodes or a load balancer
+		gitServer := values.state.GitServer
+		builtinMap["GIT_SERVICE_TYPE"] = gitServer.ServiceType
+		builtinMap["GIT_CLUSTER_IP"] = ""
+		if gitServer.ServiceType == "" || gitServer.ServiceType == "ClusterIP" {
+			builtinMap["GIT_CLUSTER_IP"] = "None"
+		}
+		builtinMap["GIT_NODE_PORT"] = ""
+		if gitServer.NodePort > 0 {
+			builtinMap["GIT_NODE_PORT"] = strconv.Itoa(gitServer.NodePort)
+		}
+		builtinMap["GIT_INGRESS_ENABLED"] = strconv.FormatBool(gitServer.IngressHost != "")
+		builtinMap["GIT_INGRESS_HOST"] = gitServer.IngressHost
+		builtinMap["GIT_INGRESS_TLS"] = "[]"
+		if gitServer.CustomTLS {
+			builtinMap["GIT_INGRESS_TLS"] = fmt.Sprintf(`[{"secretName": %q, "hosts": [%q]}]`, config.ZarfGitTLSSecretName, gitServer.IngressHost)
+		}
+
 	case "logging":
 		builtinMap["LOGGING_AUTH"] = values.secret.logging
 	}
 
+	// Charts that take images in fields the agent doesn't rewrite, like env vars or custom resources, can use the reference each image is pushed to
+	for key, reference := range values.getImageReferences(component) {
+		builtinMap["IMAGE_"+key] = reference
+	}
+
+	// Controllers that pull their config from the registry, like policy engines, get the reference each artifact is pushed to
+	for key, reference := range values.getArtifactReferences(component) {
+		builtinMap["ARTIFACT_"+key] = reference
+	}
+
 	// Iterate over any custom variables and add them to the mappings for templating
 	templateMap := map[string]string{}
 	for key, value := range builtinMap {
@@ -115,6 +224,187 @@ func (values Values) Apply(component types.ZarfComponent, path string) {
 		templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_%s###", key))] = value
 	}
 
+	for key, value := range getVariableTemplateMap() {
+		templateMap[key] = value
+	}
+
+	for key, value := range getImportTemplateMap(component.Imports) {
+		templateMap[key] = value
+	}
+
+	message.Debugf("templateMap = %#v", templateMap)
+	utils.ReplaceTextTemplate(path, templateMap)
+}
+
+// getImageReferences returns the reference each image of the component has in the Zarf registry, keyed by the last element of its path
+// Images that share that name are keyed by their whole path instead, and by their tag or digest as well when that is shared too
+func (values Values) getImageReferences(component types.ZarfComponent) map[string]string {
+	componentImages := append(append([]string{}, component.Images...), component.ArchImages[values.state.Architecture]...)
+	if len(componentImages) == 0 {
+		return nil
+	}
+
+	registryInfo := values.state.RegistryInfo
+	isRegistry := component.Name == "zarf-seed-registry" || component.Name == "zarf-registry"
+	pullThroughProxy := registryInfo.ProxyURL != "" && !(config.IsZarfInitConfig() && isRegistry)
+	// The agent can't mutate itself, so its images are pushed without a checksum
+	addChecksum := !(config.IsZarfInitConfig() && component.Name == "zarf-agent")
+
+	var candidates [][]string
+	references := make(map[string]string)
+	for _, src := range componentImages {
+		if _, ok := references[src]; ok {
+			continue
+		}
+
+		var reference string
+		var err error
+		if pullThroughProxy {
+			reference, err = utils.SwapHostWithoutChecksum(src, values.registry)
+		} else {
+			reference, err = utils.SwapHostWithPushPath(src, values.registry, registryInfo.PushPath, registryInfo.Project, addChecksum)
+		}
+		image, parseErr := utils.ParseImageURL(src)
+		if err != nil || parseErr != nil {
+			message.Debugf("Unable to get the reference of %s in the registry: %v %v", src, err, parseErr)
+			continue
+		}
+		references[src] = reference
+
+		toKey := func(text string) string {
+			return strings.Trim(imageKeyPattern.ReplaceAllString(strings.ToUpper(text), "_"), "_")
+		}
+		candidates = append(candidates, []string{
+			src,
+			toKey(path.Base(image.Path)),
+			toKey(image.Path),
+			toKey(image.Path + "_" + image.TagOrDigest),
+		})
+	}
+
+	imageMap := make(map[string]string)
+	for _, candidate := range candidates {
+		for level := 1; level < len(candidate); level++ {
+			shared := 0
+			for _, other := range candidates {
+				if other[level] == candidate[level] {
+					shared++
+				}
+			}
+			if shared == 1 {
+				imageMap[candidate[level]] = references[candidate[0]]
+				break
+			}
+		}
+	}
+
+	return imageMap
+}
+
+// getArtifactReferences returns the reference each OCI artifact of the component has in the registry, keyed by its name
+// The controllers that pull artifacts run in pods rather than on the nodes, so they reach the Zarf registry through its service
+func (values Values) getArtifactReferences(component types.ZarfComponent) map[string]string {
+	if len(component.Artifacts) == 0 {
+		return nil
+	}
+
+	registryInfo := values.state.RegistryInfo
+	registry := registryInfo.Address
+	if host := config.GetInClusterRegistryHost(registryInfo); host != "" {
+		registry = host
+	}
+
+	artifactMap := make(map[string]string)
+	for _, artifact := range component.Artifacts {
+		var reference string
+		var err error
+		if registryInfo.ProxyURL != "" {
+			reference, err = utils.SwapHostWithoutChecksum(artifact.Source, registry)
+		} else {
+			reference, err = utils.SwapHostWithPushPath(artifact.Source, registry, registryInfo.PushPath, registryInfo.Project, false)
+		}
+		if err != nil {
+			message.Fatalf(err, "Unable to get the reference of the %s artifact in the registry", artifact.Name)
+		}
+		artifactMap[artifact.Name] = reference
+	}
+
+	return artifactMap
+}
+
+// ApplyVariables templates package variables and constants into text that doesn't need the cluster state, such as scripts
+func ApplyVariables(text string) string {
+	for key, value := range getVariableTemplateMap() {
+		text = strings.ReplaceAll(text, key, value)
+	}
+	return text
+}
+
+// ApplyShellVariables fills ${ZARF_VAR_*} and ${ZARF_CONST_*} into a script before the shell runs it, so it never depends on the environment
+// $${ZARF_VAR_*} passes a literal ${ZARF_VAR_*} through to the shell and every other $ is left for the shell to expand
+func ApplyShellVariables(script string) (string, error) {
+	templateMap := getVariableTemplateMap()
+
+	var unknown []string
+	script = shellVariablePattern.ReplaceAllStringFunc(script, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		groups := shellVariablePattern.FindStringSubmatch(match)
+		value, ok := templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_%s_%s###", groups[2], groups[3]))]
+		if !ok {
+			unknown = append(unknown, match)
+			return match
+		}
+		return value
+	})
+
+	if len(unknown) > 0 {
+		return script, fmt.Errorf("%s is not a variable or constant this package sets", strings.Join(unknown, ", "))
+	}
+	return script, nil
+}
+
+// GetShellVariableReferences returns the ZARF_VAR_* and ZARF_CONST_* names a script refers to with ${...}, leaving out escaped ones
+func GetShellVariableReferences(script string) []string {
+	var references []string
+	for _, groups := range shellVariablePattern.FindAllStringSubmatch(script, -1) {
+		if groups[1] == "" {
+			references = append(references, fmt.Sprintf("ZARF_%s_%s", groups[2], groups[3]))
+		}
+	}
+	return references
+}
+
+// ApplyComponentVariables templates the package variables, constants and the values a component imports into text
+// Everything is replaced in a single pass like in charts and manifests, so tokens inside a value are never templated again
+func ApplyComponentVariables(text string, imports []string) string {
+	var replacements []string
+	for key, value := range getVariableTemplateMap() {
+		replacements = append(replacements, key, value)
+	}
+	for key, value := range getImportTemplateMap(imports) {
+		replacements = append(replacements, key, value)
+	}
+	return strings.NewReplacer(replacements...).Replace(text)
+}
+
+func getImportTemplateMap(imports []string) map[string]string {
+	templateMap := map[string]string{}
+
+	for _, name := range imports {
+		if value, ok := config.GetExportedValue(name); ok {
+			// Import keys are always uppercase in the format ###ZARF_IMPORT_KEY###
+			templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_IMPORT_%s###", name))] = value
+		}
+	}
+
+	return templateMap
+}
+
+func getVariableTemplateMap() map[string]string {
+	templateMap := map[string]string{}
+
 	for key, value := range config.SetVariableMap {
 		// Variable keys are always uppercase in the format ###ZARF_VAR_KEY###
 		templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_VAR_%s###", key))] = value
@@ -125,6 +415,5 @@ func (values Values) Apply(component types.ZarfComponent, path string) {
 		templateMap[strings.ToUpper(fmt.Sprintf("###ZARF_CONST_%s###", constant.Name))] = constant.Value
 	}
 
-	message.Debugf("templateMap = %#v", templateMap)
-	utils.ReplaceTextTemplate(path, templateMap)
+	return templateMap
 }
@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"fmt"
 	"math"
 	"strconv"
+	"strings"
 )
 
 // forked from https://www.socketloop.com/tutorials/golang-byte-format-example
@@ -44,3 +46,30 @@ func ByteFormat(inputNum float64, precision int) string {
 
 	return strconv.FormatFloat(returnVal, 'f', precision, 64) + unit
 }
+
+// ParseByteSize converts a human readable size such as 4GB or 500M into bytes, bare numbers are treated as bytes
+func ParseByteSize(size string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(size))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+
+	multiplier := int64(1)
+	units := map[string]int64{
+		"K": 1024,
+		"M": 1024 * 1024,
+		"G": 1024 * 1024 * 1024,
+		"T": 1024 * 1024 * 1024 * 1024,
+	}
+	if len(value) > 0 {
+		if unit, ok := units[value[len(value)-1:]]; ok {
+			multiplier = unit
+			value = value[:len(value)-1]
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("invalid size %s, expected a positive value such as 4GB or 500MB", size)
+	}
+
+	return int64(number * float64(multiplier)), nil
+}
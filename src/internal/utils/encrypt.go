@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedFileHeader marks the start of data encrypted with EncryptWithPassphrase
+var encryptedFileHeader = []byte("ZARFENC1")
+
+const (
+	passphraseSaltSize = 16
+	passphraseKeySize  = 32
+)
+
+// KeySize is the size of the keys EncryptWithKey and DecryptWithKey take
+const KeySize = passphraseKeySize
+
+// EncryptWithPassphrase encrypts data with AES-256-GCM using a key derived from the passphrase with scrypt
+// The result holds a header, the salt and the nonce ahead of the ciphertext so only the passphrase is needed to decrypt it
+func EncryptWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, passphraseSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newPassphraseCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{}, encryptedFileHeader...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, encryptedFileHeader), nil
+}
+
+// DecryptWithPassphrase decrypts data written by EncryptWithPassphrase
+func DecryptWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(data, encryptedFileHeader) {
+		return nil, errors.New("the data wasn't encrypted by Zarf")
+	}
+	data = data[len(encryptedFileHeader):]
+	if len(data) < passphraseSaltSize {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+
+	gcm, err := newPassphraseCipher(passphrase, data[:passphraseSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[passphraseSaltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], encryptedFileHeader)
+	if err != nil {
+		return nil, errors.New("the passphrase is wrong or the encrypted data is corrupted")
+	}
+	return plaintext, nil
+}
+
+// EncryptWithKey encrypts data with AES-256-GCM using a 32 byte key, the nonce is kept ahead of the ciphertext
+func EncryptWithKey(data, key []byte) ([]byte, error) {
+	gcm, err := newKeyCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// DecryptWithKey decrypts data written by EncryptWithKey
+func DecryptWithKey(data, key []byte) ([]byte, error) {
+	gcm, err := newKeyCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("the encrypted data is truncated")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("the key is wrong or the encrypted data is corrupted")
+	}
+	return plaintext, nil
+}
+
+func newPassphraseCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 32768, 8, 1, passphraseKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return newKeyCipher(key)
+}
+
+func newKeyCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != passphraseKeySize {
+		return nil, fmt.Errorf("the key must be %d bytes", passphraseKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
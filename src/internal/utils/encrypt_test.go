@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	data := []byte("zarf-state: registry password")
+
+	encrypted, err := EncryptWithPassphrase(data, "correct horse")
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(encrypted, data))
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		wantErr    string
+	}{
+		{
+			name:       "round trip",
+			data:       encrypted,
+			passphrase: "correct horse",
+		},
+		{
+			name:       "wrong passphrase",
+			data:       encrypted,
+			passphrase: "battery staple",
+			wantErr:    "the passphrase is wrong or the encrypted data is corrupted",
+		},
+		{
+			name:       "tampered ciphertext",
+			data:       tampered,
+			passphrase: "correct horse",
+			wantErr:    "the passphrase is wrong or the encrypted data is corrupted",
+		},
+		{
+			name:       "truncated salt",
+			data:       encrypted[:len(encryptedFileHeader)+passphraseSaltSize/2],
+			passphrase: "correct horse",
+			wantErr:    "the encrypted data is truncated",
+		},
+		{
+			name:       "not encrypted by zarf",
+			data:       data,
+			passphrase: "correct horse",
+			wantErr:    "the data wasn't encrypted by Zarf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := DecryptWithPassphrase(tt.data, tt.passphrase)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, data, plaintext)
+		})
+	}
+}
+
+func TestEncryptWithKey(t *testing.T) {
+	data := []byte("zarf-state: registry password")
+	key := bytes.Repeat([]byte{1}, KeySize)
+	otherKey := bytes.Repeat([]byte{2}, KeySize)
+
+	encrypted, err := EncryptWithKey(data, key)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(encrypted, data))
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name    string
+		data    []byte
+		key     []byte
+		wantErr string
+	}{
+		{
+			name: "round trip",
+			data: encrypted,
+			key:  key,
+		},
+		{
+			name:    "wrong key",
+			data:    encrypted,
+			key:     otherKey,
+			wantErr: "the key is wrong or the encrypted data is corrupted",
+		},
+		{
+			name:    "tampered ciphertext",
+			data:    tampered,
+			key:     key,
+			wantErr: "the key is wrong or the encrypted data is corrupted",
+		},
+		{
+			name:    "truncated nonce",
+			data:    encrypted[:4],
+			key:     key,
+			wantErr: "the encrypted data is truncated",
+		},
+		{
+			name:    "short key",
+			data:    encrypted,
+			key:     key[:16],
+			wantErr: "the key must be 32 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := DecryptWithKey(tt.data, tt.key)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, data, plaintext)
+		})
+	}
+
+	_, err = EncryptWithKey(data, key[:16])
+	require.EqualError(t, err, "the key must be 32 bytes")
+}
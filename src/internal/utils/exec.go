@@ -10,6 +10,8 @@ import (
 	"os/exec"
 	"runtime"
 	"sync"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
 )
 
 // Change terminal colors
@@ -25,12 +27,26 @@ func ExecCommandWithContext(ctx context.Context, showLogs bool, commandName stri
 
 // ExecCommandWithContextAndDir executes a given command with args in the specified directory.
 func ExecCommandWithContextAndDir(ctx context.Context, dir string, showLogs bool, commandName string, args ...string) (string, string, error) {
+	return execCommand(ctx, dir, nil, showLogs, commandName, args...)
+}
+
+// ExecCommandWithContextAndEnv executes a given command with args in the current working directory, with env (KEY=VALUE) added to the environment.
+func ExecCommandWithContextAndEnv(ctx context.Context, env []string, showLogs bool, commandName string, args ...string) (string, string, error) {
+	return execCommand(ctx, "", env, showLogs, commandName, args...)
+}
+
+// ExecCommandWithContextDirAndEnv executes a given command with args in the specified directory, with env (KEY=VALUE) added to the environment.
+func ExecCommandWithContextDirAndEnv(ctx context.Context, dir string, env []string, showLogs bool, commandName string, args ...string) (string, string, error) {
+	return execCommand(ctx, dir, env, showLogs, commandName, args...)
+}
+
+func execCommand(ctx context.Context, dir string, extraEnv []string, showLogs bool, commandName string, args ...string) (string, string, error) {
 	if showLogs {
 		fmt.Println()
 		fmt.Printf("  %s", colorGreen)
 		fmt.Print(commandName + " ")
 		fmt.Printf("%s", colorCyan)
-		fmt.Printf("%v", args)
+		fmt.Print(message.Mask(fmt.Sprintf("%v", args)))
 		fmt.Printf("%s", colorWhite)
 		fmt.Printf("%s", colorReset)
 		fmt.Println("")
@@ -38,7 +54,8 @@ func ExecCommandWithContextAndDir(ctx context.Context, dir string, showLogs bool
 
 	cmd := exec.CommandContext(ctx, commandName, args...)
 
-	env := os.Environ()
+	// Later entries win, so the extra environment overrides the one of this process
+	env := append(os.Environ(), extraEnv...)
 	cmd.Env = env
 	cmd.Dir = dir
 
@@ -47,8 +64,10 @@ func ExecCommandWithContextAndDir(ctx context.Context, dir string, showLogs bool
 	stderrIn, _ := cmd.StderrPipe()
 
 	var errStdout, errStderr error
-	stdout := io.MultiWriter(os.Stdout, &stdoutBuf)
-	stderr := io.MultiWriter(os.Stderr, &stderrBuf)
+	stdoutMask := message.NewMaskingWriter(os.Stdout)
+	stderrMask := message.NewMaskingWriter(os.Stderr)
+	stdout := io.MultiWriter(stdoutMask, &stdoutBuf)
+	stderr := io.MultiWriter(stderrMask, &stderrBuf)
 
 	if err := cmd.Start(); err != nil {
 		return "", "", err
@@ -65,6 +84,9 @@ func ExecCommandWithContextAndDir(ctx context.Context, dir string, showLogs bool
 
 		_, errStderr = io.Copy(stderr, stderrIn)
 		wg.Wait()
+
+		_ = stdoutMask.Flush()
+		_ = stderrMask.Flush()
 	}
 
 	if err := cmd.Wait(); err != nil {
@@ -3,10 +3,19 @@ package utils
 import (
 	"fmt"
 	"hash/crc32"
+	"path"
+	"strings"
+	"text/template"
 
 	"github.com/distribution/distribution/v3/reference"
 )
 
+// Image sources with these prefixes are loaded from a local container daemon instead of pulled from a registry
+const (
+	DockerDaemonPrefix = "docker-daemon:"
+	PodmanDaemonPrefix = "podman:"
+)
+
 type Image struct {
 	Host        string
 	Name        string
@@ -24,11 +33,7 @@ func SwapHost(src string, targetHost string) (string, error) {
 		return "", err
 	}
 
-	// Generate a crc32 hash of the image host + name
-	table := crc32.MakeTable(crc32.IEEE)
-	checksum := crc32.Checksum([]byte(image.Name), table)
-
-	return fmt.Sprintf("%s/%s-%d%s", targetHost, image.Path, checksum, image.TagOrDigest), nil
+	return fmt.Sprintf("%s/%s-%d%s", targetHost, image.Path, getImageChecksum(image), image.TagOrDigest), nil
 }
 
 // SwapHostWithoutChecksum Perform base url replacement but avoids adding a checksum of the original url.
@@ -40,7 +45,100 @@ func SwapHostWithoutChecksum(src string, targetHost string) (string, error) {
 	return fmt.Sprintf("%s/%s%s", targetHost, image.Path, image.TagOrDigest), nil
 }
 
+// ImagePushPath is what a registry push path template can use to place an image, e.g. {{.Project}}/{{.Namespace}}/{{.Name}}
+type ImagePushPath struct {
+	// Project is the project (or other prefix) the registry requires images to be pushed under
+	Project string
+	// Host is the registry the image came from (e.g. docker.io)
+	Host string
+	// Namespace is the path of the image without its last element (e.g. library)
+	Namespace string
+	// Name is the last element of the path of the image, with the checksum of the original name when one is added
+	Name string
+	// Path is the full path of the image without the checksum (e.g. library/nginx)
+	Path string
+}
+
+// SwapHostWithPushPath performs base url replacement, placing the image at the path the push path template renders
+// An empty template keeps the flattened paths of SwapHost and SwapHostWithoutChecksum
+func SwapHostWithPushPath(src string, targetHost string, pushPath string, project string, addChecksum bool) (string, error) {
+	if pushPath == "" {
+		if addChecksum {
+			return SwapHost(src, targetHost)
+		}
+		return SwapHostWithoutChecksum(src, targetHost)
+	}
+
+	image, err := ParseImageURL(src)
+	if err != nil {
+		return "", err
+	}
+
+	namespace, name := path.Split(image.Path)
+	if addChecksum {
+		name = fmt.Sprintf("%s-%d", name, getImageChecksum(image))
+	}
+
+	rendered, err := RenderImagePushPath(pushPath, ImagePushPath{
+		Project:   project,
+		Host:      image.Host,
+		Namespace: strings.TrimSuffix(namespace, "/"),
+		Name:      name,
+		Path:      image.Path,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s%s", targetHost, rendered, image.TagOrDigest), nil
+}
+
+// RenderImagePushPath renders the push path template for an image
+func RenderImagePushPath(pushPath string, values ImagePushPath) (string, error) {
+	tmpl, err := template.New("push-path").Option("missingkey=error").Parse(pushPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry push path %s: %w", pushPath, err)
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, values); err != nil {
+		return "", fmt.Errorf("invalid registry push path %s: %w", pushPath, err)
+	}
+
+	// Drop the empty elements left by values that aren't set, such as the namespace of a top-level image
+	var elements []string
+	for _, element := range strings.Split(rendered.String(), "/") {
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+	if len(elements) == 0 {
+		return "", fmt.Errorf("the registry push path %s rendered an empty path", pushPath)
+	}
+
+	return strings.Join(elements, "/"), nil
+}
+
+// getImageChecksum returns a crc32 hash of the image host + name
+func getImageChecksum(image Image) uint32 {
+	table := crc32.MakeTable(crc32.IEEE)
+	return crc32.Checksum([]byte(image.Name), table)
+}
+
+// SplitDaemonSource returns the image name of a source and the daemon prefix it was given with, which is empty for registry images
+func SplitDaemonSource(src string) (string, string) {
+	for _, prefix := range []string{DockerDaemonPrefix, PodmanDaemonPrefix} {
+		if strings.HasPrefix(src, prefix) {
+			return strings.TrimPrefix(src, prefix), prefix
+		}
+	}
+	return src, ""
+}
+
 func ParseImageURL(src string) (out Image, err error) {
+	// Images loaded from a daemon keep the name they were built with
+	src, _ = SplitDaemonSource(src)
+
 	ref, err := reference.ParseAnyReference(src)
 	if err != nil {
 		return out, err
@@ -3,6 +3,7 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/exec"
@@ -124,6 +125,26 @@ func RecursiveFileList(dir string, pattern *regexp.Regexp) (files []string, err
 	return files, err
 }
 
+// GetDirSize returns the combined size of the files in a directory, including hidden ones
+func GetDirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
 func CreateFilePath(destination string) error {
 	parentDest := path.Dir(destination)
 	return CreateDirectory(parentDest, 0700)
@@ -154,3 +175,53 @@ func GetFinalExecutablePath() (string, error) {
 	linkedPath, err := filepath.EvalSymlinks(binaryPath)
 	return linkedPath, err
 }
+
+// ReplaceExecutable swaps the executable at path for the binary at source, keeping the file mode of the executable
+// The binary is copied next to the executable first so the swap is a single rename that never leaves a partial executable behind
+func ReplaceExecutable(path, source string) error {
+	message.Debugf("utils.ReplaceExecutable(%s, %s)", path, source)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	staged, err := os.CreateTemp(filepath.Dir(path), ".zarf-update-")
+	if err != nil {
+		return fmt.Errorf("unable to write next to %s: %w", path, err)
+	}
+	defer os.Remove(staged.Name())
+
+	if _, err := io.Copy(staged, in); err != nil {
+		_ = staged.Close()
+		return err
+	}
+	if err := staged.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(staged.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if err := os.Rename(staged.Name(), path); err == nil {
+		return nil
+	}
+
+	// Windows can't replace a running executable, but it can move it out of the way
+	previous := path + ".old"
+	_ = os.Remove(previous)
+	if err := os.Rename(path, previous); err != nil {
+		return err
+	}
+	if err := os.Rename(staged.Name(), path); err != nil {
+		_ = os.Rename(previous, path)
+		return err
+	}
+	return nil
+}
@@ -2,19 +2,94 @@ package utils
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/zarf/src/internal/message"
 )
 
 const SGETProtocol = "sget://"
 
+// GetTLSConfig returns the TLS settings to reach a server with a custom CA or without verifying its certificate
+// A nil config means the defaults (the system CAs) apply
+func GetTLSConfig(caBundle []byte, insecureSkipVerify bool) (*tls.Config, error) {
+	if len(caBundle) == 0 && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if len(caBundle) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("unable to parse the CA bundle")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	return tlsConfig, nil
+}
+
+// NewHTTPTransport returns a copy of the default http transport using the TLS settings of GetTLSConfig
+func NewHTTPTransport(caBundle []byte, insecureSkipVerify bool) (*http.Transport, error) {
+	tlsConfig, err := GetTLSConfig(caBundle, insecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+// GetServerCertificates returns the certificate chain a TLS server presents without verifying it, so the chain can be inspected even when it is invalid
+// The address is a URL or a host with an optional port, which defaults to 443, and plain http URLs have no certificates
+func GetServerCertificates(address string) ([]*x509.Certificate, error) {
+	if strings.HasPrefix(address, "http://") {
+		return nil, nil
+	}
+	host := strings.TrimPrefix(address, "https://")
+	host, _, _ = strings.Cut(host, "/")
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "443")
+	}
+
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", host, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.ConnectionState().PeerCertificates, nil
+}
+
+// IsLoopbackURL returns whether the URL points at this machine, such as the local end of a tunnel
+func IsLoopbackURL(source string) bool {
+	parsedURL, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+	if parsedURL.Hostname() == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(parsedURL.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
 func IsUrl(source string) bool {
 	parsedUrl, err := url.Parse(source)
 	return err == nil && parsedUrl.Scheme != "" && parsedUrl.Host != ""
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	sigs "github.com/sigstore/cosign/pkg/signature"
+)
+
+// VerifyBlobSignature checks a file against a signature made with cosign sign-blob and the public key (path, URL or KMS URI) of its key-pair
+func VerifyBlobSignature(path, signaturePath, key string) error {
+	message.Debugf("utils.VerifyBlobSignature(%s, %s, %s)", path, signaturePath, key)
+
+	ctx := context.TODO()
+	verifier, err := sigs.LoadPublicKey(ctx, key)
+	if err != nil {
+		return fmt.Errorf("unable to load the public key %s: %w", key, err)
+	}
+
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return fmt.Errorf("unable to read the signature %s: %w", signaturePath, err)
+	}
+	// cosign writes the signature base64 encoded, but a raw signature works too
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature))); err == nil {
+		signature = decoded
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := verifier.VerifySignature(bytes.NewReader(signature), file); err != nil {
+		return fmt.Errorf("the signature %s doesn't match %s: %w", signaturePath, path, err)
+	}
+	return nil
+}
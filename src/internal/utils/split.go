@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/defenseunicorns/zarf/src/internal/message"
+	"github.com/defenseunicorns/zarf/src/types"
+)
+
+// SplitIndexSuffix is the suffix of the index file written for a split package, data parts start at .part001
+const SplitIndexSuffix = ".part000"
+
+// SplitFile breaks a file into numbered parts no larger than chunkSize and writes an index file describing them
+func SplitFile(path string, chunkSize int64) ([]string, error) {
+	message.Debugf("utils.SplitFile(%s, %d)", path, chunkSize)
+
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", chunkSize)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	reader := io.TeeReader(file, hash)
+
+	var parts []string
+	for written := int64(0); written < info.Size(); {
+		partPath := fmt.Sprintf("%s.part%03d", path, len(parts)+1)
+		part, err := os.Create(partPath)
+		if err != nil {
+			return parts, err
+		}
+
+		copied, err := io.CopyN(part, reader, chunkSize)
+		_ = part.Close()
+		if err != nil && err != io.EOF {
+			return parts, err
+		}
+
+		written += copied
+		parts = append(parts, partPath)
+	}
+
+	index := types.ZarfSplitPackageData{
+		Sha256Sum: hex.EncodeToString(hash.Sum(nil)),
+		Bytes:     info.Size(),
+		Count:     len(parts),
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return parts, err
+	}
+
+	indexPath := path + SplitIndexSuffix
+	if err := os.WriteFile(indexPath, data, 0644); err != nil {
+		return parts, err
+	}
+
+	return append([]string{indexPath}, parts...), nil
+}
+
+// GetSplitIndexPath returns the index file for a split file given either the index itself or the original file name, or an empty string if the file was not split
+func GetSplitIndexPath(path string) string {
+	if strings.HasSuffix(path, SplitIndexSuffix) && !InvalidPath(path) {
+		return path
+	}
+
+	if InvalidPath(path) && !InvalidPath(path+SplitIndexSuffix) {
+		return path + SplitIndexSuffix
+	}
+
+	return ""
+}
+
+// ReassembleSplitFile joins the parts described by a split index into destinationDir and returns the path of the reassembled file
+func ReassembleSplitFile(indexPath string, destinationDir string) (string, error) {
+	message.Debugf("utils.ReassembleSplitFile(%s, %s)", indexPath, destinationDir)
+
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the split index %s: %w", indexPath, err)
+	}
+
+	var index types.ZarfSplitPackageData
+	if err := json.Unmarshal(data, &index); err != nil {
+		return "", fmt.Errorf("unable to parse the split index %s: %w", indexPath, err)
+	}
+
+	basePath := strings.TrimSuffix(indexPath, SplitIndexSuffix)
+	destination := filepath.Join(destinationDir, filepath.Base(basePath))
+
+	output, err := os.Create(destination)
+	if err != nil {
+		return "", err
+	}
+	defer output.Close()
+
+	hash := sha256.New()
+	writer := io.MultiWriter(output, hash)
+
+	for i := 1; i <= index.Count; i++ {
+		partPath := fmt.Sprintf("%s.part%03d", basePath, i)
+		part, err := os.Open(partPath)
+		if err != nil {
+			return "", fmt.Errorf("unable to open part %d of %d: %w", i, index.Count, err)
+		}
+
+		_, err = io.Copy(writer, part)
+		_ = part.Close()
+		if err != nil {
+			return "", fmt.Errorf("unable to copy part %d of %d: %w", i, index.Count, err)
+		}
+	}
+
+	info, err := output.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if info.Size() != index.Bytes {
+		return "", fmt.Errorf("reassembled file is %d bytes, expected %d", info.Size(), index.Bytes)
+	}
+
+	if computed := hex.EncodeToString(hash.Sum(nil)); computed != index.Sha256Sum {
+		return "", fmt.Errorf("reassembled file has checksum %s, expected %s", computed, index.Sha256Sum)
+	}
+
+	return destination, nil
+}
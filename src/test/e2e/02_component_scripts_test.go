@@ -14,11 +14,12 @@ func TestComponentScripts(t *testing.T) {
 
 	// Note these files will be created in the package directory, not CWD
 	prepareArtifact := "examples/component-scripts/test-prepare.txt"
+	postPrepareArtifact := "examples/component-scripts/test-post-prepare.txt"
 	deployArtifacts := []string{
 		"test-deploy-before.txt",
 		"test-deploy-after.txt",
 	}
-	allArtifacts := append(deployArtifacts, prepareArtifact)
+	allArtifacts := append(deployArtifacts, prepareArtifact, postPrepareArtifact)
 	e2e.cleanFiles(allArtifacts...)
 	defer e2e.cleanFiles(allArtifacts...)
 
@@ -28,6 +29,7 @@ func TestComponentScripts(t *testing.T) {
 
 	// Test for package create prepare artirfact
 	require.FileExists(t, prepareArtifact)
+	require.FileExists(t, postPrepareArtifact)
 
 	// Test to ensure the deploy scripts are not executed
 	for _, artifact := range deployArtifacts {
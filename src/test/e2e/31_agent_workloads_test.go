@@ -0,0 +1,51 @@
+package test
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentWorkloads(t *testing.T) {
+	t.Log("E2E: Agent workloads")
+	e2e.setupWithCluster(t)
+	defer e2e.teardown(t)
+
+	path := fmt.Sprintf("build/zarf-package-agent-workloads-%s.tar.zst", e2e.arch)
+
+	stdOut, stdErr, err := e2e.execZarfCommand("package", "deploy", path, "--confirm")
+	require.NoError(t, err, stdOut, stdErr)
+
+	// The images in the Zarf registry get a checksum of the original name
+	mutatedImage := "/library/alpine-"
+
+	// The specs of the workloads point at the Zarf registry, including their init containers
+	images := []struct {
+		resource string
+		jsonPath string
+	}{
+		{"cronjob/agent-workloads", "{.spec.jobTemplate.spec.template.spec.initContainers[0].image}"},
+		{"cronjob/agent-workloads", "{.spec.jobTemplate.spec.template.spec.containers[0].image}"},
+		{"statefulset/agent-workloads", "{.spec.template.spec.initContainers[0].image}"},
+		{"statefulset/agent-workloads", "{.spec.template.spec.containers[0].image}"},
+		{"pod/agent-workloads-0", "{.spec.initContainers[0].image}"},
+	}
+	for _, image := range images {
+		kubectlOut, err := exec.Command("kubectl", "-n", "agent-workloads", "get", image.resource, "-o", "jsonpath="+image.jsonPath).Output()
+		require.NoError(t, err, string(kubectlOut))
+		assert.Contains(t, string(kubectlOut), mutatedImage, image.resource)
+	}
+
+	// Ephemeral containers added to the running pod point at the Zarf registry too
+	kubectlOut, err := exec.Command("kubectl", "-n", "agent-workloads", "debug", "agent-workloads-0", "--image=alpine:3.15", "--container=debugger", "--", "true").CombinedOutput()
+	require.NoError(t, err, string(kubectlOut))
+	kubectlOut, err = exec.Command("kubectl", "-n", "agent-workloads", "get", "pod", "agent-workloads-0", "-o", "jsonpath={.spec.ephemeralContainers[0].image}").Output()
+	require.NoError(t, err, string(kubectlOut))
+	assert.Contains(t, string(kubectlOut), mutatedImage)
+
+	stdOut, stdErr, err = e2e.execZarfCommand("package", "remove", "agent-workloads", "--confirm")
+	require.NoError(t, err, stdOut, stdErr)
+}
@@ -17,10 +17,23 @@ type ZarfComponent struct {
 	// Only include compatible components during package deployment
 	Only ZarfComponentOnlyTarget `json:"only,omitempty" jsonschema:"description=Filter when this component is included in package creation or deployment"`
 
+	// RequiresStorage marks a component that keeps its data in a PersistentVolume, so zarf init checks the StorageClass before deploying it
+	RequiresStorage bool `json:"requiresStorage,omitempty" jsonschema:"description=The component keeps its data in a PersistentVolume, zarf init validates the StorageClass before the stateful components deploy"`
+
 	// Key to match other components to produce a user selector field, used to create a BOOLEAN XOR for a set of components
 	// Note: ignores default and required flags
 	Group string `json:"group,omitempty" jsonschema:"description=Create a user selector field based on all components in the same group"`
 
+	// DependsOn lists other components in this package that must finish deploying before this one starts
+	// Note: a component that doesn't set this waits for the component listed before it, unless that one depends on it
+	DependsOn []string `json:"dependsOn,omitempty" jsonschema:"description=Names of other components in this package that must be deployed before this component"`
+
+	// Exports are named values this component publishes for the components deployed after it
+	Exports []ZarfComponentExport `json:"exports,omitempty" jsonschema:"description=Values this component publishes for later components in the same deployment to import"`
+
+	// Imports are the names of values exported by earlier components, templated as ###ZARF_IMPORT_NAME###
+	Imports []string `json:"imports,omitempty" jsonschema:"description=Names of values exported by components deployed before this one, available as ###ZARF_IMPORT_NAME### in charts, manifests, scripts, exports, file targets and data injection targets"`
+
 	//Path to cosign publickey for signed online resources
 	CosignKeyPath string `json:"cosignKeyPath,omitempty" jsonschema:"description=Specify a path to a public key to validate signed online resources"`
 
@@ -33,6 +46,9 @@ type ZarfComponent struct {
 	// Files are files to place on disk during deploy
 	Files []ZarfFile `json:"files,omitempty" jsonschema:"description=Files to place on disk during package deployment"`
 
+	// Binaries are tool binaries to install onto the host PATH during deploy
+	Binaries []ZarfBinary `json:"binaries,omitempty" jsonschema:"description=Tool binaries to install onto the host PATH during package deployment"`
+
 	// Charts are helm charts to install during package deploy
 	Charts []ZarfChart `json:"charts,omitempty" jsonschema:"description=Helm charts to install during package deploy"`
 
@@ -40,13 +56,22 @@ type ZarfComponent struct {
 	Manifests []ZarfManifest `json:"manifests,omitempty"`
 
 	// Images are the online images needed to be included in the zarf package
-	Images []string `json:"images,omitempty" jsonschema:"description=List of OCI images to include in the package"`
+	Images []string `json:"images,omitempty" jsonschema:"description=List of OCI images to include in the package (prefix with docker-daemon: or podman: to load a local image)"`
+
+	// ArchImages are images that only apply to a single architecture, only the ones matching the cluster are pushed on deploy
+	ArchImages map[string][]string `json:"archImages,omitempty" jsonschema:"description=Lists of OCI images keyed by architecture, only the images matching the cluster architecture are pushed during package deploy"`
+
+	// Artifacts are OCI artifacts other than images, like policy bundles, that controllers in the cluster pull from the registry
+	Artifacts []ZarfArtifact `json:"artifacts,omitempty" jsonschema:"description=List of OCI artifacts such as policy bundles to push to the registry, with their references templated as ###ZARF_ARTIFACT_NAME###"`
 
 	// Repos are any git repos that need to be pushed into the git server
-	Repos []string `json:"repos,omitempty" jsonschema:"description=List of git repos to include in the package"`
+	Repos []string `json:"repos,omitempty" jsonschema:"description=List of git repos to include in the package, add !shallow to leave out the history behind the ref and !force to overwrite rewritten history on the git server"`
 
 	// Data pacakges to push into a running cluster
 	DataInjections []ZarfDataInjection `json:"dataInjections,omitempty" jsonschema:"description=Datasets to inject into a pod in the target cluster"`
+
+	// NamespaceGuardrails are added to each namespace Zarf creates for the charts and manifests of the component
+	NamespaceGuardrails ZarfNamespaceGuardrails `json:"namespaceGuardrails,omitempty" jsonschema:"description=A ResourceQuota and LimitRange to add to the namespaces Zarf creates for the charts and manifests of this component"`
 }
 
 // ZarfComponentOnlyTarget filters a component to only show it for a given OS/Arch
@@ -69,17 +94,39 @@ type ZarfFile struct {
 	Symlinks   []string `json:"symlinks,omitempty" jsonschema:"description=List of symlinks to create during package deploy"`
 }
 
+// ZarfArtifact defines an OCI artifact that is pushed to the registry as is, keeping its media types.
+type ZarfArtifact struct {
+	Name   string `json:"name" jsonschema:"description=The name of the artifact in the ###ZARF_ARTIFACT_NAME### template of its reference in the registry,pattern=^[A-Z_]+$"`
+	Source string `json:"source" jsonschema:"description=The OCI reference of the artifact to add to the package"`
+}
+
+// ZarfBinary defines a tool binary to install onto the host.
+type ZarfBinary struct {
+	Name    string `json:"name" jsonschema:"description=The name of the binary as it will be called on the PATH,pattern=^[a-zA-Z0-9_.\\-]+$"`
+	Version string `json:"version,omitempty" jsonschema:"description=The version of the binary recorded in the host catalog"`
+	Source  string `json:"source" jsonschema:"description=Local file path or remote URL of the binary to add to the package"`
+	Shasum  string `json:"shasum,omitempty" jsonschema:"description=SHA256 checksum of the binary (recorded automatically during package create if not set)"`
+}
+
 // ZarfChart defines a helm chart to be deployed.
 type ZarfChart struct {
 	Name        string   `json:"name" jsonschema:"description=The name of the chart to deploy, this should be the name of the chart as it is installed in the helm repo"`
 	ReleaseName string   `json:"releaseName,omitempty" jsonschema:"description=The name of the release to create, defaults to the name of the chart"`
-	Url         string   `json:"url,omitempty" jsonschema:"oneof_required=url,description=The URL of the chart repository or git url if the chart is using a git repo instead of helm repo"`
+	Url         string   `json:"url,omitempty" jsonschema:"oneof_required=url,description=The URL of the chart repository, the oci:// reference of the chart, or git url if the chart is using a git repo instead of helm repo"`
 	Version     string   `json:"version" jsonschema:"description=The version of the chart to deploy, for git-based charts this is also the tag of the git repo"`
 	Namespace   string   `json:"namespace" jsonschema:"description=The namespace to deploy the chart to"`
 	ValuesFiles []string `json:"valuesFiles,omitempty" jsonschema:"description=List of values files to include in the package, these will be merged together"`
+	ValuesMerge string   `json:"valuesMerge,omitempty" jsonschema:"description=How valuesFiles are merged. helm (default) uses the Helm merge; override lets the last file to set a top-level key replace it; deep merges over the chart defaults and deletes any key set to null including subchart defaults,enum=helm,enum=override,enum=deep"`
 	GitPath     string   `json:"gitPath,omitempty" jsonschema:"description=If using a git repo, the path to the chart in the repo"`
 	LocalPath   string   `json:"localPath,omitempty" jsonschema:"oneof_required=localPath,description=The path to the chart folder"`
 	NoWait      bool     `json:"noWait,omitempty" jsonschema:"description=Wait for chart resources to be ready before continuing"`
+	WaitForJobs bool     `json:"waitForJobs,omitempty" jsonschema:"description=Wait for any Jobs in the chart (such as migrations) to complete before marking the chart as deployed"`
+	Timeout     string   `json:"timeout,omitempty" jsonschema:"description=How long Helm waits for resources and hooks to complete as a Go duration (defaults to 15m),example=5m"`
+	Retries     int      `json:"retries,omitempty" jsonschema:"description=How many times to retry a failed install or upgrade (defaults to the deploy --retries for charts)"`
+	Atomic      bool     `json:"atomic,omitempty" jsonschema:"description=Roll back or uninstall the release as soon as an attempt fails instead of only after the last attempt"`
+	KeepFailed  bool     `json:"keepFailed,omitempty" jsonschema:"description=Leave a release that failed its last attempt in place for debugging instead of rolling it back or uninstalling it"`
+	SkipCRDs    bool     `json:"skipCRDs,omitempty" jsonschema:"description=Don't install the CRDs in the chart's crds/ directory or update them on upgrades"`
+	ForceCRDs   bool     `json:"forceCRDs,omitempty" jsonschema:"description=Take over the fields of the chart's CRDs that other field managers own when updating them on upgrades"`
 }
 
 // ZarfManifest defines raw manifests Zarf will deploy as a helm chart
@@ -92,14 +139,41 @@ type ZarfManifest struct {
 	NoWait                     bool     `json:"noWait,omitempty" jsonschema:"description=Wait for manifest resources to be ready before continuing"`
 }
 
+// ZarfComponentExport is a value a component publishes once it has deployed
+type ZarfComponentExport struct {
+	Name        string `json:"name" jsonschema:"description=The name of the value, imported as ###ZARF_IMPORT_NAME###,pattern=^[A-Z_]+$"`
+	Description string `json:"description,omitempty" jsonschema:"description=A description of the value for the components importing it"`
+	Value       string `json:"value,omitempty" jsonschema:"description=The value to export, package variables, constants and imports are templated into it"`
+	Script      string `json:"script,omitempty" jsonschema:"description=A script run after the component deploys whose output is the value to export"`
+	Sensitive   bool   `json:"sensitive,omitempty" jsonschema:"description=Hide the value in the output of the deployment"`
+}
+
 // ZarfComponentScripts are scripts that run before or after a component is deployed
 type ZarfComponentScripts struct {
 	ShowOutput     bool     `json:"showOutput,omitempty" jsonschema:"description=Show the output of the script during package deployment"`
 	TimeoutSeconds int      `json:"timeoutSeconds,omitempty" jsonschema:"description=Timeout in seconds for the script"`
 	Retry          bool     `json:"retry,omitempty" jsonschema:"description=Retry the script if it fails"`
 	Prepare        []string `json:"prepare,omitempty" jsonschema:"description=Scripts to run before the component is added during package create"`
+	PostPrepare    []string `json:"postPrepare,omitempty" jsonschema:"description=Scripts to run after the component is added during package create, such as to clean up what the prepare scripts generated"`
+	PrepareDir     string   `json:"prepareDir,omitempty" jsonschema:"description=Directory the prepare and postPrepare scripts run in, relative to the zarf.yaml of the component"`
 	Before         []string `json:"before,omitempty" jsonschema:"description=Scripts to run before the component is deployed"`
 	After          []string `json:"after,omitempty" jsonschema:"description=Scripts to run after the component successfully deploys"`
+
+	ServiceAccount ZarfScriptServiceAccount `json:"serviceAccount,omitempty" jsonschema:"description=Run the before and after scripts with a temporary service account instead of the credentials of the deployer"`
+}
+
+// ZarfScriptServiceAccount is a temporary service account, limited to one namespace, the deploy scripts of a component run as
+type ZarfScriptServiceAccount struct {
+	Namespace string               `json:"namespace,omitempty" jsonschema:"description=Namespace the service account is created in and limited to"`
+	Rules     []ZarfScriptRBACRule `json:"rules,omitempty" jsonschema:"description=Permissions the service account has in the namespace"`
+}
+
+// ZarfScriptRBACRule is a permission given to the service account of the deploy scripts, matching a rule of a Kubernetes Role
+type ZarfScriptRBACRule struct {
+	APIGroups     []string `json:"apiGroups,omitempty" jsonschema:"description=API groups of the resources, use an empty string for the core group"`
+	Resources     []string `json:"resources" jsonschema:"description=Resources the rule applies to (such as configmaps or deployments/scale)"`
+	ResourceNames []string `json:"resourceNames,omitempty" jsonschema:"description=Only allow the verbs on resources with these names"`
+	Verbs         []string `json:"verbs" jsonschema:"description=Verbs allowed on the resources (such as get, list or patch)"`
 }
 
 // ZarfContainerTarget defines the destination info for a ZarfData target
@@ -117,6 +191,20 @@ type ZarfDataInjection struct {
 	Compress bool                `json:"compress,omitempty" jsonschema:"description=Compress the data before transmitting using gzip.  Note: this requires support for tar/gzip locally and in the target image."`
 }
 
+// ZarfNamespaceGuardrails sizes the ResourceQuota and LimitRange Zarf adds to the namespaces it creates
+type ZarfNamespaceGuardrails struct {
+	ResourceQuota map[string]string `json:"resourceQuota,omitempty" jsonschema:"description=Hard limits of the zarf-quota ResourceQuota keyed by resource (such as requests.cpu or limits.memory or pods) which can use ###ZARF_VAR_*### and ###ZARF_CONST_*###"`
+	LimitRange    ZarfLimitRange    `json:"limitRange,omitempty" jsonschema:"description=Container limits of the zarf-limit-range LimitRange"`
+}
+
+// ZarfLimitRange is the container limits of the LimitRange Zarf adds to the namespaces it creates
+type ZarfLimitRange struct {
+	Default        map[string]string `json:"default,omitempty" jsonschema:"description=Limits of containers that don't set their own keyed by resource (such as cpu or memory) which can use ###ZARF_VAR_*### and ###ZARF_CONST_*###"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty" jsonschema:"description=Requests of containers that don't set their own keyed by resource which can use ###ZARF_VAR_*### and ###ZARF_CONST_*###"`
+	Min            map[string]string `json:"min,omitempty" jsonschema:"description=The least each container can request keyed by resource which can use ###ZARF_VAR_*### and ###ZARF_CONST_*###"`
+	Max            map[string]string `json:"max,omitempty" jsonschema:"description=The most each container can be limited to keyed by resource which can use ###ZARF_VAR_*### and ###ZARF_CONST_*###"`
+}
+
 // ZarfImport structure for including imported zarf components
 type ZarfComponentImport struct {
 	ComponentName string `json:"name,omitempty"`
@@ -7,10 +7,44 @@ type ZarfState struct {
 	Architecture  string       `json:"architecture" jsonschema:"description=Machine architecture of the k8s node(s)"`
 	StorageClass  string       `json:"storageClass" jsonschema:"Default StorageClass value Zarf uses for variable templating"`
 	AgentTLS      GeneratedPKI `json:"agentTLS" jsonschema:"PKI certificate information for the agent pods Zarf manages"`
+	AgentWebhook  AgentWebhook `json:"agentWebhook" jsonschema:"description=Settings applied to the agent mutating webhook"`
 
 	GitServer     GitServerInfo `json:"gitServer" jsonschema:"description=Information about the repository Zarf is configured to use"`
 	RegistryInfo  RegistryInfo  `json:"registryInfo" jsonschema:"description=Information about the registry Zarf is configured to use"`
 	LoggingSecret string        `json:"loggingSecret" jsonschema:"description=Secret value that the internal Grafana server was seeded with"`
+
+	StateStore StateStore      `json:"stateStore,omitempty" jsonschema:"description=Where the credentials of the state are kept when not in the zarf-state secret"`
+	Encryption StateEncryption `json:"encryption,omitempty" jsonschema:"description=Key the credentials of the state and the sensitive exports of packages are encrypted with"`
+}
+
+// StateStore describes the secrets manager that holds the credentials of the ZarfState
+type StateStore struct {
+	Type   string `json:"type,omitempty" jsonschema:"description=Kind of secrets manager the credentials are kept in,enum=kubernetes,enum=vault,enum=external-secret"`
+	URL    string `json:"url,omitempty" jsonschema:"description=API URL of the Vault KV version 2 secret the credentials are kept in"`
+	Secret string `json:"secret,omitempty" jsonschema:"description=NAMESPACE/NAME of the secret an external secrets operator syncs the credentials into"`
+}
+
+// ZarfStateBackup is the contents of a file written by zarf tools state export
+type ZarfStateBackup struct {
+	Version    int                `json:"version"`
+	CLIVersion string             `json:"cliVersion"`
+	ExportedAt string             `json:"exportedAt"`
+	State      ZarfState          `json:"state"`
+	Secrets    []ZarfSecretBackup `json:"secrets"`
+}
+
+// ZarfSecretBackup is a secret in the zarf namespace (e.g. a deployed package) that is kept with a ZarfStateBackup
+type ZarfSecretBackup struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+	Data   map[string][]byte `json:"data"`
+}
+
+// StateEncryption describes the key the data key of the ZarfState is encrypted with, the data key in turn encrypts the credentials
+type StateEncryption struct {
+	Provider string `json:"provider,omitempty" jsonschema:"description=Kind of key the data key is encrypted with,enum=key-file,enum=vault-transit"`
+	Key      string `json:"key,omitempty" jsonschema:"description=Path of the key file or URL of the Vault transit key the data key is encrypted with"`
+	DataKey  string `json:"dataKey,omitempty" jsonschema:"description=The data key the values are encrypted with, encrypted with the key"`
 }
 
 // DeployedPackage contains information about a Zarf Package that has been deployed to a cluster
@@ -19,14 +53,41 @@ type DeployedPackage struct {
 	Name       string      `json:"name"`
 	Data       ZarfPackage `json:"data"`
 	CLIVersion string      `json:"cliVersion"`
+	DeployedAt string      `json:"deployedAt,omitempty"`
 
 	DeployedComponents []DeployedComponent `json:"deployedComponents"`
+	ConnectStrings     ConnectStrings      `json:"connectStrings,omitempty"`
+
+	// Set when the package was deployed with --force-image-policy despite images violating the image policy
+	ImagePolicyViolations []string `json:"imagePolicyViolations,omitempty"`
 }
 
 // DeployedComponent contains information about a Zarf Package Component that has been deployed to a cluster.
 type DeployedComponent struct {
-	Name            string           `json:"name"`
-	InstalledCharts []InstalledChart `json:"installedCharts"`
+	Name                string            `json:"name"`
+	InstalledCharts     []InstalledChart  `json:"installedCharts"`
+	Images              []DeployedImage   `json:"images,omitempty"`
+	Artifacts           []DeployedImage   `json:"artifacts,omitempty"`
+	DataInjectionMarker string            `json:"dataInjectionMarker,omitempty"`
+	Files               []DeployedFile    `json:"files,omitempty"`
+	Exports             map[string]string `json:"exports,omitempty"`
+}
+
+// DeployedFile records a file or symlink a component placed on the host and where any file it replaced was kept.
+// The host, checksum and link target let package remove check that it only deletes what it placed.
+type DeployedFile struct {
+	Path   string `json:"path"`
+	Backup string `json:"backup,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Shasum string `json:"shasum,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// DeployedImage records where an image was pushed and the digest it had so the deployment can be verified later.
+type DeployedImage struct {
+	Source    string `json:"source"`
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
 }
 
 type InstalledChart struct {
@@ -34,6 +95,33 @@ type InstalledChart struct {
 	ChartName string `json:"chartName"`
 }
 
+// PackageInventory describes the images a deployed package brought into the cluster.
+// This object is saved as the data of a k8s configmap within the 'zarf' namespace and served by the inventory component.
+type PackageInventory struct {
+	Name      string           `json:"name"`
+	Version   string           `json:"version"`
+	Timestamp string           `json:"timestamp"`
+	Images    []InventoryImage `json:"images"`
+}
+
+// InventoryImage is a single image deployed by a package component along with the digest of its SBOM
+type InventoryImage struct {
+	Component  string `json:"component"`
+	Image      string `json:"image"`
+	SBOMDigest string `json:"sbomDigest,omitempty"`
+}
+
+// AgentWebhook contains the settings Zarf applies to the agent MutatingWebhookConfiguration
+type AgentWebhook struct {
+	FailurePolicy     string `json:"failurePolicy" jsonschema:"description=How the API server handles requests when the agent is unavailable,enum=Fail,enum=Ignore"`
+	TimeoutSeconds    int    `json:"timeoutSeconds" jsonschema:"description=Seconds the API server waits for the agent before applying the failure policy"`
+	NamespaceSelector string `json:"namespaceSelector" jsonschema:"description=Additional label selector a namespace must match for its resources to be mutated by the agent"`
+	// Namespaces that hold workloads with images that were already rewritten, which are left out like kube-system
+	IgnoredNamespaces []string `json:"ignoredNamespaces,omitempty" jsonschema:"description=Namespaces whose resources are never mutated by the agent"`
+	Mode              string   `json:"mode,omitempty" jsonschema:"description=Whether the agent mutates every namespace or only the ones Zarf manages and ones labeled zarf.dev/agent=enabled,enum=all,enum=opt-in"`
+	Replicas          int      `json:"replicas,omitempty" jsonschema:"description=Number of agent pods serving the webhook, one of them is elected to write secrets"`
+}
+
 // GitServerInfo contains information Zarf uses to communicate with a git repository to push/pull repositories to.
 type GitServerInfo struct {
 	PushUsername string `json:"pushUsername" jsonschema:"description=Username of a user with push access to the git repository"`
@@ -43,6 +131,18 @@ type GitServerInfo struct {
 
 	Address        string `json:"address" jsonschema:"description=URL address of the git server"`
 	InternalServer bool   `json:"internalServer" jsonschema:"description=Indicates if we are using a git server that Zarf is directly managing"`
+	Provider       string `json:"provider,omitempty" jsonschema:"description=API Zarf uses to manage repos and the pull-only user on the git server,enum=gitea,enum=gitlab,enum=http"`
+
+	// The Zarf git server keeps being reached on its in-cluster address however its service is exposed
+	ServiceType string `json:"serviceType,omitempty" jsonschema:"description=Type of the service of the git server Zarf deploys,enum=ClusterIP,enum=NodePort,enum=LoadBalancer"`
+	NodePort    int    `json:"nodePort,omitempty" jsonschema:"description=Nodeport of the git server Zarf deploys when its service is a NodePort"`
+	IngressHost string `json:"ingressHost,omitempty" jsonschema:"description=Host of the ingress to the git server Zarf deploys"`
+
+	CABundle []byte `json:"caBundle,omitempty" jsonschema:"description=PEM encoded CA chain used to verify the TLS certificate of an external git server"`
+	// InsecureSkipVerify is an escape hatch for git servers whose certificate can't be verified at all
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty" jsonschema:"description=Skip verifying the TLS certificate of an external git server"`
+	// CustomTLS serves the ingress of the Zarf git server with a certificate issued by the CA in the CA bundle
+	CustomTLS bool `json:"customTLS,omitempty" jsonschema:"description=Indicates the ingress of the git server Zarf deploys is served with the certificate given to zarf init"`
 }
 
 // RegistryInfo contains information Zarf uses to communicate with a container registry to push/pull images.
@@ -51,14 +151,74 @@ type RegistryInfo struct {
 	PushPassword string `json:"pushPassword" jsonschema:"description=Password of a user with push access to the registry"`
 	PullUsername string `json:"pullUsername" jsonschema:"description=Username of a user with pull-only access to the registry. If not provided for an external registry than the push-user is used"`
 	PullPassword string `json:"pullPassword" jsonschema:"description=Password of a user with pull-only access to the registry. If not provided for an external registry than the push-user is used"`
+	PushIdentity string `json:"pushIdentity,omitempty" jsonschema:"description=Cloud workload identity that provides short-lived push tokens instead of the push user,enum=aws,enum=azure,enum=gcp"`
+	// CredentialHelper gets push and pull tokens when they are needed instead of keeping them in the state
+	CredentialHelper string `json:"credentialHelper,omitempty" jsonschema:"description=Workload identity (aws/azure/gcp) or docker credential helper (docker-credential-NAME) that provides short-lived push and pull tokens instead of the push and pull users"`
 
 	Address          string `json:"address" jsonschema:"description=URL address of the registry"`
 	NodePort         int    `json:"nodePort" jsonschema:"description=Nodeport of the registry. Only needed if the registry is running inside the kubernetes cluster"`
 	InternalRegistry bool   `json:"internalRegistry" jsonschema:"description=Indicates if we are using a registry that Zarf is directly managing"`
+	CABundle         []byte `json:"caBundle,omitempty" jsonschema:"description=PEM encoded CA chain used to verify the TLS certificate of an external registry"`
+	// InsecureSkipVerify is an escape hatch for registries whose certificate can't be verified at all
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty" jsonschema:"description=Skip verifying the TLS certificate of an external registry"`
+	// CustomTLS serves the Zarf registry with a certificate issued by the CA in the CA bundle instead of plain HTTP
+	CustomTLS bool `json:"customTLS,omitempty" jsonschema:"description=Indicates the registry Zarf deploys is served with the certificate given to zarf init"`
+
+	// The nodes pull from localhost through a NodePort, otherwise from the address of the load balancer or ingress
+	ServiceType string `json:"serviceType,omitempty" jsonschema:"description=Type of the service of the registry Zarf deploys,enum=NodePort,enum=LoadBalancer,enum=ClusterIP"`
+	IngressHost string `json:"ingressHost,omitempty" jsonschema:"description=Host of the ingress the nodes pull from the registry Zarf deploys through when its service is a ClusterIP"`
+
+	// ManifestFormat converts the images to the one manifest format a registry accepts, which gives them new digests
+	ManifestFormat    string `json:"manifestFormat,omitempty" jsonschema:"description=Manifest format the images are converted to before they are pushed (defaults to the format they were pulled in),enum=docker,enum=oci"`
+	PushForeignLayers bool   `json:"pushForeignLayers,omitempty" jsonschema:"description=Push the foreign (non-distributable) layers of images as regular layers for registries that reject them"`
+
+	PushPath string `json:"pushPath,omitempty" jsonschema:"description=Go template of the path images are pushed to such as {{.Project}}/{{.Namespace}}/{{.Name}} (defaults to the flattened path with a checksum)"`
+	Project  string `json:"project,omitempty" jsonschema:"description=Project (or other prefix) the registry requires images to be pushed under that the push path can use as {{.Project}}"`
+
+	InClusterService     string `json:"inClusterService,omitempty" jsonschema:"description=Namespace and name (NAMESPACE/NAME) of the service of a registry that was already running in the cluster before Zarf"`
+	InClusterServicePort int    `json:"inClusterServicePort,omitempty" jsonschema:"description=Port of the service of a registry that was already running in the cluster before Zarf"`
+	// InClusterExternal is a registry that runs in the cluster but that Zarf didn't deploy, so Zarf reaches it like its own registry but never manages it
+	InClusterExternal bool `json:"inClusterExternal,omitempty" jsonschema:"description=Indicates the registry runs in the cluster but was not deployed by Zarf"`
+
+	// ProxyURL turns the Zarf registry into a pull-through cache of an upstream registry, which serves the images instead of having them pushed
+	ProxyURL      string `json:"proxyUrl,omitempty" jsonschema:"description=URL of the upstream registry the Zarf registry is a pull-through cache of"`
+	ProxyUsername string `json:"proxyUsername,omitempty" jsonschema:"description=Username the Zarf registry pulls from the upstream registry with"`
+	ProxyPassword string `json:"proxyPassword,omitempty" jsonschema:"description=Password the Zarf registry pulls from the upstream registry with"`
+
+	Deployment RegistryDeployment `json:"deployment,omitempty" jsonschema:"description=Size and resources of the registry Zarf deploys into the cluster"`
+	// S3 keeps the images of the Zarf registry in an object store instead of a volume claim
+	S3 RegistryS3Storage `json:"s3,omitempty" jsonschema:"description=S3 compatible object store the registry Zarf deploys keeps its images in instead of a volume claim"`
 
 	Secret string `json:"secret" jsonschema:"description=Secret value that the registry was seeded with"`
 }
 
+// RegistryDeployment sizes the registry Zarf deploys into the cluster
+type RegistryDeployment struct {
+	PVCSize       string `json:"pvcSize,omitempty" jsonschema:"description=Size of the volume claim the registry stores images in"`
+	PVCAccessMode string `json:"pvcAccessMode,omitempty" jsonschema:"description=Access mode of the volume claim which has to be ReadWriteMany for more than one replica,enum=ReadWriteOnce,enum=ReadWriteMany"`
+	StorageClass  string `json:"storageClass,omitempty" jsonschema:"description=StorageClass of the volume claim when it differs from the StorageClass of the cluster"`
+
+	Replicas    int `json:"replicas,omitempty" jsonschema:"description=Number of registry replicas"`
+	MaxReplicas int `json:"maxReplicas,omitempty" jsonschema:"description=Most replicas a HorizontalPodAutoscaler scales the registry to, with 0 not autoscaling it"`
+
+	CPURequest    string `json:"cpuRequest,omitempty" jsonschema:"description=CPU request of each registry replica"`
+	MemoryRequest string `json:"memoryRequest,omitempty" jsonschema:"description=Memory request of each registry replica"`
+	CPULimit      string `json:"cpuLimit,omitempty" jsonschema:"description=CPU limit of each registry replica"`
+	MemoryLimit   string `json:"memoryLimit,omitempty" jsonschema:"description=Memory limit of each registry replica"`
+}
+
+// RegistryS3Storage points the registry Zarf deploys at an S3 compatible object store such as MinIO
+type RegistryS3Storage struct {
+	Endpoint string `json:"endpoint,omitempty" jsonschema:"description=URL of the object store, left empty for AWS S3 itself"`
+	Region   string `json:"region,omitempty" jsonschema:"description=Region of the bucket"`
+	Bucket   string `json:"bucket,omitempty" jsonschema:"description=Bucket the registry keeps its images in"`
+
+	AccessKey string `json:"accessKey,omitempty" jsonschema:"description=Access key of the object store, left empty to use the identity of the registry pods"`
+	SecretKey string `json:"secretKey,omitempty" jsonschema:"description=Secret key of the object store"`
+
+	CABundle []byte `json:"caBundle,omitempty" jsonschema:"description=PEM encoded CA chain used to verify the TLS certificate of the object store"`
+}
+
 // GeneratedPKI
 type GeneratedPKI struct {
 	CA   []byte `json:"ca"`
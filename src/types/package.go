@@ -8,6 +8,7 @@ type ZarfPackage struct {
 	Components []ZarfComponent       `json:"components" jsonschema:"description=List of components to deploy in this package"`
 	Variables  []ZarfPackageVariable `json:"variables,omitempty" jsonschema:"description=Variable template values applied on deploy for K8s resources"`
 	Constants  []ZarfPackageConstant `json:"constants,omitempty" jsonschema:"description=Constant template values applied on deploy for K8s resources"`
+	Profiles   []ZarfPackageProfile  `json:"profiles,omitempty" jsonschema:"description=Named component selections and variable presets that can be deployed with --profile"`
 }
 
 // ZarfMetadata lists information about the current ZarfPackage.
@@ -18,7 +19,7 @@ type ZarfMetadata struct {
 	Url          string `json:"url,omitempty" jsonschema:"description=Link to package information when online"`
 	Image        string `json:"image,omitempty" jsonschema:"description=An image URL to embed in this package for future Zarf UI listing"`
 	Uncompressed bool   `json:"uncompressed,omitempty" jsonschema:"description=Disable compression of this package"`
-	Architecture string `json:"architecture,omitempty" jsonschema:"description=The target cluster architecture of this package"`
+	Architecture string `json:"architecture,omitempty" jsonschema:"description=The target cluster architecture of this package or multi to build it for amd64 and arm64"`
 }
 
 // ZarfBuildData is written during the packager.Create() operation to track details of the created package.
@@ -28,6 +29,30 @@ type ZarfBuildData struct {
 	Architecture string `json:"architecture"`
 	Timestamp    string `json:"timestamp"`
 	Version      string `json:"version"`
+
+	// Set when the package only carries what changed since a previously built package
+	DifferentialPackageVersion string   `json:"differentialPackageVersion,omitempty"`
+	DifferentialMissingImages  []string `json:"differentialMissingImages,omitempty"`
+	DifferentialMissingRepos   []string `json:"differentialMissingRepos,omitempty"`
+
+	// Set when the package was built with --force-image-policy despite images violating the image policy
+	ImagePolicyViolations []string `json:"imagePolicyViolations,omitempty"`
+
+	// Estimated requests of the charts and manifests of each component so operators can check cluster capacity before deploying
+	ResourceEstimates map[string]ZarfResourceEstimate `json:"resourceEstimates,omitempty"`
+
+	// The cosign signature and attestation artifacts bundled for each image, pushed next to the images on deploy
+	ImageSignatures map[string][]string `json:"imageSignatures,omitempty"`
+
+	// The in-toto link metadata of the build steps, checked against a layout on deploy
+	InTotoLinks []string `json:"inTotoLinks,omitempty"`
+}
+
+// ZarfResourceEstimate is what the charts and manifests of a component request from the cluster, counted at package create.
+type ZarfResourceEstimate struct {
+	CPU     string `json:"cpu,omitempty" jsonschema:"description=The CPU requests of the component workloads multiplied by their replicas"`
+	Memory  string `json:"memory,omitempty" jsonschema:"description=The memory requests of the component workloads multiplied by their replicas"`
+	Storage string `json:"storage,omitempty" jsonschema:"description=The storage requested by the persistent volume claims of the component"`
 }
 
 // ZarfPackageVariable are variables that can be used to dynamically template K8s resources.
@@ -36,6 +61,7 @@ type ZarfPackageVariable struct {
 	Description string `json:"description,omitempty" jsonschema:"description=A description of the variable to be used when prompting the user a value"`
 	Default     string `json:"default,omitempty" jsonschema:"description=The default value to use for the variable"`
 	Prompt      bool   `json:"prompt,omitempty" jsonschema:"description=Whether to prompt the user for input for this variable"`
+	Sensitive   bool   `json:"sensitive,omitempty" jsonschema:"description=Whether to mask the value of this variable when it is displayed"`
 }
 
 // ZarfPackageConstant are constants that can be used to dynamically template K8s resources.
@@ -45,3 +71,34 @@ type ZarfPackageConstant struct {
 	// Include a description that will only be displayed during package create/deploy confirm prompts
 	Description string `json:"description,omitempty" jsonschema:"description=A description of the constant to explain its purpose on package create or deploy confirmation prompts"`
 }
+
+// ZarfPackageProfile is a common way to deploy a package, picked with zarf package deploy --profile instead of a long --components list.
+type ZarfPackageProfile struct {
+	Name        string            `json:"name" jsonschema:"description=The name of the profile to pass to --profile,pattern=^[a-z0-9\\-]+$"`
+	Description string            `json:"description,omitempty" jsonschema:"description=What the profile deploys, shown when the profile is picked"`
+	Components  []string          `json:"components,omitempty" jsonschema:"description=The optional components the profile deploys along with the required ones"`
+	Variables   map[string]string `json:"variables,omitempty" jsonschema:"description=Values the profile presets for package variables, which --set still overrides"`
+}
+
+// ZarfSplitPackageData is the index written alongside a package that was split into multiple parts.
+type ZarfSplitPackageData struct {
+	Sha256Sum string `json:"sha256Sum"`
+	Bytes     int64  `json:"bytes"`
+	Count     int    `json:"count"`
+}
+
+// ZarfBinaryCatalog records the binaries Zarf packages have installed into a host directory.
+type ZarfBinaryCatalog struct {
+	Binaries []ZarfInstalledBinary `json:"binaries"`
+}
+
+// ZarfInstalledBinary is a single binary entry in the host binary catalog.
+type ZarfInstalledBinary struct {
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Path        string `json:"path"`
+	Shasum      string `json:"shasum"`
+	Package     string `json:"package"`
+	Component   string `json:"component"`
+	InstalledAt string `json:"installedAt"`
+}
@@ -2,17 +2,41 @@ package types
 
 // ZarfCommonOptions tracks the user-defined preferences used across commands.
 type ZarfCommonOptions struct {
-	Confirm       bool   `json:"confirm" jsonschema:"description=Verify that Zarf should perform an action"`
-	CachePath     string `json:"cachePath" jsonschema:"description=Path to use to cache images and git repos on package create"`
-	TempDirectory string `json:"tempDirectory" jsonschema:"description=Location Zarf should use as a staging ground when managing files and images for package creation and deployment"`
+	Confirm        bool   `json:"confirm" jsonschema:"description=Verify that Zarf should perform an action"`
+	CachePath      string `json:"cachePath" jsonschema:"description=Path to use to cache images and git repos on package create"`
+	TempDirectory  string `json:"tempDirectory" jsonschema:"description=Location Zarf should use as a staging ground when managing files and images for package creation and deployment"`
+	NoProxyCluster bool   `json:"noProxyCluster" jsonschema:"description=Connect to the cluster and the Zarf registry and git server directly instead of through the HTTP(S)_PROXY settings"`
+	ResultFile     string `json:"resultFile" jsonschema:"description=Path to write a JSON summary of a deploy, remove or init to when it finishes or fails"`
 }
 
 // ZarfDeployOptions tracks the user-defined preferences during a package deployment
 type ZarfDeployOptions struct {
-	PackagePath  string            `json:"packagePath" jsonschema:"description=Location where a Zarf package to deploy can be found"`
-	Components   string            `json:"components" jsonschema:"description=Comma separated list of optional components to deploy"`
-	SGetKeyPath  string            `json:"sGetKeyPath" jsonschema:"description=Location where the public key component of a cosign key-pair can be found"`
-	SetVariables map[string]string `json:"setVariables" jsonschema:"description=Key-Value map of variable names and their corresponding values that will be used to template against the Zarf package being used"`
+	PackagePath        string            `json:"packagePath" jsonschema:"description=Location where a Zarf package to deploy can be found"`
+	Components         string            `json:"components" jsonschema:"description=Comma separated list of optional components to deploy"`
+	Profile            string            `json:"profile" jsonschema:"description=Profile of the package whose components and variable presets to deploy"`
+	SGetKeyPath        string            `json:"sGetKeyPath" jsonschema:"description=Location where the public key component of a cosign key-pair can be found"`
+	Insecure           bool              `json:"insecure" jsonschema:"description=Skip shasum validation of remote packages"`
+	OCIInsecure        bool              `json:"ociInsecure" jsonschema:"description=Allow plain HTTP and unverified TLS connections to the OCI registry of an oci:// package"`
+	SetVariables       map[string]string `json:"setVariables" jsonschema:"description=Key-Value map of variable names and their corresponding values that will be used to template against the Zarf package being used"`
+	Resume             bool              `json:"resume" jsonschema:"description=Skip the components that an earlier failed deployment of this package already finished"`
+	ImageSizeWarningMB int               `json:"imageSizeWarningMB" jsonschema:"description=Warn about images larger than this many megabytes, 0 disables the check"`
+	BinDirectory       string            `json:"binDirectory" jsonschema:"description=Directory on the host where component binaries are installed"`
+	Retries            map[string]string `json:"retries" jsonschema:"description=Number of retries for each deploy phase after its first attempt fails"`
+	Timeouts           map[string]string `json:"timeouts" jsonschema:"description=Time limit for each deploy phase including its retries"`
+	Deadline           string            `json:"deadline" jsonschema:"description=Time limit for the whole deployment"`
+
+	AdoptExistingResources bool   `json:"adoptExistingResources" jsonschema:"description=Take over resources that already exist in the cluster by adding helm ownership metadata before installing charts"`
+	AtomicCharts           bool   `json:"atomicCharts" jsonschema:"description=Roll back or uninstall a release as soon as an attempt fails for every chart"`
+	KeepFailedCharts       bool   `json:"keepFailedCharts" jsonschema:"description=Leave releases that failed their last attempt in place for debugging for every chart"`
+	GitForce               bool   `json:"gitForce" jsonschema:"description=Force-push every repo and remove the branches and tags the package no longer has from the git server"`
+	GitChunkSizeMB         int    `json:"gitChunkSizeMB" jsonschema:"description=Push the history of repos larger than this many megabytes in resumable chunks of about this size and 0 disables chunking"`
+	OCIConcurrency         int    `json:"ociConcurrency" jsonschema:"description=Number of images and layers of each image pushed to the registry at once"`
+	PrePullSizeMB          int    `json:"prePullSizeMB" jsonschema:"description=Pull images of at least this many megabytes on every node after they are pushed and 0 disables the pre-pull"`
+	ImagePolicyPath        string `json:"imagePolicyPath" jsonschema:"description=Path to an image policy file the package images must meet before they are pushed"`
+	ForceImagePolicy       bool   `json:"forceImagePolicy" jsonschema:"description=Deploy the package even when images violate the image policy and record the violations in the cluster"`
+
+	InTotoLayoutPath string   `json:"inTotoLayoutPath" jsonschema:"description=Path to a signed in-toto layout the in-toto links of the package must meet"`
+	InTotoLayoutKeys []string `json:"inTotoLayoutKeys" jsonschema:"description=Public keys of the owners of the in-toto layout"`
 }
 
 // ZarfInitOptions tracks the user-defined options during cluster initialization.
@@ -28,14 +52,61 @@ type ZarfInitOptions struct {
 	Components string `json:"components" jsonschema:"description=Comma separated list of optional components to deploy"`
 
 	StorageClass string `json:"storageClass" jsonschema:"description=StorageClass of the k8s cluster Zarf is initializing"`
+
+	StorageClassCheck bool `json:"storageClassCheck" jsonschema:"description=Prove the StorageClass can bind volumes with a test claim before deploying the stateful init components"`
+
+	PodSecurityExemption bool `json:"podSecurityExemption" jsonschema:"description=Label the zarf namespace with the Pod Security Standard the init components need when a stricter one is enforced"`
+
+	AgentWebhook AgentWebhook `json:"agentWebhook" jsonschema:"description=Settings for the agent mutating webhook"`
+
+	StateStore StateStore `json:"stateStore" jsonschema:"description=Secrets manager to keep the credentials of the Zarf state in"`
+
+	StateEncryption StateEncryption `json:"stateEncryption" jsonschema:"description=Key to encrypt the credentials of the Zarf state with"`
+
+	// Certificates issued by the CA of the organization, which replace the plain HTTP of the Zarf registry and git server ingress
+	RegistryTLS GeneratedPKI `json:"registryTLS" jsonschema:"description=Certificate, key and CA the registry Zarf deploys is served with"`
+	GitTLS      GeneratedPKI `json:"gitTLS" jsonschema:"description=Certificate, key and CA the ingress of the git server Zarf deploys is served with"`
 }
 
 // ZarfCreateOptions tracks the user-defined options used to create the package.
 type ZarfCreateOptions struct {
-	SkipSBOM        bool              `json:"skipSBOM" jsonschema:"description=Disable the generation of SBOM materials during package creation"`
-	Insecure        bool              `json:"insecure" jsonschema:"description=Disable the need for shasum validations when pulling down files from the internet"`
-	OutputDirectory string            `json:"outputDirectory" jsonschema:"description=Location where the finalized Zarf package will be placed"`
-	SetVariables    map[string]string `json:"setVariables" jsonschema:"description=Key-Value map of variable names and their corresponding values that will be used to template against the Zarf package being used"`
+	SkipSBOM           bool              `json:"skipSBOM" jsonschema:"description=Disable the generation of SBOM materials during package creation"`
+	Insecure           bool              `json:"insecure" jsonschema:"description=Disable the need for shasum validations when pulling down files from the internet"`
+	OutputDirectory    string            `json:"outputDirectory" jsonschema:"description=Location where the finalized Zarf package will be placed"`
+	DifferentialPath   string            `json:"differentialPath" jsonschema:"description=Path to a previously built package whose unchanged images and git repos are left out of this package"`
+	MaxPackageSize     string            `json:"maxPackageSize" jsonschema:"description=Split the package into numbered parts no larger than this size (such as 4GB)"`
+	Compression        string            `json:"compression" jsonschema:"description=Compression to use for the package archive,enum=zstd,enum=gzip,enum=none"`
+	CompressionLevel   int               `json:"compressionLevel" jsonschema:"description=Compression level to use (1-22 for zstd and 1-9 for gzip) with 0 using the default"`
+	SetVariables       map[string]string `json:"setVariables" jsonschema:"description=Key-Value map of variable names and their corresponding values that will be used to template against the Zarf package being used"`
+	ImageSizeWarningMB int               `json:"imageSizeWarningMB" jsonschema:"description=Warn about images larger than this many megabytes, 0 disables the check"`
+	Watch              bool              `json:"watch" jsonschema:"description=Rebuild the components that change until interrupted"`
+	HelmRepositories   []HelmRepository  `json:"helmRepositories" jsonschema:"description=Credentials and TLS settings for private helm repositories"`
+	ImagePolicyPath    string            `json:"imagePolicyPath" jsonschema:"description=Path to an image policy file the package images must meet"`
+	ForceImagePolicy   bool              `json:"forceImagePolicy" jsonschema:"description=Build the package even when images violate the image policy and record the violations in the package"`
+	PinDigests         bool              `json:"pinDigests" jsonschema:"description=Record each image with the digest its tag resolved to and push it by digest on deploy"`
+	IncludeSignatures  bool              `json:"includeSignatures" jsonschema:"description=Bundle the cosign signatures and attestations of the images and push them next to the images on deploy"`
+	SignatureKeys      []string          `json:"signatureKeys" jsonschema:"description=Public keys the bundled signatures must verify against"`
+	MultiArch          bool              `json:"multiArch" jsonschema:"description=Build the package for every supported architecture so deploy can pick the one of the cluster"`
+	InTotoLinksPath    string            `json:"inTotoLinksPath" jsonschema:"description=Directory of the in-toto link metadata the build pipeline recorded for this package"`
+}
+
+// HelmRepository holds the credentials and TLS settings used to download charts from a private helm repository
+type HelmRepository struct {
+	URL                   string `json:"url" mapstructure:"url" jsonschema:"description=URL of the repository, charts whose url starts with it use these settings"`
+	Username              string `json:"username" mapstructure:"username" jsonschema:"description=Username for the repository"`
+	Password              string `json:"password" mapstructure:"password" jsonschema:"description=Password or access token for the repository"`
+	CAFile                string `json:"caFile" mapstructure:"ca_file" jsonschema:"description=Path to a PEM encoded CA bundle to verify the repository with"`
+	CertFile              string `json:"certFile" mapstructure:"cert_file" jsonschema:"description=Path to a client certificate for the repository"`
+	KeyFile               string `json:"keyFile" mapstructure:"key_file" jsonschema:"description=Path to the key of the client certificate"`
+	InsecureSkipTLSVerify bool   `json:"insecureSkipTLSVerify" mapstructure:"insecure_skip_tls_verify" jsonschema:"description=Skip verifying the TLS certificate of the repository"`
+	PassCredentialsAll    bool   `json:"passCredentialsAll" mapstructure:"pass_credentials_all" jsonschema:"description=Send the credentials to chart downloads on other hosts too"`
+}
+
+// ImagePolicy lists the images an organization allows into the airgap
+type ImagePolicy struct {
+	Allow    []string `json:"allow,omitempty" jsonschema:"description=Glob patterns of the image repositories allowed (such as ghcr.io/defenseunicorns/**) and every repository is allowed when empty"`
+	Deny     []string `json:"deny,omitempty" jsonschema:"description=Glob patterns of the image repositories denied even when they are allowed"`
+	DenyTags []string `json:"denyTags,omitempty" jsonschema:"description=Glob patterns of the image tags denied (such as latest)"`
 }
 
 type ConnectString struct {
@@ -44,3 +115,48 @@ type ConnectString struct {
 }
 
 type ConnectStrings map[string]ConnectString
+
+// CommandResult is the machine-readable summary of a deploy, remove or init written to the --result-file
+type CommandResult struct {
+	Command         string               `json:"command" jsonschema:"description=Command the result is for,enum=deploy,enum=remove,enum=init"`
+	Package         string               `json:"package,omitempty" jsonschema:"description=Name of the package"`
+	Version         string               `json:"version,omitempty" jsonschema:"description=Version of the package"`
+	Status          string               `json:"status" jsonschema:"description=How the command ended,enum=succeeded,enum=failed,enum=cancelled,enum=dry-run"`
+	Error           string               `json:"error,omitempty" jsonschema:"description=Error that stopped the command"`
+	StartedAt       string               `json:"startedAt" jsonschema:"description=When the command started"`
+	FinishedAt      string               `json:"finishedAt" jsonschema:"description=When the command finished"`
+	DurationSeconds float64              `json:"durationSeconds" jsonschema:"description=How long the command ran"`
+	Components      []ComponentResult    `json:"components,omitempty" jsonschema:"description=The components the command deployed or removed in the order they started"`
+	ConnectStrings  ConnectStrings       `json:"connectStrings,omitempty" jsonschema:"description=Names zarf connect can open for the deployed package"`
+	Credentials     []CredentialLocation `json:"credentials,omitempty" jsonschema:"description=Where the credentials Zarf generated are kept, never their values"`
+	Metrics         *DeployMetrics       `json:"metrics,omitempty" jsonschema:"description=What the deploy or init pushed and how long its phases took"`
+}
+
+// DeployMetrics sums up the data a deploy sent to the Zarf registry and git server and the time it spent in each phase
+type DeployMetrics struct {
+	ImageBytesPushed   int64           `json:"imageBytesPushed" jsonschema:"description=Bytes of image layers pushed to the registry"`
+	ImageBytesSkipped  int64           `json:"imageBytesSkipped" jsonschema:"description=Bytes of image layers not pushed because the registry already had them"`
+	ImageLayersPushed  int             `json:"imageLayersPushed" jsonschema:"description=Image layers pushed to the registry"`
+	ImageLayersSkipped int             `json:"imageLayersSkipped" jsonschema:"description=Image layers not pushed because the registry already had them"`
+	RepoBytesPushed    int64           `json:"repoBytesPushed" jsonschema:"description=Bytes of git packfiles pushed to the git server"`
+	Phases             []PhaseDuration `json:"phases,omitempty" jsonschema:"description=Wall time spent in each deploy phase"`
+}
+
+// PhaseDuration is the time a deploy spent in one phase, added up across its components and retries
+type PhaseDuration struct {
+	Name            string  `json:"name" jsonschema:"description=Name of the phase,enum=extract,enum=images,enum=repos,enum=charts,enum=data"`
+	DurationSeconds float64 `json:"durationSeconds" jsonschema:"description=Time spent in the phase"`
+}
+
+// ComponentResult is the outcome of one component in a CommandResult
+type ComponentResult struct {
+	Name            string  `json:"name" jsonschema:"description=Name of the component"`
+	Status          string  `json:"status" jsonschema:"description=What happened to the component,enum=deployed,enum=removed,enum=skipped,enum=planned,enum=failed"`
+	DurationSeconds float64 `json:"durationSeconds" jsonschema:"description=How long the component took"`
+}
+
+// CredentialLocation points at where generated credentials are kept
+type CredentialLocation struct {
+	Name     string `json:"name" jsonschema:"description=What the credentials are for"`
+	Location string `json:"location" jsonschema:"description=Where the credentials are kept"`
+}